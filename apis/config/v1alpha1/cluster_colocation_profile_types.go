@@ -94,12 +94,77 @@ type ClusterColocationProfileSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	Patch runtime.RawExtension `json:"patch,omitempty"`
+
+	// DryRun indicates the profile only reports the Pods and Reservations it would mutate in the status,
+	// and neither the webhook nor the controller mutates them.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // ClusterColocationProfileStatus represents information about the status of a ClusterColocationProfile.
 type ClusterColocationProfileStatus struct {
+	// ObservedGeneration is the most recent generation of the profile reconciled by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Summary is the result of the last reconciliation.
+	// +optional
+	Summary *ColocationProfileSummary `json:"summary,omitempty"`
+	// DryRunResults are the mutations the profile would make in the last reconciliation when DryRun is set.
+	// +optional
+	DryRunResults []ColocationProfileDryRunResult `json:"dryRunResults,omitempty"`
+	// Conditions describe the current state of the profile.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ColocationProfileSummary counts the objects handled in a reconciliation of the profile.
+type ColocationProfileSummary struct {
+	// MatchedPods is the number of the pending Pods matched by the profile.
+	MatchedPods int32 `json:"matchedPods"`
+	// DesiredPods is the number of the matched Pods which should be mutated.
+	DesiredPods int32 `json:"desiredPods"`
+	// ChangedPods is the number of the Pods mutated, or to mutate in dry-run.
+	ChangedPods int32 `json:"changedPods"`
+	// FailedPods is the number of the Pods failed to mutate.
+	FailedPods int32 `json:"failedPods"`
+	// RateLimitedPods is the number of the Pods not mutated because of the rate limit.
+	RateLimitedPods int32 `json:"rateLimitedPods"`
+	// SkippedPods is the number of the Pods skipped by the probability.
+	SkippedPods int32 `json:"skippedPods"`
+	// CachedPods is the number of the Pods already mutated recently.
+	CachedPods int32 `json:"cachedPods"`
+	// MatchedReservations is the number of the pending Reservations matched by the profile.
+	MatchedReservations int32 `json:"matchedReservations"`
+	// ChangedReservations is the number of the Reservations mutated, or to mutate in dry-run.
+	ChangedReservations int32 `json:"changedReservations"`
+	// FailedReservations is the number of the Reservations failed to mutate.
+	FailedReservations int32 `json:"failedReservations"`
+}
+
+// ColocationProfileDryRunResult describes the mutation of an object the profile would make.
+type ColocationProfileDryRunResult struct {
+	// Kind is the kind of the object, Pod or Reservation.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object. It is empty for the Reservation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// Patch is the JSON merge patch the profile would apply to the object.
+	Patch string `json:"patch"`
 }
 
+const (
+	// ColocationProfileConditionSynced indicates whether all the matched objects are mutated by the profile.
+	ColocationProfileConditionSynced = "Synced"
+
+	ColocationProfileReasonSynced        = "Synced"
+	ColocationProfileReasonDryRun        = "DryRun"
+	ColocationProfileReasonRateLimited   = "RateLimited"
+	ColocationProfileReasonMutateFailed  = "MutateFailed"
+	ColocationProfileReasonInvalidConfig = "InvalidConfig"
+)
+
 // +genclient
 // +genclient:nonNamespaced
 // +kubebuilder:resource:scope=Cluster
@@ -32,7 +32,7 @@ func (in *ClusterColocationProfile) DeepCopyInto(out *ClusterColocationProfile)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterColocationProfile.
@@ -152,6 +152,23 @@ func (in *ClusterColocationProfileSpec) DeepCopy() *ClusterColocationProfileSpec
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterColocationProfileStatus) DeepCopyInto(out *ClusterColocationProfileStatus) {
 	*out = *in
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(ColocationProfileSummary)
+		**out = **in
+	}
+	if in.DryRunResults != nil {
+		in, out := &in.DryRunResults, &out.DryRunResults
+		*out = make([]ColocationProfileDryRunResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterColocationProfileStatus.
@@ -163,3 +180,33 @@ func (in *ClusterColocationProfileStatus) DeepCopy() *ClusterColocationProfileSt
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationProfileDryRunResult) DeepCopyInto(out *ColocationProfileDryRunResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationProfileDryRunResult.
+func (in *ColocationProfileDryRunResult) DeepCopy() *ColocationProfileDryRunResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationProfileDryRunResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationProfileSummary) DeepCopyInto(out *ColocationProfileSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationProfileSummary.
+func (in *ColocationProfileSummary) DeepCopy() *ColocationProfileSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationProfileSummary)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -198,6 +198,13 @@ const (
 	// It is the conservative policy where the resources are NOT over-committed between priority bands while HP's usage
 	// is also protected from the overcommitment.
 	CalculateByPodMaxUsageRequest CalculatePolicy = "maxUsageRequest"
+	// CalculateByPrediction is the calculate policy according to the seasonal forecast of the pod resource usage.
+	// When the policy="prediction", the low-priority (LP) resources are calculated according to the high-priority (HP)
+	// pods' usages forecasted within a horizon by the koordlet (the ProdSeasonalReclaimableMetric in NodeMetric), so LP
+	// pods do not reclaim the resources which HP pods are expected to use soon, e.g. before the daily ramp.
+	// It is also bounded by the "usage" policy, and it falls back to "usage" when the forecast is not reported.
+	// The forecast is node-level only, so the NUMA-level batch resources are calculated with the "usage" policy.
+	CalculateByPrediction CalculatePolicy = "prediction"
 )
 
 // +k8s:deepcopy-gen=true
@@ -237,11 +244,11 @@ type ColocationStrategy struct {
 
 	CPUReclaimThresholdPercent *int64 `json:"cpuReclaimThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	// CPUCalculatePolicy determines the calculation policy of the CPU resources for the Batch pods.
-	// Supported: "usage" (default), "maxUsageRequest".
+	// Supported: "usage" (default), "maxUsageRequest", "prediction".
 	CPUCalculatePolicy            *CalculatePolicy `json:"cpuCalculatePolicy,omitempty"`
 	MemoryReclaimThresholdPercent *int64           `json:"memoryReclaimThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	// MemoryCalculatePolicy determines the calculation policy of the memory resources for the Batch pods.
-	// Supported: "usage" (default), "request", "maxUsageRequest".
+	// Supported: "usage" (default), "request", "maxUsageRequest", "prediction".
 	MemoryCalculatePolicy      *CalculatePolicy `json:"memoryCalculatePolicy,omitempty"`
 	DegradeTimeMinutes         *int64           `json:"degradeTimeMinutes,omitempty" validate:"omitempty,min=1"`
 	UpdateTimeThresholdSeconds *int64           `json:"updateTimeThresholdSeconds,omitempty" validate:"omitempty,min=1"`
@@ -17,6 +17,8 @@ limitations under the License.
 package extension
 
 import (
+	"encoding/json"
+	"fmt"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -62,8 +64,36 @@ const (
 	// AnnotationAliasGangMatchPolicy defines same match policy but different prefix.
 	// Duplicate definitions here are only for compatibility considerations
 	AnnotationAliasGangMatchPolicy = "pod-group.scheduling.sigs.k8s.io/match-policy"
+
+	// AnnotationGangNetworkTopology defines the network topology constraint of the gang or gang group,
+	// e.g. {"layers":["network.topology.koordinator.sh/switch","network.topology.koordinator.sh/spine"],"mode":"Required"}
+	AnnotationGangNetworkTopology = AnnotationGangPrefix + "/network-topology"
+	// AnnotationGangTopologyDomain is set by the scheduler on the PodGroup to report the network topology domain chosen
+	// for the gang, e.g. "network.topology.koordinator.sh/switch=s1"
+	AnnotationGangTopologyDomain = AnnotationGangPrefix + "/topology-domain"
+
+	// GangNetworkTopologyModeRequired means the whole gang must be placed in a single domain.
+	GangNetworkTopologyModeRequired = "Required"
+	// GangNetworkTopologyModePreferred means the gang prefers a single domain but can spread if no domain fits.
+	GangNetworkTopologyModePreferred = "Preferred"
+
+	// AnnotationGangElastic enables the elastic mode of the gang if it is "true".
+	// Once min-available members are bound, the scheduler grows the gang towards total-number opportunistically,
+	// and the members beyond min-available can be preempted by the gangs which have not reached their min-available.
+	AnnotationGangElastic = AnnotationGangPrefix + "/elastic"
+	// LabelGangElasticMember is set to "true" by the scheduler on the members of an elastic gang beyond min-available.
+	LabelGangElasticMember = AnnotationGangPrefix + "/elastic-member"
 )
 
+// GangNetworkTopologySpec describes the topology domains the gang members should be placed in.
+type GangNetworkTopologySpec struct {
+	// Layers are the node label keys of the topology hierarchy, ordered from the innermost domain to the outermost one.
+	// The gang is placed in a single domain of the innermost layer which can hold it.
+	Layers []string `json:"layers"`
+	// Mode is Required or Preferred, defaults to Required.
+	Mode string `json:"mode,omitempty"`
+}
+
 const (
 	// Deprecated: kubernetes-sigs/scheduler-plugins/lightweight-coscheduling
 	LabelLightweightCoschedulingPodGroupName = "pod-group.scheduling.sigs.k8s.io/name"
@@ -79,6 +109,10 @@ func GetMinNum(pod *corev1.Pod) (int, error) {
 	return int(minRequiredNum), nil
 }
 
+func IsGangElasticMember(pod *corev1.Pod) bool {
+	return pod.Labels[LabelGangElasticMember] == "true"
+}
+
 func GetGangName(pod *corev1.Pod) string {
 	return pod.Annotations[AnnotationGangName]
 }
@@ -90,3 +124,24 @@ func GetGangMatchPolicy(pod *corev1.Pod) string {
 	}
 	return pod.Annotations[AnnotationAliasGangMatchPolicy]
 }
+
+func GetGangNetworkTopologySpec(annotations map[string]string) (*GangNetworkTopologySpec, error) {
+	data, ok := annotations[AnnotationGangNetworkTopology]
+	if !ok || data == "" {
+		return nil, nil
+	}
+	spec := &GangNetworkTopologySpec{}
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		return nil, err
+	}
+	if len(spec.Layers) == 0 {
+		return nil, fmt.Errorf("layers must be specified")
+	}
+	if spec.Mode == "" {
+		spec.Mode = GangNetworkTopologyModeRequired
+	}
+	if spec.Mode != GangNetworkTopologyModeRequired && spec.Mode != GangNetworkTopologyModePreferred {
+		return nil, fmt.Errorf("unsupported mode %q", spec.Mode)
+	}
+	return spec, nil
+}
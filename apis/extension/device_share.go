@@ -34,6 +34,8 @@ const (
 	AnnotationDeviceAllocateHint = SchedulingDomainPrefix + "/device-allocate-hint"
 	// AnnotationDeviceJointAllocate guides the scheduler joint-allocates devices
 	AnnotationDeviceJointAllocate = SchedulingDomainPrefix + "/device-joint-allocate"
+	// AnnotationDeviceJobTopology declares the device topology requirements of a job whose members span nodes
+	AnnotationDeviceJobTopology = SchedulingDomainPrefix + "/device-job-topology"
 	// AnnotationGPUPartitionSpec represents the GPU partition spec that pod requests
 	AnnotationGPUPartitionSpec = SchedulingDomainPrefix + "/gpu-partition-spec"
 	// AnnotationGPUPartitions represents the GPU partitions supported on the node
@@ -55,6 +57,7 @@ const (
 
 const (
 	LabelGPUPartitionPolicy         string = NodeDomainPrefix + "/gpu-partition-policy"
+	LabelGPUVendor                  string = NodeDomainPrefix + "/gpu-vendor"
 	LabelGPUModel                   string = NodeDomainPrefix + "/gpu-model"
 	LabelGPUDriverVersion           string = NodeDomainPrefix + "/gpu-driver-version"
 	LabelSecondaryDeviceWellPlanned string = NodeDomainPrefix + "/secondary-device-well-planned"
@@ -134,6 +137,17 @@ const (
 	SamePCIeDeviceJointAllocateScope DeviceJointAllocateScope = "SamePCIe"
 )
 
+// DeviceJobTopology describes the device topology requirements of a multi-node job.
+// Each member of the job joint-allocates its GPUs and RDMA VFs under the same PCIe switch,
+// unless the member specifies AnnotationDeviceJointAllocate explicitly.
+type DeviceJobTopology struct {
+	// JobName identifies the members of the job in the same namespace, defaults to the gang name of the pod.
+	JobName string `json:"jobName,omitempty"`
+	// FabricDomainLabel is the node label key of the fabric domain, e.g. the RDMA network the nodes are connected to.
+	// The nodes in the same fabric domain as the placed members of the job are preferred.
+	FabricDomainLabel string `json:"fabricDomainLabel,omitempty"`
+}
+
 type DeviceAllocateHints map[schedulingv1alpha1.DeviceType]*DeviceHint
 
 type DeviceHint struct {
@@ -318,6 +332,19 @@ func GetDeviceJointAllocate(annotations map[string]string) (*DeviceJointAllocate
 	return &jointAllocate, nil
 }
 
+func GetDeviceJobTopology(annotations map[string]string) (*DeviceJobTopology, error) {
+	val, ok := annotations[AnnotationDeviceJobTopology]
+	if !ok {
+		return nil, nil
+	}
+	var jobTopology DeviceJobTopology
+	err := json.Unmarshal([]byte(val), &jobTopology)
+	if err != nil {
+		return nil, err
+	}
+	return &jobTopology, nil
+}
+
 func GetGPUPartitionSpec(annotations map[string]string) (*GPUPartitionSpec, error) {
 	val, ok := annotations[AnnotationGPUPartitionSpec]
 	if !ok {
@@ -18,13 +18,27 @@ package extension
 
 import (
 	"encoding/json"
+	"math"
 )
 
 const (
 	// AnnotationResctrl describes the resctrl config of pod
 	AnnotationResctrl = NodeDomainPrefix + "/resctrl"
+	// AnnotationNodeResctrlInfo describes the resctrl capacity of the node, which is reported by the koordlet in the
+	// NodeResourceTopology
+	AnnotationNodeResctrlInfo = NodeDomainPrefix + "/resctrl-info"
 )
 
+// NodeResctrlInfo is the resctrl capacity of a node. Each pod with the resctrl annotation takes a dedicated resctrl
+// group, which consumes a CLOS ID of the hardware.
+type NodeResctrlInfo struct {
+	// ClosIDs is the number of CLOS IDs available for the pod-level resctrl groups, excluding the ones taken by the
+	// root group, the QoS class groups and the other groups not created for pods.
+	ClosIDs int `json:"closIDs"`
+	// CacheWays is the number of the L3 cache ways, i.e. the bits of the L3 cbm mask.
+	CacheWays int `json:"cacheWays,omitempty"`
+}
+
 type Resctrl struct {
 	L3 map[int]string
 	MB map[int]string
@@ -67,3 +81,51 @@ func GetResctrlInfo(annotations map[string]string) (*ResctrlConfig, error) {
 	}
 	return res, nil
 }
+
+// HasResctrlConfig returns true if the pod requests a dedicated resctrl group.
+func HasResctrlConfig(annotations map[string]string) bool {
+	_, ok := annotations[AnnotationResctrl]
+	return ok
+}
+
+// GetNodeResctrlInfo parses the resctrl capacity from the NodeResourceTopology annotations.
+// It returns nil if the node does not report the resctrl capacity.
+func GetNodeResctrlInfo(annotations map[string]string) (*NodeResctrlInfo, error) {
+	data, ok := annotations[AnnotationNodeResctrlInfo]
+	if !ok {
+		return nil, nil
+	}
+	info := &NodeResctrlInfo{}
+	if err := json.Unmarshal([]byte(data), info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// MinCacheWays returns the minimum number of the L3 cache ways in the requested LLC ranges on a cache with the
+// given ways, where the ways of a range [start, end) are calculated the same as the koordlet does.
+// It returns -1 if no LLC range is requested.
+func (r *ResctrlConfig) MinCacheWays(cacheWays int) int {
+	minWays := -1
+	var ranges [][]int
+	if len(r.LLC.Schemata.Range) == 2 {
+		ranges = append(ranges, r.LLC.Schemata.Range)
+	}
+	for _, v := range r.LLC.SchemataPerCache {
+		if len(v.Range) == 2 {
+			ranges = append(ranges, v.Range)
+		}
+	}
+	for _, v := range ranges {
+		startWay := int(math.Ceil(float64(cacheWays) * float64(v[0]) / 100))
+		endWay := int(math.Ceil(float64(cacheWays) * float64(v[1]) / 100))
+		ways := endWay - startWay
+		if ways < 0 {
+			ways = 0
+		}
+		if minWays < 0 || ways < minWays {
+			minWays = ways
+		}
+	}
+	return minWays
+}
@@ -186,3 +186,89 @@ func TestGetResctrlInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNodeResctrlInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *NodeResctrlInfo
+		wantErr     bool
+	}{
+		{
+			name: "not reported",
+			want: nil,
+		},
+		{
+			name: "parse correctly",
+			annotations: map[string]string{
+				AnnotationNodeResctrlInfo: `{"closIDs":11,"cacheWays":15}`,
+			},
+			want: &NodeResctrlInfo{ClosIDs: 11, CacheWays: 15},
+		},
+		{
+			name: "parse failed",
+			annotations: map[string]string{
+				AnnotationNodeResctrlInfo: `invalid`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNodeResctrlInfo(tt.annotations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNodeResctrlInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNodeResctrlInfo() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResctrlConfig_MinCacheWays(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *ResctrlConfig
+		cacheWays int
+		want      int
+	}{
+		{
+			name:      "no LLC range",
+			config:    &ResctrlConfig{MB: MB{Schemata: SchemataConfig{Percent: 50}}},
+			cacheWays: 11,
+			want:      -1,
+		},
+		{
+			name:      "LLC range of the whole cache",
+			config:    &ResctrlConfig{LLC: LLC{Schemata: SchemataConfig{Range: []int{0, 100}}}},
+			cacheWays: 11,
+			want:      11,
+		},
+		{
+			name:      "LLC range too small to take a way",
+			config:    &ResctrlConfig{LLC: LLC{Schemata: SchemataConfig{Range: []int{20, 25}}}},
+			cacheWays: 4,
+			want:      0,
+		},
+		{
+			name: "min of the LLC ranges per cache",
+			config: &ResctrlConfig{LLC: LLC{
+				Schemata: SchemataConfig{Range: []int{0, 50}},
+				SchemataPerCache: []SchemataPerCacheConfig{
+					{CacheID: 1, SchemataConfig: SchemataConfig{Range: []int{10, 30}}},
+				},
+			}},
+			cacheWays: 10,
+			want:      2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.MinCacheWays(tt.cacheWays); got != tt.want {
+				t.Errorf("MinCacheWays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -29,6 +29,20 @@ const (
 	MidMemory   corev1.ResourceName = ResourceDomainPrefix + "mid-memory"
 )
 
+const (
+	// NetworkBandwidth is the network bandwidth of the node which can be requested by pods. The unit is bps.
+	NetworkBandwidth corev1.ResourceName = DomainPrefix + "network-bandwidth"
+	// BatchNetworkBandwidth is the network bandwidth of the node reclaimed for the Batch pods. The unit is bps.
+	BatchNetworkBandwidth corev1.ResourceName = ResourceDomainPrefix + "batch-network-bandwidth"
+	// DiskIOBandwidth is the disk io bandwidth of the node which can be requested by pods. The unit is bytes per second.
+	// The bandwidth is per direction, a pod requesting it is allowed to read and write at the requested bps each,
+	// so the bandwidth of a disk is the smaller one of its read and write bps.
+	DiskIOBandwidth corev1.ResourceName = DomainPrefix + "disk-io-bandwidth"
+	// BatchDiskIOBandwidth is the disk io bandwidth of the node reclaimed for the Batch pods.
+	// The unit is bytes per second.
+	BatchDiskIOBandwidth corev1.ResourceName = ResourceDomainPrefix + "batch-disk-io-bandwidth"
+)
+
 const (
 	// AnnotationExtendedResourceSpec specifies the resource requirements of extended resources for internal usage.
 	// It annotates the requests/limits of extended resources and can be used by runtime proxy and koordlet that
@@ -41,6 +55,8 @@ var (
 		PriorityBatch: {
 			corev1.ResourceCPU:    BatchCPU,
 			corev1.ResourceMemory: BatchMemory,
+			DiskIOBandwidth:       BatchDiskIOBandwidth,
+			NetworkBandwidth:      BatchNetworkBandwidth,
 		},
 		PriorityMid: {
 			corev1.ResourceCPU:    MidCPU,
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,14 +39,50 @@ type ElasticQuotaProfileSpec struct {
 }
 
 type ElasticQuotaProfileStatus struct {
+	// ObservedGeneration is the most recent generation of the profile synced by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// QuotaName is the name of the quota generated by the profile.
+	// +optional
+	QuotaName string `json:"quotaName,omitempty"`
+	// MatchedNodes is the number of the nodes selected by the NodeSelector.
+	// +optional
+	MatchedNodes int32 `json:"matchedNodes,omitempty"`
+	// UnschedulableNodes is the number of the matched nodes which are unschedulable or not ready.
+	// +optional
+	UnschedulableNodes int32 `json:"unschedulableNodes,omitempty"`
+	// TotalResource is the total allocatable of the matched nodes.
+	// +optional
+	TotalResource corev1.ResourceList `json:"totalResource,omitempty"`
+	// DecoratedTotalResource is the TotalResource decorated by the ResourceRatio, which is the min of the quota.
+	// +optional
+	DecoratedTotalResource corev1.ResourceList `json:"decoratedTotalResource,omitempty"`
+	// LastSyncTime is the last time the generated quota is changed by the profile.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Conditions describe the current state of the profile.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ElasticQuotaProfileConditionNodesMatched indicates whether the NodeSelector selects any node.
+	ElasticQuotaProfileConditionNodesMatched = "NodesMatched"
+	// ElasticQuotaProfileConditionResourceRatioValid indicates whether the ResourceRatio is valid.
+	ElasticQuotaProfileConditionResourceRatioValid = "ResourceRatioValid"
+	// ElasticQuotaProfileConditionParentQuotaFit indicates whether the generated quota fits in its parent quota.
+	ElasticQuotaProfileConditionParentQuotaFit = "ParentQuotaFit"
+	// ElasticQuotaProfileConditionQuotaSynced indicates whether the generated quota is synced.
+	ElasticQuotaProfileConditionQuotaSynced = "QuotaSynced"
+)
+
 //  ElasticQuotaProfile is the Schema for the ElasticQuotaProfile API
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +genclient
 // +kubebuilder:resource:shortName=eqp
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 type ElasticQuotaProfile struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -119,6 +119,11 @@ type NodeMetricStatus struct {
 
 	// ProdReclaimableMetric is the indicator statistics of Prod type resources reclaimable
 	ProdReclaimableMetric *ReclaimableMetric `json:"prodReclaimableMetric,omitempty"`
+
+	// ProdSeasonalReclaimableMetric is the forward-looking estimate of Prod type resources reclaimable, which is
+	// calculated by the seasonal forecasts of the peak usages within the forecast horizon.
+	// It is missing until the seasonal models have learned the daily cycle of the node.
+	ProdSeasonalReclaimableMetric *ReclaimableMetric `json:"prodSeasonalReclaimableMetric,omitempty"`
 }
 
 // +genclient
@@ -59,6 +59,16 @@ const (
 	NETQOSPolicyTerwayQos NETQOSPolicy = "terway-qos"
 )
 
+type ResctrlQOSPolicy string
+
+const (
+	// ResctrlQOSPolicyStatic indicates the CAT ranges and MBA percents of the QoS classes are applied as configured.
+	ResctrlQOSPolicyStatic ResctrlQOSPolicy = "static"
+	// ResctrlQOSPolicyDynamic indicates the CAT range ends and MBA percents are adjusted by the koordlet according to
+	// the resctrl monitoring and the CPI of the LS pods, within the min/max bounds of the QoS classes.
+	ResctrlQOSPolicyDynamic ResctrlQOSPolicy = "dynamic"
+)
+
 // MemoryQOS enables memory qos features.
 type MemoryQOS struct {
 	// memcg qos
@@ -284,6 +294,9 @@ type ResourceQOSPolicies struct {
 
 	// applied policy for the Net QoS, default = "tc"
 	NETQOSPolicy *NETQOSPolicy `json:"netQOSPolicy,omitempty"`
+
+	// applied policy for the Resctrl QoS, default = "static"
+	ResctrlPolicy *ResctrlQOSPolicy `json:"resctrlPolicy,omitempty"`
 }
 
 type ResourceQOSStrategy struct {
@@ -311,8 +324,105 @@ type CPUSuppressPolicy string
 const (
 	CPUSetPolicy      CPUSuppressPolicy = "cpuset"
 	CPUCfsQuotaPolicy CPUSuppressPolicy = "cfsQuota"
+	// CPUAdaptivePolicy suppresses BE by cfs quota whose value is adjusted by a proportional-integral feedback
+	// controller on the LS usage headroom, which is configured by the CPUSuppressAdaptiveStrategy.
+	CPUAdaptivePolicy CPUSuppressPolicy = "adaptive"
 )
 
+// CPUSuppressAdaptiveStrategy configures the feedback controller of the adaptive cpu suppress policy.
+// The controller takes the headroom between the BE suppress target and the BE usage as its error, and outputs the
+// BE cpu allowance. All percentages of cpu cores are relative to the node cpu capacity.
+type CPUSuppressAdaptiveStrategy struct {
+	// ProportionalGainPercent is the proportional gain of the controller in percentage, default = 50
+	// +kubebuilder:validation:Minimum=0
+	ProportionalGainPercent *int64 `json:"proportionalGainPercent,omitempty" validate:"omitempty,min=0"`
+	// IntegralGainPercent is the integral gain of the controller in percentage, default = 20
+	// +kubebuilder:validation:Minimum=0
+	IntegralGainPercent *int64 `json:"integralGainPercent,omitempty" validate:"omitempty,min=0"`
+	// IntegralLimitPercent is the anti-windup bound of the integral term, default = 50
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	IntegralLimitPercent *int64 `json:"integralLimitPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// MinStepPercent is the minimal change of the output in one cycle, smaller changes are bypassed, default = 1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MinStepPercent *int64 `json:"minStepPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// MaxStepPercent is the maximal increase of the output in one cycle, default = 10
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MaxStepPercent *int64 `json:"maxStepPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// SmoothingPercent is the weight of the last output for the exponential smoothing of the output (0,100),
+	// a larger value means a smoother but slower output, default = 30
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	SmoothingPercent *int64 `json:"smoothingPercent,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// MemoryPressureReliefStrategy configures the staged relief of the node memory pressure before the BE memory eviction.
+// When the node memory usage exceeds the threshold of a stage, the stages take effect in order:
+// 1. reclaim: proactively reclaim the cold pages of BE (and optionally LS) pods via `memory.reclaim` (cgroups-v2) or
+// `memory.force_empty` (cgroups-v1).
+// 2. throttle: lower the `memory.high` of the BE parent cgroup to throttle BE pods into reclaim, which is supported on
+// cgroups-v2 or the cgroups-v1 kernels providing the `memory.high` like Anolis OS.
+// When the node memory usage exceeds the MemoryEvictThresholdPercent, the BE pods are still evicted unless the usage
+// has dropped since the last stage took effect and the stage is cooling.
+type MemoryPressureReliefStrategy struct {
+	// whether the strategy is enabled, default = false
+	Enable *bool `json:"enable,omitempty"`
+	// Reclaim configures the cold page reclaim stage.
+	Reclaim *MemoryReclaimStage `json:"reclaim,omitempty"`
+	// Throttle configures the BE memory.high throttle stage.
+	Throttle *MemoryReliefStage `json:"throttle,omitempty"`
+}
+
+// MemoryReliefStage configures a stage of the memory pressure relief.
+type MemoryReliefStage struct {
+	// whether the stage is enabled, default = true
+	Enable *bool `json:"enable,omitempty"`
+	// the stage starts when node memory usage percentage exceeds the threshold (0,100),
+	// default = MemoryEvictThresholdPercent - 6 for the reclaim stage, MemoryEvictThresholdPercent - 3 for the throttle stage
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	ThresholdPercent *int64 `json:"thresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the maximal memory released by the stage in one round, in percentage of the node memory capacity, default = 5
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MaxReleasePercent *int64 `json:"maxReleasePercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the minimal interval between two rounds of the stage, default = 10
+	// +kubebuilder:validation:Minimum=0
+	CoolTimeSeconds *int64 `json:"coolTimeSeconds,omitempty" validate:"omitempty,min=0"`
+}
+
+// MemoryReclaimStage configures the cold page reclaim stage of the memory pressure relief.
+type MemoryReclaimStage struct {
+	MemoryReliefStage `json:",inline"`
+	// whether to reclaim the cold pages of LS pods besides BE pods, default = false
+	IncludeLS *bool `json:"includeLS,omitempty"`
+}
+
+// BEMemoryHighStrategy configures the dynamic memory.high of the BE pods on the cgroups-v2 nodes.
+// The memory.high of the BE parent cgroup is set to the node memory headroom above the footprint of the other pods
+// and the system, so the BE pods are throttled into reclaim instead of being killed when the node memory is tight:
+// memory.high = max(capacity * ThresholdPercent / 100 - (nodeUsed - beUsed), beRequest).
+// The BE pods are evicted after the BE memory usage has exceeded the memory.high for EvictAfterSeconds, or the node
+// memory usage has exceeded the MemoryEvictThresholdPercent for EvictAfterSeconds.
+type BEMemoryHighStrategy struct {
+	// whether the strategy is enabled, default = false
+	Enable *bool `json:"enable,omitempty"`
+	// the node memory usage percentage (0,100) which the BE pods can use up to, default = MemoryEvictThresholdPercent
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	ThresholdPercent *int64 `json:"thresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// whether to set the memory.high of each BE pod besides the BE parent cgroup, default = false
+	// A BE pod can use up the remaining memory of the BE parent cgroup, and the pods are throttled in proportion to
+	// their memory usage when the BE parent cgroup is exceeded. The memory.high of a pod is no less than its request.
+	PodLevel *bool `json:"podLevel,omitempty"`
+	// the BE pods are evicted after the BE memory usage exceeds the memory.high or the node memory usage exceeds the
+	// MemoryEvictThresholdPercent for the duration, default = 60
+	// +kubebuilder:validation:Minimum=0
+	EvictAfterSeconds *int64 `json:"evictAfterSeconds,omitempty" validate:"omitempty,min=0"`
+}
+
 type CPUEvictPolicy string
 
 const (
@@ -334,6 +444,8 @@ type ResourceThresholdStrategy struct {
 	CPUSuppressMinPercent *int64 `json:"cpuSuppressMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	// CPUSuppressPolicy
 	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty"`
+	// CPUSuppressAdaptiveStrategy configures the feedback controller when the CPUSuppressPolicy is `adaptive`.
+	CPUSuppressAdaptiveStrategy *CPUSuppressAdaptiveStrategy `json:"cpuSuppressAdaptiveStrategy,omitempty"`
 
 	// upper: memory evict threshold percentage (0,100), default = 70
 	// +kubebuilder:validation:Maximum=100
@@ -343,6 +455,10 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	MemoryEvictLowerPercent *int64 `json:"memoryEvictLowerPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=MemoryEvictThresholdPercent"`
+	// MemoryPressureRelief configures the staged relief of the memory pressure before the memory eviction.
+	MemoryPressureRelief *MemoryPressureReliefStrategy `json:"memoryPressureRelief,omitempty"`
+	// BEMemoryHigh configures the dynamic memory.high of the BE pods on the cgroups-v2 nodes.
+	BEMemoryHigh *BEMemoryHighStrategy `json:"beMemoryHigh,omitempty"`
 
 	// be.satisfactionRate = be.CPURealLimit/be.CPURequest
 	// if be.satisfactionRate > CPUEvictBESatisfactionUpperPercent/100, then stop to evict.
@@ -381,6 +497,21 @@ type ResctrlQOS struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
 	MBAPercent *int64 `json:"mbaPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the lower bound of the LLC range end when the resctrl policy is "dynamic", default = catRangeEndPercent
+	// NOTE: It takes effect if resctrlPolicy = "dynamic".
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CATRangeEndMinPercent *int64 `json:"catRangeEndMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the upper bound of the LLC range end when the resctrl policy is "dynamic", default = catRangeEndPercent
+	// NOTE: It takes effect if resctrlPolicy = "dynamic".
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CATRangeEndMaxPercent *int64 `json:"catRangeEndMaxPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the lower bound of the MBA percent when the resctrl policy is "dynamic", default = mbaPercent
+	// NOTE: It takes effect if resctrlPolicy = "dynamic".
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MBAMinPercent *int64 `json:"mbaMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
 }
 
 type CPUBurstPolicy string
@@ -394,6 +525,9 @@ const (
 	CFSQuotaBurstOnly CPUBurstPolicy = "cfsQuotaBurstOnly"
 	// CPUBurstAuto enables both
 	CPUBurstAuto CPUBurstPolicy = "auto"
+	// CPUBurstAdaptive enables cpu burst policy by setting cpu.cfs_burst_us of each container according to its recent
+	// cpu throttling and usage spikes, which is bounded by cpuBurstPercent and the node burst budget
+	CPUBurstAdaptive CPUBurstPolicy = "adaptive"
 )
 
 type CPUBurstConfig struct {
@@ -412,6 +546,10 @@ type CPUBurstStrategy struct {
 	CPUBurstConfig `json:",inline"`
 	// scale down cfs quota if node cpu overload, default = 50
 	SharePoolThresholdPercent *int64 `json:"sharePoolThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// the total cpu burst of the containers in adaptive policy, as a percentage of the node cpu cores, default = 100
+	// NOTE: It takes effect if policy = "adaptive".
+	// +kubebuilder:validation:Minimum=0
+	NodeBurstBudgetPercent *int64 `json:"nodeBurstBudgetPercent,omitempty" validate:"omitempty,min=0"`
 }
 
 type SystemStrategy struct {
@@ -93,7 +93,8 @@ func GetPodCPUQoSConfigByAttr(labels, annotations map[string]string) (*CPUQOSCfg
 const (
 	// LabelCoreSchedGroupID is the label key of the group ID of the Linux Core Scheduling.
 	// Value can be a valid UUID or empty. If it is empty, the pod is considered to belong to a core sched group "".
-	// Otherwise, the pod is set its core sched group ID according to the value.
+	// Otherwise, the pod is set its core sched group ID according to the value. A tenant can label all its trusted
+	// pods with the same group ID so that they share one cookie and never share an SMT sibling with other tenants.
 	//
 	// Core Sched: https://docs.kernel.org/admin-guide/hw-vuln/core-scheduling.html
 	// When the Core Sched is enabled, pods with the different core sched group IDs will not be running at the same SMT
@@ -112,7 +113,10 @@ const (
 	//   the value of the LabelCoreSchedGroupID.
 	// - "none": The core sched is explicitly disabled for the pod even if the node-level strategy is enabled.
 	// - "exclusive": If the core sched is enabled for the node, the pod is set the group ID according to the pod UID,
-	//   so that the pod is exclusive to any other pods.
+	//   so that the pod is exclusive to any other pods while all containers of the pod share one cookie.
+	// - "workload": If the core sched is enabled for the node, the pod is set the group ID according to its
+	//   controller workload (e.g. Deployment, StatefulSet, Job), so that all pods of the same workload share one
+	//   cookie and are exclusive to the pods of other workloads.
 	LabelCoreSchedPolicy = apiext.DomainPrefix + "core-sched-policy"
 )
 
@@ -128,6 +132,10 @@ const (
 	// CoreSchedPolicyExclusive is the exclusive policy of the core scheduling which indicates the core sched group ID
 	// is set the same as the pod's UID
 	CoreSchedPolicyExclusive CoreSchedPolicy = "exclusive"
+	// CoreSchedPolicyWorkload is the workload policy of the core scheduling which indicates the core sched group ID
+	// is set according to the controller workload of the pod. If the pod has no controller, it falls back to the
+	// exclusive policy.
+	CoreSchedPolicyWorkload CoreSchedPolicy = "workload"
 )
 
 // GetCoreSchedGroupID gets the core sched group ID for the pod according to the labels.
@@ -147,6 +155,8 @@ func GetCoreSchedPolicy(labels map[string]string) CoreSchedPolicy {
 		return CoreSchedPolicyNone
 	} else if v == string(CoreSchedPolicyExclusive) {
 		return CoreSchedPolicyExclusive
+	} else if v == string(CoreSchedPolicyWorkload) {
+		return CoreSchedPolicyWorkload
 	}
 	return CoreSchedPolicyDefault
 }
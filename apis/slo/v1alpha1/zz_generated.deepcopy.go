@@ -72,6 +72,41 @@ func (in *AggregatedUsage) DeepCopy() *AggregatedUsage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BEMemoryHighStrategy) DeepCopyInto(out *BEMemoryHighStrategy) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ThresholdPercent != nil {
+		in, out := &in.ThresholdPercent, &out.ThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodLevel != nil {
+		in, out := &in.PodLevel, &out.PodLevel
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EvictAfterSeconds != nil {
+		in, out := &in.EvictAfterSeconds, &out.EvictAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BEMemoryHighStrategy.
+func (in *BEMemoryHighStrategy) DeepCopy() *BEMemoryHighStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(BEMemoryHighStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlkIOQOS) DeepCopyInto(out *BlkIOQOS) {
 	*out = *in
@@ -174,6 +209,11 @@ func (in *CPUBurstStrategy) DeepCopyInto(out *CPUBurstStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.NodeBurstBudgetPercent != nil {
+		in, out := &in.NodeBurstBudgetPercent, &out.NodeBurstBudgetPercent
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUBurstStrategy.
@@ -237,6 +277,51 @@ func (in *CPUQOSCfg) DeepCopy() *CPUQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUSuppressAdaptiveStrategy) DeepCopyInto(out *CPUSuppressAdaptiveStrategy) {
+	*out = *in
+	if in.ProportionalGainPercent != nil {
+		in, out := &in.ProportionalGainPercent, &out.ProportionalGainPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntegralGainPercent != nil {
+		in, out := &in.IntegralGainPercent, &out.IntegralGainPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IntegralLimitPercent != nil {
+		in, out := &in.IntegralLimitPercent, &out.IntegralLimitPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinStepPercent != nil {
+		in, out := &in.MinStepPercent, &out.MinStepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxStepPercent != nil {
+		in, out := &in.MaxStepPercent, &out.MaxStepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SmoothingPercent != nil {
+		in, out := &in.SmoothingPercent, &out.SmoothingPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUSuppressAdaptiveStrategy.
+func (in *CPUSuppressAdaptiveStrategy) DeepCopy() *CPUSuppressAdaptiveStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUSuppressAdaptiveStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CgroupPath) DeepCopyInto(out *CgroupPath) {
 	*out = *in
@@ -403,6 +488,36 @@ func (in *IOCfg) DeepCopy() *IOCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPressureReliefStrategy) DeepCopyInto(out *MemoryPressureReliefStrategy) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Reclaim != nil {
+		in, out := &in.Reclaim, &out.Reclaim
+		*out = new(MemoryReclaimStage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Throttle != nil {
+		in, out := &in.Throttle, &out.Throttle
+		*out = new(MemoryReliefStage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPressureReliefStrategy.
+func (in *MemoryPressureReliefStrategy) DeepCopy() *MemoryPressureReliefStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPressureReliefStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 	*out = *in
@@ -484,6 +599,62 @@ func (in *MemoryQOSCfg) DeepCopy() *MemoryQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryReclaimStage) DeepCopyInto(out *MemoryReclaimStage) {
+	*out = *in
+	in.MemoryReliefStage.DeepCopyInto(&out.MemoryReliefStage)
+	if in.IncludeLS != nil {
+		in, out := &in.IncludeLS, &out.IncludeLS
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryReclaimStage.
+func (in *MemoryReclaimStage) DeepCopy() *MemoryReclaimStage {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryReclaimStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryReliefStage) DeepCopyInto(out *MemoryReliefStage) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ThresholdPercent != nil {
+		in, out := &in.ThresholdPercent, &out.ThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxReleasePercent != nil {
+		in, out := &in.MaxReleasePercent, &out.MaxReleasePercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CoolTimeSeconds != nil {
+		in, out := &in.CoolTimeSeconds, &out.CoolTimeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryReliefStage.
+func (in *MemoryReliefStage) DeepCopy() *MemoryReliefStage {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryReliefStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkQOS) DeepCopyInto(out *NetworkQOS) {
 	*out = *in
@@ -724,6 +895,11 @@ func (in *NodeMetricStatus) DeepCopyInto(out *NodeMetricStatus) {
 		*out = new(ReclaimableMetric)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ProdSeasonalReclaimableMetric != nil {
+		in, out := &in.ProdSeasonalReclaimableMetric, &out.ProdSeasonalReclaimableMetric
+		*out = new(ReclaimableMetric)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricStatus.
@@ -948,6 +1124,21 @@ func (in *ResctrlQOS) DeepCopyInto(out *ResctrlQOS) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CATRangeEndMinPercent != nil {
+		in, out := &in.CATRangeEndMinPercent, &out.CATRangeEndMinPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CATRangeEndMaxPercent != nil {
+		in, out := &in.CATRangeEndMaxPercent, &out.CATRangeEndMaxPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MBAMinPercent != nil {
+		in, out := &in.MBAMinPercent, &out.MBAMinPercent
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlQOS.
@@ -1063,6 +1254,11 @@ func (in *ResourceQOSPolicies) DeepCopyInto(out *ResourceQOSPolicies) {
 		*out = new(NETQOSPolicy)
 		**out = **in
 	}
+	if in.ResctrlPolicy != nil {
+		in, out := &in.ResctrlPolicy, &out.ResctrlPolicy
+		*out = new(ResctrlQOSPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQOSPolicies.
@@ -1138,6 +1334,11 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CPUSuppressAdaptiveStrategy != nil {
+		in, out := &in.CPUSuppressAdaptiveStrategy, &out.CPUSuppressAdaptiveStrategy
+		*out = new(CPUSuppressAdaptiveStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MemoryEvictThresholdPercent != nil {
 		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
 		*out = new(int64)
@@ -1148,6 +1349,16 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MemoryPressureRelief != nil {
+		in, out := &in.MemoryPressureRelief, &out.MemoryPressureRelief
+		*out = new(MemoryPressureReliefStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BEMemoryHigh != nil {
+		in, out := &in.BEMemoryHigh, &out.BEMemoryHigh
+		*out = new(BEMemoryHighStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CPUEvictBESatisfactionUpperPercent != nil {
 		in, out := &in.CPUEvictBESatisfactionUpperPercent, &out.CPUEvictBESatisfactionUpperPercent
 		*out = new(int64)
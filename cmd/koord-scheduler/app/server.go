@@ -441,6 +441,7 @@ func Setup(ctx context.Context, opts *options.Options, outOfTreeRegistryOptions
 	schedAdapter := frameworkExtenderFactory.Scheduler()
 
 	eventhandlers.AddScheduleEventHandler(sched, schedAdapter, frameworkExtenderFactory.KoordinatorSharedInformerFactory())
+	eventhandlers.AddDiagnosisEventHandler(cc.InformerFactory, frameworkExtenderFactory.DiagnosisStore())
 	reservationErrorHandler := eventhandlers.MakeReservationErrorHandler(
 		sched,
 		schedAdapter,
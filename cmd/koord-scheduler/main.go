@@ -32,6 +32,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/loadaware"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource"
 	noderesourcesfitplus "github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/noderesourcefitplus"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/reservation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/scarceresourceavoidance"
 
@@ -51,6 +52,7 @@ var koordinatorPlugins = map[string]frameworkruntime.PluginFactory{
 	defaultprebind.Name:          defaultprebind.New,
 	noderesourcesfitplus.Name:    noderesourcesfitplus.New,
 	scarceresourceavoidance.Name: scarceresourceavoidance.New,
+	resctrl.Name:                 resctrl.New,
 }
 
 func flatten(plugins map[string]frameworkruntime.PluginFactory) []app.Option {
@@ -30,9 +30,12 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
@@ -45,24 +48,36 @@ var (
 	ExpirePodCacheDuration = 10 * time.Minute
 	MaxUpdatePodQPS        = 5.0
 	MaxUpdatePodQPSBurst   = 10
+	MaxDryRunResults       = 100
 )
 
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=clustercolocationprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.koordinator.sh,resources=clustercolocationprofiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=reservations,verbs=get;list;watch;patch
+
 type Reconciler struct {
 	client.Client
 	Recorder record.EventRecorder
 	Scheme   *runtime.Scheme
 
-	rateLimiter    *rate.Limiter
-	podUpdateCache gocache.Cache
+	rateLimiter            *rate.Limiter
+	podUpdateCache         gocache.Cache
+	reservationUpdateCache gocache.Cache
+	// skipCache keeps the probability decisions of the objects, so that a skipped object is not rolled again
+	// in every reconciliation until the decision expires.
+	skipCache gocache.Cache
 }
 
 func newReconciler(mgr ctrl.Manager) *Reconciler {
 	return &Reconciler{
-		Client:         mgr.GetClient(),
-		Recorder:       mgr.GetEventRecorderFor(Name),
-		Scheme:         mgr.GetScheme(),
-		rateLimiter:    rate.NewLimiter(rate.Limit(MaxUpdatePodQPS), MaxUpdatePodQPSBurst),
-		podUpdateCache: *gocache.New(ForceUpdatePodDuration, ExpirePodCacheDuration),
+		Client:                 mgr.GetClient(),
+		Recorder:               mgr.GetEventRecorderFor(Name),
+		Scheme:                 mgr.GetScheme(),
+		rateLimiter:            rate.NewLimiter(rate.Limit(MaxUpdatePodQPS), MaxUpdatePodQPSBurst),
+		podUpdateCache:         *gocache.New(ForceUpdatePodDuration, ExpirePodCacheDuration),
+		reservationUpdateCache: *gocache.New(ForceUpdatePodDuration, ExpirePodCacheDuration),
+		skipCache:              *gocache.New(ExpirePodCacheDuration, ExpirePodCacheDuration),
 	}
 }
 
@@ -81,28 +96,78 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	summary := newSummary(profile.Name)
+	if err := validateProfile(profile); err != nil {
+		klog.ErrorS(err, "invalid ClusterColocationProfile", "profile", profile.Name)
+		if err = r.updateProfileStatus(ctx, profile, summary, err); err != nil {
+			klog.ErrorS(err, "failed to update status for ClusterColocationProfile", "profile", profile.Name)
+			return ctrl.Result{Requeue: true}, err
+		}
+		return ctrl.Result{RequeueAfter: ReconcileInterval}, nil
+	}
+
 	podList, err := r.listPodsForProfile(profile)
 	if err != nil {
 		klog.ErrorS(err, "failed to list pods for ClusterColocationProfile", "profile", profile.Name)
 		return ctrl.Result{Requeue: true}, err
 	}
 	if podList == nil || len(podList.Items) <= 0 {
-		klog.V(6).InfoS("list no pod for ClusterColocationProfile, retry later", "profile", profile.Name)
-		return ctrl.Result{RequeueAfter: ReconcileInterval}, nil
+		klog.V(6).InfoS("list no pod for ClusterColocationProfile", "profile", profile.Name)
+	} else {
+		klog.V(5).InfoS("list pods for ClusterColocationProfile", "profile", profile.Name, "pods", len(podList.Items))
+		if err = r.reconcilePods(ctx, profile, podList, summary); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
 	}
-	klog.V(5).InfoS("list pods for ClusterColocationProfile", "profile", profile.Name, "pods", len(podList.Items))
 
-	summary := newSummary(profile.Name)
+	reservations, err := r.listReservationsForProfile(profile)
+	if err != nil {
+		klog.ErrorS(err, "failed to list reservations for ClusterColocationProfile", "profile", profile.Name)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if len(reservations) > 0 {
+		klog.V(5).InfoS("list reservations for ClusterColocationProfile", "profile", profile.Name, "reservations", len(reservations))
+		if err = r.reconcileReservations(ctx, profile, reservations, summary); err != nil {
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+	klog.V(4).InfoS("successfully update pods and reservations for clusterColocationProfile",
+		"profile", profile.Name, "allSucceeded", summary.IsAllSucceeded(), "summary", summary)
+
+	if err = r.updateProfileStatus(ctx, profile, summary, nil); err != nil {
+		klog.ErrorS(err, "failed to update status for ClusterColocationProfile", "profile", profile.Name)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{RequeueAfter: ReconcileInterval}, nil
+}
+
+func (r *Reconciler) reconcilePods(ctx context.Context, profile *configv1alpha1.ClusterColocationProfile, podList *corev1.PodList, summary *ReconcileSummary) error {
 	for i := range podList.Items {
 		pod := &podList.Items[i]
 		// NOTE: Only handle pending and unscheduled pods.
 		if pod.Spec.NodeName != "" || pod.Status.Phase != corev1.PodPending {
 			continue
 		}
-		skip, err := shouldSkipProfile(profile)
+		summary.Matched++
+		if profile.Spec.DryRun {
+			patch, err := r.dryRunPodByClusterColocationProfile(profile, pod)
+			if err != nil {
+				summary.Failed++
+				klog.ErrorS(err, "failed to dry-run pod for clusterColocationProfile", "profile", profile.Name, "pod", klog.KObj(pod))
+				continue
+			}
+			summary.Desired++
+			summary.Succeeded++
+			if patch != "" {
+				summary.Changed++
+				summary.addDryRunResult("Pod", pod.Namespace, pod.Name, patch)
+			}
+			continue
+		}
+		skip, err := r.shouldSkipObject(profile, getPodUpdateKey(profile, pod))
 		if err != nil {
 			klog.ErrorS(err, "failed to check skip profile for pod", "profile", profile.Name, "pod", klog.KObj(pod))
-			return ctrl.Result{Requeue: true}, err
+			return err
 		}
 		if skip {
 			summary.Skipped++
@@ -122,8 +187,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				"profile", profile.Name, "pod", klog.KObj(pod), "qps", r.rateLimiter.Limit(), "burst", r.rateLimiter.Burst())
 			continue
 		}
-		isUpdated, err := r.updatePodByClusterColocationProfile(context.TODO(), profile, pod)
+		isUpdated, err := r.updatePodByClusterColocationProfile(ctx, profile, pod)
 		if err != nil {
+			summary.Failed++
 			klog.ErrorS(err, "failed to patch pod for clusterColocationProfile", "profile", profile.Name, "pod", klog.KObj(pod))
 			continue
 		}
@@ -133,17 +199,64 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			summary.Changed++
 		}
 	}
-	klog.V(4).InfoS("successfully update pods for clusterColocationProfile",
-		"profile", profile.Name, "allSucceeded", summary.IsAllSucceeded(), "summary", summary)
+	return nil
+}
 
-	// TODO: handle reservations
-	return ctrl.Result{RequeueAfter: ReconcileInterval}, nil
+// reconcileReservations applies the profile to the templates of the pending reservations, so that the reserved
+// resources are allocated with the same QoS and priority as the pods mutated by the profile.
+func (r *Reconciler) reconcileReservations(ctx context.Context, profile *configv1alpha1.ClusterColocationProfile, reservations []*schedulingv1alpha1.Reservation, summary *ReconcileSummary) error {
+	for _, reservation := range reservations {
+		summary.MatchedReservations++
+		if profile.Spec.DryRun {
+			patch, err := r.dryRunReservationByClusterColocationProfile(profile, reservation)
+			if err != nil {
+				summary.FailedReservations++
+				klog.ErrorS(err, "failed to dry-run reservation for clusterColocationProfile", "profile", profile.Name, "reservation", klog.KObj(reservation))
+				continue
+			}
+			if patch != "" {
+				summary.ChangedReservations++
+				summary.addDryRunResult("Reservation", "", reservation.Name, patch)
+			}
+			continue
+		}
+		skip, err := r.shouldSkipObject(profile, getReservationUpdateKey(profile, reservation))
+		if err != nil {
+			klog.ErrorS(err, "failed to check skip profile for reservation", "profile", profile.Name, "reservation", klog.KObj(reservation))
+			return err
+		}
+		if skip {
+			klog.V(5).InfoS("skip update Reservation by clusterColocationProfile", "profile", profile.Name, "reservation", klog.KObj(reservation))
+			continue
+		}
+		if _, exists := r.reservationUpdateCache.Get(getReservationUpdateKey(profile, reservation)); exists {
+			klog.V(5).InfoS("skip update Reservation by clusterColocationProfile, already updated", "profile", profile.Name, "reservation", klog.KObj(reservation))
+			continue
+		}
+		if !r.rateLimiter.Allow() {
+			summary.RateLimited++
+			klog.V(4).InfoS("abort update Reservation by clusterColocationProfile, rate limiter is not allowed",
+				"profile", profile.Name, "reservation", klog.KObj(reservation))
+			continue
+		}
+		isUpdated, err := r.updateReservationByClusterColocationProfile(ctx, profile, reservation)
+		if err != nil {
+			summary.FailedReservations++
+			klog.ErrorS(err, "failed to patch reservation for clusterColocationProfile", "profile", profile.Name, "reservation", klog.KObj(reservation))
+			continue
+		}
+		if isUpdated {
+			r.reservationUpdateCache.SetDefault(getReservationUpdateKey(profile, reservation), struct{}{})
+			summary.ChangedReservations++
+		}
+	}
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&configv1alpha1.ClusterColocationProfile{}).
+		For(&configv1alpha1.ClusterColocationProfile{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named(Name).
 		Complete(r)
 }
@@ -154,6 +267,7 @@ func InitFlags(fs *flag.FlagSet) {
 	pflag.DurationVar(&ExpirePodCacheDuration, "colocation-profile-expire-pod-cache-duration", ExpirePodCacheDuration, "The duration for colocation-profile controller to expire pod cache.")
 	pflag.Float64Var(&MaxUpdatePodQPS, "colocation-profile-update-pod-qps", MaxUpdatePodQPS, "The QPS for colocation-profile controller to update pods.")
 	pflag.IntVar(&MaxUpdatePodQPSBurst, "colocation-profile-update-pod-qps-burst", MaxUpdatePodQPSBurst, "The QPS burst for colocation-profile controller to update pods.")
+	pflag.IntVar(&MaxDryRunResults, "colocation-profile-max-dry-run-results", MaxDryRunResults, "The max number of the dry-run results kept in the status of a ClusterColocationProfile.")
 }
 
 func Add(mgr ctrl.Manager) error {
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
@@ -288,13 +289,16 @@ func TestReconciler_Reconcile(t *testing.T) {
 		r := &Reconciler{
 			Client: fake.NewClientBuilder().WithScheme(scheme).
 				WithObjects(testPod, testUnmatchedPod, testUnmatchedPod2, testProfile).
+				WithStatusSubresource(&configv1alpha1.ClusterColocationProfile{}).
 				WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
 					return []string{obj.(*corev1.Pod).Spec.NodeName}
 				}).
 				Build(),
-			Scheme:         scheme,
-			rateLimiter:    rate.NewLimiter(rate.Limit(1), 5),
-			podUpdateCache: *gocache.New(2*ReconcileInterval, 5*time.Minute),
+			Scheme:                 scheme,
+			rateLimiter:            rate.NewLimiter(rate.Limit(1), 5),
+			podUpdateCache:         *gocache.New(2*ReconcileInterval, 5*time.Minute),
+			reservationUpdateCache: *gocache.New(2*ReconcileInterval, 5*time.Minute),
+			skipCache:              *gocache.New(5*time.Minute, 5*time.Minute),
 		}
 
 		originalFn := randIntnFn
@@ -491,3 +495,185 @@ func getTestScheme() *runtime.Scheme {
 	_ = configv1alpha1.AddToScheme(s)
 	return s
 }
+
+func TestReconciler_ReconcileDryRunAndReservations(t *testing.T) {
+	scheme := getTestScheme()
+	testProfile := &configv1alpha1.ClusterColocationProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-profile",
+			Generation: 1,
+		},
+		Spec: configv1alpha1.ClusterColocationProfileSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"koordinator-colocation-pod": "true",
+				},
+			},
+			QoSClass: string(extension.QoSBE),
+			DryRun:   true,
+		},
+	}
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+			UID:       "xxx",
+			Labels: map[string]string{
+				"koordinator-colocation-pod": "true",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+	testReservation := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-reservation",
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"koordinator-colocation-pod": "true",
+					},
+				},
+			},
+		},
+	}
+	testAvailableReservation := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-available-reservation",
+		},
+		Spec: *testReservation.Spec.DeepCopy(),
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase:    schedulingv1alpha1.ReservationAvailable,
+			NodeName: "test-node",
+		},
+	}
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(testPod, testProfile, testReservation, testAvailableReservation).
+			WithStatusSubresource(&configv1alpha1.ClusterColocationProfile{}).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+				return []string{obj.(*corev1.Pod).Spec.NodeName}
+			}).
+			Build(),
+		Scheme:                 scheme,
+		rateLimiter:            rate.NewLimiter(rate.Limit(1), 5),
+		podUpdateCache:         *gocache.New(2*ReconcileInterval, 5*time.Minute),
+		reservationUpdateCache: *gocache.New(2*ReconcileInterval, 5*time.Minute),
+		skipCache:              *gocache.New(5*time.Minute, 5*time.Minute),
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: testProfile.Name}}
+
+	// dry-run reports the mutations without patching
+	result, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: ReconcileInterval}, result)
+	gotPod := &corev1.Pod{}
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testPod), gotPod))
+	assert.Empty(t, gotPod.Labels[extension.LabelPodQoS])
+	gotReservation := &schedulingv1alpha1.Reservation{}
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testReservation), gotReservation))
+	assert.Empty(t, gotReservation.Spec.Template.Labels[extension.LabelPodQoS])
+
+	gotProfile := &configv1alpha1.ClusterColocationProfile{}
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testProfile), gotProfile))
+	assert.Equal(t, int64(1), gotProfile.Status.ObservedGeneration)
+	assert.Equal(t, &configv1alpha1.ColocationProfileSummary{
+		MatchedPods:         1,
+		DesiredPods:         1,
+		ChangedPods:         1,
+		MatchedReservations: 1,
+		ChangedReservations: 1,
+	}, gotProfile.Status.Summary)
+	assert.Equal(t, []configv1alpha1.ColocationProfileDryRunResult{
+		{Kind: "Pod", Namespace: "default", Name: "test-pod", Patch: `{"metadata":{"labels":{"koordinator.sh/qosClass":"BE"}}}`},
+		{Kind: "Reservation", Name: "test-reservation", Patch: `{"spec":{"template":{"metadata":{"labels":{"koordinator.sh/qosClass":"BE"}}}}}`},
+	}, gotProfile.Status.DryRunResults)
+	assert.Len(t, gotProfile.Status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, gotProfile.Status.Conditions[0].Status)
+	assert.Equal(t, configv1alpha1.ColocationProfileReasonDryRun, gotProfile.Status.Conditions[0].Reason)
+
+	// disable dry-run to patch the pod and the pending reservation
+	newProfile := gotProfile.DeepCopy()
+	newProfile.Spec.DryRun = false
+	newProfile.Generation = 2
+	assert.NoError(t, r.Client.Update(context.TODO(), newProfile))
+	result, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: ReconcileInterval}, result)
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testPod), gotPod))
+	assert.Equal(t, string(extension.QoSBE), gotPod.Labels[extension.LabelPodQoS])
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testReservation), gotReservation))
+	assert.Equal(t, string(extension.QoSBE), gotReservation.Spec.Template.Labels[extension.LabelPodQoS])
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testAvailableReservation), gotReservation))
+	assert.Empty(t, gotReservation.Spec.Template.Labels[extension.LabelPodQoS])
+
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testProfile), gotProfile))
+	assert.Equal(t, int64(2), gotProfile.Status.ObservedGeneration)
+	assert.Equal(t, &configv1alpha1.ColocationProfileSummary{
+		MatchedPods:         1,
+		DesiredPods:         1,
+		ChangedPods:         1,
+		MatchedReservations: 1,
+		ChangedReservations: 1,
+	}, gotProfile.Status.Summary)
+	assert.Nil(t, gotProfile.Status.DryRunResults)
+	assert.Equal(t, metav1.ConditionTrue, gotProfile.Status.Conditions[0].Status)
+	assert.Equal(t, configv1alpha1.ColocationProfileReasonSynced, gotProfile.Status.Conditions[0].Reason)
+
+	// the recently updated reservation is not patched again
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testReservation), gotReservation))
+	revertedReservation := gotReservation.DeepCopy()
+	delete(revertedReservation.Spec.Template.Labels, extension.LabelPodQoS)
+	assert.NoError(t, r.Client.Update(context.TODO(), revertedReservation))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testReservation), gotReservation))
+	assert.Empty(t, gotReservation.Spec.Template.Labels[extension.LabelPodQoS])
+
+	// invalid probability
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testProfile), gotProfile))
+	newProfile = gotProfile.DeepCopy()
+	newProfile.Spec.Probability = &intstr.IntOrString{Type: intstr.String, StrVal: "abc"}
+	assert.NoError(t, r.Client.Update(context.TODO(), newProfile))
+	result, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: ReconcileInterval}, result)
+	assert.NoError(t, r.Client.Get(context.TODO(), client.ObjectKeyFromObject(testProfile), gotProfile))
+	assert.Nil(t, gotProfile.Status.Summary)
+	assert.Equal(t, configv1alpha1.ColocationProfileReasonInvalidConfig, gotProfile.Status.Conditions[0].Reason)
+}
+
+func TestReconciler_shouldSkipObject(t *testing.T) {
+	r := &Reconciler{
+		skipCache: *gocache.New(5*time.Minute, 5*time.Minute),
+	}
+	profile := &configv1alpha1.ClusterColocationProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-profile",
+			Generation: 1,
+		},
+		Spec: configv1alpha1.ClusterColocationProfileSpec{
+			Probability: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+		},
+	}
+	originalFn := randIntnFn
+	defer func() {
+		randIntnFn = originalFn
+	}()
+
+	randIntnFn = func(i int) int { return 99 }
+	skip, err := r.shouldSkipObject(profile, "1/xxx")
+	assert.NoError(t, err)
+	assert.True(t, skip)
+	// the decision is not rolled again
+	randIntnFn = func(i int) int { return 0 }
+	skip, err = r.shouldSkipObject(profile, "1/xxx")
+	assert.NoError(t, err)
+	assert.True(t, skip)
+	skip, err = r.shouldSkipObject(profile, "1/yyy")
+	assert.NoError(t, err)
+	assert.False(t, skip)
+}
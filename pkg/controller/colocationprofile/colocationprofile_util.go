@@ -26,8 +26,10 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/klog/v2"
@@ -35,6 +37,7 @@ import (
 
 	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
 )
@@ -68,6 +71,47 @@ func (r *Reconciler) listPodsForProfile(profile *configv1alpha1.ClusterColocatio
 	return podList, nil
 }
 
+func (r *Reconciler) listReservationsForProfile(profile *configv1alpha1.ClusterColocationProfile) ([]*schedulingv1alpha1.Reservation, error) {
+	if profile.Spec.Selector == nil { // match nothing
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(profile.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate selector %+v, err: %w", profile.Spec.Selector, err)
+	}
+
+	reservationList := &schedulingv1alpha1.ReservationList{}
+	if err = r.Client.List(context.TODO(), reservationList, utilclient.DisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("list reservations failed, err: %w", err)
+	}
+
+	var reservations []*schedulingv1alpha1.Reservation
+	for i := range reservationList.Items {
+		reservation := &reservationList.Items[i]
+		// NOTE: Only handle the reservations not scheduled yet, and match the selector with the reserved pod template.
+		if reservation.Spec.Template == nil || reservation.Status.NodeName != "" ||
+			(reservation.Status.Phase != "" && reservation.Status.Phase != schedulingv1alpha1.ReservationPending) {
+			continue
+		}
+		if selector.Matches(labels.Set(reservation.Spec.Template.Labels)) {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *Reconciler) dryRunPodByClusterColocationProfile(profile *configv1alpha1.ClusterColocationProfile, pod *corev1.Pod) (string, error) {
+	modifiedPod := pod.DeepCopy()
+	err := r.doMutateByColocationProfile(modifiedPod, profile)
+	if err != nil {
+		return "", fmt.Errorf("failed to mutate pod, err: %w", err)
+	}
+	if reflect.DeepEqual(pod, modifiedPod) {
+		return "", nil
+	}
+	return getMergePatch(pod, modifiedPod)
+}
+
 func (r *Reconciler) updatePodByClusterColocationProfile(ctx context.Context, profile *configv1alpha1.ClusterColocationProfile, pod *corev1.Pod) (bool, error) {
 	modifiedPod := pod.DeepCopy()
 	err := r.doMutateByColocationProfile(modifiedPod, profile)
@@ -95,6 +139,52 @@ func (r *Reconciler) updatePodByClusterColocationProfile(ctx context.Context, pr
 	return true, nil
 }
 
+func (r *Reconciler) mutateReservationByClusterColocationProfile(profile *configv1alpha1.ClusterColocationProfile, reservation *schedulingv1alpha1.Reservation) (*schedulingv1alpha1.Reservation, error) {
+	modifiedReservation := reservation.DeepCopy()
+	template := modifiedReservation.Spec.Template
+	pod := &corev1.Pod{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	if err := r.doMutateByColocationProfile(pod, profile); err != nil {
+		return nil, fmt.Errorf("failed to mutate reservation template, err: %w", err)
+	}
+	template.ObjectMeta = pod.ObjectMeta
+	template.Spec = pod.Spec
+	return modifiedReservation, nil
+}
+
+func (r *Reconciler) dryRunReservationByClusterColocationProfile(profile *configv1alpha1.ClusterColocationProfile, reservation *schedulingv1alpha1.Reservation) (string, error) {
+	modifiedReservation, err := r.mutateReservationByClusterColocationProfile(profile, reservation)
+	if err != nil {
+		return "", err
+	}
+	if reflect.DeepEqual(reservation, modifiedReservation) {
+		return "", nil
+	}
+	return getMergePatch(reservation, modifiedReservation)
+}
+
+func (r *Reconciler) updateReservationByClusterColocationProfile(ctx context.Context, profile *configv1alpha1.ClusterColocationProfile, reservation *schedulingv1alpha1.Reservation) (bool, error) {
+	modifiedReservation, err := r.mutateReservationByClusterColocationProfile(profile, reservation)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(reservation, modifiedReservation) {
+		return false, nil
+	}
+
+	err = util.RetryOnConflictOrTooManyRequests(func() error {
+		return r.Client.Patch(ctx, modifiedReservation, client.MergeFrom(reservation))
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to patch reservation, err: %w", err)
+	}
+
+	klog.V(4).InfoS("successfully patch reservation for clusterColocationProfile", "profile", profile.Name, "reservation", klog.KObj(reservation))
+	return true, nil
+}
+
 func (r *Reconciler) doMutateByColocationProfile(pod *corev1.Pod, profile *configv1alpha1.ClusterColocationProfile) error {
 	if len(profile.Spec.Labels) > 0 {
 		if pod.Labels == nil {
@@ -166,14 +256,20 @@ func (r *Reconciler) doMutateByColocationProfile(pod *corev1.Pod, profile *confi
 }
 
 type ReconcileSummary struct {
-	Time        string
-	Profile     string
-	Desired     int
-	Succeeded   int
-	Changed     int
-	RateLimited int
-	Skipped     int
-	Cached      int
+	Time                string
+	Profile             string
+	Matched             int
+	Desired             int
+	Succeeded           int
+	Changed             int
+	Failed              int
+	RateLimited         int
+	Skipped             int
+	Cached              int
+	MatchedReservations int
+	ChangedReservations int
+	FailedReservations  int
+	DryRunResults       []configv1alpha1.ColocationProfileDryRunResult
 }
 
 func newSummary(profileName string) *ReconcileSummary {
@@ -184,7 +280,97 @@ func newSummary(profileName string) *ReconcileSummary {
 }
 
 func (s *ReconcileSummary) IsAllSucceeded() bool {
-	return s.Succeeded >= s.Desired
+	return s.Succeeded >= s.Desired && s.FailedReservations <= 0
+}
+
+func (s *ReconcileSummary) addDryRunResult(kind, namespace, name, patch string) {
+	if len(s.DryRunResults) >= MaxDryRunResults {
+		return
+	}
+	s.DryRunResults = append(s.DryRunResults, configv1alpha1.ColocationProfileDryRunResult{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Patch:     patch,
+	})
+}
+
+func (s *ReconcileSummary) toStatusSummary() *configv1alpha1.ColocationProfileSummary {
+	return &configv1alpha1.ColocationProfileSummary{
+		MatchedPods:         int32(s.Matched),
+		DesiredPods:         int32(s.Desired),
+		ChangedPods:         int32(s.Changed),
+		FailedPods:          int32(s.Failed),
+		RateLimitedPods:     int32(s.RateLimited),
+		SkippedPods:         int32(s.Skipped),
+		CachedPods:          int32(s.Cached),
+		MatchedReservations: int32(s.MatchedReservations),
+		ChangedReservations: int32(s.ChangedReservations),
+		FailedReservations:  int32(s.FailedReservations),
+	}
+}
+
+func (r *Reconciler) updateProfileStatus(ctx context.Context, profile *configv1alpha1.ClusterColocationProfile, summary *ReconcileSummary, invalidErr error) error {
+	newProfile := profile.DeepCopy()
+	newStatus := &newProfile.Status
+	newStatus.ObservedGeneration = profile.Generation
+	newStatus.Summary = summary.toStatusSummary()
+	newStatus.DryRunResults = summary.DryRunResults
+	condition := metav1.Condition{
+		Type:               configv1alpha1.ColocationProfileConditionSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: profile.Generation,
+	}
+	switch {
+	case invalidErr != nil:
+		newStatus.Summary = nil
+		condition.Reason = configv1alpha1.ColocationProfileReasonInvalidConfig
+		condition.Message = invalidErr.Error()
+	case profile.Spec.DryRun:
+		condition.Reason = configv1alpha1.ColocationProfileReasonDryRun
+		condition.Message = fmt.Sprintf("%d pods and %d reservations would be mutated", summary.Changed, summary.ChangedReservations)
+	case summary.Failed > 0 || summary.FailedReservations > 0:
+		condition.Reason = configv1alpha1.ColocationProfileReasonMutateFailed
+		condition.Message = fmt.Sprintf("failed to mutate %d pods and %d reservations", summary.Failed, summary.FailedReservations)
+	case summary.RateLimited > 0:
+		condition.Reason = configv1alpha1.ColocationProfileReasonRateLimited
+		condition.Message = fmt.Sprintf("%d objects are waiting for the rate limiter", summary.RateLimited)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = configv1alpha1.ColocationProfileReasonSynced
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, condition)
+	if reflect.DeepEqual(profile.Status, newProfile.Status) {
+		return nil
+	}
+
+	if err := r.Client.Status().Update(ctx, newProfile); err != nil {
+		return fmt.Errorf("failed to update status, err: %w", err)
+	}
+	klog.V(5).InfoS("successfully update status for clusterColocationProfile", "profile", profile.Name, "status", util.DumpJSON(newProfile.Status))
+	return nil
+}
+
+func validateProfile(profile *configv1alpha1.ClusterColocationProfile) error {
+	if profile.Spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(profile.Spec.Selector); err != nil {
+			return fmt.Errorf("invalid selector, err: %w", err)
+		}
+	}
+	if profile.Spec.Probability != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(profile.Spec.Probability, 100, false); err != nil {
+			return fmt.Errorf("invalid probability, err: %w", err)
+		}
+	}
+	return nil
+}
+
+func getMergePatch(original, modified client.Object) (string, error) {
+	patch, err := client.MergeFrom(original).Data(modified)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate patch, err: %w", err)
+	}
+	return string(patch), nil
 }
 
 func shouldSkipProfile(profile *configv1alpha1.ClusterColocationProfile) (bool, error) {
@@ -199,6 +385,24 @@ func shouldSkipProfile(profile *configv1alpha1.ClusterColocationProfile) (bool,
 	return percent == 0 || (percent != 100 && randIntnFn(100) > percent), nil
 }
 
+// shouldSkipObject rolls the probability of the profile once for the object identified by the key.
+func (r *Reconciler) shouldSkipObject(profile *configv1alpha1.ClusterColocationProfile, key string) (bool, error) {
+	if skip, ok := r.skipCache.Get(key); ok {
+		return skip.(bool), nil
+	}
+	skip, err := shouldSkipProfile(profile)
+	if err != nil {
+		return false, err
+	}
+	r.skipCache.SetDefault(key, skip)
+	return skip, nil
+}
+
 func getPodUpdateKey(profile *configv1alpha1.ClusterColocationProfile, pod *corev1.Pod) string {
-	return profile.ResourceVersion + "/" + string(pod.UID)
+	// NOTE: Use the generation since the status updates of the profile should not expire the cached updates.
+	return strconv.FormatInt(profile.Generation, 10) + "/" + string(pod.UID)
+}
+
+func getReservationUpdateKey(profile *configv1alpha1.ClusterColocationProfile, reservation *schedulingv1alpha1.Reservation) string {
+	return strconv.FormatInt(profile.Generation, 10) + "/" + string(reservation.UID)
 }
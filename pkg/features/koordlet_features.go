@@ -160,6 +160,13 @@ const (
 	// PodResourcesProxy enabled hooked podResources of kubelet provided by koordlet.
 	// It provides a grpc service to enable discovery of pod resources allocated by koordinator system.
 	PodResourcesProxy featuregate.Feature = "PodResourcesProxy"
+
+	// alpha: v1.6
+	//
+	// CPUSetMemoryMigrate migrates the existing memory pages of the NUMA-aligned containers to the allocated NUMA nodes
+	// when their cpuset.mems are updated. It takes effect on the cgroups v1 by enabling the cpuset.memory_migrate,
+	// while the kernel always migrates the pages on the cgroups v2.
+	CPUSetMemoryMigrate featuregate.Feature = "CPUSetMemoryMigrate"
 )
 
 func init() {
@@ -192,6 +199,7 @@ var (
 		ColdPageCollector:      {Default: false, PreRelease: featuregate.Alpha},
 		HugePageReport:         {Default: false, PreRelease: featuregate.Alpha},
 		PodResourcesProxy:      {Default: false, PreRelease: featuregate.Alpha},
+		CPUSetMemoryMigrate:    {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
@@ -30,6 +30,7 @@ import (
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	qmframework "github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/remotewrite"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
@@ -54,6 +55,7 @@ type Configuration struct {
 	RuntimeHookConf    *runtimehooks.Config
 	AuditConf          *audit.Config
 	PredictionConf     *prediction.Config
+	RemoteWriteConf    *remotewrite.Config
 
 	FeatureGates map[string]bool
 }
@@ -69,6 +71,7 @@ func NewConfiguration() *Configuration {
 		RuntimeHookConf:    runtimehooks.NewDefaultConfig(),
 		AuditConf:          audit.NewDefaultConfig(),
 		PredictionConf:     prediction.NewDefaultConfig(),
+		RemoteWriteConf:    remotewrite.NewDefaultConfig(),
 	}
 }
 
@@ -83,6 +86,7 @@ func (c *Configuration) InitFlags(fs *flag.FlagSet) {
 	c.RuntimeHookConf.InitFlags(fs)
 	c.AuditConf.InitFlags(fs)
 	c.PredictionConf.InitFlags(fs)
+	c.RemoteWriteConf.InitFlags(fs)
 	resourceexecutor.Conf.InitFlags(fs)
 	fs.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
 		"Options are:\n"+strings.Join(features.DefaultKoordletFeatureGate.KnownFeatures(), "\n"))
@@ -38,6 +38,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/remotewrite"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -68,6 +69,7 @@ type daemon struct {
 	runtimeHook    runtimehooks.RuntimeHook
 	predictServer  prediction.PredictServer
 	executor       resourceexecutor.ResourceUpdateExecutor
+	remoteWriter   *remotewrite.Exporter
 
 	extensionControllers []extension.Controller
 }
@@ -94,6 +96,14 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	if err != nil {
 		return nil, err
 	}
+	var remoteWriter *remotewrite.Exporter
+	if config.RemoteWriteConf.URL != "" {
+		remoteWriter, err = remotewrite.NewExporter(config.RemoteWriteConf, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		metricCache = remoteWriter.Wrap(metricCache)
+	}
 	predictServer := prediction.NewPeakPredictServer(config.PredictionConf)
 	predictorFactory := prediction.NewPredictorFactory(predictServer, config.PredictionConf.ColdStartDuration, config.PredictionConf.SafetyMarginPercent)
 
@@ -111,7 +121,7 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 
 	qosManager := qosmanager.NewQOSManager(config.QOSManagerConf, scheme, kubeClient, crdClient, nodeName, statesInformer, metricCache, config.CollectorConf, evictVersion)
 
-	runtimeHook, err := runtimehooks.NewRuntimeHook(statesInformer, config.RuntimeHookConf, scheme, kubeClient, nodeName)
+	runtimeHook, err := runtimehooks.NewRuntimeHook(statesInformer, metricCache, config.RuntimeHookConf, scheme, kubeClient, nodeName)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +139,7 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 		runtimeHook:    runtimeHook,
 		predictServer:  predictServer,
 		executor:       resourceexecutor.NewResourceUpdateExecutor(),
+		remoteWriter:   remoteWriter,
 
 		extensionControllers: extensionControllers,
 	}
@@ -181,6 +192,16 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 		}
 	}()
 
+	// start remote-write exporter
+	if d.remoteWriter != nil {
+		d.remoteWriter.Setup(d.statesInformer)
+		go func() {
+			if err := d.remoteWriter.Run(stopCh); err != nil {
+				klog.Fatal("Unable to run the remote-write exporter: ", err)
+			}
+		}()
+	}
+
 	// start qos manager
 	go func() {
 		if err := d.qosManager.Run(stopCh); err != nil {
@@ -39,6 +39,12 @@ type Config struct {
 	TSDBMinBlockDuration          time.Duration
 	TSDBMaxBlockDuration          time.Duration
 	TSDBHeadChunksWriteBufferSize int
+
+	// the prometheus compatible query api over the tsdb, disabled if the address is empty
+	TSDBQueryAPIAddr       string
+	TSDBQueryAPITokenFile  string
+	TSDBQueryAPITimeout    time.Duration
+	TSDBQueryAPIMaxSamples int
 }
 
 func NewDefaultConfig() *Config {
@@ -57,6 +63,10 @@ func NewDefaultConfig() *Config {
 		TSDBMinBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBMaxBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,      // 1 MB
+
+		TSDBQueryAPIAddr:       "",
+		TSDBQueryAPITimeout:    30 * time.Second,
+		TSDBQueryAPIMaxSamples: 5000000,
 	}
 }
 
@@ -76,4 +86,9 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.TSDBMaxBlockDuration, "tsdb-max-block-duration", c.TSDBMaxBlockDuration, "The maximum timestamp range of compacted blocks, recommend >= 1h or this will cause chunks_head leak.")
 	fs.IntVar(&c.TSDBHeadChunksWriteBufferSize, "tsdb-head-chunks-write-buffer-size", c.TSDBHeadChunksWriteBufferSize, "Write buffer size used by the head chunks mapper.")
 
+	fs.StringVar(&c.TSDBQueryAPIAddr, "tsdb-query-api-addr", c.TSDBQueryAPIAddr, "The address to serve the prometheus compatible query api over tsdb, e.g. 127.0.0.1:9317. Disabled if empty. A non-loopback address requires --tsdb-query-api-token-file.")
+	fs.StringVar(&c.TSDBQueryAPITokenFile, "tsdb-query-api-token-file", c.TSDBQueryAPITokenFile, "The file containing the bearer token required by the tsdb query api.")
+	fs.DurationVar(&c.TSDBQueryAPITimeout, "tsdb-query-api-timeout", c.TSDBQueryAPITimeout, "The max duration of a tsdb query api request.")
+	fs.IntVar(&c.TSDBQueryAPIMaxSamples, "tsdb-query-api-max-samples", c.TSDBQueryAPIMaxSamples, "The max number of samples a tsdb query api request can load into memory.")
+
 }
@@ -41,6 +41,10 @@ func Test_NewDefaultConfig(t *testing.T) {
 		TSDBMinBlockDuration:          10 * time.Minute,
 		TSDBMaxBlockDuration:          10 * time.Minute,
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,
+
+		TSDBQueryAPIAddr:       "",
+		TSDBQueryAPITimeout:    30 * time.Second,
+		TSDBQueryAPIMaxSamples: 5000000,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -63,6 +67,11 @@ func Test_InitFlags(t *testing.T) {
 		"--tsdb-min-block-duration=10m",
 		"--tsdb-max-block-duration=20m",
 		"--tsdb-head-chunks-write-buffer-size=512",
+
+		"--tsdb-query-api-addr=127.0.0.1:9317",
+		"--tsdb-query-api-token-file=/etc/koordlet/token",
+		"--tsdb-query-api-timeout=10s",
+		"--tsdb-query-api-max-samples=1000",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -81,6 +90,11 @@ func Test_InitFlags(t *testing.T) {
 		TSDBMinBlockDuration          time.Duration
 		TSDBMaxBlockDuration          time.Duration
 		TSDBHeadChunksWriteBufferSize int
+
+		TSDBQueryAPIAddr       string
+		TSDBQueryAPITokenFile  string
+		TSDBQueryAPITimeout    time.Duration
+		TSDBQueryAPIMaxSamples int
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -105,6 +119,10 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          10 * time.Minute,
 				TSDBMaxBlockDuration:          20 * time.Minute,
 				TSDBHeadChunksWriteBufferSize: 512,
+				TSDBQueryAPIAddr:              "127.0.0.1:9317",
+				TSDBQueryAPITokenFile:         "/etc/koordlet/token",
+				TSDBQueryAPITimeout:           10 * time.Second,
+				TSDBQueryAPIMaxSamples:        1000,
 			},
 			args: args{fs: fs},
 		},
@@ -126,6 +144,11 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          tt.fields.TSDBMinBlockDuration,
 				TSDBMaxBlockDuration:          tt.fields.TSDBMaxBlockDuration,
 				TSDBHeadChunksWriteBufferSize: tt.fields.TSDBHeadChunksWriteBufferSize,
+
+				TSDBQueryAPIAddr:       tt.fields.TSDBQueryAPIAddr,
+				TSDBQueryAPITokenFile:  tt.fields.TSDBQueryAPITokenFile,
+				TSDBQueryAPITimeout:    tt.fields.TSDBQueryAPITimeout,
+				TSDBQueryAPIMaxSamples: tt.fields.TSDBQueryAPIMaxSamples,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -17,7 +17,12 @@ limitations under the License.
 package metriccache
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"time"
+
+	"k8s.io/klog/v2"
 )
 
 type InterferenceMetricName string
@@ -79,7 +84,41 @@ func NewMetricCache(cfg *Config) (MetricCache, error) {
 }
 
 func (m *metricCache) Run(stopCh <-chan struct{}) error {
+	if m.config.TSDBQueryAPIAddr != "" {
+		if err := m.runQueryAPI(stopCh); err != nil {
+			return err
+		}
+	}
 	<-stopCh
 	m.Close()
 	return nil
 }
+
+func (m *metricCache) runQueryAPI(stopCh <-chan struct{}) error {
+	storage, ok := m.TSDBStorage.(*tsdbStorage)
+	if !ok {
+		return fmt.Errorf("query api is not supported by tsdb storage %T", m.TSDBStorage)
+	}
+	token, err := loadQueryAPIToken(m.config)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              m.config.TSDBQueryAPIAddr,
+		Handler:           newQueryAPI(storage.db, m.config, token).Handler(),
+		ReadHeaderTimeout: queryAPIReadHeaderTimeout,
+	}
+	go func() {
+		klog.Infof("starting tsdb query api on %v", m.config.TSDBQueryAPIAddr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("tsdb query api failed, err: %v", err)
+		}
+	}()
+	go func() {
+		<-stopCh
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Warningf("failed to shutdown tsdb query api, err: %v", err)
+		}
+	}()
+	return nil
+}
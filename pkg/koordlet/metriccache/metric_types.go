@@ -185,7 +185,6 @@ var MetricPropertiesFunc = struct {
 	ResctrlMB: func(qos string, cacheid int, mbType string) map[MetricProperty]string {
 		return map[MetricProperty]string{
 			MetricPropertyResctrlCacheId: strconv.Itoa(cacheid),
-			MetricPropertyResctrlType:    string(ResctrlTypeMB),
 			MetricPropertyResctrlMbType:  mbType,
 			MetricPropertyQos:            qos,
 		}
@@ -266,6 +265,11 @@ type MetricSample interface {
 	value() float64
 }
 
+// GetSamplePoint returns the timestamp in milliseconds and the value of the sample.
+func GetSamplePoint(s MetricSample) (int64, float64) {
+	return s.timestamp(), s.value()
+}
+
 // metricSample is an implementation of MetricSample
 var _ MetricSample = &metricSample{}
 
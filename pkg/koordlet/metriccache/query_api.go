@@ -0,0 +1,378 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	promstorage "github.com/prometheus/prometheus/storage"
+	"k8s.io/klog/v2"
+)
+
+const (
+	QueryAPIPath      = "/api/v1/query"
+	QueryRangeAPIPath = "/api/v1/query_range"
+	SeriesAPIPath     = "/api/v1/series"
+
+	// queryAPIMaxPoints is the max number of points per series of a range query, same as prometheus.
+	queryAPIMaxPoints = 11000
+	// queryAPIReadHeaderTimeout bounds the time to read the request headers against slow clients.
+	queryAPIReadHeaderTimeout = 10 * time.Second
+
+	queryAPIAuthorizationPrefix = "Bearer "
+)
+
+type queryAPIErrorType string
+
+const (
+	queryAPIErrorBadData   queryAPIErrorType = "bad_data"
+	queryAPIErrorTimeout   queryAPIErrorType = "timeout"
+	queryAPIErrorCanceled  queryAPIErrorType = "canceled"
+	queryAPIErrorExecution queryAPIErrorType = "execution"
+	queryAPIErrorInternal  queryAPIErrorType = "internal"
+)
+
+var (
+	queryAPIMinTime = time.Unix(math.MinInt64/1000+62135596801, 0).UTC()
+	queryAPIMaxTime = time.Unix(math.MaxInt64/1000-62135596801, 999999999).UTC()
+)
+
+type queryAPIResponse struct {
+	Status    string            `json:"status"`
+	Data      interface{}       `json:"data,omitempty"`
+	ErrorType queryAPIErrorType `json:"errorType,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+type queryAPIData struct {
+	ResultType parser.ValueType `json:"resultType"`
+	Result     parser.Value     `json:"result"`
+}
+
+type queryAPIError struct {
+	typ queryAPIErrorType
+	err error
+}
+
+// queryAPI serves the Prometheus HTTP API of instant queries, range queries and series over the local TSDB,
+// so the tools on the node can read the samples koordlet acts on at full resolution.
+type queryAPI struct {
+	queryable promstorage.Queryable
+	engine    *promql.Engine
+	token     string
+}
+
+func newQueryAPI(queryable promstorage.Queryable, cfg *Config, token string) *queryAPI {
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+	return &queryAPI{
+		queryable: queryable,
+		engine: promql.NewEngine(promql.EngineOpts{
+			Logger:               log.With(logger, "component", "query engine"),
+			MaxSamples:           cfg.TSDBQueryAPIMaxSamples,
+			Timeout:              cfg.TSDBQueryAPITimeout,
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		}),
+		token: token,
+	}
+}
+
+func (q *queryAPI) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(QueryAPIPath, q.wrap(q.query))
+	mux.HandleFunc(QueryRangeAPIPath, q.wrap(q.queryRange))
+	mux.HandleFunc(SeriesAPIPath, q.wrap(q.series))
+	return mux
+}
+
+func (q *queryAPI) wrap(f func(r *http.Request) (interface{}, promstorage.Warnings, *queryAPIError)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if !q.authorized(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		data, warnings, apiErr := f(r)
+		resp := &queryAPIResponse{Status: "success", Data: data}
+		for _, warning := range warnings {
+			resp.Warnings = append(resp.Warnings, warning.Error())
+		}
+		code := http.StatusOK
+		if apiErr != nil {
+			resp = &queryAPIResponse{Status: "error", ErrorType: apiErr.typ, Error: apiErr.err.Error()}
+			code = apiErr.statusCode()
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			klog.Errorf("failed to marshal query api response, err: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if _, err = w.Write(b); err != nil {
+			klog.V(4).Infof("failed to write query api response, err: %v", err)
+		}
+	}
+}
+
+func (q *queryAPI) authorized(r *http.Request) bool {
+	if q.token == "" {
+		return true
+	}
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, queryAPIAuthorizationPrefix) {
+		return false
+	}
+	token := strings.TrimPrefix(authorization, queryAPIAuthorizationPrefix)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(q.token)) == 1
+}
+
+func (q *queryAPI) query(r *http.Request) (interface{}, promstorage.Warnings, *queryAPIError) {
+	ts, err := parseQueryAPITime(r.FormValue("time"), time.Now())
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"time\": %w", err)}
+	}
+	ctx, cancel, err := queryAPIContext(r)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: err}
+	}
+	defer cancel()
+
+	qry, err := q.engine.NewInstantQuery(q.queryable, nil, r.FormValue("query"), ts)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"query\": %w", err)}
+	}
+	return execQuery(ctx, qry)
+}
+
+func (q *queryAPI) queryRange(r *http.Request) (interface{}, promstorage.Warnings, *queryAPIError) {
+	start, err := parseQueryAPITime(r.FormValue("start"), time.Time{})
+	if err != nil || start.IsZero() {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"start\": %v", err)}
+	}
+	end, err := parseQueryAPITime(r.FormValue("end"), time.Time{})
+	if err != nil || end.IsZero() {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"end\": %v", err)}
+	}
+	if end.Before(start) {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: errors.New("end timestamp must not be before start time")}
+	}
+	step, err := parseQueryAPIDuration(r.FormValue("step"))
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"step\": %w", err)}
+	}
+	if step <= 0 {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: errors.New("zero or negative query resolution step widths are not accepted")}
+	}
+	if end.Sub(start)/step > queryAPIMaxPoints {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("exceeded maximum resolution of %d points per timeseries", queryAPIMaxPoints)}
+	}
+	ctx, cancel, err := queryAPIContext(r)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: err}
+	}
+	defer cancel()
+
+	qry, err := q.engine.NewRangeQuery(q.queryable, nil, r.FormValue("query"), start, end, step)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"query\": %w", err)}
+	}
+	return execQuery(ctx, qry)
+}
+
+func (q *queryAPI) series(r *http.Request) (interface{}, promstorage.Warnings, *queryAPIError) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("error parsing form values: %w", err)}
+	}
+	if len(r.Form["match[]"]) == 0 {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: errors.New("no match[] parameter provided")}
+	}
+	start, err := parseQueryAPITime(r.FormValue("start"), queryAPIMinTime)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"start\": %w", err)}
+	}
+	end, err := parseQueryAPITime(r.FormValue("end"), queryAPIMaxTime)
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: fmt.Errorf("invalid parameter \"end\": %w", err)}
+	}
+	var matcherSets [][]*labels.Matcher
+	for _, s := range r.Form["match[]"] {
+		matchers, err := parser.ParseMetricSelector(s)
+		if err != nil {
+			return nil, nil, &queryAPIError{typ: queryAPIErrorBadData, err: err}
+		}
+		matcherSets = append(matcherSets, matchers)
+	}
+
+	querier, err := q.queryable.Querier(r.Context(), start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return nil, nil, &queryAPIError{typ: queryAPIErrorExecution, err: err}
+	}
+	defer querier.Close()
+
+	hints := &promstorage.SelectHints{Start: start.UnixMilli(), End: end.UnixMilli(), Func: "series"}
+	var sets []promstorage.SeriesSet
+	for _, matchers := range matcherSets {
+		sets = append(sets, querier.Select(true, hints, matchers...))
+	}
+	set := promstorage.NewMergeSeriesSet(sets, promstorage.ChainedSeriesMerge)
+	metrics := []labels.Labels{}
+	for set.Next() {
+		metrics = append(metrics, set.At().Labels())
+	}
+	if set.Err() != nil {
+		return nil, set.Warnings(), &queryAPIError{typ: queryAPIErrorExecution, err: set.Err()}
+	}
+	return metrics, set.Warnings(), nil
+}
+
+func execQuery(ctx context.Context, qry promql.Query) (interface{}, promstorage.Warnings, *queryAPIError) {
+	defer qry.Close()
+	res := qry.Exec(ctx)
+	if res.Err != nil {
+		return nil, res.Warnings, newQueryAPIErrorFromPromQL(res.Err)
+	}
+	return &queryAPIData{
+		ResultType: res.Value.Type(),
+		Result:     res.Value,
+	}, res.Warnings, nil
+}
+
+func queryAPIContext(r *http.Request) (context.Context, context.CancelFunc, error) {
+	ctx := r.Context()
+	if to := r.FormValue("timeout"); to != "" {
+		timeout, err := parseQueryAPIDuration(to)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid parameter \"timeout\": %w", err)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel, nil
+}
+
+func newQueryAPIErrorFromPromQL(err error) *queryAPIError {
+	var typ queryAPIErrorType
+	switch err.(type) {
+	case promql.ErrQueryCanceled:
+		typ = queryAPIErrorCanceled
+	case promql.ErrQueryTimeout:
+		typ = queryAPIErrorTimeout
+	case promql.ErrStorage:
+		typ = queryAPIErrorInternal
+	default:
+		typ = queryAPIErrorExecution
+	}
+	return &queryAPIError{typ: typ, err: err}
+}
+
+func (e *queryAPIError) statusCode() int {
+	switch e.typ {
+	case queryAPIErrorBadData:
+		return http.StatusBadRequest
+	case queryAPIErrorExecution:
+		return http.StatusUnprocessableEntity
+	case queryAPIErrorCanceled, queryAPIErrorTimeout:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseQueryAPITime parses a unix timestamp in seconds or a RFC3339 time, the same as prometheus.
+func parseQueryAPITime(s string, defaultValue time.Time) (time.Time, error) {
+	if s == "" {
+		return defaultValue, nil
+	}
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, ns := math.Modf(t)
+		ns = math.Round(ns*1000) / 1000
+		return time.Unix(int64(sec), int64(ns*float64(time.Second))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parseQueryAPIDuration parses a duration in seconds or a prometheus duration like 5m.
+func parseQueryAPIDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		ts := d * float64(time.Second)
+		if ts > float64(math.MaxInt64) || ts < float64(math.MinInt64) {
+			return 0, fmt.Errorf("cannot parse %q to a valid duration, it overflows int64", s)
+		}
+		return time.Duration(ts), nil
+	}
+	if d, err := model.ParseDuration(s); err == nil {
+		return time.Duration(d), nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
+
+// loadQueryAPIToken reads the token of the query API, and requires the token if the API is not bound to localhost.
+func loadQueryAPIToken(cfg *Config) (string, error) {
+	var token string
+	if cfg.TSDBQueryAPITokenFile != "" {
+		b, err := os.ReadFile(cfg.TSDBQueryAPITokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the query api token file, err: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+		if token == "" {
+			return "", fmt.Errorf("the query api token file %s is empty", cfg.TSDBQueryAPITokenFile)
+		}
+	}
+	if token == "" && !isLoopbackAddr(cfg.TSDBQueryAPIAddr) {
+		return "", fmt.Errorf("the query api address %s is not a loopback address and no token is specified", cfg.TSDBQueryAPIAddr)
+	}
+	return token, nil
+}
+
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
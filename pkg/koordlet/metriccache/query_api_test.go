@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_queryAPI(t *testing.T) {
+	conf := NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.TSDBEnablePromMetrics = false
+	db, err := NewTSDBStorage(conf)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	var samples []MetricSample
+	for i, uid := range []string{"uid-1", "uid-2"} {
+		for j := 0; j < 3; j++ {
+			s, err := PodCPUUsageMetric.GenerateSample(map[MetricProperty]string{MetricPropertyPodUID: uid},
+				now.Add(time.Duration(j-3)*time.Second), float64(i+j+1))
+			assert.NoError(t, err)
+			samples = append(samples, s)
+		}
+	}
+	appender := db.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	server := httptest.NewServer(newQueryAPI(db.(*tsdbStorage).db, conf, "test-token").Handler())
+	defer server.Close()
+
+	type response struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType string          `json:"errorType"`
+	}
+	get := func(path string, params url.Values, token string) (int, *response) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path+"?"+params.Encode(), nil)
+		assert.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized {
+			return resp.StatusCode, nil
+		}
+		r := &response{}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(r))
+		return resp.StatusCode, r
+	}
+	unixSeconds := func(t time.Time) string {
+		return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', 3, 64)
+	}
+
+	code, _ := get(QueryAPIPath, url.Values{"query": {"pod_cpu_usage"}}, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	// the token must be sent as a bearer token
+	req, err := http.NewRequest(http.MethodGet, server.URL+QueryAPIPath+"?query=pod_cpu_usage", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "test-token")
+	rawResp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	rawResp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, rawResp.StatusCode)
+
+	code, resp := get(QueryAPIPath, url.Values{
+		"query": {`sum(pod_cpu_usage{pod_uid="uid-2"})`},
+		"time":  {unixSeconds(now)},
+	}, "test-token")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "success", resp.Status)
+	assert.JSONEq(t, `{"resultType":"vector","result":[{"metric":{},"value":[`+unixSeconds(now)+`,"4"]}]}`, string(resp.Data))
+
+	code, resp = get(QueryRangeAPIPath, url.Values{
+		"query": {`pod_cpu_usage{pod_uid="uid-1"}`},
+		"start": {unixSeconds(now.Add(-3 * time.Second))},
+		"end":   {unixSeconds(now.Add(-time.Second))},
+		"step":  {"1s"},
+	}, "test-token")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"resultType":"matrix","result":[{"metric":{"__name__":"pod_cpu_usage","pod_uid":"uid-1"},"values":[`+
+		`[`+unixSeconds(now.Add(-3*time.Second))+`,"1"],[`+unixSeconds(now.Add(-2*time.Second))+`,"2"],[`+unixSeconds(now.Add(-time.Second))+`,"3"]]}]}`,
+		string(resp.Data))
+
+	code, resp = get(SeriesAPIPath, url.Values{"match[]": {"pod_cpu_usage"}}, "test-token")
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `[{"__name__":"pod_cpu_usage","pod_uid":"uid-1"},{"__name__":"pod_cpu_usage","pod_uid":"uid-2"}]`, string(resp.Data))
+
+	code, resp = get(QueryAPIPath, url.Values{"query": {"sum("}}, "test-token")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "error", resp.Status)
+	assert.Equal(t, string(queryAPIErrorBadData), resp.ErrorType)
+
+	code, resp = get(QueryRangeAPIPath, url.Values{"query": {"pod_cpu_usage"}, "start": {"1"}, "end": {"2"}, "step": {"0"}}, "test-token")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, string(queryAPIErrorBadData), resp.ErrorType)
+}
+
+func Test_loadQueryAPIToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("test-token\n"), 0600))
+	tests := []struct {
+		name      string
+		addr      string
+		tokenFile string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "localhost without token",
+			addr: "localhost:9317",
+		},
+		{
+			name: "loopback ip without token",
+			addr: "127.0.0.1:9317",
+		},
+		{
+			name:    "all interfaces without token",
+			addr:    ":9317",
+			wantErr: true,
+		},
+		{
+			name:      "all interfaces with token",
+			addr:      ":9317",
+			tokenFile: tokenFile,
+			want:      "test-token",
+		},
+		{
+			name:      "token file not exist",
+			addr:      "127.0.0.1:9317",
+			tokenFile: filepath.Join(t.TempDir(), "not-exist"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := NewDefaultConfig()
+			conf.TSDBQueryAPIAddr = tt.addr
+			conf.TSDBQueryAPITokenFile = tt.tokenFile
+			got, err := loadQueryAPIToken(conf)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -42,9 +42,23 @@ var (
 		Help:      "the manage status of the core scheduling cookie",
 	}, []string{NodeKey, CoreSchedGroupKey, StatusKey}))
 
+	CoreSchedGroupCookie = metrics.NewGCGaugeVec("core_sched_group_cookie", prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "core_sched_group_cookie",
+		Help:      "the number of PIDs which are assigned the core scheduling cookie of the group",
+	}, []string{NodeKey, CoreSchedGroupKey, CoreSchedCookieKey}))
+
+	ContainerCoreSchedForceIdleSeconds = metrics.NewGCGaugeVec("container_core_sched_force_idle_seconds", prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_core_sched_force_idle_seconds",
+		Help:      "the accumulated time in seconds that the SMT siblings are forced idle by the core scheduling for the container",
+	}, []string{NodeKey, PodName, PodNamespace, PodUID, ContainerName, ContainerID, CoreSchedGroupKey}))
+
 	CoreSchedCollector = []prometheus.Collector{
 		ContainerCoreSchedCookie.GetGaugeVec(),
 		CoreSchedCookieManageStatus.GetCounterVec(),
+		CoreSchedGroupCookie.GetGaugeVec(),
+		ContainerCoreSchedForceIdleSeconds.GetGaugeVec(),
 	}
 )
 
@@ -90,3 +104,51 @@ func RecordCoreSchedCookieManageStatus(groupID string, isSucceeded bool) {
 	}
 	CoreSchedCookieManageStatus.WithInc(labels)
 }
+
+func RecordCoreSchedGroupCookie(groupID string, cookieID uint64, pidNum int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CoreSchedGroupKey] = groupID
+	labels[CoreSchedCookieKey] = strconv.FormatUint(cookieID, 10)
+	CoreSchedGroupCookie.WithSet(labels, float64(pidNum))
+}
+
+func ResetCoreSchedGroupCookie(groupID string, cookieID uint64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CoreSchedGroupKey] = groupID
+	labels[CoreSchedCookieKey] = strconv.FormatUint(cookieID, 10)
+	CoreSchedGroupCookie.Delete(labels)
+}
+
+func RecordContainerCoreSchedForceIdleSeconds(namespace, podName, podUID, containerName, containerID, groupID string, seconds float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = namespace
+	labels[PodName] = podName
+	labels[PodUID] = podUID
+	labels[ContainerName] = containerName
+	labels[ContainerID] = containerID
+	labels[CoreSchedGroupKey] = groupID
+	ContainerCoreSchedForceIdleSeconds.WithSet(labels, seconds)
+}
+
+func ResetContainerCoreSchedForceIdleSeconds(namespace, podName, podUID, containerName, containerID, groupID string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = namespace
+	labels[PodName] = podName
+	labels[PodUID] = podUID
+	labels[ContainerName] = containerName
+	labels[ContainerID] = containerID
+	labels[CoreSchedGroupKey] = groupID
+	ContainerCoreSchedForceIdleSeconds.Delete(labels)
+}
@@ -31,12 +31,33 @@ var (
 		Help:      "Run-time replenished within a period (in microseconds) in container-level set by koordlet",
 	}, []string{NodeKey, PodNamespace, PodName, ContainerID, ContainerName})
 
+	ContainerDesiredCFSBurstUS = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "container_desired_cfs_burst_us",
+		Help:      "The maximum accumulated run-time(in microseconds) in container-level desired by the adaptive cpu burst before bounded by the node",
+	}, []string{NodeKey, PodNamespace, PodName, ContainerID, ContainerName})
+
+	NodeCPUBurstCores = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_burst_cores",
+		Help:      "The cpu cores of the adaptive cpu burst in node-level, including the budget, the headroom, the desired and the allocated",
+	}, []string{NodeKey, CPUBurstCoresTypeKey})
+
 	CPUBurstCollector = []prometheus.Collector{
 		ContainerScaledCFSBurstUS,
 		ContainerScaledCFSQuotaUS,
+		ContainerDesiredCFSBurstUS,
+		NodeCPUBurstCores,
 	}
 )
 
+const (
+	CPUBurstCoresBudget    = "budget"
+	CPUBurstCoresHeadroom  = "headroom"
+	CPUBurstCoresDesired   = "desired"
+	CPUBurstCoresAllocated = "allocated"
+)
+
 func RecordContainerScaledCFSBurstUS(podNS, podName, containerID, containerName string, value float64) {
 	labels := genNodeLabels()
 	if labels == nil {
@@ -61,7 +82,34 @@ func RecordContainerScaledCFSQuotaUS(podNS, podName, containerID, containerName
 	ContainerScaledCFSQuotaUS.With(labels).Set(value)
 }
 
+func RecordContainerDesiredCFSBurstUS(podNS, podName, containerID, containerName string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodNamespace] = podNS
+	labels[PodName] = podName
+	labels[ContainerID] = containerID
+	labels[ContainerName] = containerName
+	ContainerDesiredCFSBurstUS.With(labels).Set(value)
+}
+
+func ResetContainerDesiredCFSBurstUS(containerID string) {
+	ContainerDesiredCFSBurstUS.DeletePartialMatch(prometheus.Labels{ContainerID: containerID})
+}
+
+func RecordNodeCPUBurstCores(coresType string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CPUBurstCoresTypeKey] = coresType
+	NodeCPUBurstCores.With(labels).Set(value)
+}
+
 func ResetCPUBurstCollector() {
 	ContainerScaledCFSBurstUS.Reset()
 	ContainerScaledCFSQuotaUS.Reset()
+	ContainerDesiredCFSBurstUS.Reset()
+	NodeCPUBurstCores.Reset()
 }
@@ -37,10 +37,17 @@ var (
 		Help:      "Number of cpu cores used by BE.",
 	}, []string{NodeKey})
 
+	BESuppressAdaptiveControllerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_adaptive_controller_state",
+		Help:      "State of the adaptive cpu suppress controller in cpu cores, including the error, proportional, integral and output terms.",
+	}, []string{NodeKey, BESuppressTermKey})
+
 	CPUSuppressCollector = []prometheus.Collector{
 		BESuppressCPU,
 		BESuppressLSUsedCPU,
 		BESuppressBEUsedCPU,
+		BESuppressAdaptiveControllerState,
 	}
 )
 
@@ -68,3 +75,12 @@ func RecordBESuppressBEUsedCPU(value float64) {
 	}
 	BESuppressBEUsedCPU.With(labels).Set(value)
 }
+
+func RecordBESuppressAdaptiveControllerState(term string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[BESuppressTermKey] = term
+	BESuppressAdaptiveControllerState.With(labels).Set(value)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	MemoryReliefReleasedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "memory_relief_released_bytes",
+		Help:      "Estimated bytes of memory released by each stage of the memory pressure relief",
+	}, []string{NodeKey, MemoryReliefStageKey})
+
+	MemoryReliefStageStatus = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "memory_relief_stage_status",
+		Help:      "the count of actions taken by each stage of the memory pressure relief",
+	}, []string{NodeKey, MemoryReliefStageKey, StatusKey})
+
+	BEMemoryHighBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_high_bytes",
+		Help:      "the memory.high of the BE parent cgroup managed by koordlet, 0 means unlimited",
+	}, []string{NodeKey})
+
+	MemoryReliefCollector = []prometheus.Collector{
+		MemoryReliefReleasedBytes,
+		MemoryReliefStageStatus,
+		BEMemoryHighBytes,
+	}
+)
+
+func RecordMemoryReliefReleasedBytes(stage string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[MemoryReliefStageKey] = stage
+	MemoryReliefReleasedBytes.With(labels).Add(value)
+}
+
+func RecordMemoryReliefStageStatus(stage string, err error) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[MemoryReliefStageKey] = stage
+	labels[StatusKey] = StatusSucceed
+	if err != nil {
+		labels[StatusKey] = StatusFailed
+	}
+	MemoryReliefStageStatus.With(labels).Inc()
+}
+
+func RecordBEMemoryHighBytes(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEMemoryHighBytes.With(labels).Set(value)
+}
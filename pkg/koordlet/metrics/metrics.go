@@ -38,6 +38,11 @@ const (
 
 	EvictionReasonKey = "reason"
 	BESuppressTypeKey = "type"
+	BESuppressTermKey = "term"
+
+	MemoryReliefStageKey = "stage"
+
+	CPUBurstCoresTypeKey = "type"
 
 	ContainerID   = "container_id"
 	ContainerName = "container_name"
@@ -139,10 +139,18 @@ func TestCommonCollectors(t *testing.T) {
 		RecordBESuppressCores("cfsQuota", float64(1000))
 		RecordBESuppressLSUsedCPU(1.0)
 		RecordBESuppressBEUsedCPU(1.0)
+		RecordBESuppressAdaptiveControllerState("output", 2.0)
+		RecordMemoryReliefReleasedBytes("reclaim", float64(1024))
+		RecordMemoryReliefStageStatus("reclaim", nil)
+		RecordMemoryReliefStageStatus("throttle", testingErr)
+		RecordBEMemoryHighBytes(float64(1024))
 		RecordNodeUsedCPU(2.0)
 		RecordNodeUsedMemory(float64(1024))
 		RecordContainerScaledCFSBurstUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
 		RecordContainerScaledCFSQuotaUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
+		RecordContainerDesiredCFSBurstUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
+		ResetContainerDesiredCFSBurstUS(testingContainer.ContainerID)
+		RecordNodeCPUBurstCores(CPUBurstCoresBudget, 4)
 		RecordPodEviction(testingPod.Namespace, testingPod.Name, "evictByCPU")
 		ResetContainerCPI()
 		RecordContainerCPI(testingContainer, testingPod, 1, 1)
@@ -380,6 +388,14 @@ func TestCoreSchedCollector(t *testing.T) {
 		RecordContainerCoreSchedCookie(testingPod.Namespace, testingPod.Name, string(testingPod.UID),
 			testingPod.Status.ContainerStatuses[0].Name, testingPod.Status.ContainerStatuses[0].ContainerID,
 			testCoreSchedGroup, testCoreSchedCookie)
+		RecordCoreSchedGroupCookie(testCoreSchedGroup, testCoreSchedCookie, 4)
+		RecordContainerCoreSchedForceIdleSeconds(testingPod.Namespace, testingPod.Name, string(testingPod.UID),
+			testingPod.Status.ContainerStatuses[0].Name, testingPod.Status.ContainerStatuses[0].ContainerID,
+			testCoreSchedGroup, 1.5)
+		ResetCoreSchedGroupCookie(testCoreSchedGroup, testCoreSchedCookie)
+		ResetContainerCoreSchedForceIdleSeconds(testingPod.Namespace, testingPod.Name, string(testingPod.UID),
+			testingPod.Status.ContainerStatuses[0].Name, testingPod.Status.ContainerStatuses[0].ContainerID,
+			testCoreSchedGroup)
 	})
 }
 
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RemoteWriteStatusKey represents the status of the samples exported by remote-write
+	RemoteWriteStatusKey = "status"
+)
+
+const (
+	RemoteWriteStatusQueued  = "queued"
+	RemoteWriteStatusLimited = "limited"
+)
+
+var (
+	remoteWriteSamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_samples_total",
+		Help:      "the number of metric samples exported by remote-write",
+	}, []string{RemoteWriteStatusKey})
+
+	remoteWriteFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_failures_total",
+		Help:      "the number of failed remote-write requests",
+	})
+
+	remoteWriteDroppedBatches = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_dropped_batches_total",
+		Help:      "the number of remote-write batches dropped due to rejected requests or the wal exceeding its max bytes",
+	})
+
+	remoteWriteWALBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "remote_write_wal_bytes",
+		Help:      "the bytes of the remote-write batches not yet sent",
+	})
+
+	RemoteWriteCollectors = []prometheus.Collector{
+		remoteWriteSamples,
+		remoteWriteFailures,
+		remoteWriteDroppedBatches,
+		remoteWriteWALBytes,
+	}
+)
+
+func RecordRemoteWriteSamples(status string, count int) {
+	remoteWriteSamples.WithLabelValues(status).Add(float64(count))
+}
+
+func RecordRemoteWriteFailure() {
+	remoteWriteFailures.Inc()
+}
+
+func RecordRemoteWriteDroppedBatches(count int) {
+	remoteWriteDroppedBatches.Add(float64(count))
+}
+
+func RecordRemoteWriteWALBytes(bytes int64) {
+	remoteWriteWALBytes.Set(float64(bytes))
+}
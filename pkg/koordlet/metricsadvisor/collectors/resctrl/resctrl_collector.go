@@ -104,6 +104,7 @@ func (r *resctrlCollector) collectQoSResctrlStat() {
 			llcSample, err := metriccache.ResctrlLLCMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlLLC(qos, int(cacheId)), collectTime, float64(value))
 			if err != nil {
 				klog.Warningf("generate QoS %s resctrl llc sample error: %v", qos, err)
+				continue
 			}
 			resctrlMetrics = append(resctrlMetrics, llcSample)
 		}
@@ -118,6 +119,7 @@ func (r *resctrlCollector) collectQoSResctrlStat() {
 				mbSample, err := metriccache.ResctrlMBMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlMB(qos, int(cacheId), mbType), collectTime, float64(mbValue))
 				if err != nil {
 					klog.V(4).Infof("generate QoS %s resctrl mb sample error: %v", qos, err)
+					continue
 				}
 				resctrlMetrics = append(resctrlMetrics, mbSample)
 			}
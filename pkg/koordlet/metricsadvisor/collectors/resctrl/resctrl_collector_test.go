@@ -17,12 +17,16 @@ limitations under the License.
 package resctrl
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
@@ -165,3 +169,70 @@ func Test_collectQosResctrlStatNoErr(t *testing.T) {
 		})
 	}
 }
+
+func Test_collectQoSResctrlStatSamples(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	appender := mockmetriccache.NewMockAppender(ctrl)
+	mockMetricCache.EXPECT().Appender().Return(appender).AnyTimes()
+	var samples []metriccache.MetricSample
+	appender.EXPECT().Append(gomock.Any()).DoAndReturn(func(s []metriccache.MetricSample) error {
+		samples = append(samples, s...)
+		return nil
+	}).AnyTimes()
+	appender.EXPECT().Commit().Return(nil).AnyTimes()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("cpuinfo", "vendor_id       : GenuineIntel\nflags           : cat_l3 mba cqm_llc cqm_occup_llc cqm_mbm_total cqm_mbm_local")
+	helper.WriteFileContents(filepath.Join("fs", "resctrl", system.ResctrlSchemataName), "L3:0=fff;1=fff\nMB:0=100;1=100\n")
+	system.TestingPrepareResctrlMondata(t, system.Conf.SysFSRootDir, "BE", system.MockMonData{
+		CacheItems: map[int]system.MockCacheItem{
+			0: {
+				"llc_occupancy":   11,
+				"mbm_local_bytes": 21,
+				"mbm_total_bytes": 31,
+			},
+		},
+	})
+
+	metrics.Register(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
+	defer metrics.Register(nil)
+
+	collector := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		MetricCache:  mockMetricCache,
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*resctrlCollector)
+	c.collectQoSResctrlStat()
+	assert.True(t, c.Started())
+
+	// the MB samples carry the properties of the MB metric schema, and no nil sample is saved
+	type sampleKey struct {
+		kind    string
+		cacheID string
+		mbType  string
+	}
+	got := map[sampleKey]float64{}
+	for _, sample := range samples {
+		if !assert.NotNil(t, sample) {
+			continue
+		}
+		properties := sample.GetProperties()
+		assert.Equal(t, "BE", properties[string(metriccache.MetricPropertyQos)])
+		assert.NotContains(t, properties, string(metriccache.MetricPropertyResctrlType))
+		_, value := metriccache.GetSamplePoint(sample)
+		got[sampleKey{
+			kind:    sample.GetKind(),
+			cacheID: properties[string(metriccache.MetricPropertyResctrlCacheId)],
+			mbType:  properties[string(metriccache.MetricPropertyResctrlMbType)],
+		}] = value
+	}
+	assert.Equal(t, map[sampleKey]float64{
+		{kind: string(metriccache.ResctrlLLC), cacheID: "0"}:                           11,
+		{kind: string(metriccache.ResctrlMB), cacheID: "0", mbType: "mbm_local_bytes"}: 21,
+		{kind: string(metriccache.ResctrlMB), cacheID: "0", mbType: "mbm_total_bytes"}: 31,
+	}, got)
+}
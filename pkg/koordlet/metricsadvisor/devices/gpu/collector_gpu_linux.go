@@ -59,24 +59,40 @@ type device struct {
 	Device      nvml.Device
 }
 
+// gpuDeviceManagerFactories are the vendor backends of the gpu device manager in order of priority.
+// A factory returns nil if the devices of its vendor are not found on the node.
+var gpuDeviceManagerFactories = []func() GPUDeviceManager{
+	initNVMLDeviceManager,
+	initSysfsDeviceManager,
+}
+
 // initGPUDeviceManager will not retry if init fails,
 func initGPUDeviceManager() GPUDeviceManager {
 	if !features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) {
 		return &dummyDeviceManager{}
 	}
+	for _, factory := range gpuDeviceManagerFactories {
+		if manager := factory(); manager != nil {
+			return manager
+		}
+	}
+	return &dummyDeviceManager{}
+}
+
+func initNVMLDeviceManager() GPUDeviceManager {
 	if ret := nvml.Init(); ret != nvml.SUCCESS {
 		if ret == nvml.ERROR_LIBRARY_NOT_FOUND {
 			klog.Warning("nvml init failed, library not found")
-			return &dummyDeviceManager{}
+			return nil
 		}
 		klog.Warningf("nvml init failed, return %s", nvml.ErrorString(ret))
-		return &dummyDeviceManager{}
+		return nil
 	}
 	manager := &gpuDeviceManager{start: atomic.NewBool(false)}
 	if err := manager.initGPUData(); err != nil {
 		klog.Warningf("nvml init gpu data, error %s", err)
 		manager.shutdown()
-		return &dummyDeviceManager{}
+		return nil
 	}
 
 	return manager
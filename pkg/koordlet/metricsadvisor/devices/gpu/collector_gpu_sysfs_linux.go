@@ -0,0 +1,433 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/helper"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	gpuVendorAMD   = "amd"
+	gpuVendorHygon = "hygon"
+
+	// sysfs attributes under /sys/class/drm/cardN/device
+	sysfsPCIVendorFile     = "vendor"
+	sysfsPCIDeviceFile     = "device"
+	sysfsProductNameFile   = "product_name"
+	sysfsVRAMTotalFile     = "mem_info_vram_total"
+	sysfsVRAMUsedFile      = "mem_info_vram_used"
+	sysfsGPUBusyFile       = "gpu_busy_percent"
+	sysfsDriverLink        = "driver"
+	sysfsDeviceDRMDir      = "drm"
+	sysfsKernelVersionFile = "version"
+
+	// kfd attributes under /sys/class/kfd/kfd
+	kfdGPUIDFile                  = "gpu_id"
+	kfdPropertiesFile             = "properties"
+	kfdPropertyRenderMinor        = "drm_render_minor"
+	kfdPropertyUniqueID           = "unique_id"
+	kfdProcVRAMFilePrefix         = "vram_"
+	kfdInvalidGPUID               = "0"
+	kfdUnknownUniqueID     uint64 = 0
+)
+
+var (
+	// sysfsGPUVendors are the PCI vendor IDs of the gpus discovered from the sysfs.
+	sysfsGPUVendors = map[string]string{
+		"0x1002": gpuVendorAMD,
+		"0x1d94": gpuVendorHygon,
+	}
+
+	drmCardRegexp   = regexp.MustCompile(`^card(\d+)$`)
+	drmRenderRegexp = regexp.MustCompile(`^renderD(\d+)$`)
+)
+
+// sysfsDeviceManager discovers the AMD and Hygon gpus from /sys/class/drm and /sys/class/kfd, which are exported
+// by the amdgpu-compatible kernel drivers, so it requires no vendor library.
+//
+// The device-level usages are read from the sysfs counters. The process-level usages only contain the memory used
+// since the kfd does not export the per-process utilization.
+type sysfsDeviceManager struct {
+	*gpuDeviceManager
+	// sysfsDevices are indexed the same as the devices
+	sysfsDevices  []*sysfsDevice
+	deviceMetrics []rawGPUMetric
+}
+
+type sysfsDevice struct {
+	Vendor        string
+	Model         string
+	DriverVersion string
+	// DeviceDir is the pci device dir of the gpu, e.g. /sys/class/drm/card0/device
+	DeviceDir string
+	// KFDGPUID is the gpu_id in the kfd topology, which is used to index the per-process usages
+	KFDGPUID string
+}
+
+type kfdNode struct {
+	GPUID    string
+	UniqueID uint64
+}
+
+func initSysfsDeviceManager() GPUDeviceManager {
+	manager := &sysfsDeviceManager{
+		gpuDeviceManager: &gpuDeviceManager{start: atomic.NewBool(false)},
+	}
+	if err := manager.initGPUData(); err != nil {
+		klog.V(4).Infof("sysfs init gpu data, error %s", err)
+		return nil
+	}
+	return manager
+}
+
+func (s *sysfsDeviceManager) shutdown() error {
+	return nil
+}
+
+func (s *sysfsDeviceManager) initGPUData() error {
+	cardDirs, err := os.ReadDir(system.GetDRMClassDir())
+	if err != nil {
+		return fmt.Errorf("failed to read drm class dir, err: %w", err)
+	}
+	kfdNodes, err := parseKFDTopologyNodes()
+	if err != nil {
+		// the kfd is optional for the discovery, while the per-process usages are unavailable without it
+		klog.V(4).Infof("failed to parse kfd topology nodes, err: %s", err)
+	}
+
+	var devices []*device
+	var sysfsDevices []*sysfsDevice
+	for _, cardDir := range cardDirs {
+		matches := drmCardRegexp.FindStringSubmatch(cardDir.Name())
+		if len(matches) != 2 {
+			continue
+		}
+		deviceDir := filepath.Join(system.GetDRMClassDir(), cardDir.Name(), "device")
+		vendorID, err := readSysfsString(filepath.Join(deviceDir, sysfsPCIVendorFile))
+		if err != nil {
+			klog.V(5).Infof("failed to read vendor of drm device %s, err: %s", cardDir.Name(), err)
+			continue
+		}
+		vendor, ok := sysfsGPUVendors[strings.ToLower(vendorID)]
+		if !ok {
+			continue
+		}
+		minor, err := strconv.ParseInt(matches[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("failed to parse minor of drm device %s, err: %w", cardDir.Name(), err)
+		}
+		pciDevicePath, err := filepath.EvalSymlinks(deviceDir)
+		if err != nil {
+			return fmt.Errorf("failed to get pci device of drm device %s, err: %w", cardDir.Name(), err)
+		}
+		nodeID, pcie, busID, err := helper.ParsePCIInfo(strings.ToLower(filepath.Base(pciDevicePath)))
+		if err != nil {
+			return err
+		}
+		memoryTotal, err := readSysfsUint64(filepath.Join(deviceDir, sysfsVRAMTotalFile))
+		if err != nil {
+			return fmt.Errorf("failed to get memory total of drm device %s, err: %w", cardDir.Name(), err)
+		}
+
+		// use the unique id reported by the kfd as the uuid if possible, otherwise the bus id is unique on the node
+		uuid := busID
+		var kfdGPUID string
+		if renderMinor, err := getDRMRenderMinor(deviceDir); err == nil {
+			if node, ok := kfdNodes[renderMinor]; ok {
+				kfdGPUID = node.GPUID
+				if node.UniqueID != kfdUnknownUniqueID {
+					uuid = fmt.Sprintf("GPU-%016x", node.UniqueID)
+				}
+			}
+		} else {
+			klog.V(5).Infof("failed to get render minor of drm device %s, err: %s", cardDir.Name(), err)
+		}
+
+		devices = append(devices, &device{
+			Minor:       int32(minor),
+			DeviceUUID:  uuid,
+			MemoryTotal: memoryTotal,
+			NodeID:      nodeID,
+			PCIE:        pcie,
+			BusID:       busID,
+		})
+		sysfsDevices = append(sysfsDevices, &sysfsDevice{
+			Vendor:        vendor,
+			Model:         getSysfsGPUModel(deviceDir),
+			DriverVersion: getSysfsGPUDriverVersion(deviceDir),
+			DeviceDir:     deviceDir,
+			KFDGPUID:      kfdGPUID,
+		})
+	}
+	if len(devices) == 0 {
+		return errors.New("no gpu device found")
+	}
+
+	sort.Sort(&sysfsDeviceSorter{devices: devices, sysfsDevices: sysfsDevices})
+
+	s.Lock()
+	defer s.Unlock()
+	s.deviceCount = len(devices)
+	s.devices = devices
+	s.sysfsDevices = sysfsDevices
+	return nil
+}
+
+func (s *sysfsDeviceManager) deviceInfos() metriccache.Devices {
+	gpuDevices := s.gpuDeviceManager.deviceInfos().(util.GPUDevices)
+	s.RLock()
+	defer s.RUnlock()
+	for i := range gpuDevices {
+		gpuDevices[i].Vendor = s.sysfsDevices[i].Vendor
+		gpuDevices[i].Model = s.sysfsDevices[i].Model
+		gpuDevices[i].DriverVersion = s.sysfsDevices[i].DriverVersion
+	}
+	return gpuDevices
+}
+
+func (s *sysfsDeviceManager) collectGPUUsage() {
+	s.RLock()
+	sysfsDevices := s.sysfsDevices
+	deviceCount := s.deviceCount
+	s.RUnlock()
+
+	deviceMetrics := make([]rawGPUMetric, deviceCount)
+	for idx, d := range sysfsDevices {
+		busyPercent, err := readSysfsUint64(filepath.Join(d.DeviceDir, sysfsGPUBusyFile))
+		if err != nil {
+			klog.V(4).Infof("failed to read gpu busy percent of device %d, err: %s", idx, err)
+		} else {
+			deviceMetrics[idx].SMUtil = uint32(busyPercent)
+		}
+		memoryUsed, err := readSysfsUint64(filepath.Join(d.DeviceDir, sysfsVRAMUsedFile))
+		if err != nil {
+			klog.V(4).Infof("failed to read gpu memory used of device %d, err: %s", idx, err)
+		} else {
+			deviceMetrics[idx].MemoryUsed = memoryUsed
+		}
+	}
+	processesGPUUsages := collectKFDProcessesGPUUsage(sysfsDevices)
+
+	s.Lock()
+	s.deviceMetrics = deviceMetrics
+	s.processesMetrics = processesGPUUsages
+	s.collectTime = time.Now()
+	s.start.Store(true)
+	s.Unlock()
+}
+
+func (s *sysfsDeviceManager) getNodeGPUUsage() []metriccache.MetricSample {
+	s.RLock()
+	defer s.RUnlock()
+	gpuMetrics := make([]metriccache.MetricSample, 0)
+	for idx, r := range s.deviceMetrics {
+		properties := metriccache.MetricPropertiesFunc.GPU(fmt.Sprintf("%d", s.devices[idx].Minor), s.devices[idx].DeviceUUID)
+		gpuCoreMetric := buildMetricSample(
+			metriccache.NodeGPUCoreUsageMetric,
+			properties,
+			s.collectTime,
+			float64(r.SMUtil),
+		)
+		if gpuCoreMetric != nil {
+			gpuMetrics = append(gpuMetrics, gpuCoreMetric)
+		}
+		gpuMemUsedMetric := buildMetricSample(
+			metriccache.NodeGPUMemUsageMetric,
+			properties,
+			s.collectTime,
+			float64(r.MemoryUsed),
+		)
+		if gpuMemUsedMetric != nil {
+			gpuMetrics = append(gpuMetrics, gpuMemUsedMetric)
+		}
+	}
+	return gpuMetrics
+}
+
+// collectKFDProcessesGPUUsage reads the memory used by the processes on each gpu from /sys/class/kfd/kfd/proc/<pid>.
+func collectKFDProcessesGPUUsage(sysfsDevices []*sysfsDevice) map[uint32][]*rawGPUMetric {
+	processesGPUUsages := make(map[uint32][]*rawGPUMetric)
+	procDirs, err := os.ReadDir(system.GetKFDProcDir())
+	if err != nil {
+		klog.V(5).Infof("failed to read kfd proc dir, err: %s", err)
+		return processesGPUUsages
+	}
+	for _, procDir := range procDirs {
+		pid, err := strconv.ParseUint(procDir.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		for idx, d := range sysfsDevices {
+			if d.KFDGPUID == "" {
+				continue
+			}
+			memoryUsed, err := readSysfsUint64(filepath.Join(system.GetKFDProcDir(), procDir.Name(), kfdProcVRAMFilePrefix+d.KFDGPUID))
+			if err != nil || memoryUsed == 0 {
+				continue
+			}
+			if _, ok := processesGPUUsages[uint32(pid)]; !ok {
+				processesGPUUsages[uint32(pid)] = make([]*rawGPUMetric, len(sysfsDevices))
+			}
+			processesGPUUsages[uint32(pid)][idx] = &rawGPUMetric{MemoryUsed: memoryUsed}
+		}
+	}
+	return processesGPUUsages
+}
+
+// parseKFDTopologyNodes returns the gpu nodes of the kfd topology indexed by the drm render minor.
+func parseKFDTopologyNodes() (map[int]*kfdNode, error) {
+	nodesDir := system.GetKFDTopologyNodesDir()
+	nodeDirs, err := os.ReadDir(nodesDir)
+	if err != nil {
+		return nil, err
+	}
+	kfdNodes := map[int]*kfdNode{}
+	for _, nodeDir := range nodeDirs {
+		gpuID, err := readSysfsString(filepath.Join(nodesDir, nodeDir.Name(), kfdGPUIDFile))
+		if err != nil || gpuID == kfdInvalidGPUID { // cpu node
+			continue
+		}
+		properties, err := readKFDProperties(filepath.Join(nodesDir, nodeDir.Name(), kfdPropertiesFile))
+		if err != nil {
+			klog.V(5).Infof("failed to read properties of kfd node %s, err: %s", nodeDir.Name(), err)
+			continue
+		}
+		renderMinor, ok := properties[kfdPropertyRenderMinor]
+		if !ok {
+			continue
+		}
+		kfdNodes[int(renderMinor)] = &kfdNode{
+			GPUID:    gpuID,
+			UniqueID: properties[kfdPropertyUniqueID],
+		}
+	}
+	return kfdNodes, nil
+}
+
+// readKFDProperties parses the kfd properties file, whose lines are in the format of `<name> <value>`.
+func readKFDProperties(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	properties := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		properties[fields[0]] = value
+	}
+	return properties, scanner.Err()
+}
+
+func getDRMRenderMinor(deviceDir string) (int, error) {
+	drmDirs, err := os.ReadDir(filepath.Join(deviceDir, sysfsDeviceDRMDir))
+	if err != nil {
+		return -1, err
+	}
+	for _, drmDir := range drmDirs {
+		matches := drmRenderRegexp.FindStringSubmatch(drmDir.Name())
+		if len(matches) != 2 {
+			continue
+		}
+		return strconv.Atoi(matches[1])
+	}
+	return -1, fmt.Errorf("render node not found under %s", deviceDir)
+}
+
+// getSysfsGPUModel returns the product name of the gpu, e.g. "AMD Instinct MI210" -> "AMD-Instinct-MI210".
+// The pci device id is used when the product name is not exported by the driver.
+func getSysfsGPUModel(deviceDir string) string {
+	model, err := readSysfsString(filepath.Join(deviceDir, sysfsProductNameFile))
+	if err != nil || model == "" {
+		model, err = readSysfsString(filepath.Join(deviceDir, sysfsPCIDeviceFile))
+		if err != nil {
+			klog.V(5).Infof("failed to read model of gpu %s, err: %s", deviceDir, err)
+			return ""
+		}
+	}
+	return strings.ReplaceAll(model, " ", "-")
+}
+
+// getSysfsGPUDriverVersion returns the version of the kernel module bound to the gpu.
+func getSysfsGPUDriverVersion(deviceDir string) string {
+	driverPath, err := filepath.EvalSymlinks(filepath.Join(deviceDir, sysfsDriverLink))
+	if err != nil {
+		klog.V(5).Infof("failed to get driver of gpu %s, err: %s", deviceDir, err)
+		return ""
+	}
+	version, err := readSysfsString(filepath.Join(system.GetKernelModuleDir(filepath.Base(driverPath)), sysfsKernelVersionFile))
+	if err != nil {
+		klog.V(5).Infof("failed to read driver version of gpu %s, err: %s", deviceDir, err)
+		return ""
+	}
+	return version
+}
+
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readSysfsUint64(path string) (uint64, error) {
+	s, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+type sysfsDeviceSorter struct {
+	devices      []*device
+	sysfsDevices []*sysfsDevice
+}
+
+func (s *sysfsDeviceSorter) Len() int { return len(s.devices) }
+
+func (s *sysfsDeviceSorter) Less(i, j int) bool { return s.devices[i].Minor < s.devices[j].Minor }
+
+func (s *sysfsDeviceSorter) Swap(i, j int) {
+	s.devices[i], s.devices[j] = s.devices[j], s.devices[i]
+	s.sysfsDevices[i], s.sysfsDevices[j] = s.sysfsDevices[j], s.sysfsDevices[i]
+}
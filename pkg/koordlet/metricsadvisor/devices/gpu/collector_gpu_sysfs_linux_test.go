@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+type fakeSysfsGPU struct {
+	card        string
+	busID       string
+	pcie        string
+	numaNode    int
+	vendor      string
+	productName string
+	driver      string
+	renderMinor int
+	attributes  map[string]string
+	kfdNode     string
+	kfdGPUID    string
+	uniqueID    uint64
+}
+
+func prepareFakeSysfsGPU(t *testing.T, helper *system.FileTestUtil, gpu fakeSysfsGPU) {
+	pciDir := filepath.Join("devices", gpu.pcie, gpu.busID)
+	helper.WriteFileContents(filepath.Join(pciDir, "numa_node"), fmt.Sprintf("%d\n", gpu.numaNode))
+	helper.WriteFileContents(filepath.Join(pciDir, sysfsPCIVendorFile), gpu.vendor+"\n")
+	if gpu.productName != "" {
+		helper.WriteFileContents(filepath.Join(pciDir, sysfsProductNameFile), gpu.productName+"\n")
+	}
+	for name, value := range gpu.attributes {
+		helper.WriteFileContents(filepath.Join(pciDir, name), value+"\n")
+	}
+	helper.MkDirAll(filepath.Join(pciDir, sysfsDeviceDRMDir, fmt.Sprintf("renderD%d", gpu.renderMinor)))
+	helper.MkDirAll(filepath.Join(pciDir, sysfsDeviceDRMDir, gpu.card))
+	if gpu.driver != "" {
+		driverDir := filepath.Join(helper.TempDir, "bus/pci/drivers", gpu.driver)
+		helper.MkDirAll(driverDir)
+		assert.NoError(t, os.Symlink(driverDir, filepath.Join(helper.TempDir, pciDir, sysfsDriverLink)))
+	}
+
+	helper.MkDirAll(system.GetPCIDeviceDir())
+	assert.NoError(t, os.Symlink(filepath.Join(helper.TempDir, pciDir), filepath.Join(system.GetPCIDeviceDir(), gpu.busID)))
+	helper.MkDirAll(filepath.Join(system.GetDRMClassDir(), gpu.card))
+	assert.NoError(t, os.Symlink(filepath.Join(helper.TempDir, pciDir), filepath.Join(system.GetDRMClassDir(), gpu.card, "device")))
+
+	if gpu.kfdNode != "" {
+		nodeDir := filepath.Join(system.GetKFDTopologyNodesDir(), gpu.kfdNode)
+		helper.WriteFileContents(filepath.Join(nodeDir, kfdGPUIDFile), gpu.kfdGPUID+"\n")
+		helper.WriteFileContents(filepath.Join(nodeDir, kfdPropertiesFile),
+			fmt.Sprintf("cpu_cores_count 0\nsimd_count 416\n%s %d\n%s %d\n", kfdPropertyRenderMinor, gpu.renderMinor, kfdPropertyUniqueID, gpu.uniqueID))
+	}
+}
+
+func Test_sysfsDeviceManager(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	gpus := []fakeSysfsGPU{
+		{
+			card:        "card1",
+			busID:       "0000:83:00.0",
+			pcie:        "pci0000:80",
+			numaNode:    1,
+			vendor:      "0x1002",
+			productName: "AMD Instinct MI210",
+			driver:      "amdgpu",
+			renderMinor: 129,
+			attributes: map[string]string{
+				sysfsVRAMTotalFile: "68702699520",
+				sysfsVRAMUsedFile:  "2048",
+				sysfsGPUBusyFile:   "30",
+			},
+			kfdNode:  "2",
+			kfdGPUID: "5678",
+			uniqueID: 0,
+		},
+		{
+			card:        "card0",
+			busID:       "0000:03:00.0",
+			pcie:        "pci0000:00",
+			numaNode:    0,
+			vendor:      "0x1002",
+			productName: "AMD Instinct MI210",
+			driver:      "amdgpu",
+			renderMinor: 128,
+			attributes: map[string]string{
+				sysfsVRAMTotalFile: "68702699520",
+				sysfsVRAMUsedFile:  "1024",
+				sysfsGPUBusyFile:   "80",
+			},
+			kfdNode:  "1",
+			kfdGPUID: "1234",
+			uniqueID: 0x1234567890abcdef,
+		},
+		{
+			// not discovered from the sysfs
+			card:        "card2",
+			busID:       "0000:04:00.0",
+			pcie:        "pci0000:00",
+			vendor:      "0x10de",
+			renderMinor: 130,
+		},
+	}
+	for _, gpu := range gpus {
+		prepareFakeSysfsGPU(t, helper, gpu)
+	}
+	// cpu node
+	helper.WriteFileContents(filepath.Join(system.GetKFDTopologyNodesDir(), "0", kfdGPUIDFile), "0\n")
+	helper.WriteFileContents(filepath.Join(system.GetKFDTopologyNodesDir(), "0", kfdPropertiesFile), "cpu_cores_count 64\n")
+	// not a card
+	helper.MkDirAll(filepath.Join(system.GetDRMClassDir(), "renderD128"))
+	helper.WriteFileContents(filepath.Join(system.GetKernelModuleDir("amdgpu"), sysfsKernelVersionFile), "6.2.4\n")
+	// processes
+	helper.WriteFileContents(filepath.Join(system.GetKFDProcDir(), "100", kfdProcVRAMFilePrefix+"1234"), "512")
+	helper.WriteFileContents(filepath.Join(system.GetKFDProcDir(), "100", kfdProcVRAMFilePrefix+"5678"), "0")
+	helper.WriteFileContents(filepath.Join(system.GetKFDProcDir(), "200", kfdProcVRAMFilePrefix+"1234"), "256")
+	helper.WriteFileContents(filepath.Join(system.GetKFDProcDir(), "200", kfdProcVRAMFilePrefix+"5678"), "1024")
+
+	manager := initSysfsDeviceManager()
+	assert.NotNil(t, manager)
+	assert.NoError(t, manager.shutdown())
+
+	wantInfos := util.GPUDevices{
+		{
+			UUID:          "GPU-1234567890abcdef",
+			Minor:         0,
+			MemoryTotal:   68702699520,
+			NodeID:        0,
+			PCIE:          "pci0000:00",
+			BusID:         "0000:03:00.0",
+			Vendor:        gpuVendorAMD,
+			Model:         "AMD-Instinct-MI210",
+			DriverVersion: "6.2.4",
+		},
+		{
+			UUID:          "0000:83:00.0",
+			Minor:         1,
+			MemoryTotal:   68702699520,
+			NodeID:        1,
+			PCIE:          "pci0000:80",
+			BusID:         "0000:83:00.0",
+			Vendor:        gpuVendorAMD,
+			Model:         "AMD-Instinct-MI210",
+			DriverVersion: "6.2.4",
+		},
+	}
+	assert.Equal(t, wantInfos, manager.deviceInfos())
+
+	assert.False(t, manager.started())
+	manager.collectGPUUsage()
+	assert.True(t, manager.started())
+
+	sysfsManager := manager.(*sysfsDeviceManager)
+	assert.Equal(t, []rawGPUMetric{{SMUtil: 80, MemoryUsed: 1024}, {SMUtil: 30, MemoryUsed: 2048}}, sysfsManager.deviceMetrics)
+	assert.Equal(t, map[uint32][]*rawGPUMetric{
+		100: {{MemoryUsed: 512}, nil},
+		200: {{MemoryUsed: 256}, {MemoryUsed: 1024}},
+	}, sysfsManager.processesMetrics)
+
+	collectTime := sysfsManager.collectTime
+	wantNodeMetrics := []metriccache.MetricSample{
+		buildMetricSample(metriccache.NodeGPUCoreUsageMetric, metriccache.MetricPropertiesFunc.GPU("0", "GPU-1234567890abcdef"), collectTime, 80),
+		buildMetricSample(metriccache.NodeGPUMemUsageMetric, metriccache.MetricPropertiesFunc.GPU("0", "GPU-1234567890abcdef"), collectTime, 1024),
+		buildMetricSample(metriccache.NodeGPUCoreUsageMetric, metriccache.MetricPropertiesFunc.GPU("1", "0000:83:00.0"), collectTime, 30),
+		buildMetricSample(metriccache.NodeGPUMemUsageMetric, metriccache.MetricPropertiesFunc.GPU("1", "0000:83:00.0"), collectTime, 2048),
+	}
+	assert.Equal(t, wantNodeMetrics, manager.getNodeGPUUsage())
+
+	wantPodMetrics := []metriccache.MetricSample{
+		buildMetricSample(metriccache.PodGPUCoreUsageMetric, metriccache.MetricPropertiesFunc.PodGPU("test-pod", "0", "GPU-1234567890abcdef"), collectTime, 0),
+		buildMetricSample(metriccache.PodGPUMemUsageMetric, metriccache.MetricPropertiesFunc.PodGPU("test-pod", "0", "GPU-1234567890abcdef"), collectTime, 768),
+		buildMetricSample(metriccache.PodGPUCoreUsageMetric, metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "0000:83:00.0"), collectTime, 0),
+		buildMetricSample(metriccache.PodGPUMemUsageMetric, metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "0000:83:00.0"), collectTime, 1024),
+	}
+	assert.Equal(t, wantPodMetrics, sysfsManager.getPodOrContainerTotalGPUUsageOfPIDs("test-pod", true, []uint32{100, 200}))
+}
+
+func Test_initSysfsDeviceManager_noDevice(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	// drm class dir not exist
+	assert.Nil(t, initSysfsDeviceManager())
+
+	// no amd or hygon gpu
+	prepareFakeSysfsGPU(t, helper, fakeSysfsGPU{
+		card:        "card0",
+		busID:       "0000:04:00.0",
+		pcie:        "pci0000:00",
+		vendor:      "0x10de",
+		renderMinor: 128,
+	})
+	assert.Nil(t, initSysfsDeviceManager())
+
+	// hygon dcu without kfd
+	prepareFakeSysfsGPU(t, helper, fakeSysfsGPU{
+		card:        "card1",
+		busID:       "0000:05:00.0",
+		pcie:        "pci0000:00",
+		vendor:      "0x1d94",
+		renderMinor: 129,
+		attributes: map[string]string{
+			sysfsPCIDeviceFile: "0x54b7",
+			sysfsVRAMTotalFile: "17163091968",
+		},
+	})
+	manager := initSysfsDeviceManager()
+	assert.NotNil(t, manager)
+	assert.Equal(t, util.GPUDevices{
+		{
+			UUID:        "0000:05:00.0",
+			Minor:       1,
+			MemoryTotal: 17163091968,
+			NodeID:      0,
+			PCIE:        "pci0000:00",
+			BusID:       "0000:05:00.0",
+			Vendor:      gpuVendorHygon,
+			Model:       "0x54b7",
+		},
+	}, manager.deviceInfos())
+}
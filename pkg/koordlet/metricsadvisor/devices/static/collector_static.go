@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package static
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	DeviceCollectorName = "Static"
+
+	// resyncInterval is the interval to reload the descriptors in case the events are missed or the dir is created
+	// after the collector started.
+	resyncInterval = 60 * time.Second
+)
+
+// staticCollector reports the devices declared by the device descriptor files, so the devices not recognized by
+// the vendor libraries (e.g. FPGAs, NPUs) can be described in the Device CR and scheduled by the deviceshare.
+type staticCollector struct {
+	enabled bool
+	dir     string
+	started *atomic.Bool
+
+	lock    sync.RWMutex
+	devices util.StaticDevices
+
+	watcher *fsnotify.Watcher
+	watched bool
+}
+
+func New(opt *framework.Options) framework.DeviceCollector {
+	return &staticCollector{
+		enabled: features.DefaultKoordletFeatureGate.Enabled(features.Accelerators) && system.Conf.DeviceDescriptorDir != "",
+		dir:     system.Conf.DeviceDescriptorDir,
+		started: atomic.NewBool(false),
+	}
+}
+
+func (s *staticCollector) Shutdown() {
+	if s.watcher == nil {
+		return
+	}
+	if err := s.watcher.Close(); err != nil {
+		klog.Warningf("static device collector shutdown failed, error %v", err)
+	}
+}
+
+func (s *staticCollector) Enabled() bool {
+	return s.enabled
+}
+
+func (s *staticCollector) Setup(fra *framework.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		// the descriptors are still reloaded periodically
+		klog.Warningf("failed to create watcher for device descriptors, error %v", err)
+		return
+	}
+	s.watcher = watcher
+}
+
+func (s *staticCollector) Run(stopCh <-chan struct{}) {
+	go wait.Until(s.resync, resyncInterval, stopCh)
+	if s.watcher != nil {
+		go s.watch(stopCh)
+	}
+}
+
+func (s *staticCollector) Started() bool {
+	return s.started.Load()
+}
+
+func (s *staticCollector) Infos() metriccache.Devices {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.devices == nil {
+		return nil
+	}
+	return s.devices
+}
+
+func (s *staticCollector) GetNodeMetric() ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (s *staticCollector) GetPodMetric(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (s *staticCollector) GetContainerMetric(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (s *staticCollector) resync() {
+	if s.watcher != nil && !s.watched {
+		// the dir may not exist when the collector starts
+		if err := s.watcher.Add(s.dir); err != nil {
+			klog.V(4).Infof("failed to watch device descriptor dir %s, error %v", s.dir, err)
+		} else {
+			s.watched = true
+		}
+	}
+	s.reload()
+}
+
+func (s *staticCollector) watch(stopCh <-chan struct{}) {
+	for {
+		select {
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			// reload on any change since the ConfigMap volumes are updated by renaming the "..data" symlink
+			klog.V(5).Infof("device descriptor dir changed, event %v", event)
+			s.reload()
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			klog.Warningf("failed to watch device descriptor dir %s, error %v", s.dir, err)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (s *staticCollector) reload() {
+	devices, err := util.LoadDeviceDescriptors(s.dir)
+	if err != nil {
+		// the valid devices are still reported
+		klog.Warningf("failed to load some device descriptors in %s, error %v", s.dir, err)
+	}
+	if devices == nil {
+		devices = util.StaticDevices{}
+	}
+
+	s.lock.Lock()
+	s.devices = devices
+	s.lock.Unlock()
+	s.started.Store(true)
+	klog.V(5).Infof("load %d static devices from %s", len(devices), s.dir)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package static
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+func Test_staticCollector(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "devices.d")
+	s := &staticCollector{
+		enabled: true,
+		dir:     dir,
+		started: atomic.NewBool(false),
+	}
+	s.Setup(nil)
+	defer s.Shutdown()
+	assert.False(t, s.Started())
+	assert.Nil(t, s.Infos())
+
+	// the dir does not exist
+	s.resync()
+	assert.True(t, s.Started())
+	assert.False(t, s.watched)
+	assert.Equal(t, util.StaticDevices{}, s.Infos())
+
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	s.resync()
+	assert.True(t, s.watched)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go s.watch(stopCh)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fpga.yaml"), []byte("devices:\n- type: fpga\n  id: fpga-0\n  minor: 0\n"), 0644))
+	want := util.StaticDevices{{Type: schedulingv1alpha1.FPGA, UUID: "fpga-0", Minor: 0}}
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(metriccache.Devices(want), s.Infos())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "fpga.yaml")))
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(metriccache.Devices(util.StaticDevices{}), s.Infos())
+	}, 5*time.Second, 10*time.Millisecond)
+}
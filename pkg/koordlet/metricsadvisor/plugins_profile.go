@@ -31,6 +31,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/sysresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/rdma"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/static"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 )
 
@@ -38,8 +39,9 @@ import (
 
 var (
 	devicePlugins = map[string]framework.DeviceFactory{
-		gpu.DeviceCollectorName:  gpu.New,
-		rdma.DeviceCollectorName: rdma.New,
+		gpu.DeviceCollectorName:    gpu.New,
+		rdma.DeviceCollectorName:   rdma.New,
+		static.DeviceCollectorName: static.New,
 	}
 
 	collectorPlugins = map[string]framework.CollectorFactory{
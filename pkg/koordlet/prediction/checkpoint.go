@@ -38,6 +38,9 @@ type ModelCheckpoint struct {
 	CPU         *histogram.HistogramCheckpoint
 	Memory      *histogram.HistogramCheckpoint
 	LastUpdated metav1.Time
+	// CPUSeasonal and MemorySeasonal are the checkpoints of the seasonal models, only for the node-level items.
+	CPUSeasonal    *SeasonalCheckpoint `json:",omitempty"`
+	MemorySeasonal *SeasonalCheckpoint `json:",omitempty"`
 
 	Error error `json:"-,omitempty"`
 }
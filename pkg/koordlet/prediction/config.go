@@ -31,6 +31,8 @@ type Config struct {
 	ModelExpirationDuration      time.Duration
 	ModelCheckpointInterval      time.Duration
 	ModelCheckpointMaxPerStep    int
+	SeasonalSlotDuration         time.Duration
+	SeasonalForecastHorizon      time.Duration
 }
 
 func NewDefaultConfig() *Config {
@@ -44,6 +46,8 @@ func NewDefaultConfig() *Config {
 		ModelExpirationDuration:      30 * time.Minute,
 		ModelCheckpointInterval:      10 * time.Minute,
 		ModelCheckpointMaxPerStep:    12,
+		SeasonalSlotDuration:         15 * time.Minute,
+		SeasonalForecastHorizon:      4 * time.Hour,
 	}
 }
 
@@ -57,4 +61,6 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.ModelExpirationDuration, "prediction-model-expiration-duration", c.ModelExpirationDuration, "Expiration of prediction model without updated")
 	fs.DurationVar(&c.ModelCheckpointInterval, "prediction-model-checkpoint-interval", c.ModelCheckpointInterval, "Interval of prediction model take checkpoint")
 	fs.IntVar(&c.ModelCheckpointMaxPerStep, "prediction-model-checkpoint-max-per-step", c.ModelCheckpointMaxPerStep, "The maximum number of prediction models saved at a time")
+	fs.DurationVar(&c.SeasonalSlotDuration, "prediction-seasonal-slot-duration", c.SeasonalSlotDuration, "The time slot of the seasonal models, which fit the peak usage of each slot. It must divide 24h, otherwise 15m is used")
+	fs.DurationVar(&c.SeasonalForecastHorizon, "prediction-seasonal-forecast-horizon", c.SeasonalForecastHorizon, "How far the seasonal models look ahead, the forecast is the peak within the horizon")
 }
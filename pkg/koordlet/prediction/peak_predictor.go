@@ -40,6 +40,9 @@ type PredictorContext struct {
 const (
 	// ProdReclaimablePredictor represents the type of a reclaimable production predictor.
 	ProdReclaimablePredictor PredictorType = iota
+	// ProdSeasonalReclaimablePredictor represents the type of a reclaimable production predictor which looks ahead
+	// with the seasonal forecasts of the node-level usages, so the daily and weekly cycles are anticipated.
+	ProdSeasonalReclaimablePredictor
 )
 
 // PredictorFactory is an interface for creating predictors of different types.
@@ -95,6 +98,16 @@ func (f *predictorFactory) New(t PredictorType, context PredictorContext) Predic
 				priorityPredictor,
 			},
 		}
+	case ProdSeasonalReclaimablePredictor:
+		return &seasonalReclaimablePredictor{
+			priorityReclaimablePredictor: priorityReclaimablePredictor{
+				predictServer:         f.predictServer,
+				node:                  context.Node,
+				safetyMarginPercent:   f.safetyMarginPercent,
+				priorityClassFilterFn: isPriorityClassReclaimableForProd,
+				reclaimRequest:        util.NewZeroResourceList(),
+			},
+		}
 	default:
 		return &emptyPredictor{}
 	}
@@ -293,9 +306,15 @@ func (p *priorityReclaimablePredictor) AddPod(pod *v1.Pod) error {
 }
 
 func (p *priorityReclaimablePredictor) GetResult() (v1.ResourceList, error) {
+	return p.calculateReclaimable(p.GetPredictorName(), getPeakResource)
+}
+
+// calculateReclaimable calculates the reclaimable resources with the predictions picked by getResource.
+func (p *priorityReclaimablePredictor) calculateReclaimable(predictorName string,
+	getResource func(result Result) (v1.ResourceList, error)) (v1.ResourceList, error) {
 	// if failed to get node info, stop the reclaimPredictor
 	if p.node == nil {
-		return nil, fmt.Errorf("failed to get priorityReclaimablePredictor result for node is nil")
+		return nil, fmt.Errorf("failed to get %s result for node is nil", predictorName)
 	}
 	nodeAllocatable, err := getNodeAllocatable(p.node)
 	if err != nil {
@@ -306,11 +325,9 @@ func (p *priorityReclaimablePredictor) GetResult() (v1.ResourceList, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get prediction of sys, err: %w", err)
 	}
-	sysResultForCPU := sysResult.Data["p95"]
-	sysResultForMemory := sysResult.Data["p98"]
-	unReclaimable := v1.ResourceList{
-		v1.ResourceCPU:    *sysResultForCPU.Cpu(),
-		v1.ResourceMemory: *sysResultForMemory.Memory(),
+	unReclaimable, err := getResource(sysResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prediction of sys, err: %w", err)
 	}
 
 	// get reclaimable priority class prediction
@@ -324,11 +341,9 @@ func (p *priorityReclaimablePredictor) GetResult() (v1.ResourceList, error) {
 			return nil, fmt.Errorf("failed to get prediction of priority %s, err: %s", priorityClass, err)
 		}
 
-		resultForCPU := result.Data["p95"]
-		resultForMemory := result.Data["p98"]
-		predictResource := v1.ResourceList{
-			v1.ResourceCPU:    *resultForCPU.Cpu(),
-			v1.ResourceMemory: *resultForMemory.Memory(),
+		predictResource, err := getResource(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prediction of priority %s, err: %s", priorityClass, err)
 		}
 		unReclaimable = quotav1.Add(unReclaimable, predictResource)
 	}
@@ -345,11 +360,30 @@ func (p *priorityReclaimablePredictor) GetResult() (v1.ResourceList, error) {
 	// fixReclaimable[P] := min(nodeAllocatable[P]-unReclaimable[P],reclaimable[P])
 	fixReclaimable := quotav1.SubtractWithNonNegativeResult(nodeAllocatable, unReclaimable)
 	fixReclaimable = util.MinResourceList(fixReclaimable, reclaimable)
-	metrics.RecordNodePredictedResourceReclaimable(string(v1.ResourceCPU), metrics.UnitCore, p.GetPredictorName(), float64(fixReclaimable.Cpu().MilliValue())/1000)
-	metrics.RecordNodePredictedResourceReclaimable(string(v1.ResourceMemory), metrics.UnitByte, p.GetPredictorName(), float64(fixReclaimable.Memory().Value()))
+	metrics.RecordNodePredictedResourceReclaimable(string(v1.ResourceCPU), metrics.UnitCore, predictorName, float64(fixReclaimable.Cpu().MilliValue())/1000)
+	metrics.RecordNodePredictedResourceReclaimable(string(v1.ResourceMemory), metrics.UnitByte, predictorName, float64(fixReclaimable.Memory().Value()))
 	return fixReclaimable, nil
 }
 
+var _ Predictor = (*seasonalReclaimablePredictor)(nil)
+
+// seasonalReclaimablePredictor predicts like the priorityReclaimablePredictor, but it takes the seasonal forecasts
+// of the node priority resources and the system components, i.e. the peaks expected within the forecast horizon.
+// e.g. At 3am it does not reclaim the resources which the Prod-tier is expected to use in the 9am ramp.
+// It fails until the seasonal models have learned the daily cycle.
+type seasonalReclaimablePredictor struct {
+	priorityReclaimablePredictor
+}
+
+// GetPredictorName is used to obtain the predictor name.
+func (p *seasonalReclaimablePredictor) GetPredictorName() string {
+	return "seasonalReclaimablePredictor"
+}
+
+func (p *seasonalReclaimablePredictor) GetResult() (v1.ResourceList, error) {
+	return p.calculateReclaimable(p.GetPredictorName(), getForecastResource)
+}
+
 var _ Predictor = (*minPredictor)(nil)
 
 // minPredictor predicts the peak according to the minimal of the results of the sub-predictors.
@@ -405,6 +439,28 @@ func isPriorityClassReclaimableForProd(priorityClass extension.PriorityClass) bo
 	return priorityClass == extension.PriorityProd || priorityClass == extension.PriorityNone
 }
 
+// getPeakResource returns the p95 of the CPU and the p98 of the memory.
+func getPeakResource(result Result) (v1.ResourceList, error) {
+	resultForCPU := result.Data["p95"]
+	resultForMemory := result.Data["p98"]
+	return v1.ResourceList{
+		v1.ResourceCPU:    *resultForCPU.Cpu(),
+		v1.ResourceMemory: *resultForMemory.Memory(),
+	}, nil
+}
+
+// getForecastResource returns the seasonal forecast, which is missing until the seasonal models are ready.
+func getForecastResource(result Result) (v1.ResourceList, error) {
+	forecast, ok := result.Data[ForecastResultKey]
+	if !ok {
+		return nil, fmt.Errorf("seasonal forecast is not ready")
+	}
+	return v1.ResourceList{
+		v1.ResourceCPU:    *forecast.Cpu(),
+		v1.ResourceMemory: *forecast.Memory(),
+	}, nil
+}
+
 func getNodeAllocatable(node *v1.Node) (v1.ResourceList, error) {
 	res, err := extension.GetNodeRawAllocatable(node.Annotations)
 	if err == nil && res != nil {
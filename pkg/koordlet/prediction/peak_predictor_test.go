@@ -599,3 +599,89 @@ func Test_priorityReclaimablePredictor(t *testing.T) {
 		})
 	}
 }
+
+func Test_seasonalReclaimablePredictor(t *testing.T) {
+	newForecast := func(milliCPU, memory int64) Result {
+		return Result{
+			Data: map[string]v1.ResourceList{
+				"p95": testPredictionResult.Data["p95"],
+				"p98": testPredictionResult.Data["p98"],
+				ForecastResultKey: {
+					v1.ResourceCPU:    *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+					v1.ResourceMemory: *resource.NewQuantity(memory, resource.BinarySI),
+				},
+			},
+		}
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    *resource.NewMilliQuantity(3000, resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity(3*1024*1024*1024, resource.BinarySI),
+			},
+		},
+	}
+	podProd := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "prod-pod",
+			UID:  "pod-1-uid",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    *resource.NewMilliQuantity(2000, resource.DecimalSI),
+							v1.ResourceMemory: *resource.NewQuantity(2048*1024*1024, resource.BinarySI),
+						},
+					},
+				},
+			},
+			Priority: pointer.Int32(extension.PriorityProdValueMin),
+		},
+	}
+	tests := []struct {
+		name                string
+		results             map[UIDType]Result
+		expectedReclaimable v1.ResourceList
+		wantErr             bool
+	}{
+		{
+			name: "reclaim the requests not expected to use within the horizon",
+			results: map[UIDType]Result{
+				getNodeItemUID(string(extension.PriorityProd)): newForecast(1200, 1024*1024*1024),
+				getNodeItemUID(string(extension.PriorityNone)): newForecast(0, 0),
+				getNodeItemUID(SystemItemID):                   newForecast(300, 512*1024*1024),
+			},
+			expectedReclaimable: v1.ResourceList{
+				v1.ResourceCPU:    *resource.NewMilliQuantity(2000-1500, resource.DecimalSI),
+				v1.ResourceMemory: *resource.NewQuantity((2048-1536)*1024*1024, resource.BinarySI),
+			},
+		},
+		{
+			name: "failed when the forecast is not ready",
+			results: map[UIDType]Result{
+				getNodeItemUID(string(extension.PriorityProd)): testPredictionResult,
+				getNodeItemUID(string(extension.PriorityNone)): newForecast(0, 0),
+				getNodeItemUID(SystemItemID):                   newForecast(300, 512*1024*1024),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewPredictorFactory(&mockPredictServer{ResultMap: tt.results}, time.Hour, 0)
+			predictor := factory.New(ProdSeasonalReclaimablePredictor, PredictorContext{Node: node})
+			assert.Equal(t, "seasonalReclaimablePredictor", predictor.GetPredictorName())
+			assert.NoError(t, predictor.AddPod(podProd))
+			got, err := predictor.GetResult()
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.True(t, quotav1.Equals(tt.expectedReclaimable, got), got)
+			}
+		})
+	}
+}
@@ -59,7 +59,7 @@ this business logic should be processed when using the predicted data instead of
 to the predictive model.
 
 The predictive model currently provides histogram-based statistics with exponentially decaying
-weights over time periods. PredictServer is responsible for storing the intermediate results of
+weights over time periods, and seasonal forecasts with daily and weekly periods for the node-level items. PredictServer is responsible for storing the intermediate results of
 the model and recovering when the process restarts.
 */
 type PredictServer interface {
@@ -72,6 +72,9 @@ type PredictServer interface {
 type PredictModel struct {
 	CPU    histogram.Histogram
 	Memory histogram.Histogram
+	// CPUSeasonal and MemorySeasonal are only kept for the node-level items (node, priority classes and sys).
+	CPUSeasonal    *SeasonalModel
+	MemorySeasonal *SeasonalModel
 
 	LastUpdated      time.Time
 	LastCheckpointed time.Time
@@ -230,6 +233,10 @@ func (p *peakPredictServer) updateModel(uid UIDType, cpu, memory float64) {
 			CPU:    p.defaultCPUHistogram(),
 			Memory: p.defaultMemoryHistogram(),
 		}
+		if p.isNodeLevelUID(uid) {
+			model.CPUSeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+			model.MemorySeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+		}
 		p.models[uid] = model
 	}
 	now := p.clock.Now()
@@ -239,6 +246,23 @@ func (p *peakPredictServer) updateModel(uid UIDType, cpu, memory float64) {
 	// TODO Add adjusted weights
 	model.CPU.AddSample(cpu, 1, now)
 	model.Memory.AddSample(memory, 1, now)
+	if model.CPUSeasonal != nil && model.MemorySeasonal != nil {
+		model.CPUSeasonal.AddSample(cpu, now)
+		model.MemorySeasonal.AddSample(memory, now)
+	}
+}
+
+// isNodeLevelUID returns true if the uid is the node or a node item, whose usage has the seasonal models.
+func (p *peakPredictServer) isNodeLevelUID(uid UIDType) bool {
+	if uid == p.uidGenerator.Node() || uid == p.uidGenerator.NodeItem(SystemItemID) {
+		return true
+	}
+	for _, priorityClass := range extension.KnownPriorityClasses {
+		if uid == p.uidGenerator.NodeItem(string(priorityClass)) {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *peakPredictServer) GetPrediction(metric MetricDesc) (Result, error) {
@@ -251,7 +275,7 @@ func (p *peakPredictServer) GetPrediction(metric MetricDesc) (Result, error) {
 	model.Lock.Lock()
 	defer model.Lock.Unlock()
 	//
-	return Result{
+	result := Result{
 		Data: map[string]v1.ResourceList{
 			"p60": {
 				v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPU.Percentile(0.6)*1000.0), resource.DecimalSI),
@@ -274,7 +298,17 @@ func (p *peakPredictServer) GetPrediction(metric MetricDesc) (Result, error) {
 				v1.ResourceMemory: *resource.NewQuantity(int64(model.Memory.Percentile(1.0)), resource.BinarySI),
 			},
 		},
-	}, nil
+	}
+	// the forecast is only provided when the seasonal models have learned the daily cycle
+	if model.CPUSeasonal != nil && model.CPUSeasonal.IsReady() &&
+		model.MemorySeasonal != nil && model.MemorySeasonal.IsReady() {
+		now := p.clock.Now()
+		result.Data[ForecastResultKey] = v1.ResourceList{
+			v1.ResourceCPU:    *resource.NewMilliQuantity(int64(model.CPUSeasonal.Forecast(now, p.cfg.SeasonalForecastHorizon)*1000.0), resource.DecimalSI),
+			v1.ResourceMemory: *resource.NewQuantity(int64(model.MemorySeasonal.Forecast(now, p.cfg.SeasonalForecastHorizon)), resource.BinarySI),
+		}
+	}
+	return result, nil
 }
 
 func (p *peakPredictServer) gcModels() {
@@ -342,6 +376,10 @@ func (p *peakPredictServer) doCheckpoint() {
 		pair.Model.Lock.Lock()
 		ckpt.CPU, _ = pair.Model.CPU.SaveToCheckpoint()
 		ckpt.Memory, _ = pair.Model.Memory.SaveToCheckpoint()
+		if pair.Model.CPUSeasonal != nil && pair.Model.MemorySeasonal != nil {
+			ckpt.CPUSeasonal = pair.Model.CPUSeasonal.SaveToCheckpoint()
+			ckpt.MemorySeasonal = pair.Model.MemorySeasonal.SaveToCheckpoint()
+		}
 		pair.Model.Lock.Unlock()
 
 		err := p.checkpointer.Save(ckpt)
@@ -400,6 +438,21 @@ func (p *peakPredictServer) restoreModels() (unknownUIDs []UIDType) {
 		if err := model.Memory.LoadFromCheckpoint(checkpoint.Memory); err != nil {
 			klog.Errorf("failed to Memory checkpoint %v, err %v", checkpoint.UID, err)
 		}
+		if p.isNodeLevelUID(checkpoint.UID) {
+			model.CPUSeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+			model.MemorySeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+			// the seasonal models restart from scratch if the checkpoint is missing or mismatched
+			if checkpoint.CPUSeasonal != nil && checkpoint.MemorySeasonal != nil {
+				if err := model.CPUSeasonal.LoadFromCheckpoint(checkpoint.CPUSeasonal); err != nil {
+					klog.Errorf("failed to CPU seasonal checkpoint %v, err %v", checkpoint.UID, err)
+					model.CPUSeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+				}
+				if err := model.MemorySeasonal.LoadFromCheckpoint(checkpoint.MemorySeasonal); err != nil {
+					klog.Errorf("failed to Memory seasonal checkpoint %v, err %v", checkpoint.UID, err)
+					model.MemorySeasonal = NewSeasonalModel(p.cfg.SeasonalSlotDuration)
+				}
+			}
+		}
 		klog.InfoS("restoring checkpoint", "uid", checkpoint.UID, "lastUpdated", checkpoint.LastUpdated)
 		p.modelsLock.Lock()
 		p.models[checkpoint.UID] = model
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clock "k8s.io/utils/clock/testing"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/util/histogram"
 )
 
@@ -529,3 +530,69 @@ func TestDoCheckpoint_RestoreModels(t *testing.T) {
 	unknownUIDs := predictServer.restoreModels()
 	assert.Equal(t, 1, len(unknownUIDs), "unknown uids")
 }
+
+func TestPredictServerSeasonalForecast(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockClock := clock.NewFakeClock(now)
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod1",
+				UID:       "pod1",
+			},
+		},
+	}
+	predictServer := &peakPredictServer{
+		cfg:          NewDefaultConfig(),
+		hasSynced:    &atomic.Bool{},
+		informer:     &mockInformer{Pods: pods, Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}},
+		metricServer: &mockMetricServer{},
+		uidGenerator: &generator{},
+		clock:        mockClock,
+		models:       make(map[UIDType]*PredictModel),
+		checkpointer: NewFileCheckpointer(tempDir),
+	}
+	nodeUID := predictServer.uidGenerator.Node()
+	prodUID := predictServer.uidGenerator.NodeItem(string(extension.PriorityProd))
+
+	// the seasonal forecast is not ready in the first day
+	for i := 0; i < 12*60; i++ {
+		predictServer.updateModel(nodeUID, dailyUsage(mockClock.Now()), 1024*1024*1024)
+		predictServer.updateModel("pod1", 1, 1024*1024*1024)
+		mockClock.Step(time.Minute)
+	}
+	result, err := predictServer.GetPrediction(MetricDesc{UID: nodeUID})
+	assert.NoError(t, err)
+	_, ok := result.Data[ForecastResultKey]
+	assert.False(t, ok)
+
+	for i := 0; i < 2*24*60; i++ {
+		predictServer.updateModel(nodeUID, dailyUsage(mockClock.Now()), 1024*1024*1024)
+		predictServer.updateModel("pod1", 1, 1024*1024*1024)
+		mockClock.Step(time.Minute)
+	}
+	result, err = predictServer.GetPrediction(MetricDesc{UID: nodeUID})
+	assert.NoError(t, err)
+	forecast, ok := result.Data[ForecastResultKey]
+	assert.True(t, ok)
+	// the forecast is only kept for the node-level items
+	result, err = predictServer.GetPrediction(MetricDesc{UID: "pod1"})
+	assert.NoError(t, err)
+	_, ok = result.Data[ForecastResultKey]
+	assert.False(t, ok)
+	assert.True(t, predictServer.isNodeLevelUID(prodUID))
+	assert.False(t, predictServer.isNodeLevelUID("pod1"))
+
+	// the seasonal models are restored from the checkpoints
+	predictServer.hasSynced.Store(true)
+	predictServer.doCheckpoint()
+	predictServer.models = make(map[UIDType]*PredictModel)
+	unknownUIDs := predictServer.restoreModels()
+	assert.Empty(t, unknownUIDs)
+	result, err = predictServer.GetPrediction(MetricDesc{UID: nodeUID})
+	assert.NoError(t, err)
+	assert.Equal(t, forecast, result.Data[ForecastResultKey])
+	assert.Nil(t, predictServer.models["pod1"].CPUSeasonal)
+}
@@ -69,7 +69,8 @@ func getNodeItemUID(itemID string) UIDType {
 }
 
 type Result struct {
-	// Use different quantile type as key, currently support "p60", "p90", "p95" "p98", "max".
+	// Use different quantile type as key, currently support "p60", "p90", "p95" "p98", "max",
+	// and "forecast" for the node-level items whose seasonal models are ready.
 	Data map[string]v1.ResourceList
 }
 
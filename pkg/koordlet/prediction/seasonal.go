@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// ForecastResultKey is the key of the seasonal forecast in the prediction Result.
+	ForecastResultKey = "forecast"
+
+	seasonalDay  = 24 * time.Hour
+	seasonalWeek = 7 * seasonalDay
+
+	defaultSeasonalSlotDuration = 15 * time.Minute
+)
+
+var (
+	// smoothing factors of the level, trend, daily and weekly seasonal components and the deviation
+	seasonalLevelAlpha     = 0.05
+	seasonalTrendBeta      = 0.001
+	seasonalDailyGamma     = 0.3
+	seasonalWeeklyGamma    = 0.1
+	seasonalDeviationGamma = 0.05
+	// seasonalTrendDamping damps the trend when forecasting, so a short ramp is not extrapolated forever
+	seasonalTrendDamping = 0.9
+	// seasonalDeviationFactor is the times of the mean absolute deviation added to the forecast
+	seasonalDeviationFactor = 2.0
+)
+
+/*
+SeasonalModel is an additive Holt-Winters model with a daily and a weekly period, fitted on the per-slot peaks of
+a series (e.g. the peak usage in each 15 minutes). For the slot t, the one-step forecast is
+
+	y'(t) = level + trend + daily(t % slotsPerDay) + weekly(t % slotsPerWeek)
+
+and each component is corrected with the forecast error e = y(t) - y'(t). Unlike the decaying histogram which
+only knows the peaks of the recent window, it anticipates the daily and weekly cycles, e.g. the morning ramp
+after a quiet night.
+*/
+type SeasonalModel struct {
+	slotDuration time.Duration
+
+	level     float64
+	trend     float64
+	daily     []float64
+	weekly    []float64
+	deviation float64
+
+	// fittedSlots is the number of the slots fitted into the model.
+	fittedSlots int
+	// currentSlot is the slot receiving samples, and currentMax is the peak of its samples.
+	currentSlot int64
+	currentMax  float64
+	hasCurrent  bool
+}
+
+// SeasonalCheckpoint is the checkpoint of a SeasonalModel.
+type SeasonalCheckpoint struct {
+	SlotSeconds int64
+	Level       float64
+	Trend       float64
+	Daily       []float64
+	Weekly      []float64
+	Deviation   float64
+	FittedSlots int
+	CurrentSlot int64
+	CurrentMax  float64
+	HasCurrent  bool
+}
+
+// NewSeasonalModel creates a model with the slot duration, which must divide a day, otherwise the default 15 minutes
+// is used, so the slots of the same time of different days share the same daily component.
+func NewSeasonalModel(slotDuration time.Duration) *SeasonalModel {
+	if !isValidSeasonalSlot(slotDuration) {
+		slotDuration = defaultSeasonalSlotDuration
+	}
+	return &SeasonalModel{
+		slotDuration: slotDuration,
+		daily:        make([]float64, int(seasonalDay/slotDuration)),
+		weekly:       make([]float64, int(seasonalWeek/slotDuration)),
+	}
+}
+
+func isValidSeasonalSlot(slotDuration time.Duration) bool {
+	return slotDuration > 0 && slotDuration <= seasonalDay && seasonalDay%slotDuration == 0
+}
+
+func (m *SeasonalModel) slotOf(t time.Time) int64 {
+	return t.UnixNano() / int64(m.slotDuration)
+}
+
+func (m *SeasonalModel) seasonal(slot int64) float64 {
+	return m.daily[slot%int64(len(m.daily))] + m.weekly[slot%int64(len(m.weekly))]
+}
+
+// AddSample adds a sample observed at the time t. The samples older than the current slot are ignored.
+func (m *SeasonalModel) AddSample(value float64, t time.Time) {
+	slot := m.slotOf(t)
+	if !m.hasCurrent {
+		m.currentSlot, m.currentMax, m.hasCurrent = slot, value, true
+		return
+	}
+	if slot < m.currentSlot {
+		return
+	}
+	if slot == m.currentSlot {
+		m.currentMax = math.Max(m.currentMax, value)
+		return
+	}
+
+	m.fit(m.currentSlot, m.currentMax)
+	// the missing slots are skipped with their forecasts, and a gap longer than a week is skipped at once
+	missing := slot - m.currentSlot - 1
+	if missing > int64(len(m.weekly)) {
+		missing = int64(len(m.weekly))
+	}
+	for i := int64(0); i < missing; i++ {
+		m.level += m.trend
+		m.trend *= seasonalTrendDamping
+	}
+	m.currentSlot, m.currentMax = slot, value
+}
+
+func (m *SeasonalModel) fit(slot int64, value float64) {
+	if m.fittedSlots == 0 {
+		m.level = value
+		m.fittedSlots++
+		return
+	}
+	dailyIdx, weeklyIdx := slot%int64(len(m.daily)), slot%int64(len(m.weekly))
+	err := value - (m.level + m.trend + m.daily[dailyIdx] + m.weekly[weeklyIdx])
+	m.level += m.trend + seasonalLevelAlpha*err
+	m.trend = seasonalTrendDamping*m.trend + seasonalTrendBeta*err
+	m.level += addSeasonal(m.daily, dailyIdx, seasonalDailyGamma*err)
+	m.level += addSeasonal(m.weekly, weeklyIdx, seasonalWeeklyGamma*err)
+	m.deviation = (1-seasonalDeviationGamma)*m.deviation + seasonalDeviationGamma*math.Abs(err)
+	m.fittedSlots++
+}
+
+// addSeasonal adds the delta to the seasonal component of the index, and keeps the mean of the seasonal components
+// as zero, so they do not drift with the level. It returns the mean moved into the level.
+func addSeasonal(seasonal []float64, idx int64, delta float64) float64 {
+	seasonal[idx] += delta
+	mean := delta / float64(len(seasonal))
+	for i := range seasonal {
+		seasonal[i] -= mean
+	}
+	return mean
+}
+
+// IsReady returns true if the model has fitted at least one day, so the daily cycle is known.
+func (m *SeasonalModel) IsReady() bool {
+	return m.fittedSlots >= len(m.daily)
+}
+
+// Forecast returns the max of the forecasts of the slots from now until the horizon, with the deviation added.
+// The peak observed in the current slot is also counted.
+func (m *SeasonalModel) Forecast(now time.Time, horizon time.Duration) float64 {
+	if !m.hasCurrent {
+		return 0
+	}
+	start := m.slotOf(now)
+	if start < m.currentSlot {
+		start = m.currentSlot
+	}
+	end := m.slotOf(now.Add(horizon))
+	if end < start {
+		end = start
+	}
+
+	peak := m.currentMax
+	level, trend := m.level, m.trend
+	// the level is at the end of the last fitted slot, which is the current slot - 1
+	for slot := m.currentSlot; slot <= end; slot++ {
+		level += trend
+		trend *= seasonalTrendDamping
+		if slot < start {
+			continue
+		}
+		peak = math.Max(peak, level+m.seasonal(slot)+seasonalDeviationFactor*m.deviation)
+	}
+	return math.Max(peak, 0)
+}
+
+func (m *SeasonalModel) SaveToCheckpoint() *SeasonalCheckpoint {
+	return &SeasonalCheckpoint{
+		SlotSeconds: int64(m.slotDuration / time.Second),
+		Level:       m.level,
+		Trend:       m.trend,
+		Daily:       append([]float64(nil), m.daily...),
+		Weekly:      append([]float64(nil), m.weekly...),
+		Deviation:   m.deviation,
+		FittedSlots: m.fittedSlots,
+		CurrentSlot: m.currentSlot,
+		CurrentMax:  m.currentMax,
+		HasCurrent:  m.hasCurrent,
+	}
+}
+
+func (m *SeasonalModel) LoadFromCheckpoint(checkpoint *SeasonalCheckpoint) error {
+	if checkpoint == nil {
+		return fmt.Errorf("seasonal checkpoint is nil")
+	}
+	if time.Duration(checkpoint.SlotSeconds)*time.Second != m.slotDuration {
+		return fmt.Errorf("seasonal checkpoint slot %ds mismatches the model slot %v", checkpoint.SlotSeconds, m.slotDuration)
+	}
+	if len(checkpoint.Daily) != len(m.daily) || len(checkpoint.Weekly) != len(m.weekly) {
+		return fmt.Errorf("seasonal checkpoint periods mismatch, daily %d, weekly %d",
+			len(checkpoint.Daily), len(checkpoint.Weekly))
+	}
+	m.level = checkpoint.Level
+	m.trend = checkpoint.Trend
+	copy(m.daily, checkpoint.Daily)
+	copy(m.weekly, checkpoint.Weekly)
+	m.deviation = checkpoint.Deviation
+	m.fittedSlots = checkpoint.FittedSlots
+	m.currentSlot = checkpoint.CurrentSlot
+	m.currentMax = checkpoint.CurrentMax
+	m.hasCurrent = checkpoint.HasCurrent
+	return nil
+}
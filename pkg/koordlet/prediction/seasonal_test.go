@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dailyUsage is 2 in the night and 10 during the day from 9am to 6pm
+func dailyUsage(t time.Time) float64 {
+	if hour := t.UTC().Hour(); hour >= 9 && hour < 18 {
+		return 10
+	}
+	return 2
+}
+
+func trainSeasonalModel(m *SeasonalModel, start time.Time, duration time.Duration) time.Time {
+	t := start
+	for ; t.Before(start.Add(duration)); t = t.Add(time.Minute) {
+		m.AddSample(dailyUsage(t), t)
+	}
+	return t
+}
+
+func TestSeasonalModel(t *testing.T) {
+	m := NewSeasonalModel(15 * time.Minute)
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.False(t, m.IsReady())
+	assert.Equal(t, float64(0), m.Forecast(start, time.Hour))
+
+	now := trainSeasonalModel(m, start, 12*time.Hour)
+	assert.False(t, m.IsReady())
+	now = trainSeasonalModel(m, now, 14*24*time.Hour+15*time.Hour)
+	assert.True(t, m.IsReady())
+	assert.Equal(t, 3, now.UTC().Hour())
+
+	// at 3am, the usage is expected to keep low in the next hour
+	nightForecast := m.Forecast(now, time.Hour)
+	assert.Less(t, nightForecast, 4.0)
+	assert.GreaterOrEqual(t, nightForecast, 2.0)
+	// but the morning ramp is anticipated if the horizon covers 9am
+	rampForecast := m.Forecast(now, 7*time.Hour)
+	assert.Greater(t, rampForecast, 9.0)
+	assert.Less(t, rampForecast, 14.0)
+
+	// the forecast at noon is at least the current peak
+	noon := trainSeasonalModel(m, now, 9*time.Hour)
+	assert.GreaterOrEqual(t, m.Forecast(noon, time.Hour), 10.0)
+
+	// the old samples are ignored
+	m.AddSample(100, start)
+	assert.Less(t, m.Forecast(noon, time.Hour), 14.0)
+}
+
+func TestSeasonalModelGap(t *testing.T) {
+	m := NewSeasonalModel(15 * time.Minute)
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	now := trainSeasonalModel(m, start, 2*24*time.Hour)
+	fitted := m.fittedSlots
+	// the gap longer than a week is skipped at once
+	m.AddSample(5, now.Add(30*24*time.Hour))
+	assert.Equal(t, fitted+1, m.fittedSlots)
+	assert.True(t, m.IsReady())
+}
+
+func TestSeasonalModelCheckpoint(t *testing.T) {
+	m := NewSeasonalModel(15 * time.Minute)
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	now := trainSeasonalModel(m, start, 3*24*time.Hour)
+
+	data, err := json.Marshal(m.SaveToCheckpoint())
+	assert.NoError(t, err)
+	checkpoint := &SeasonalCheckpoint{}
+	assert.NoError(t, json.Unmarshal(data, checkpoint))
+
+	restored := NewSeasonalModel(15 * time.Minute)
+	assert.NoError(t, restored.LoadFromCheckpoint(checkpoint))
+	assert.Equal(t, m.IsReady(), restored.IsReady())
+	assert.Equal(t, m.Forecast(now, 8*time.Hour), restored.Forecast(now, 8*time.Hour))
+
+	// the checkpoint of a different slot is rejected
+	mismatched := NewSeasonalModel(30 * time.Minute)
+	assert.Error(t, mismatched.LoadFromCheckpoint(checkpoint))
+	assert.Error(t, mismatched.LoadFromCheckpoint(nil))
+	assert.False(t, mismatched.IsReady())
+}
+
+func TestNewSeasonalModel(t *testing.T) {
+	tests := []struct {
+		name         string
+		slotDuration time.Duration
+		wantSlot     time.Duration
+	}{
+		{name: "valid slot", slotDuration: 30 * time.Minute, wantSlot: 30 * time.Minute},
+		{name: "a day", slotDuration: 24 * time.Hour, wantSlot: 24 * time.Hour},
+		{name: "non-positive slot", slotDuration: 0, wantSlot: 15 * time.Minute},
+		{name: "slot longer than a day", slotDuration: 48 * time.Hour, wantSlot: 15 * time.Minute},
+		{name: "slot not dividing a day", slotDuration: 7 * time.Minute, wantSlot: 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewSeasonalModel(tt.slotDuration)
+			assert.Equal(t, tt.wantSlot, m.slotDuration)
+			assert.Equal(t, int(24*time.Hour/tt.wantSlot), len(m.daily))
+			assert.Equal(t, 7*len(m.daily), len(m.weekly))
+		})
+	}
+}
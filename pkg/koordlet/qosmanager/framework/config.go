@@ -28,6 +28,7 @@ type Config struct {
 	MemoryEvictCoolTimeSeconds int
 	CPUEvictCoolTimeSeconds    int
 	OnlyEvictByAPI             bool
+	ResctrlMBCapacityGbps      float64
 	QOSExtensionCfg            *QOSExtensionConfig
 }
 
@@ -52,5 +53,6 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
 	fs.BoolVar(&c.OnlyEvictByAPI, "only-evict-by-api", c.OnlyEvictByAPI, "only evict pod if call eviction api successed")
+	fs.Float64Var(&c.ResctrlMBCapacityGbps, "resctrl-mb-capacity-gbps", c.ResctrlMBCapacityGbps, "the memory bandwidth capacity in Gbps of each L3 cache for the dynamic resctrl policy on non-AMD nodes, the memory bandwidth pressure is not checked if it is 0")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
@@ -49,6 +49,7 @@ func Test_InitFlags(t *testing.T) {
 		"--cpu-evict-cool-time-seconds=40",
 		"--qos-extension-plugins=test-plugin=true",
 		"--only-evict-by-api=false",
+		"--resctrl-mb-capacity-gbps=100",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -60,6 +61,7 @@ func Test_InitFlags(t *testing.T) {
 		MemoryEvictCoolTimeSeconds int
 		CPUEvictCoolTimeSeconds    int
 		OnlyEvictByAPI             bool
+		ResctrlMBCapacityGbps      float64
 		QOSExtensionCfg            *QOSExtensionConfig
 	}
 	type args struct {
@@ -80,6 +82,7 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictCoolTimeSeconds: 8,
 				CPUEvictCoolTimeSeconds:    40,
 				OnlyEvictByAPI:             false,
+				ResctrlMBCapacityGbps:      100,
 				QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
 			},
 			args: args{fs: fs},
@@ -95,6 +98,7 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
 				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
 				OnlyEvictByAPI:             tt.fields.OnlyEvictByAPI,
+				ResctrlMBCapacityGbps:      tt.fields.ResctrlMBCapacityGbps,
 				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
 			}
 			c := NewDefaultConfig()
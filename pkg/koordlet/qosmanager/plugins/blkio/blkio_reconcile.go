@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
@@ -41,6 +42,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	koordutil "github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -199,16 +201,21 @@ func (b *blkIOReconcile) reconcile() {
 		}
 		var err error
 		podBlkIOQoS := &slov1alpha1.BlkIOQOS{}
-		if podVolumeResult, exist := podMeta.Pod.Annotations[slov1alpha1.AnnotationPodBlkioQoS]; exist {
+		podVolumeResult, hasBlkIOQoS := podMeta.Pod.Annotations[slov1alpha1.AnnotationPodBlkioQoS]
+		if hasBlkIOQoS {
 			if podVolumeResult != "" {
 				if podBlkIOQoS, err = parseBlkIOResult(podVolumeResult); err != nil {
 					klog.Errorf("%s: unmarshal pod annotation %v failed, error %v", BlkIOReconcileName, slov1alpha1.AnnotationPodBlkioQoS, err)
 					continue
 				}
 			}
-		} else {
+		}
+		// the pod is limited by its requested disk io bandwidth even if its blkio qos is specified
+		requestBlocks := b.getPodRequestBlocks(podMeta, strategy)
+		if !hasBlkIOQoS && len(requestBlocks) <= 0 {
 			continue
 		}
+		podBlkIOQoS.Blocks = b.mergePodRequestBlocks(podBlkIOQoS.Blocks, requestBlocks, podMeta)
 		klog.V(4).Infof("%s: start to reconcile pod %s/%s blkio config", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name)
 		err = b.updateBlkIOConfig(
 			podBlkIOQoS.Blocks,
@@ -417,6 +424,117 @@ func (b *blkIOReconcile) getDiskNumberFromBlockCfg(block *slov1alpha1.BlockCfg,
 	return diskNumber, nil
 }
 
+// getPodRequestBlocks generates the blocks which limit the read and write bps of the pod to its requested disk io
+// bandwidth each, since the requested bandwidth is per direction and the node capacity is the smaller one of the
+// read and write bps of the disks. The pod is limited on the disks of its pvc and csi ephemeral volumes, or on the disks of the node's
+// disk io bandwidth model if none of its volumes is on a local disk.
+func (b *blkIOReconcile) getPodRequestBlocks(podMeta *statesinformer.PodMeta, strategy *slov1alpha1.ResourceQOSStrategy) []*slov1alpha1.BlockCfg {
+	bandwidth := koordutil.GetPodDiskIOBandwidthRequest(podMeta.Pod)
+	if bandwidth <= 0 {
+		return nil
+	}
+	ioCfg := slov1alpha1.IOCfg{
+		ReadBPS:  pointer.Int64(bandwidth),
+		WriteBPS: pointer.Int64(bandwidth),
+	}
+
+	var blocks []*slov1alpha1.BlockCfg
+	diskNumbers := map[string]bool{}
+	for _, volume := range podMeta.Pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil && volume.CSI == nil {
+			continue
+		}
+		block := &slov1alpha1.BlockCfg{
+			Name:      volume.Name,
+			BlockType: slov1alpha1.BlockTypePodVolume,
+			IOCfg:     ioCfg,
+		}
+		diskNumber, err := b.getDiskNumberFromBlockCfg(block, podMeta)
+		if err != nil {
+			klog.V(5).Infof("%s: skip limiting pod %s/%s volume %s by requested disk io bandwidth: %s", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name, volume.Name, err.Error())
+			continue
+		}
+		// volumes on the same disk share the limit
+		if diskNumbers[diskNumber] {
+			continue
+		}
+		diskNumbers[diskNumber] = true
+		blocks = append(blocks, block)
+	}
+	if len(blocks) > 0 {
+		return blocks
+	}
+
+	for _, modelBlock := range koordutil.GetDiskIOBandwidthModelBlocks(strategy) {
+		blocks = append(blocks, &slov1alpha1.BlockCfg{
+			Name:      modelBlock.Name,
+			BlockType: modelBlock.BlockType,
+			IOCfg:     ioCfg,
+		})
+	}
+	return blocks
+}
+
+// mergePodRequestBlocks merges the blocks limiting the requested disk io bandwidth into the blkio qos blocks of the
+// pod. The bps on the same disk takes the smaller one of the configured and the requested, and the requested blocks
+// on the disks not configured are appended.
+func (b *blkIOReconcile) mergePodRequestBlocks(blocks, requestBlocks []*slov1alpha1.BlockCfg, podMeta *statesinformer.PodMeta) []*slov1alpha1.BlockCfg {
+	if len(requestBlocks) <= 0 {
+		return blocks
+	}
+	requestBlockMap := map[string]*slov1alpha1.BlockCfg{}
+	for _, block := range requestBlocks {
+		diskNumber, err := b.getDiskNumberFromBlockCfg(block, podMeta)
+		if err != nil {
+			klog.V(5).Infof("%s: skip merging the requested block %v of pod %s/%s: %s", BlkIOReconcileName, block, podMeta.Pod.Namespace, podMeta.Pod.Name, err.Error())
+			continue
+		}
+		if _, exist := requestBlockMap[diskNumber]; !exist {
+			requestBlockMap[diskNumber] = block
+		}
+	}
+
+	merged := make([]*slov1alpha1.BlockCfg, 0, len(blocks)+len(requestBlocks))
+	for _, block := range blocks {
+		diskNumber, err := b.getDiskNumberFromBlockCfg(block, podMeta)
+		requestBlock, exist := requestBlockMap[diskNumber]
+		if err != nil || !exist {
+			merged = append(merged, block)
+			continue
+		}
+		block = block.DeepCopy()
+		block.IOCfg.ReadBPS = minBPS(block.IOCfg.ReadBPS, requestBlock.IOCfg.ReadBPS)
+		block.IOCfg.WriteBPS = minBPS(block.IOCfg.WriteBPS, requestBlock.IOCfg.WriteBPS)
+		merged = append(merged, block)
+		delete(requestBlockMap, diskNumber)
+	}
+	for _, block := range requestBlocks {
+		diskNumber, err := b.getDiskNumberFromBlockCfg(block, podMeta)
+		if err != nil {
+			continue
+		}
+		if _, exist := requestBlockMap[diskNumber]; exist {
+			merged = append(merged, block)
+			delete(requestBlockMap, diskNumber)
+		}
+	}
+	return merged
+}
+
+// minBPS returns the smaller one of the bps limits, where nil or 0 means unlimited.
+func minBPS(a, b *int64) *int64 {
+	if a == nil || *a <= 0 {
+		return b
+	}
+	if b == nil || *b <= 0 {
+		return a
+	}
+	if *b < *a {
+		return b
+	}
+	return a
+}
+
 // configure cgroup root
 // dynamicPath for root: ""
 func getDiskConfigUpdaterFromBlockCfg(block *slov1alpha1.BlockCfg, diskNumber string, dynamicPath string) (resources []resourceexecutor.ResourceUpdater) {
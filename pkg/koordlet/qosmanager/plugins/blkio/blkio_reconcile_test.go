@@ -23,7 +23,9 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/utils/pointer"
@@ -188,6 +190,226 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 	})
 }
 
+func TestBlkIOReconcile_getPodRequestBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	var oldVarLibKubeletRoot string
+	helper.SetConf(func(conf *system.Config) {
+		oldVarLibKubeletRoot = conf.VarLibKubeletRootDir
+		conf.VarLibKubeletRootDir = KubePath
+	}, func(conf *system.Config) {
+		conf.VarLibKubeletRootDir = oldVarLibKubeletRoot
+	})
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetVolumeName("default", PVCName).Return(PVName).AnyTimes()
+	strategy := newNodeSLO().Spec.ResourceQOSStrategy
+	requestIOCfg := slov1alpha1.IOCfg{
+		ReadBPS:  pointer.Int64(10485760),
+		WriteBPS: pointer.Int64(10485760),
+	}
+	setRequest := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+			extension.BatchDiskIOBandwidth: resource.MustParse("10Mi"),
+		}
+		return pod
+	}
+
+	podWithoutRequest := newPodWithPVC(PodName0, PVCName)
+	// both of the pvc and the ephemeral volume are on /dev/vdb
+	podWithVolumes := setRequest(newPodWithPVC(PodName1, PVCName))
+	podWithVolumes.Spec.Volumes = append(podWithVolumes.Spec.Volumes, corev1.Volume{
+		Name: "cache",
+		VolumeSource: corev1.VolumeSource{
+			CSI: &corev1.CSIVolumeSource{},
+		},
+	})
+	podWithoutVolume := setRequest(newPodWithEphemeralVolume(PodName2))
+	podWithoutVolume.Spec.Volumes = nil
+
+	b := &blkIOReconcile{
+		statesInformer: statesInformer,
+		storageInfo: &metriccache.NodeLocalStorageInfo{
+			DiskNumberMap: map[string]string{
+				"/dev/vdb": "253:16",
+			},
+			VGDiskMap: map[string]string{
+				"yoda-pool0": "/dev/vdb",
+			},
+			LVMapperVGMap: map[string]string{
+				"/dev/mapper/yoda--pool0-yoda--87d8625a--dcc9--47bf--a14a--994cf2971193": "yoda-pool0",
+				"/dev/mapper/yoda--pool0-yoda--test1":                                    "yoda-pool0",
+			},
+			MPDiskMap: map[string]string{
+				fmt.Sprintf("%s/pods/%s/volumes/kubernetes.io~csi/%s/mount", KubePath, podWithVolumes.UID, PVName): "/dev/mapper/yoda--pool0-yoda--87d8625a--dcc9--47bf--a14a--994cf2971193",
+				fmt.Sprintf("%s/pods/%s/volumes/kubernetes.io~csi/cache/mount", KubePath, podWithVolumes.UID):      "/dev/mapper/yoda--pool0-yoda--test1",
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want []*slov1alpha1.BlockCfg
+	}{
+		{
+			name: "pod without disk io bandwidth request",
+			pod:  podWithoutRequest,
+		},
+		{
+			name: "limit the disks of the pod volumes",
+			pod:  podWithVolumes,
+			want: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "html",
+					BlockType: slov1alpha1.BlockTypePodVolume,
+					IOCfg:     requestIOCfg,
+				},
+			},
+		},
+		{
+			name: "limit the disks of the node model",
+			pod:  podWithoutVolume,
+			want: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "/dev/vdb",
+					BlockType: slov1alpha1.BlockTypeDevice,
+					IOCfg:     requestIOCfg,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.getPodRequestBlocks(&statesinformer.PodMeta{Pod: tt.pod}, strategy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBlkIOReconcile_mergePodRequestBlocks(t *testing.T) {
+	b := &blkIOReconcile{
+		storageInfo: &metriccache.NodeLocalStorageInfo{
+			DiskNumberMap: map[string]string{
+				"/dev/vdb": "253:16",
+				"/dev/vdc": "253:32",
+			},
+			VGDiskMap: map[string]string{
+				"yoda-pool0": "/dev/vdb",
+			},
+		},
+	}
+	podMeta := &statesinformer.PodMeta{Pod: newPodWithEphemeralVolume(PodName0)}
+	requestBlocks := []*slov1alpha1.BlockCfg{
+		{
+			Name:      "/dev/vdb",
+			BlockType: slov1alpha1.BlockTypeDevice,
+			IOCfg: slov1alpha1.IOCfg{
+				ReadBPS:  pointer.Int64(10485760),
+				WriteBPS: pointer.Int64(10485760),
+			},
+		},
+		{
+			Name:      "/dev/vdc",
+			BlockType: slov1alpha1.BlockTypeDevice,
+			IOCfg: slov1alpha1.IOCfg{
+				ReadBPS:  pointer.Int64(10485760),
+				WriteBPS: pointer.Int64(10485760),
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		blocks        []*slov1alpha1.BlockCfg
+		requestBlocks []*slov1alpha1.BlockCfg
+		want          []*slov1alpha1.BlockCfg
+	}{
+		{
+			name: "no requested blocks",
+			blocks: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "yoda-pool0",
+					BlockType: slov1alpha1.BlockTypeVolumeGroup,
+					IOCfg:     slov1alpha1.IOCfg{ReadBPS: pointer.Int64(20971520)},
+				},
+			},
+			want: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "yoda-pool0",
+					BlockType: slov1alpha1.BlockTypeVolumeGroup,
+					IOCfg:     slov1alpha1.IOCfg{ReadBPS: pointer.Int64(20971520)},
+				},
+			},
+		},
+		{
+			name:          "no configured blocks",
+			requestBlocks: requestBlocks,
+			want:          requestBlocks,
+		},
+		{
+			name: "take the smaller bps on the same disk and append the disks not configured",
+			blocks: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "yoda-pool0",
+					BlockType: slov1alpha1.BlockTypeVolumeGroup,
+					IOCfg: slov1alpha1.IOCfg{
+						ReadIOPS: pointer.Int64(1000),
+						ReadBPS:  pointer.Int64(20971520),
+						WriteBPS: pointer.Int64(5242880),
+					},
+				},
+			},
+			requestBlocks: requestBlocks,
+			want: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "yoda-pool0",
+					BlockType: slov1alpha1.BlockTypeVolumeGroup,
+					IOCfg: slov1alpha1.IOCfg{
+						ReadIOPS: pointer.Int64(1000),
+						ReadBPS:  pointer.Int64(10485760),
+						WriteBPS: pointer.Int64(5242880),
+					},
+				},
+				requestBlocks[1],
+			},
+		},
+		{
+			name: "the unlimited bps takes the requested",
+			blocks: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "/dev/vdb",
+					BlockType: slov1alpha1.BlockTypeDevice,
+					IOCfg: slov1alpha1.IOCfg{
+						ReadBPS:         pointer.Int64(0),
+						IOWeightPercent: pointer.Int64(50),
+					},
+				},
+			},
+			requestBlocks: requestBlocks[:1],
+			want: []*slov1alpha1.BlockCfg{
+				{
+					Name:      "/dev/vdb",
+					BlockType: slov1alpha1.BlockTypeDevice,
+					IOCfg: slov1alpha1.IOCfg{
+						ReadBPS:         pointer.Int64(10485760),
+						WriteBPS:        pointer.Int64(10485760),
+						IOWeightPercent: pointer.Int64(50),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.mergePodRequestBlocks(tt.blocks, tt.requestBlocks, podMeta)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func newNodeSLO() *slov1alpha1.NodeSLO {
 	return &slov1alpha1.NodeSLO{
 		Spec: slov1alpha1.NodeSLOSpec{
@@ -173,6 +173,10 @@ type cpuBurst struct {
 	cgroupReader          resourceexecutor.CgroupReader
 	nodeCPUBurstStrategy  *slov1alpha1.CPUBurstStrategy
 	containerLimiter      map[string]*burstLimiter
+	// adaptiveBurstCores is the burst cores of the containers in adaptive policy applied in the last round
+	adaptiveBurstCores map[string]float64
+	// adaptiveBurstVals is the cfs burst value of the containers in adaptive policy in the current round
+	adaptiveBurstVals map[string]int64
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -184,6 +188,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		executor:              resourceexecutor.NewResourceUpdateExecutor(),
 		cgroupReader:          opt.CgroupReader,
 		containerLimiter:      make(map[string]*burstLimiter),
+		adaptiveBurstCores:    make(map[string]float64),
 	}
 }
 
@@ -221,6 +226,7 @@ func (b *cpuBurst) start() {
 	nodeState := b.getNodeStateForBurst(*b.nodeCPUBurstStrategy.SharePoolThresholdPercent, podsMeta)
 	klog.V(5).Infof("get node state %v for cpu burst", nodeState)
 
+	burstPods := make([]podBurstConfig, 0, len(podsMeta))
 	for _, podMeta := range podsMeta {
 		if podMeta == nil || podMeta.Pod == nil {
 			klog.Warningf("podMeta is illegal, detail %v", podMeta)
@@ -246,10 +252,16 @@ func (b *cpuBurst) start() {
 			continue
 		}
 		klog.V(5).Infof("get pod %v/%v cpu burst config: %v", podMeta.Pod.Namespace, podMeta.Pod.Name, cpuBurstCfg)
+		burstPods = append(burstPods, podBurstConfig{podMeta: podMeta, burstCfg: cpuBurstCfg})
+	}
+
+	// size cpu.cfs_burst_us for the containers in adaptive policy as a whole, since they share the node budget
+	b.adaptiveBurstVals = b.planAdaptiveCPUBurst(burstPods, b.nodeCPUBurstStrategy.NodeBurstBudgetPercent)
+	for _, burstPod := range burstPods {
 		// set cpu.cfs_burst_us for pod and containers
-		b.applyCPUBurst(cpuBurstCfg, podMeta)
+		b.applyCPUBurst(burstPod.burstCfg, burstPod.podMeta)
 		// scale cpu.cfs_quota_us for pod and containers
-		b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState)
+		b.applyCFSQuotaBurst(burstPod.burstCfg, burstPod.podMeta, nodeState)
 	}
 	b.Recycle()
 }
@@ -587,7 +599,12 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 			continue
 		}
 
-		containerCFSBurstVal := calcStaticCPUBurstVal(container, burstCfg)
+		var containerCFSBurstVal int64
+		if burstCfg.Policy == slov1alpha1.CPUBurstAdaptive {
+			containerCFSBurstVal = b.adaptiveBurstVals[containerStat.ContainerID]
+		} else {
+			containerCFSBurstVal = calcStaticCPUBurstVal(container, burstCfg)
+		}
 		containerDir, burstPathErr := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
 		if burstPathErr != nil {
 			klog.Warningf("get container dir %s/%s/%s failed, dir %v, error %v",
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuburst
+
+import (
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var (
+	// adaptiveBurstWindow is the window of the throttling and usage history to size the adaptive burst
+	adaptiveBurstWindow = 5 * time.Minute
+	// adaptiveThrottledRatioCeil is the average throttled ratio where a container desires the maximum burst
+	adaptiveThrottledRatioCeil = 0.2
+	// adaptiveUsageSpikeRatioFloor is the ratio of the peak usage to the limit, above which a container desires burst
+	adaptiveUsageSpikeRatioFloor = 0.8
+	// adaptiveBurstDecreaseStep shrinks the burst of a container gradually when its demand drops
+	adaptiveBurstDecreaseStep = 0.8
+	// adaptiveBurstMinCores is the minimum burst of a container, the smaller burst is reset as 0
+	adaptiveBurstMinCores = 0.01
+)
+
+// podBurstConfig is the pod and its merged burst config
+type podBurstConfig struct {
+	podMeta  *statesinformer.PodMeta
+	burstCfg *slov1alpha1.CPUBurstConfig
+}
+
+// adaptiveBurstContainer is a container whose cpu.cfs_burst_us is sized by the adaptive policy
+type adaptiveBurstContainer struct {
+	pod           *corev1.Pod
+	containerStat *corev1.ContainerStatus
+	desiredCores  float64
+}
+
+// planAdaptiveCPUBurst calculates the cpu.cfs_burst_us of the containers in the adaptive policy.
+// Each container desires a burst in [0, limit * cpuBurstPercent] according to its throttled ratio and usage spikes
+// in the recent window, and the desired bursts are scaled down proportionally if the total exceeds the node budget
+// or the node cpu headroom. It returns the cfs burst value of each container id.
+func (b *cpuBurst) planAdaptiveCPUBurst(burstPods []podBurstConfig, nodeBurstBudgetPercent *int64) map[string]int64 {
+	end := time.Now()
+	querier, err := b.metricCache.Querier(end.Add(-adaptiveBurstWindow), end)
+	if err != nil {
+		klog.V(4).Infof("failed to get querier for adaptive cpu burst, err: %v", err)
+	} else {
+		defer querier.Close()
+	}
+
+	var containers []*adaptiveBurstContainer
+	totalDesiredCores := 0.0
+	for _, burstPod := range burstPods {
+		if burstPod.burstCfg.Policy != slov1alpha1.CPUBurstAdaptive || burstPod.burstCfg.CPUBurstPercent == nil {
+			continue
+		}
+		pod := burstPod.podMeta.Pod
+		containerMap := make(map[string]*corev1.Container)
+		for i := range pod.Spec.Containers {
+			container := &pod.Spec.Containers[i]
+			containerMap[container.Name] = container
+		}
+		for i := range pod.Status.ContainerStatuses {
+			containerStat := &pod.Status.ContainerStatuses[i]
+			container, exist := containerMap[containerStat.Name]
+			if !exist || container == nil || containerStat.ContainerID == "" {
+				continue
+			}
+			containerCPUMilliLimit := util.GetContainerMilliCPULimit(container)
+			if containerCPUMilliLimit <= 0 {
+				continue
+			}
+			limitCores := float64(containerCPUMilliLimit) / 1000
+			maxBurstCores := limitCores * float64(*burstPod.burstCfg.CPUBurstPercent) / 100
+			desiredCores := maxBurstCores * getAdaptiveBurstDemand(querier, containerStat.ContainerID, limitCores)
+			// shrink gradually to avoid throttling the container again at once
+			if lastCores, ok := b.adaptiveBurstCores[containerStat.ContainerID]; ok && desiredCores < lastCores {
+				desiredCores = math.Max(desiredCores, lastCores*adaptiveBurstDecreaseStep)
+			}
+			if desiredCores < adaptiveBurstMinCores {
+				desiredCores = 0
+			}
+			metrics.RecordContainerDesiredCFSBurstUS(pod.Namespace, pod.Name, containerStat.ContainerID,
+				containerStat.Name, desiredCores*float64(system.CFSBasePeriodValue))
+			containers = append(containers, &adaptiveBurstContainer{
+				pod:           pod,
+				containerStat: containerStat,
+				desiredCores:  desiredCores,
+			})
+			totalDesiredCores += desiredCores
+		}
+	}
+	// clean up the metrics of the containers no longer in the adaptive policy, e.g. deleted
+	containerIDs := make(map[string]struct{}, len(containers))
+	for _, c := range containers {
+		containerIDs[c.containerStat.ContainerID] = struct{}{}
+	}
+	for containerID := range b.adaptiveBurstCores {
+		if _, ok := containerIDs[containerID]; !ok {
+			metrics.ResetContainerDesiredCFSBurstUS(containerID)
+		}
+	}
+	b.adaptiveBurstCores = make(map[string]float64, len(containers))
+	if len(containers) == 0 {
+		return nil
+	}
+
+	nodeLimitCores := b.getAdaptiveBurstNodeLimit(nodeBurstBudgetPercent)
+	scale := 1.0
+	if totalDesiredCores > nodeLimitCores {
+		scale = nodeLimitCores / totalDesiredCores
+	}
+	klog.V(5).Infof("adaptive cpu burst desired %v cores, node limit %v cores, scale %v",
+		totalDesiredCores, nodeLimitCores, scale)
+
+	burstVals := make(map[string]int64, len(containers))
+	totalAllocatedCores := 0.0
+	for _, c := range containers {
+		allocatedCores := c.desiredCores * scale
+		b.adaptiveBurstCores[c.containerStat.ContainerID] = allocatedCores
+		burstVals[c.containerStat.ContainerID] = int64(allocatedCores * float64(system.CFSBasePeriodValue))
+		totalAllocatedCores += allocatedCores
+		klog.V(6).Infof("adaptive cpu burst for container %s/%s/%s, desired %v cores, allocated %v cores",
+			c.pod.Namespace, c.pod.Name, c.containerStat.Name, c.desiredCores, allocatedCores)
+	}
+	metrics.RecordNodeCPUBurstCores(metrics.CPUBurstCoresDesired, totalDesiredCores)
+	metrics.RecordNodeCPUBurstCores(metrics.CPUBurstCoresAllocated, totalAllocatedCores)
+	return burstVals
+}
+
+// getAdaptiveBurstDemand returns the burst demand of the container in [0, 1], which is the larger one of the
+// average throttled ratio and the peak usage against the limit in the recent window.
+func getAdaptiveBurstDemand(querier metriccache.Querier, containerID string, limitCores float64) float64 {
+	if querier == nil {
+		return 0
+	}
+	demand := 0.0
+	properties := metriccache.MetricPropertiesFunc.Container(containerID)
+	if throttled, err := helpers.Query(querier, metriccache.ContainerCPUThrottledMetric, properties); err != nil {
+		klog.V(5).Infof("failed to query container %s throttled metric, err: %v", containerID, err)
+	} else if throttled.Count() > 0 {
+		throttledRatio, err := throttled.Value(metriccache.AggregationTypeAVG)
+		if err == nil {
+			demand = math.Max(demand, throttledRatio/adaptiveThrottledRatioCeil)
+		}
+	}
+	if usage, err := helpers.Query(querier, metriccache.ContainerCPUUsageMetric, properties); err != nil {
+		klog.V(5).Infof("failed to query container %s cpu usage metric, err: %v", containerID, err)
+	} else if usage.Count() > 0 {
+		peakUsage, err := usage.Value(metriccache.AggregationTypeP99)
+		if err == nil {
+			spike := (peakUsage/limitCores - adaptiveUsageSpikeRatioFloor) / (1 - adaptiveUsageSpikeRatioFloor)
+			demand = math.Max(demand, spike)
+		}
+	}
+	return math.Min(math.Max(demand, 0), 1)
+}
+
+// getAdaptiveBurstNodeLimit returns the total burst cores allowed on the node, which is the minimum of the node burst
+// budget and the idle cpu cores of the node. The headroom is ignored if the node usage is unknown.
+func (b *cpuBurst) getAdaptiveBurstNodeLimit(nodeBurstBudgetPercent *int64) float64 {
+	nodeCPUInfoRaw, exist := b.metricCache.Get(metriccache.NodeCPUInfoKey)
+	if !exist || nodeCPUInfoRaw == nil {
+		klog.Warning("get node cpu info failed for adaptive cpu burst, allow no burst")
+		return 0
+	}
+	nodeCPUInfo, ok := nodeCPUInfoRaw.(*metriccache.NodeCPUInfo)
+	if !ok || nodeCPUInfo == nil {
+		klog.Warning("get node cpu info failed for adaptive cpu burst, allow no burst")
+		return 0
+	}
+	nodeCPUCores := float64(len(nodeCPUInfo.ProcessorInfos))
+
+	budgetPercent := int64(100)
+	if nodeBurstBudgetPercent != nil {
+		budgetPercent = *nodeBurstBudgetPercent
+	}
+	budgetCores := nodeCPUCores * float64(budgetPercent) / 100
+	metrics.RecordNodeCPUBurstCores(metrics.CPUBurstCoresBudget, budgetCores)
+
+	queryParam := helpers.GenerateQueryParamsAvg(b.metricCollectInterval * 2)
+	queryMeta, err := metriccache.NodeCPUUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("get node metric queryMeta failed, error: %v", err)
+		return budgetCores
+	}
+	queryResult, err := helpers.CollectNodeMetrics(b.metricCache, *queryParam.Start, *queryParam.End, queryMeta)
+	if err != nil || queryResult.Count() == 0 {
+		klog.V(4).Infof("node cpu usage is unknown for adaptive cpu burst, err: %v", err)
+		return budgetCores
+	}
+	nodeCPUUsed, err := queryResult.Value(queryParam.Aggregate)
+	if err != nil {
+		klog.V(4).Infof("node cpu usage is unknown for adaptive cpu burst, err: %v", err)
+		return budgetCores
+	}
+	headroomCores := math.Max(nodeCPUCores-nodeCPUUsed, 0)
+	metrics.RecordNodeCPUBurstCores(metrics.CPUBurstCoresHeadroom, headroomCores)
+	return math.Min(budgetCores, headroomCores)
+}
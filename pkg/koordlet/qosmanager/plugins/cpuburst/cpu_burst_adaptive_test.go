@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuburst
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+// the other tests replace the aggregate result factory with mocks
+var defaultAggregateResultFactory = metriccache.DefaultAggregateResultFactory
+
+func TestCPUBurst_planAdaptiveCPUBurst(t *testing.T) {
+	adaptiveBurstCfg := &slov1alpha1.CPUBurstConfig{
+		Policy:          slov1alpha1.CPUBurstAdaptive,
+		CPUBurstPercent: pointer.Int64(100),
+	}
+	// throttled for 10% periods, desires 50% of the max burst
+	throttledPod := newTestPodWithQOS("throttled-pod", apiext.QoSLS, 2000, 0)
+	// usage reaches the limit, desires the max burst
+	spikyPod := newTestPodWithQOS("spiky-pod", apiext.QoSLS, 1000, 0)
+	// neither throttled nor spiky
+	idlePod := newTestPodWithQOS("idle-pod", apiext.QoSLS, 1000, 0)
+	// in static policy
+	staticPod := newTestPodWithQOS("static-pod", apiext.QoSLS, 1000, 0)
+	burstPods := []podBurstConfig{
+		{podMeta: &statesinformer.PodMeta{Pod: throttledPod}, burstCfg: adaptiveBurstCfg},
+		{podMeta: &statesinformer.PodMeta{Pod: spikyPod}, burstCfg: adaptiveBurstCfg},
+		{podMeta: &statesinformer.PodMeta{Pod: idlePod}, burstCfg: adaptiveBurstCfg},
+		{podMeta: &statesinformer.PodMeta{Pod: staticPod}, burstCfg: &defaultAutoBurstCfg},
+	}
+	throttledContainerID := genTestDefaultContainerIDByPod(throttledPod.Name)
+	spikyContainerID := genTestDefaultContainerIDByPod(spikyPod.Name)
+	idleContainerID := genTestDefaultContainerIDByPod(idlePod.Name)
+
+	tests := []struct {
+		name                   string
+		nodeCPUUsed            *float64
+		nodeBurstBudgetPercent *int64
+		lastBurstCores         map[string]float64
+		want                   map[string]int64
+	}{
+		{
+			name:        "desired bursts within node headroom",
+			nodeCPUUsed: pointer.Float64(14),
+			want: map[string]int64{
+				throttledContainerID: 100000,
+				spikyContainerID:     100000,
+				idleContainerID:      0,
+			},
+		},
+		{
+			name:        "desired bursts scaled by node headroom",
+			nodeCPUUsed: pointer.Float64(15),
+			want: map[string]int64{
+				throttledContainerID: 50000,
+				spikyContainerID:     50000,
+				idleContainerID:      0,
+			},
+		},
+		{
+			name:                   "desired bursts scaled by node budget when node usage unknown",
+			nodeBurstBudgetPercent: pointer.Int64(6),
+			want: map[string]int64{
+				throttledContainerID: 48000,
+				spikyContainerID:     48000,
+				idleContainerID:      0,
+			},
+		},
+		{
+			name:                   "no burst allowed with zero budget",
+			nodeCPUUsed:            pointer.Float64(1),
+			nodeBurstBudgetPercent: pointer.Int64(0),
+			want: map[string]int64{
+				throttledContainerID: 0,
+				spikyContainerID:     0,
+				idleContainerID:      0,
+			},
+		},
+		{
+			name:        "burst of idle container shrinks gradually",
+			nodeCPUUsed: pointer.Float64(10),
+			lastBurstCores: map[string]float64{
+				idleContainerID: 1,
+			},
+			want: map[string]int64{
+				throttledContainerID: 100000,
+				spikyContainerID:     100000,
+				idleContainerID:      80000,
+			},
+		},
+		{
+			name:        "burst of deleted container is forgotten",
+			nodeCPUUsed: pointer.Float64(10),
+			lastBurstCores: map[string]float64{
+				"containerd://deleted-container": 1,
+			},
+			want: map[string]int64{
+				throttledContainerID: 100000,
+				spikyContainerID:     100000,
+				idleContainerID:      0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metriccache.DefaultAggregateResultFactory = defaultAggregateResultFactory
+			cfg := metriccache.NewDefaultConfig()
+			cfg.TSDBPath = t.TempDir()
+			cfg.TSDBEnablePromMetrics = false
+			metricCache, err := metriccache.NewMetricCache(cfg)
+			assert.NoError(t, err)
+			metricCache.Set(metriccache.NodeCPUInfoKey, testNodeInfo)
+
+			now := time.Now()
+			var samples []metriccache.MetricSample
+			for _, ts := range []time.Time{now.Add(-2 * time.Minute), now.Add(-10 * time.Second)} {
+				s, err := metriccache.ContainerCPUThrottledMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.Container(throttledContainerID), ts, 0.1)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+				s, err = metriccache.ContainerCPUUsageMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.Container(throttledContainerID), ts, 1)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+				s, err = metriccache.ContainerCPUThrottledMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.Container(spikyContainerID), ts, 0)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+				s, err = metriccache.ContainerCPUUsageMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.Container(spikyContainerID), ts, 1)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+				s, err = metriccache.ContainerCPUUsageMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.Container(idleContainerID), ts, 0.2)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+			}
+			if tt.nodeCPUUsed != nil {
+				s, err := metriccache.NodeCPUUsageMetric.GenerateSample(nil, now.Add(-10*time.Second), *tt.nodeCPUUsed)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+			}
+			appender := metricCache.Appender()
+			assert.NoError(t, appender.Append(samples))
+			assert.NoError(t, appender.Commit())
+
+			b := &cpuBurst{
+				metricCollectInterval: time.Minute,
+				metricCache:           metricCache,
+				adaptiveBurstCores:    tt.lastBurstCores,
+			}
+			got := b.planAdaptiveCPUBurst(burstPods, tt.nodeBurstBudgetPercent)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.want), len(b.adaptiveBurstCores))
+			for containerID := range tt.lastBurstCores {
+				_, ok := tt.want[containerID]
+				_, exist := b.adaptiveBurstCores[containerID]
+				assert.Equal(t, ok, exist)
+			}
+		})
+	}
+}
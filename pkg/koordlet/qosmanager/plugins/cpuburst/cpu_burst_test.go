@@ -65,6 +65,7 @@ func newTestCPUBurst(opt *framework.Options) *cpuBurst {
 		executor:              newTestExecutor(),
 		cgroupReader:          resourceexecutor.NewCgroupReader(),
 		containerLimiter:      make(map[string]*burstLimiter),
+		adaptiveBurstCores:    make(map[string]float64),
 	}
 }
 
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"math"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	defaultAdaptiveProportionalGainPercent = 50
+	defaultAdaptiveIntegralGainPercent     = 20
+	defaultAdaptiveIntegralLimitPercent    = 50
+	defaultAdaptiveMinStepPercent          = 1
+	defaultAdaptiveMaxStepPercent          = 10
+	defaultAdaptiveSmoothingPercent        = 30
+)
+
+// adaptiveParams is the parsed parameters of the adaptive controller, where the cpu values are in cores.
+type adaptiveParams struct {
+	kp            float64
+	ki            float64
+	integralLimit float64
+	minStep       float64
+	maxStep       float64
+	smoothing     float64
+}
+
+func newAdaptiveParams(strategy *slov1alpha1.CPUSuppressAdaptiveStrategy, capacityCores float64) adaptiveParams {
+	getOrDefault := func(v *int64, d int64) float64 {
+		if v == nil {
+			return float64(d)
+		}
+		return float64(*v)
+	}
+	if strategy == nil {
+		strategy = &slov1alpha1.CPUSuppressAdaptiveStrategy{}
+	}
+	return adaptiveParams{
+		kp:            getOrDefault(strategy.ProportionalGainPercent, defaultAdaptiveProportionalGainPercent) / 100,
+		ki:            getOrDefault(strategy.IntegralGainPercent, defaultAdaptiveIntegralGainPercent) / 100,
+		integralLimit: getOrDefault(strategy.IntegralLimitPercent, defaultAdaptiveIntegralLimitPercent) * capacityCores / 100,
+		minStep:       getOrDefault(strategy.MinStepPercent, defaultAdaptiveMinStepPercent) * capacityCores / 100,
+		maxStep:       getOrDefault(strategy.MaxStepPercent, defaultAdaptiveMaxStepPercent) * capacityCores / 100,
+		smoothing:     getOrDefault(strategy.SmoothingPercent, defaultAdaptiveSmoothingPercent) / 100,
+	}
+}
+
+// adaptiveState is the state of the adaptive controller after an update.
+type adaptiveState struct {
+	Error        float64
+	Proportional float64
+	Integral     float64
+	Output       float64
+}
+
+// adaptiveController is a proportional-integral controller which adjusts the BE cpu allowance according to the
+// headroom between the BE suppress target and the BE cpu usage, i.e. the node usage headroom under the threshold.
+//
+// output(k) = smooth(lastOutput, beUsed + Kp * e(k) + I(k)), where I(k) = clamp(I(k-1) + Ki * e(k)).
+//
+// The integral term is clamped for anti-windup, and it also stops accumulating when the output saturates. A change
+// of the output smaller than the minStep is bypassed to avoid jitters, and an increase is bounded by the maxStep.
+type adaptiveController struct {
+	integral    float64
+	lastOutput  float64
+	initialized bool
+}
+
+func newAdaptiveController() *adaptiveController {
+	return &adaptiveController{}
+}
+
+func (c *adaptiveController) Reset() {
+	c.integral = 0
+	c.lastOutput = 0
+	c.initialized = false
+}
+
+// Update calculates the new BE cpu allowance in cores.
+// target is the BE suppress target calculated by the threshold, beUsed is the current BE cpu usage, and the output
+// is bounded in [minOutput, maxOutput].
+func (c *adaptiveController) Update(params adaptiveParams, target, beUsed, minOutput, maxOutput float64) adaptiveState {
+	if !c.initialized {
+		// start from the static target to avoid a cold start from zero
+		c.lastOutput = math.Min(math.Max(target, minOutput), maxOutput)
+		c.initialized = true
+	}
+
+	e := target - beUsed
+	proportional := params.kp * e
+	integral := c.integral + params.ki*e
+	integral = math.Min(math.Max(integral, -params.integralLimit), params.integralLimit)
+
+	raw := beUsed + proportional + integral
+	// conditional integration: do not accumulate the error towards the saturated direction
+	if (raw > maxOutput && e > 0) || (raw < minOutput && e < 0) {
+		integral = c.integral
+		raw = beUsed + proportional + integral
+	}
+	c.integral = integral
+
+	output := params.smoothing*c.lastOutput + (1-params.smoothing)*raw
+
+	delta := output - c.lastOutput
+	if math.Abs(delta) < params.minStep {
+		output = c.lastOutput
+	} else if delta > params.maxStep { // scale up slow, while the scale down is not bounded to protect LS
+		output = c.lastOutput + params.maxStep
+	}
+	output = math.Min(math.Max(output, minOutput), maxOutput)
+	c.lastOutput = output
+
+	return adaptiveState{
+		Error:        e,
+		Proportional: proportional,
+		Integral:     integral,
+		Output:       output,
+	}
+}
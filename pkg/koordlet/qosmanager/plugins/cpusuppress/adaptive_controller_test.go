@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_newAdaptiveParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *slov1alpha1.CPUSuppressAdaptiveStrategy
+		capacity float64
+		want     adaptiveParams
+	}{
+		{
+			name:     "use default params",
+			strategy: nil,
+			capacity: 100,
+			want: adaptiveParams{
+				kp:            0.5,
+				ki:            0.2,
+				integralLimit: 50,
+				minStep:       1,
+				maxStep:       10,
+				smoothing:     0.3,
+			},
+		},
+		{
+			name: "use custom params",
+			strategy: &slov1alpha1.CPUSuppressAdaptiveStrategy{
+				ProportionalGainPercent: pointer.Int64(100),
+				MaxStepPercent:          pointer.Int64(5),
+				SmoothingPercent:        pointer.Int64(0),
+			},
+			capacity: 40,
+			want: adaptiveParams{
+				kp:            1,
+				ki:            0.2,
+				integralLimit: 20,
+				minStep:       0.4,
+				maxStep:       2,
+				smoothing:     0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAdaptiveParams(tt.strategy, tt.capacity)
+			assert.InDelta(t, tt.want.kp, got.kp, 1e-9)
+			assert.InDelta(t, tt.want.ki, got.ki, 1e-9)
+			assert.InDelta(t, tt.want.integralLimit, got.integralLimit, 1e-9)
+			assert.InDelta(t, tt.want.minStep, got.minStep, 1e-9)
+			assert.InDelta(t, tt.want.maxStep, got.maxStep, 1e-9)
+			assert.InDelta(t, tt.want.smoothing, got.smoothing, 1e-9)
+		})
+	}
+}
+
+func Test_adaptiveController_Update(t *testing.T) {
+	params := newAdaptiveParams(nil, 100)
+
+	t.Run("start from the target and keep stable at the setpoint", func(t *testing.T) {
+		c := newAdaptiveController()
+		got := c.Update(params, 40, 40, 1, 100)
+		assert.Equal(t, 40.0, got.Output)
+		assert.Equal(t, 0.0, got.Error)
+		got = c.Update(params, 40, 40, 1, 100)
+		assert.Equal(t, 40.0, got.Output)
+	})
+
+	t.Run("bypass the change smaller than min step", func(t *testing.T) {
+		c := newAdaptiveController()
+		c.Update(params, 40, 40, 1, 100)
+		got := c.Update(params, 40, 39.5, 1, 100)
+		assert.Equal(t, 40.0, got.Output)
+	})
+
+	t.Run("bound the increase by max step", func(t *testing.T) {
+		c := newAdaptiveController()
+		c.Update(params, 20, 20, 1, 100)
+		got := c.Update(params, 80, 20, 1, 100)
+		assert.Equal(t, 30.0, got.Output)
+	})
+
+	t.Run("suppress without bound when be exceeds the target", func(t *testing.T) {
+		c := newAdaptiveController()
+		c.Update(params, 60, 60, 1, 100)
+		got := c.Update(params, 20, 60, 1, 100)
+		assert.Less(t, got.Output, 50.0)
+		assert.GreaterOrEqual(t, got.Output, 1.0)
+	})
+
+	t.Run("integral is clamped and stops winding up when saturated", func(t *testing.T) {
+		c := newAdaptiveController()
+		for i := 0; i < 100; i++ {
+			c.Update(params, 100, 99, 1, 100)
+		}
+		assert.LessOrEqual(t, c.integral, params.integralLimit)
+		assert.Equal(t, 100.0, c.lastOutput)
+
+		c.Reset()
+		for i := 0; i < 100; i++ {
+			c.Update(params, 0, 100, 1, 100)
+		}
+		assert.GreaterOrEqual(t, c.integral, -params.integralLimit)
+		assert.GreaterOrEqual(t, c.lastOutput, 1.0)
+		assert.Less(t, c.lastOutput, 20.0)
+	})
+}
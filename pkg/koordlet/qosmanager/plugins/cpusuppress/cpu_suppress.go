@@ -73,6 +73,7 @@ type CPUSuppress struct {
 	executor               resourceexecutor.ResourceUpdateExecutor
 	cgroupReader           resourceexecutor.CgroupReader
 	suppressPolicyStatuses map[string]suppressPolicyStatus
+	adaptiveController     *adaptiveController
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -84,6 +85,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		executor:               resourceexecutor.NewResourceUpdateExecutor(),
 		cgroupReader:           opt.CgroupReader,
 		suppressPolicyStatuses: map[string]suppressPolicyStatus{},
+		adaptiveController:     newAdaptiveController(),
 	}
 }
 
@@ -258,15 +260,16 @@ func (r *CPUSuppress) suppressBECPU() {
 		klog.Warningf("suppressBECPU failed, cannot check the featuregate, err: %s", err)
 		return
 	} else if features.DefaultKoordletFeatureGate.Enabled(features.BECPUSuppress) &&
-		features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
+		features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) && (disabled || !isCPUSetSuppressPolicy(nodeSLO)) {
 		r.recoverCFSQuotaIfNeed()
 		r.recoverCPUSetForBECPUManager()
-		klog.V(5).Infof("suppressBECPU cannot work with BECPUManager together, suppress will be skipped, " +
+		klog.V(5).Infof("suppressBECPU only works with BECPUManager in cpuset policy, suppress will be skipped, " +
 			"recover cpuset on all level if be pod does not specified numa node, and let be cpu set hook handle the others")
 		return
 	} else if disabled {
 		r.recoverCFSQuotaIfNeed()
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+		r.adaptiveController.Reset()
 		klog.V(5).Infof("suppressBECPU skipped, nodeSLO disable the featuregate")
 		return
 	}
@@ -316,11 +319,23 @@ func (r *CPUSuppress) suppressBECPU() {
 	if !ok {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
-	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
+	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUAdaptivePolicy {
+		beUsedCPU := calculateBEUsedCPU(podMetas, podMetrics)
+		r.adjustByAdaptiveCfsQuota(suppressCPUQuantity, beUsedCPU, node, nodeSLO.Spec.ResourceUsedThresholdWithBE)
+		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
+		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+	} else if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
 		r.adjustByCfsQuota(suppressCPUQuantity, node)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
 		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+		r.adaptiveController.Reset()
+	} else if features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
+		r.adaptiveController.Reset()
+		r.adjustByNUMACPUSet(suppressCPUQuantity, nodeCPUInfo)
+		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
+		r.recoverCFSQuotaIfNeed()
 	} else {
+		r.adaptiveController.Reset()
 		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
 		r.recoverCFSQuotaIfNeed()
@@ -618,6 +633,36 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		newBeQuota = currentBeQuota + int64(beMaxIncreaseCPUQuota)
 	}
 
+	r.updateBECFSQuota(beCgroupPath, newBeQuota, slov1alpha1.CPUCfsQuotaPolicy)
+}
+
+// adjustByAdaptiveCfsQuota adjusts the be cfs quota with the output of the adaptive controller, which takes the
+// suppress target as the setpoint and the be cpu usage as the feedback.
+func (r *CPUSuppress) adjustByAdaptiveCfsQuota(targetQuantity *resource.Quantity, beUsedCPU float64, node *corev1.Node,
+	strategy *slov1alpha1.ResourceThresholdStrategy) {
+	capacityCores := float64(node.Status.Capacity.Cpu().MilliValue()) / 1000
+	params := newAdaptiveParams(strategy.CPUSuppressAdaptiveStrategy, capacityCores)
+	minOutput := float64(beMinQuota) / float64(system.DefaultCPUCFSPeriod)
+	if strategy.CPUSuppressMinPercent != nil {
+		minOutput = math.Max(minOutput, capacityCores*float64(*strategy.CPUSuppressMinPercent)/100)
+	}
+	target := float64(targetQuantity.MilliValue()) / 1000
+
+	state := r.adaptiveController.Update(params, target, beUsedCPU, minOutput, capacityCores)
+	metrics.RecordBESuppressAdaptiveControllerState("error", state.Error)
+	metrics.RecordBESuppressAdaptiveControllerState("proportional", state.Proportional)
+	metrics.RecordBESuppressAdaptiveControllerState("integral", state.Integral)
+	metrics.RecordBESuppressAdaptiveControllerState("output", state.Output)
+	klog.V(5).Infof("suppressBECPU: adaptive controller target %.3f, be used %.3f, error %.3f, proportional %.3f, integral %.3f, output %.3f",
+		target, beUsedCPU, state.Error, state.Proportional, state.Integral, state.Output)
+
+	newBeQuota := int64(state.Output * float64(system.DefaultCPUCFSPeriod))
+	newBeQuota = int64(math.Max(float64(newBeQuota), float64(beMinQuota)))
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	r.updateBECFSQuota(beCgroupPath, newBeQuota, slov1alpha1.CPUAdaptivePolicy)
+}
+
+func (r *CPUSuppress) updateBECFSQuota(beCgroupPath string, newBeQuota int64, policy slov1alpha1.CPUSuppressPolicy) {
 	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, beCgroupPath, strconv.FormatInt(newBeQuota, 10), eventHelper)
 	if err != nil {
@@ -629,9 +674,10 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		klog.Errorf("suppressBECPU: failed to write cfs_quota_us for be pods, error: %v", err)
 		return
 	}
-	metrics.RecordBESuppressCores(string(slov1alpha1.CPUCfsQuotaPolicy), float64(newBeQuota)/float64(system.DefaultCPUCFSPeriod))
+	metrics.RecordBESuppressCores(string(policy), float64(newBeQuota)/float64(system.DefaultCPUCFSPeriod))
 	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).Do()
-	klog.Infof("suppressBECPU: succeeded to write cfs_quota_us for offline pods, isUpdated %v, new value: %d", isUpdated, newBeQuota)
+	klog.Infof("suppressBECPU: succeeded to write cfs_quota_us for offline pods, policy %v, isUpdated %v, new value: %d",
+		policy, isUpdated, newBeQuota)
 }
 
 func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
@@ -656,6 +702,18 @@ func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyRecovered
 }
 
+// calculateBEUsedCPU sums the cpu usages of the BE pods.
+func calculateBEUsedCPU(podMetas []*statesinformer.PodMeta, podMetrics map[string]float64) float64 {
+	beUsed := 0.0
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || helpers.NonBEPodFilter(podMeta.Pod) {
+			continue
+		}
+		beUsed += podMetrics[string(podMeta.Pod.UID)]
+	}
+	return beUsed
+}
+
 // calculateBESuppressPolicy calculates the be cpu suppress policy with cpuset cpus number and node cpu info
 func calculateBESuppressCPUSetPolicy(cpus int32, processorInfos []koordletutil.ProcessorInfo) []int32 {
 	var CPUSets []int32
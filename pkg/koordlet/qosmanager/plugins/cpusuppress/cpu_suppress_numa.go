@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"math"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// isCPUSetSuppressPolicy checks if the nodeSLO suppresses the BE cpu by cpuset.
+func isCPUSetSuppressPolicy(nodeSLO *slov1alpha1.NodeSLO) bool {
+	if nodeSLO == nil || nodeSLO.Spec.ResourceUsedThresholdWithBE == nil {
+		return false
+	}
+	policy := nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy
+	return policy != slov1alpha1.CPUCfsQuotaPolicy && policy != slov1alpha1.CPUAdaptivePolicy
+}
+
+// adjustByNUMACPUSet suppresses the BE cpuset in each NUMA-level be share pool when the BECPUManager is enabled.
+// The BE pods bound to NUMA nodes only run on the suppressed cpus of their NUMA nodes, while the others run on the
+// suppressed cpus of all NUMA nodes.
+func (r *CPUSuppress) adjustByNUMACPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
+	topo := r.statesInformer.GetNodeTopo()
+	if topo == nil {
+		klog.Errorf("node topo is nil")
+		return
+	}
+	beSharePools, err := apiext.GetNodeBECPUSharePools(topo.Annotations)
+	if err != nil || len(beSharePools) <= 0 {
+		klog.Warningf("suppressBECPU failed to get be share pools, recover the cpuset, pools %v, err: %v",
+			beSharePools, err)
+		r.recoverCPUSetForBECPUManager()
+		return
+	}
+
+	numaCPUSets := calculateBESuppressNUMACPUSets(cpusetQuantity, beSharePools, nodeCPUInfo)
+	r.applyBESuppressNUMACPUSets(numaCPUSets)
+}
+
+// calculateBESuppressNUMACPUSets divides the suppressed cpus to the be share pools proportionally to their sizes,
+// and picks the cpus of each pool by the cpuset suppress policy. It returns the suppressed cpus of each NUMA node.
+func calculateBESuppressNUMACPUSets(cpusetQuantity *resource.Quantity, beSharePools []apiext.CPUSharedPool,
+	nodeCPUInfo *metriccache.NodeCPUInfo) map[int32]cpuset.CPUSet {
+	processors := map[int32]koordletutil.ProcessorInfo{}
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		processors[processor.CPUID] = processor
+	}
+	poolProcessors := map[int32][]koordletutil.ProcessorInfo{}
+	totalPoolCPUs := 0
+	for _, pool := range beSharePools {
+		poolCPUs, err := cpuset.Parse(pool.CPUSet)
+		if err != nil {
+			klog.Warningf("failed to parse be share pool of NUMA node %v, cpuset %v, err: %v", pool.Node, pool.CPUSet, err)
+			continue
+		}
+		for _, cpuID := range poolCPUs.ToSlice() {
+			if processor, ok := processors[int32(cpuID)]; ok {
+				poolProcessors[pool.Node] = append(poolProcessors[pool.Node], processor)
+				totalPoolCPUs++
+			}
+		}
+	}
+	if totalPoolCPUs <= 0 {
+		return nil
+	}
+
+	// set the number of cpuset cpus no less than 2
+	cpus := int32(math.Ceil(float64(cpusetQuantity.MilliValue()) / 1000))
+	if cpus < beMinCPUSetCores {
+		cpus = beMinCPUSetCores
+	}
+	numaCPUSets := make(map[int32]cpuset.CPUSet, len(poolProcessors))
+	for numaNode, processorInfos := range poolProcessors {
+		poolSize := int32(len(processorInfos))
+		numaCPUs := int32(math.Ceil(float64(cpus) * float64(poolSize) / float64(totalPoolCPUs)))
+		// keep the minimum cpus for each NUMA node since the bound BE pods cannot run on the others
+		if minCPUs := int32(math.Min(beMinCPUSetCores, float64(poolSize))); numaCPUs < minCPUs {
+			numaCPUs = minCPUs
+		}
+		if numaCPUs > poolSize {
+			numaCPUs = poolSize
+		}
+		builder := cpuset.NewCPUSetBuilder()
+		for _, cpuID := range calculateBESuppressCPUSetPolicy(numaCPUs, processorInfos) {
+			builder.Add(int(cpuID))
+		}
+		numaCPUSets[numaNode] = builder.Result()
+	}
+	return numaCPUSets
+}
+
+// applyBESuppressNUMACPUSets writes the BE cpuset of each level:
+// - besteffort dir and besteffort/pod dir: the be cpuset, so the container-level cpusets can be bound to any NUMA node
+// - besteffort/pod/container dir of the pod bound to NUMA nodes: the suppressed cpus of these NUMA nodes
+// - besteffort/pod/container dir of the other pods: the suppressed cpus of all NUMA nodes
+func (r *CPUSuppress) applyBESuppressNUMACPUSets(numaCPUSets map[int32]cpuset.CPUSet) {
+	allCPUs := cpuset.NewCPUSet()
+	for _, cpus := range numaCPUSets {
+		allCPUs = allCPUs.Union(cpus)
+	}
+	if allCPUs.IsEmpty() {
+		klog.Warningf("suppressBECPU failed, got empty NUMA-level suppressed cpuset")
+		return
+	}
+
+	beCPUSet, err := r.calcBECPUSet()
+	if err != nil {
+		klog.Warningf("get be cpuset failed during applyBESuppressNUMACPUSets, error %v", err)
+		return
+	}
+	// cpuset path under besteffort dir, include root, pod
+	cpusetPathOfAllBEPods, err := koordletutil.GetBECPUSetPathsByMaxDepth(koordletutil.PodCgroupPathRelativeDepth)
+	if err != nil {
+		klog.Warningf("GetBECPUSetPathsByMaxDepth until pod level failed, error %v", err)
+		return
+	}
+	// cpuset path under besteffort, only include container/sandbox dir
+	cpusetPathOfAllBEContainer, err := koordletutil.GetBECPUSetPathsByTargetDepth(koordletutil.ContainerCgroupPathRelativeDepth)
+	if err != nil {
+		klog.Warningf("GetBECPUSetPathsByTargetDepth until container level failed, error %v", err)
+		return
+	}
+
+	// cgroup dir of the pods bound to NUMA nodes -> the suppressed cpus of the NUMA nodes
+	podNUMACPUSets := map[string]cpuset.CPUSet{}
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || podMeta.Pod.Annotations == nil || podMeta.CgroupDir == "" {
+			continue
+		}
+		resourceStatus, err := apiext.GetResourceStatus(podMeta.Pod.Annotations)
+		if err != nil || resourceStatus == nil || len(resourceStatus.NUMANodeResources) <= 0 {
+			continue
+		}
+		numaCPUs := cpuset.NewCPUSet()
+		for _, numaResource := range resourceStatus.NUMANodeResources {
+			numaCPUs = numaCPUs.Union(numaCPUSets[numaResource.Node])
+		}
+		if !numaCPUs.IsEmpty() {
+			podNUMACPUSets[podMeta.CgroupDir] = numaCPUs
+		}
+	}
+
+	// cpuset str -> container paths
+	containerPaths := map[string][]string{}
+	for _, containerPath := range cpusetPathOfAllBEContainer {
+		cpus := allCPUs
+		for podCgroupDir, numaCPUs := range podNUMACPUSets {
+			if strings.Contains(containerPath, podCgroupDir) {
+				cpus = numaCPUs
+				break
+			}
+		}
+		cpusetStr := cpus.String()
+		containerPaths[cpusetStr] = append(containerPaths[cpusetStr], containerPath)
+	}
+
+	// write the upper levels first since the container-level cpusets must be their subsets
+	r.writeBECgroupsCPUSet(cpusetPathOfAllBEPods, beCPUSet.String(), false)
+	for cpusetStr, paths := range containerPaths {
+		r.writeBECgroupsCPUSet(paths, cpusetStr, false)
+	}
+	metrics.RecordBESuppressCores(string(slov1alpha1.CPUSetPolicy), float64(allCPUs.Size()))
+	klog.V(4).Infof("suppressBECPU finished, suppress be cpu by NUMA nodes successfully: current cpuset %v",
+		numaCPUSets)
+}
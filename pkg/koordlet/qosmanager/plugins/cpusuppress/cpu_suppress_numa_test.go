@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+var testNUMANodeCPUInfo = &metriccache.NodeCPUInfo{
+	ProcessorInfos: []koordletutil.ProcessorInfo{
+		{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+		{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+		{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0},
+		{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0},
+		{CPUID: 4, CoreID: 2, SocketID: 0, NodeID: 0},
+		{CPUID: 5, CoreID: 2, SocketID: 0, NodeID: 0},
+		{CPUID: 6, CoreID: 3, SocketID: 1, NodeID: 1},
+		{CPUID: 7, CoreID: 3, SocketID: 1, NodeID: 1},
+		{CPUID: 8, CoreID: 4, SocketID: 1, NodeID: 1},
+		{CPUID: 9, CoreID: 4, SocketID: 1, NodeID: 1},
+		{CPUID: 10, CoreID: 5, SocketID: 1, NodeID: 1},
+		{CPUID: 11, CoreID: 5, SocketID: 1, NodeID: 1},
+	},
+}
+
+func Test_isCPUSetSuppressPolicy(t *testing.T) {
+	genNodeSLO := func(policy slov1alpha1.CPUSuppressPolicy) *slov1alpha1.NodeSLO {
+		return &slov1alpha1.NodeSLO{
+			Spec: slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					CPUSuppressPolicy: policy,
+				},
+			},
+		}
+	}
+	assert.False(t, isCPUSetSuppressPolicy(nil))
+	assert.False(t, isCPUSetSuppressPolicy(&slov1alpha1.NodeSLO{}))
+	assert.True(t, isCPUSetSuppressPolicy(genNodeSLO(slov1alpha1.CPUSetPolicy)))
+	assert.True(t, isCPUSetSuppressPolicy(genNodeSLO("")))
+	assert.False(t, isCPUSetSuppressPolicy(genNodeSLO(slov1alpha1.CPUCfsQuotaPolicy)))
+	assert.False(t, isCPUSetSuppressPolicy(genNodeSLO(slov1alpha1.CPUAdaptivePolicy)))
+}
+
+func Test_calculateBESuppressNUMACPUSets(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantity     resource.Quantity
+		beSharePools []apiext.CPUSharedPool
+		want         map[int32]cpuset.CPUSet
+	}{
+		{
+			name:     "no valid be share pool",
+			quantity: resource.MustParse("4"),
+			beSharePools: []apiext.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "invalid"},
+			},
+			want: nil,
+		},
+		{
+			name:     "divide cpus by pool sizes",
+			quantity: resource.MustParse("4"),
+			beSharePools: []apiext.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "0-5"},
+				{Socket: 1, Node: 1, CPUSet: "6-11"},
+			},
+			want: map[int32]cpuset.CPUSet{
+				0: cpuset.NewCPUSet(0, 1),
+				1: cpuset.NewCPUSet(6, 7),
+			},
+		},
+		{
+			name:     "keep the minimum cpus of a small pool",
+			quantity: resource.MustParse("3"),
+			beSharePools: []apiext.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "0-5"},
+				{Socket: 1, Node: 1, CPUSet: "10-11"},
+			},
+			want: map[int32]cpuset.CPUSet{
+				0: cpuset.NewCPUSet(0, 1, 2),
+				1: cpuset.NewCPUSet(10, 11),
+			},
+		},
+		{
+			name:     "not exceed the pool",
+			quantity: resource.MustParse("10"),
+			beSharePools: []apiext.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "0-5"},
+				{Socket: 1, Node: 1, CPUSet: "10-11"},
+			},
+			want: map[int32]cpuset.CPUSet{
+				0: cpuset.NewCPUSet(0, 1, 2, 3, 4, 5),
+				1: cpuset.NewCPUSet(10, 11),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateBESuppressNUMACPUSets(&tt.quantity, tt.beSharePools, testNUMANodeCPUInfo)
+			assert.Equal(t, len(tt.want), len(got))
+			for node, wantCPUs := range tt.want {
+				assert.Equal(t, wantCPUs.String(), got[node].String(), "NUMA node %v", node)
+			}
+		})
+	}
+}
+
+func TestCPUSuppress_applyBESuppressNUMACPUSets(t *testing.T) {
+	genBEPod := func(name string, numaNodes ...int32) *statesinformer.PodMeta {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "test-ns",
+				Name:        name,
+				UID:         types.UID(name),
+				Labels:      map[string]string{apiext.LabelPodQoS: string(apiext.QoSBE)},
+				Annotations: map[string]string{},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}},
+			},
+			Status: corev1.PodStatus{
+				QOSClass: corev1.PodQOSBestEffort,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "main", ContainerID: "containerd://" + name + "-main"},
+				},
+			},
+		}
+		if len(numaNodes) > 0 {
+			resourceStatus := &apiext.ResourceStatus{}
+			for _, node := range numaNodes {
+				resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, apiext.NUMANodeResource{Node: node})
+			}
+			assert.NoError(t, apiext.SetResourceStatus(pod, resourceStatus))
+		}
+		return &statesinformer.PodMeta{Pod: pod, CgroupDir: koordletutil.GetPodCgroupParentDir(pod)}
+	}
+	numaPod := genBEPod("be-numa-pod", 1)
+	sharePod := genBEPod("be-share-pod")
+	podMetas := []*statesinformer.PodMeta{numaPod, sharePod}
+
+	helper := system.NewFileTestUtil(t)
+	helper.WriteCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet, "0-11")
+	for _, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, system.CPUSet, "0-11")
+		for i := range podMeta.Pod.Status.ContainerStatuses {
+			containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, &podMeta.Pod.Status.ContainerStatuses[i])
+			assert.NoError(t, err)
+			helper.WriteCgroupFileContents(containerDir, system.CPUSet, "0-11")
+		}
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctl)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{}).AnyTimes()
+	mockMetricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(testNUMANodeCPUInfo, true).AnyTimes()
+	opt := &framework.Options{
+		StatesInformer:      mockStatesInformer,
+		MetricCache:         mockMetricCache,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	cpuSuppress := newTestCPUSuppress(opt)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	cpuSuppress.executor.Run(stopCh)
+
+	cpuSuppress.applyBESuppressNUMACPUSets(map[int32]cpuset.CPUSet{
+		0: cpuset.NewCPUSet(0, 1),
+		1: cpuset.NewCPUSet(6, 7),
+	})
+
+	assert.Equal(t, "0-11", helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet))
+	wantContainerCPUSets := map[string]string{
+		numaPod.Pod.Name:  "6-7",
+		sharePod.Pod.Name: "0-1,6-7",
+	}
+	for _, podMeta := range podMetas {
+		assert.Equal(t, "0-11", helper.ReadCgroupFileContents(podMeta.CgroupDir, system.CPUSet))
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, &podMeta.Pod.Status.ContainerStatuses[0])
+		assert.NoError(t, err)
+		assert.Equal(t, wantContainerCPUSets[podMeta.Pod.Name], helper.ReadCgroupFileContents(containerDir, system.CPUSet))
+	}
+}
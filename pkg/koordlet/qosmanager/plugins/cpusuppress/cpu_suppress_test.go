@@ -59,6 +59,7 @@ func newTestCPUSuppress(opt *framework.Options) *CPUSuppress {
 		},
 		cgroupReader:           resourceexecutor.NewCgroupReader(),
 		suppressPolicyStatuses: map[string]suppressPolicyStatus{},
+		adaptiveController:     newAdaptiveController(),
 	}
 }
 
@@ -2033,6 +2034,98 @@ func Test_cpuSuppress_adjustByCfsQuota(t *testing.T) {
 	}
 }
 
+func Test_cpuSuppress_adjustByAdaptiveCfsQuota(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	beQosDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	helper.CreateCgroupFile(beQosDir, system.CPUCFSQuota)
+	helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(10*system.DefaultCPUCFSPeriod, 10))
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node0",
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("80"),
+			},
+		},
+	}
+	strategy := &slov1alpha1.ResourceThresholdStrategy{
+		CPUSuppressPolicy: slov1alpha1.CPUAdaptivePolicy,
+	}
+	opt := &framework.Options{
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	r := newTestCPUSuppress(opt)
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		r.init(stop)
+	})
+
+	// the first round starts from the target
+	r.adjustByAdaptiveCfsQuota(resource.NewMilliQuantity(20*1000, resource.BinarySI), 20, node, strategy)
+	got := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
+	assert.Equal(t, strconv.FormatInt(20*system.DefaultCPUCFSPeriod, 10), got)
+
+	// the BE usage exceeds the target, the quota should be suppressed below the BE usage
+	r.adjustByAdaptiveCfsQuota(resource.NewMilliQuantity(20*1000, resource.BinarySI), 30, node, strategy)
+	got = helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
+	gotQuota, err := strconv.ParseInt(got, 10, 64)
+	assert.NoError(t, err)
+	assert.Less(t, gotQuota, 30*system.DefaultCPUCFSPeriod)
+
+	// the quota never goes below the min quota
+	r.adjustByAdaptiveCfsQuota(resource.NewMilliQuantity(1, resource.BinarySI), 80, node, strategy)
+	got = helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
+	gotQuota, err = strconv.ParseInt(got, 10, 64)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, gotQuota, int64(beMinQuota))
+}
+
+func Test_calculateBEUsedCPU(t *testing.T) {
+	podMetas := []*statesinformer.PodMeta{
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "be-pod",
+					UID:  "be-pod",
+					Labels: map[string]string{
+						apiext.LabelPodQoS: string(apiext.QoSBE),
+					},
+				},
+			},
+		},
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ls-pod",
+					UID:  "ls-pod",
+					Labels: map[string]string{
+						apiext.LabelPodQoS: string(apiext.QoSLS),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("4"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		nil,
+	}
+	podMetrics := map[string]float64{
+		"be-pod": 2.5,
+		"ls-pod": 4,
+	}
+	assert.Equal(t, 2.5, calculateBEUsedCPU(podMetas, podMetrics))
+}
+
 func Test_cpuSuppress_writeBECgroupsCPUSet(t *testing.T) {
 	// prepare testing files
 	helper := system.NewFileTestUtil(t)
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	defaultBEMemoryHighEvictAfterSeconds = 60
+)
+
+type beMemoryHighConfig struct {
+	thresholdPercent int64
+	podLevel         bool
+	evictAfter       time.Duration
+}
+
+// parseBEMemoryHighConfig parses the BEMemoryHigh strategy with the defaults. It returns nil if the strategy is disabled.
+func parseBEMemoryHighConfig(thresholdConfig *slov1alpha1.ResourceThresholdStrategy) *beMemoryHighConfig {
+	strategy := thresholdConfig.BEMemoryHigh
+	if strategy == nil || strategy.Enable == nil || !*strategy.Enable {
+		return nil
+	}
+	c := &beMemoryHighConfig{
+		thresholdPercent: *thresholdConfig.MemoryEvictThresholdPercent,
+		evictAfter:       defaultBEMemoryHighEvictAfterSeconds * time.Second,
+	}
+	if strategy.ThresholdPercent != nil {
+		c.thresholdPercent = *strategy.ThresholdPercent
+	}
+	if strategy.PodLevel != nil {
+		c.podLevel = *strategy.PodLevel
+	}
+	if strategy.EvictAfterSeconds != nil {
+		c.evictAfter = time.Duration(*strategy.EvictAfterSeconds) * time.Second
+	}
+	return c
+}
+
+// manageBEMemoryHigh sets the memory.high of the BE pods by the node memory headroom above the footprint of the other
+// pods and the system. It returns whether the memory.high is managed, and the memory to release by evicting the BE
+// pods when the BE memory usage has exceeded the memory.high for the configured duration.
+func (m *memoryEvictor) manageBEMemoryHigh(thresholdConfig *slov1alpha1.ResourceThresholdStrategy, memoryCapacity,
+	nodeMemoryUsed int64, podMetrics map[string]float64) (bool, int64) {
+	config := parseBEMemoryHighConfig(thresholdConfig)
+	if config == nil {
+		m.recoverManagedBEMemoryHigh()
+		return false, 0
+	}
+	if system.GetCurrentCgroupVersion() != system.CgroupVersionV2 {
+		klog.V(4).Infof("skip managing BE memory.high, only supported on cgroups-v2")
+		return false, 0
+	}
+
+	var bePodMetas []*statesinformer.PodMeta
+	beMemoryUsed, beMemoryRequest := int64(0), int64(0)
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		bePodMetas = append(bePodMetas, podMeta)
+		beMemoryUsed += int64(podMetrics[string(pod.UID)])
+		beMemoryRequest += util.GetPodBEMemoryByteRequestIgnoreUnlimited(pod)
+	}
+	footprint := util.MaxInt64(nodeMemoryUsed-beMemoryUsed, 0)
+	memoryHigh := calculateBEMemoryHigh(memoryCapacity, footprint, beMemoryRequest, config.thresholdPercent)
+
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).Message("update BE group to memory.high: %v", memoryHigh)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, beCgroupDir, strconv.FormatInt(memoryHigh, 10), eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get BE memory.high updater, err: %v", err)
+		return false, 0
+	}
+	if _, err = m.executor.Update(false, updater); err != nil {
+		klog.Warningf("failed to update BE memory.high, err: %v", err)
+		return false, 0
+	}
+	// the managed memory.high supersedes the throttle stage of the memory pressure relief
+	m.isBEMemoryThrottled = false
+	m.isBEMemoryHighManaged = true
+	metrics.RecordBEMemoryHighBytes(float64(memoryHigh))
+	klog.V(5).Infof("update BE memory.high %v, BE memory used(%v) request(%v), footprint(%v)",
+		memoryHigh, beMemoryUsed, beMemoryRequest, footprint)
+
+	if config.podLevel {
+		m.updateBEPodsMemoryHigh(bePodMetas, podMetrics, memoryHigh, beMemoryUsed)
+	} else {
+		m.recoverBEPodsMemoryHigh()
+	}
+
+	if beMemoryUsed <= memoryHigh {
+		m.beMemoryHighExceededSince = time.Time{}
+		return true, 0
+	}
+	now := time.Now()
+	if m.beMemoryHighExceededSince.IsZero() {
+		m.beMemoryHighExceededSince = now
+	}
+	if exceeded := now.Sub(m.beMemoryHighExceededSince); exceeded < config.evictAfter {
+		klog.V(4).Infof("BE memory used(%v) exceeds memory.high(%v) for %v, wait for the reclaim",
+			beMemoryUsed, memoryHigh, exceeded)
+		return true, 0
+	}
+	m.beMemoryHighExceededSince = time.Time{}
+	return true, beMemoryUsed - memoryHigh
+}
+
+// waitBEMemoryHighBeforeEvict returns true if the eviction by the MemoryEvictThresholdPercent should wait for the
+// BE memory.high to take effect. The eviction is the backstop when the node memory usage stays above the threshold
+// for the evictAfter duration, e.g. the memory of the other pods grows.
+func (m *memoryEvictor) waitBEMemoryHighBeforeEvict(thresholdConfig *slov1alpha1.ResourceThresholdStrategy) bool {
+	config := parseBEMemoryHighConfig(thresholdConfig)
+	if config == nil {
+		return false
+	}
+	now := time.Now()
+	if m.nodeMemoryExceededSince.IsZero() {
+		m.nodeMemoryExceededSince = now
+	}
+	return now.Sub(m.nodeMemoryExceededSince) < config.evictAfter
+}
+
+// calculateBEMemoryHigh returns the memory.high of the BE parent cgroup:
+// max(capacity * thresholdPercent / 100 - footprint, beRequest), which is aligned to the page size.
+func calculateBEMemoryHigh(memoryCapacity, footprint, beMemoryRequest, thresholdPercent int64) int64 {
+	memoryHigh := util.MaxInt64(memoryCapacity*thresholdPercent/100-footprint, beMemoryRequest)
+	return memoryHigh / system.PageSize * system.PageSize
+}
+
+// calculateBEPodMemoryHigh returns the memory.high of a BE pod. The pod can use up the remaining memory of the BE
+// parent cgroup, and it is throttled in proportion to its memory usage when the BE parent cgroup is exceeded.
+// The memory.high of the pod is no less than its memory request.
+func calculateBEPodMemoryHigh(podMemoryUsed, podMemoryRequest, beMemoryHigh, beMemoryUsed int64) int64 {
+	podMemoryHigh := podMemoryUsed + beMemoryHigh - beMemoryUsed
+	if beMemoryUsed > beMemoryHigh {
+		podMemoryHigh = int64(float64(podMemoryUsed) * float64(beMemoryHigh) / float64(beMemoryUsed))
+	}
+	podMemoryHigh = util.MaxInt64(podMemoryHigh, podMemoryRequest)
+	return podMemoryHigh / system.PageSize * system.PageSize
+}
+
+func (m *memoryEvictor) updateBEPodsMemoryHigh(bePodMetas []*statesinformer.PodMeta, podMetrics map[string]float64,
+	beMemoryHigh, beMemoryUsed int64) {
+	for _, podMeta := range bePodMetas {
+		pod := podMeta.Pod
+		podMemoryHigh := calculateBEPodMemoryHigh(int64(podMetrics[string(pod.UID)]),
+			util.GetPodBEMemoryByteRequestIgnoreUnlimited(pod), beMemoryHigh, beMemoryUsed)
+		podKey := util.GetPodKey(pod)
+		eventHelper := audit.V(3).Pod(pod.Namespace, pod.Name).Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).
+			Message("update BE pod to memory.high: %v", podMemoryHigh)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, podMeta.CgroupDir,
+			strconv.FormatInt(podMemoryHigh, 10), eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get memory.high updater for pod %s, err: %v", podKey, err)
+			continue
+		}
+		if _, err = m.executor.Update(false, updater); err != nil {
+			klog.V(4).Infof("failed to update memory.high for pod %s, err: %v", podKey, err)
+			continue
+		}
+		klog.V(5).Infof("update memory.high for BE pod %s, memory.high %v", podKey, podMemoryHigh)
+	}
+	m.isBEPodMemoryHighManaged = true
+}
+
+// recoverManagedBEMemoryHigh resets the memory.high of the BE parent cgroup and the BE pods to unlimited if they are
+// managed by the BEMemoryHigh strategy.
+func (m *memoryEvictor) recoverManagedBEMemoryHigh() {
+	m.beMemoryHighExceededSince = time.Time{}
+	m.recoverBEPodsMemoryHigh()
+	if !m.isBEMemoryHighManaged {
+		return
+	}
+	if !m.resetBEMemoryHigh() {
+		return
+	}
+	m.isBEMemoryHighManaged = false
+	klog.V(4).Infof("recover the managed BE memory.high")
+}
+
+func (m *memoryEvictor) recoverBEPodsMemoryHigh() {
+	if !m.isBEPodMemoryHighManaged {
+		return
+	}
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		eventHelper := audit.V(3).Pod(pod.Namespace, pod.Name).Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).
+			Message("recover BE pod memory.high to unlimited")
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, podMeta.CgroupDir,
+			system.CgroupMaxValueStr, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get memory.high updater for pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		if _, err = m.executor.Update(false, updater); err != nil {
+			klog.V(4).Infof("failed to recover memory.high for pod %s, err: %v", util.GetPodKey(pod), err)
+		}
+	}
+	m.isBEPodMemoryHighManaged = false
+	klog.V(4).Infof("recover the memory.high of BE pods")
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func Test_parseBEMemoryHighConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		thresholdConfig *slov1alpha1.ResourceThresholdStrategy
+		want            *beMemoryHighConfig
+	}{
+		{
+			name: "strategy not set",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				MemoryEvictThresholdPercent: pointer.Int64(70),
+			},
+		},
+		{
+			name: "strategy disabled",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				MemoryEvictThresholdPercent: pointer.Int64(70),
+				BEMemoryHigh: &slov1alpha1.BEMemoryHighStrategy{
+					Enable: pointer.Bool(false),
+				},
+			},
+		},
+		{
+			name: "use default config",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				MemoryEvictThresholdPercent: pointer.Int64(70),
+				BEMemoryHigh: &slov1alpha1.BEMemoryHighStrategy{
+					Enable: pointer.Bool(true),
+				},
+			},
+			want: &beMemoryHighConfig{
+				thresholdPercent: 70,
+				evictAfter:       defaultBEMemoryHighEvictAfterSeconds * time.Second,
+			},
+		},
+		{
+			name: "use custom config",
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				MemoryEvictThresholdPercent: pointer.Int64(70),
+				BEMemoryHigh: &slov1alpha1.BEMemoryHighStrategy{
+					Enable:            pointer.Bool(true),
+					ThresholdPercent:  pointer.Int64(75),
+					PodLevel:          pointer.Bool(true),
+					EvictAfterSeconds: pointer.Int64(30),
+				},
+			},
+			want: &beMemoryHighConfig{
+				thresholdPercent: 75,
+				podLevel:         true,
+				evictAfter:       30 * time.Second,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseBEMemoryHighConfig(tt.thresholdConfig)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_memoryEvictor_waitBEMemoryHighBeforeEvict(t *testing.T) {
+	thresholdConfig := &slov1alpha1.ResourceThresholdStrategy{
+		MemoryEvictThresholdPercent: pointer.Int64(80),
+		BEMemoryHigh: &slov1alpha1.BEMemoryHighStrategy{
+			Enable:            pointer.Bool(true),
+			EvictAfterSeconds: pointer.Int64(30),
+		},
+	}
+	m := &memoryEvictor{}
+	// the node memory usage just exceeds the threshold
+	assert.True(t, m.waitBEMemoryHighBeforeEvict(thresholdConfig))
+	assert.False(t, m.nodeMemoryExceededSince.IsZero())
+	assert.True(t, m.waitBEMemoryHighBeforeEvict(thresholdConfig))
+
+	// the node memory usage exceeds the threshold for too long, evict by the threshold
+	m.nodeMemoryExceededSince = time.Now().Add(-time.Minute)
+	assert.False(t, m.waitBEMemoryHighBeforeEvict(thresholdConfig))
+
+	// no wait without the strategy
+	m = &memoryEvictor{}
+	assert.False(t, m.waitBEMemoryHighBeforeEvict(&slov1alpha1.ResourceThresholdStrategy{
+		MemoryEvictThresholdPercent: pointer.Int64(80),
+	}))
+}
+
+func Test_calculateBEPodMemoryHigh(t *testing.T) {
+	tests := []struct {
+		name             string
+		podMemoryUsed    int64
+		podMemoryRequest int64
+		beMemoryHigh     int64
+		beMemoryUsed     int64
+		want             int64
+	}{
+		{
+			name:          "pod can use up the remaining memory",
+			podMemoryUsed: 2 << 30,
+			beMemoryHigh:  10 << 30,
+			beMemoryUsed:  6 << 30,
+			want:          6 << 30,
+		},
+		{
+			name:          "pod is throttled in proportion to its usage",
+			podMemoryUsed: 2 << 30,
+			beMemoryHigh:  6 << 30,
+			beMemoryUsed:  8 << 30,
+			want:          3 << 29,
+		},
+		{
+			name:             "pod memory.high is no less than its request",
+			podMemoryUsed:    2 << 30,
+			podMemoryRequest: 2 << 30,
+			beMemoryHigh:     6 << 30,
+			beMemoryUsed:     8 << 30,
+			want:             2 << 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateBEPodMemoryHigh(tt.podMemoryUsed, tt.podMemoryRequest, tt.beMemoryHigh, tt.beMemoryUsed)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_memoryEvictor_manageBEMemoryHigh(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	bePod := createMemoryEvictTestPod("test_be_pod", apiext.QoSBE, 100)
+	bePod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		apiext.BatchMemory: resource.MustParse("4G"),
+	}
+	lsPod := createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500)
+	bePodCgroupDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podtest_be_pod.slice"
+	lsPodCgroupDir := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podtest_ls_pod.slice"
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	helper.WriteCgroupFileContents(beCgroupDir, system.MemoryHighV2, "max")
+	helper.WriteCgroupFileContents(bePodCgroupDir, system.MemoryHighV2, "max")
+	helper.WriteCgroupFileContents(lsPodCgroupDir, system.MemoryHighV2, "max")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{Pod: bePod, CgroupDir: bePodCgroupDir},
+		{Pod: lsPod, CgroupDir: lsPodCgroupDir},
+	}).AnyTimes()
+
+	m := &memoryEvictor{
+		statesInformer: mockStatesInformer,
+		executor: &resourceexecutor.ResourceUpdateExecutorImpl{
+			Config:        resourceexecutor.NewDefaultConfig(),
+			ResourceCache: cache.NewCacheDefault(),
+		},
+	}
+
+	memoryCapacity := int64(100 << 30)
+	podMetrics := map[string]float64{
+		"test_be_pod": 10 << 30,
+		"test_ls_pod": 50 << 30,
+	}
+	thresholdConfig := &slov1alpha1.ResourceThresholdStrategy{
+		MemoryEvictThresholdPercent: pointer.Int64(80),
+		MemoryPressureRelief: &slov1alpha1.MemoryPressureReliefStrategy{
+			Enable: pointer.Bool(true),
+			Reclaim: &slov1alpha1.MemoryReclaimStage{
+				MemoryReliefStage: slov1alpha1.MemoryReliefStage{
+					Enable: pointer.Bool(false),
+				},
+			},
+		},
+		BEMemoryHigh: &slov1alpha1.BEMemoryHighStrategy{
+			Enable:   pointer.Bool(true),
+			PodLevel: pointer.Bool(true),
+		},
+	}
+
+	// headroom: 100Gi * 80% - (70Gi - 10Gi) = 20Gi
+	managed, needRelease := m.manageBEMemoryHigh(thresholdConfig, memoryCapacity, 70<<30, podMetrics)
+	assert.True(t, managed)
+	assert.Equal(t, int64(0), needRelease)
+	assert.Equal(t, "21474836480", helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+	assert.Equal(t, "21474836480", helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryHighV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(lsPodCgroupDir, system.MemoryHighV2))
+
+	// headroom: 100Gi * 80% - (85Gi - 10Gi) = 5Gi, BE memory usage exceeds the memory.high and waits for the reclaim
+	managed, needRelease = m.manageBEMemoryHigh(thresholdConfig, memoryCapacity, 85<<30, podMetrics)
+	assert.True(t, managed)
+	assert.Equal(t, int64(0), needRelease)
+	assert.Equal(t, "5368709120", helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+	assert.Equal(t, "5368709120", helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryHighV2))
+	assert.False(t, m.beMemoryHighExceededSince.IsZero())
+
+	// the throttle stage of the memory pressure relief is skipped
+	assert.False(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 85, podMetrics, managed))
+	assert.Equal(t, "5368709120", helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+
+	// BE memory usage exceeds the memory.high for too long
+	m.beMemoryHighExceededSince = time.Now().Add(-2 * defaultBEMemoryHighEvictAfterSeconds * time.Second)
+	managed, needRelease = m.manageBEMemoryHigh(thresholdConfig, memoryCapacity, 85<<30, podMetrics)
+	assert.True(t, managed)
+	assert.Equal(t, int64(5<<30), needRelease)
+	assert.True(t, m.beMemoryHighExceededSince.IsZero())
+
+	// recover the memory.high when the strategy is disabled
+	disabledConfig := &slov1alpha1.ResourceThresholdStrategy{MemoryEvictThresholdPercent: pointer.Int64(80)}
+	managed, needRelease = m.manageBEMemoryHigh(disabledConfig, memoryCapacity, 85<<30, podMetrics)
+	assert.False(t, managed)
+	assert.Equal(t, int64(0), needRelease)
+	assert.Equal(t, system.CgroupMaxValueStr, helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+	assert.Equal(t, system.CgroupMaxValueStr, helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryHighV2))
+	assert.False(t, m.isBEMemoryHighManaged)
+	assert.False(t, m.isBEPodMemoryHighManaged)
+
+	// not supported on cgroups-v1
+	helper.SetCgroupsV2(false)
+	managed, _ = m.manageBEMemoryHigh(thresholdConfig, memoryCapacity, 70<<30, podMetrics)
+	assert.False(t, managed)
+}
@@ -44,14 +44,31 @@ const (
 var _ framework.QOSStrategy = &memoryEvictor{}
 
 type memoryEvictor struct {
-	evictInterval         time.Duration
-	evictCoolingInterval  time.Duration
-	metricCollectInterval time.Duration
-	statesInformer        statesinformer.StatesInformer
-	metricCache           metriccache.MetricCache
-	evictor               *framework.Evictor
-	lastEvictTime         time.Time
-	onlyEvictByAPI        bool
+	evictInterval           time.Duration
+	evictCoolingInterval    time.Duration
+	metricCollectInterval   time.Duration
+	coldPageCollectInterval time.Duration
+	statesInformer          statesinformer.StatesInformer
+	metricCache             metriccache.MetricCache
+	executor                resourceexecutor.ResourceUpdateExecutor
+	evictor                 *framework.Evictor
+	lastEvictTime           time.Time
+	onlyEvictByAPI          bool
+
+	// states of the memory pressure relief
+	lastReclaimTime     time.Time
+	lastThrottleTime    time.Time
+	isBEMemoryThrottled bool
+	// reliefMemoryUsage is the node memory usage when a stage of the memory pressure relief took effect last time
+	reliefMemoryUsage int64
+
+	// states of the BE memory.high strategy
+	isBEMemoryHighManaged     bool
+	isBEPodMemoryHighManaged  bool
+	beMemoryHighExceededSince time.Time
+	// nodeMemoryExceededSince is when the node memory usage exceeds the eviction threshold with the BE memory.high
+	// managed
+	nodeMemoryExceededSince time.Time
 }
 
 type podInfo struct {
@@ -61,12 +78,14 @@ type podInfo struct {
 
 func New(opt *framework.Options) framework.QOSStrategy {
 	return &memoryEvictor{
-		evictInterval:         time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
-		evictCoolingInterval:  time.Duration(opt.Config.MemoryEvictCoolTimeSeconds) * time.Second,
-		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
-		statesInformer:        opt.StatesInformer,
-		metricCache:           opt.MetricCache,
-		onlyEvictByAPI:        opt.Config.OnlyEvictByAPI,
+		evictInterval:           time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		evictCoolingInterval:    time.Duration(opt.Config.MemoryEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval:   opt.MetricAdvisorConfig.CollectResUsedInterval,
+		coldPageCollectInterval: opt.MetricAdvisorConfig.ColdPageCollectorInterval,
+		statesInformer:          opt.StatesInformer,
+		metricCache:             opt.MetricCache,
+		executor:                resourceexecutor.NewResourceUpdateExecutor(),
+		onlyEvictByAPI:          opt.Config.OnlyEvictByAPI,
 	}
 }
 
@@ -79,6 +98,7 @@ func (m *memoryEvictor) Setup(ctx *framework.Context) {
 }
 
 func (m *memoryEvictor) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
 	go wait.Until(m.memoryEvict, m.evictInterval, stopCh)
 }
 
@@ -147,10 +167,30 @@ func (m *memoryEvictor) memoryEvict() {
 		return
 	}
 	nodeMemoryUsage := int64(nodeMemoryUsed) * 100 / memoryCapacity
+	beMemoryHighManaged, beMemoryNeedRelease := m.manageBEMemoryHigh(thresholdConfig, memoryCapacity, int64(nodeMemoryUsed), podMetrics)
+	if beMemoryNeedRelease > 0 {
+		klog.Infof("BE memory usage exceeds memory.high for too long, node MemoryUsage(%v): %.2f",
+			nodeMemoryUsed, float64(nodeMemoryUsage)/100)
+		m.killAndEvictBEPods(node, podMetrics, beMemoryNeedRelease)
+		return
+	}
+	isRelieving := m.relieveMemoryPressure(thresholdConfig, memoryCapacity, nodeMemoryUsage, podMetrics, beMemoryHighManaged)
+	if !beMemoryHighManaged || nodeMemoryUsage < *thresholdPercent {
+		m.nodeMemoryExceededSince = time.Time{}
+	} else if m.waitBEMemoryHighBeforeEvict(thresholdConfig) {
+		klog.V(5).Infof("skip memory evict by threshold, BE pods are throttled by memory.high, node memory usage(%v)", nodeMemoryUsage)
+		return
+	}
 	if nodeMemoryUsage < *thresholdPercent {
 		klog.V(5).Infof("skip memory evict, node memory usage(%v) is below threshold(%v)", nodeMemoryUsage, *thresholdPercent)
 		return
 	}
+	// the relief only holds the eviction above the threshold when the node memory usage actually drops
+	if isRelieving && nodeMemoryUsage < m.reliefMemoryUsage {
+		klog.V(4).Infof("skip memory evict, wait for the effect of memory pressure relief, node memory usage(%v) drops from %v",
+			nodeMemoryUsage, m.reliefMemoryUsage)
+		return
+	}
 
 	klog.Infof("node MemoryUsage(%v): %.2f, evictThresholdUsage: %.2f, evictLowerUsage: %.2f",
 		nodeMemoryUsed,
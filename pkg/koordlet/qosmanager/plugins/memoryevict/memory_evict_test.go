@@ -62,6 +62,8 @@ func Test_memoryEvict(t *testing.T) {
 		thresholdConfig    *slov1alpha1.ResourceThresholdStrategy
 		expectEvictPods    []*corev1.Pod
 		expectNotEvictPods []*corev1.Pod
+		isReliefCooling    bool
+		reliefMemoryUsage  int64
 	}
 
 	tests := []args{
@@ -335,8 +337,97 @@ func Test_memoryEvict(t *testing.T) {
 				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
 			},
 		},
+		{
+			name: "test_memoryevict_relief_cooling_85",
+			node: testutil.MockTestNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+			nodeMemUsed: resource.MustParse("102G"),
+			podMetrics: []podMemSample{
+				{UID: "test_lsr_pod", MemUsed: resource.MustParse("40G")},
+				{UID: "test_ls_pod", MemUsed: resource.MustParse("20G")},
+				{UID: "test_noqos_pod", MemUsed: resource.MustParse("7G")},
+				{UID: "test_be_pod_priority100_1", MemUsed: resource.MustParse("5G")},
+				{UID: "test_be_pod_priority100_2", MemUsed: resource.MustParse("20G")},
+				{UID: "test_be_pod_priority120", MemUsed: resource.MustParse("10G")},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      pointer.Bool(true),
+				MemoryEvictThresholdPercent: pointer.Int64(80),
+				MemoryPressureRelief: &slov1alpha1.MemoryPressureReliefStrategy{
+					Enable: pointer.Bool(true),
+					Throttle: &slov1alpha1.MemoryReliefStage{
+						Enable: pointer.Bool(false),
+					},
+				},
+			}, // >93.6G
+			isReliefCooling:   true,
+			reliefMemoryUsage: 85,
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
+		{
+			name: "test_memoryevict_relief_cooling_usage_dropped_85",
+			node: testutil.MockTestNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+			nodeMemUsed: resource.MustParse("102G"),
+			podMetrics: []podMemSample{
+				{UID: "test_lsr_pod", MemUsed: resource.MustParse("40G")},
+				{UID: "test_ls_pod", MemUsed: resource.MustParse("20G")},
+				{UID: "test_noqos_pod", MemUsed: resource.MustParse("7G")},
+				{UID: "test_be_pod_priority100_1", MemUsed: resource.MustParse("5G")},
+				{UID: "test_be_pod_priority100_2", MemUsed: resource.MustParse("20G")},
+				{UID: "test_be_pod_priority120", MemUsed: resource.MustParse("10G")},
+			},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      pointer.Bool(true),
+				MemoryEvictThresholdPercent: pointer.Int64(80),
+				MemoryPressureRelief: &slov1alpha1.MemoryPressureReliefStrategy{
+					Enable: pointer.Bool(true),
+					Throttle: &slov1alpha1.MemoryReliefStage{
+						Enable: pointer.Bool(false),
+					},
+				},
+			}, // >93.6G
+			isReliefCooling:   true,
+			reliefMemoryUsage: 90,
+			expectEvictPods:   []*corev1.Pod{},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
 	}
 
+	defaultAggregateResultFactory := metriccache.DefaultAggregateResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = defaultAggregateResultFactory
+	}()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctl := gomock.NewController(t)
@@ -409,6 +500,10 @@ func Test_memoryEvict(t *testing.T) {
 			memoryEvictor.Setup(&framework.Context{Evictor: evictor})
 			memoryEvictor.lastEvictTime = time.Now().Add(-30 * time.Second)
 			memoryEvictor.onlyEvictByAPI = true
+			if tt.isReliefCooling {
+				memoryEvictor.lastReclaimTime = time.Now()
+				memoryEvictor.reliefMemoryUsage = tt.reliefMemoryUsage
+			}
 			memoryEvictor.memoryEvict()
 
 			// evict subresource will not be creat or update in client go testing, check evict object
@@ -0,0 +1,325 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	memoryReliefStageReclaim  = "reclaim"
+	memoryReliefStageThrottle = "throttle"
+
+	defaultMemoryReclaimThresholdGap     = 6
+	defaultMemoryThrottleThresholdGap    = 3
+	defaultMemoryReliefMaxReleasePercent = 5
+	defaultMemoryReliefCoolTimeSeconds   = 10
+)
+
+type memoryReliefStage struct {
+	enabled           bool
+	thresholdPercent  int64
+	maxReleasePercent int64
+	coolTime          time.Duration
+}
+
+func parseMemoryReliefStage(stage *slov1alpha1.MemoryReliefStage, defaultThresholdPercent int64) *memoryReliefStage {
+	s := &memoryReliefStage{
+		enabled:           true,
+		thresholdPercent:  defaultThresholdPercent,
+		maxReleasePercent: defaultMemoryReliefMaxReleasePercent,
+		coolTime:          defaultMemoryReliefCoolTimeSeconds * time.Second,
+	}
+	if stage == nil {
+		return s
+	}
+	if stage.Enable != nil {
+		s.enabled = *stage.Enable
+	}
+	if stage.ThresholdPercent != nil {
+		s.thresholdPercent = *stage.ThresholdPercent
+	}
+	if stage.MaxReleasePercent != nil {
+		s.maxReleasePercent = *stage.MaxReleasePercent
+	}
+	if stage.CoolTimeSeconds != nil {
+		s.coolTime = time.Duration(*stage.CoolTimeSeconds) * time.Second
+	}
+	return s
+}
+
+// needRelease returns the memory to release in this round for the stage, which is bounded by the max release bytes.
+func (s *memoryReliefStage) needRelease(memoryCapacity, nodeMemoryUsage int64) int64 {
+	need := memoryCapacity * (nodeMemoryUsage - s.thresholdPercent + memoryReleaseBufferPercent) / 100
+	return util.MinInt64(need, memoryCapacity*s.maxReleasePercent/100)
+}
+
+// relieveMemoryPressure relieves the node memory pressure by the stages in order, and it returns true if any stage
+// takes effect in this round or is still cooling after taking effect. The node memory usage when a stage takes effect
+// is recorded, so the eviction above the threshold only waits for the relief when the usage has actually dropped.
+// The throttle stage is skipped when the BE memory.high is managed by the BEMemoryHigh strategy.
+func (m *memoryEvictor) relieveMemoryPressure(thresholdConfig *slov1alpha1.ResourceThresholdStrategy, memoryCapacity,
+	nodeMemoryUsage int64, podMetrics map[string]float64, beMemoryHighManaged bool) bool {
+	reliefConfig := thresholdConfig.MemoryPressureRelief
+	if reliefConfig == nil || reliefConfig.Enable == nil || !*reliefConfig.Enable {
+		m.recoverBEMemoryHigh()
+		return false
+	}
+
+	evictThresholdPercent := *thresholdConfig.MemoryEvictThresholdPercent
+	var reclaimStageConfig *slov1alpha1.MemoryReliefStage
+	includeLS := false
+	if reliefConfig.Reclaim != nil {
+		reclaimStageConfig = &reliefConfig.Reclaim.MemoryReliefStage
+		includeLS = reliefConfig.Reclaim.IncludeLS != nil && *reliefConfig.Reclaim.IncludeLS
+	}
+	reclaimStage := parseMemoryReliefStage(reclaimStageConfig, evictThresholdPercent-defaultMemoryReclaimThresholdGap)
+	throttleStage := parseMemoryReliefStage(reliefConfig.Throttle, evictThresholdPercent-defaultMemoryThrottleThresholdGap)
+	if beMemoryHighManaged {
+		throttleStage.enabled = false
+	}
+
+	if !throttleStage.enabled || nodeMemoryUsage < throttleStage.thresholdPercent-memoryReleaseBufferPercent {
+		m.recoverBEMemoryHigh()
+	}
+
+	now := time.Now()
+	isReclaimTriggered := reclaimStage.enabled && nodeMemoryUsage >= reclaimStage.thresholdPercent
+	isReclaimCooling := now.Before(m.lastReclaimTime.Add(reclaimStage.coolTime))
+	if isReclaimTriggered && !isReclaimCooling {
+		memoryNeedRelease := reclaimStage.needRelease(memoryCapacity, nodeMemoryUsage)
+		if reclaimed := m.reclaimColdPages(memoryNeedRelease, includeLS); reclaimed > 0 {
+			m.lastReclaimTime = now
+			m.reliefMemoryUsage = nodeMemoryUsage
+			metrics.RecordMemoryReliefReleasedBytes(memoryReliefStageReclaim, float64(reclaimed))
+			klog.Infof("memory relief stage %s completed, memoryNeedRelease(%v) memoryReclaimed(%v)",
+				memoryReliefStageReclaim, memoryNeedRelease, reclaimed)
+			return true
+		}
+	}
+
+	isThrottleTriggered := throttleStage.enabled && nodeMemoryUsage >= throttleStage.thresholdPercent
+	isThrottleCooling := now.Before(m.lastThrottleTime.Add(throttleStage.coolTime))
+	if isThrottleTriggered && !isThrottleCooling {
+		memoryNeedRelease := throttleStage.needRelease(memoryCapacity, nodeMemoryUsage)
+		if throttled := m.throttleBEMemoryHigh(memoryNeedRelease, podMetrics); throttled > 0 {
+			m.lastThrottleTime = now
+			m.reliefMemoryUsage = nodeMemoryUsage
+			metrics.RecordMemoryReliefReleasedBytes(memoryReliefStageThrottle, float64(throttled))
+			klog.Infof("memory relief stage %s completed, memoryNeedRelease(%v) memoryThrottled(%v)",
+				memoryReliefStageThrottle, memoryNeedRelease, throttled)
+			return true
+		}
+	}
+
+	// wait for the effect of the stages which took effect within their cool time
+	if (isReclaimTriggered && isReclaimCooling) || (isThrottleTriggered && isThrottleCooling) {
+		klog.V(5).Infof("memory relief is cooling, reclaim cooling %v, throttle cooling %v",
+			isReclaimTriggered && isReclaimCooling, isThrottleTriggered && isThrottleCooling)
+		return true
+	}
+	return false
+}
+
+// reclaimColdPages reclaims the cold pages of the pods in the descending order of the cold page size, and returns
+// the estimated bytes reclaimed. Only the cold page samples collected after the last reclaim are considered.
+// On cgroups-v2, `memory.reclaim` reclaims the specified bytes. On cgroups-v1, `memory.force_empty` cannot specify the
+// bytes and reclaims as much as possible, so the whole cold page size of the pod is counted.
+func (m *memoryEvictor) reclaimColdPages(memoryNeedRelease int64, includeLS bool) int64 {
+	if memoryNeedRelease <= 0 {
+		return 0
+	}
+	isCgroupV2 := system.GetCurrentCgroupVersion() == system.CgroupVersionV2
+	reclaimResource := system.ResourceType(system.MemoryReclaimName)
+	if !isCgroupV2 {
+		reclaimResource = system.MemoryForceEmptyName
+	}
+	podMetas := m.statesInformer.GetAllPods()
+	coldPageQueryWindow := m.metricCollectInterval
+	if m.coldPageCollectInterval > coldPageQueryWindow {
+		coldPageQueryWindow = m.coldPageCollectInterval
+	}
+	queryParam := helpers.GenerateQueryParamsLast(coldPageQueryWindow * 2)
+	// the cold pages sampled before the last reclaim are stale
+	if queryParam.Start.Before(m.lastReclaimTime) {
+		lastReclaimTime := m.lastReclaimTime
+		queryParam.Start = &lastReclaimTime
+	}
+	coldPageMetrics := helpers.CollectAllPodMetrics(m.statesInformer, m.metricCache, *queryParam, metriccache.PodMemoryColdPageSizeMetric)
+
+	var candidates []*podInfo
+	podCgroupDirs := map[string]string{}
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		qosClass := extension.GetPodQoSClassRaw(pod)
+		if qosClass != extension.QoSBE && (!includeLS || qosClass != extension.QoSLS) {
+			continue
+		}
+		coldPageSize := coldPageMetrics[string(pod.UID)]
+		if coldPageSize <= 0 {
+			continue
+		}
+		candidates = append(candidates, &podInfo{pod: pod, memUsed: coldPageSize})
+		podCgroupDirs[string(pod.UID)] = podMeta.CgroupDir
+	}
+	// reclaim BE pods first, then the pods with more cold pages
+	sort.Slice(candidates, func(i, j int) bool {
+		iIsBE := extension.GetPodQoSClassRaw(candidates[i].pod) == extension.QoSBE
+		jIsBE := extension.GetPodQoSClassRaw(candidates[j].pod) == extension.QoSBE
+		if iIsBE != jIsBE {
+			return iIsBE
+		}
+		return candidates[i].memUsed > candidates[j].memUsed
+	})
+
+	memoryReclaimed := int64(0)
+	for _, candidate := range candidates {
+		if memoryReclaimed >= memoryNeedRelease {
+			break
+		}
+		reclaimBytes := int64(candidate.memUsed)
+		value := "0"
+		if isCgroupV2 {
+			reclaimBytes = util.MinInt64(reclaimBytes, memoryNeedRelease-memoryReclaimed) / system.PageSize * system.PageSize
+			if reclaimBytes <= 0 {
+				continue
+			}
+			value = strconv.FormatInt(reclaimBytes, 10)
+		}
+
+		podKey := util.GetPodKey(candidate.pod)
+		eventHelper := audit.V(3).Pod(candidate.pod.Namespace, candidate.pod.Name).Reason(resourceexecutor.ReclaimByNodeMemoryUsage).
+			Message("reclaim cold pages %v bytes", reclaimBytes)
+		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(reclaimResource, podCgroupDirs[string(candidate.pod.UID)], value, eventHelper)
+		if err != nil {
+			klog.V(4).Infof("failed to get memory reclaim updater for pod %s, err: %v", podKey, err)
+			continue
+		}
+		_, err = m.executor.Update(false, updater)
+		metrics.RecordMemoryReliefStageStatus(memoryReliefStageReclaim, err)
+		if err != nil {
+			klog.V(4).Infof("failed to reclaim cold pages for pod %s, err: %v", podKey, err)
+			continue
+		}
+		memoryReclaimed += reclaimBytes
+		klog.V(5).Infof("memory relief reclaims cold pages for pod %s, bytes %v", podKey, reclaimBytes)
+	}
+	return memoryReclaimed
+}
+
+// throttleBEMemoryHigh lowers the memory.high of the BE parent cgroup below the BE memory usage, and returns the
+// bytes throttled. The memory.high is no less than the sum of BE memory requests.
+// It is only supported on cgroups-v2 or the cgroups-v1 kernels providing the memory.high like Anolis OS.
+func (m *memoryEvictor) throttleBEMemoryHigh(memoryNeedRelease int64, podMetrics map[string]float64) int64 {
+	if memoryNeedRelease <= 0 {
+		return 0
+	}
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	if supported, msg := isMemoryHighSupported(beCgroupDir); !supported {
+		klog.V(5).Infof("skip memory relief stage %s, memory.high is unsupported, msg: %s", memoryReliefStageThrottle, msg)
+		return 0
+	}
+	beMemoryUsed, beMemoryRequest := int64(0), int64(0)
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+			continue
+		}
+		beMemoryUsed += int64(podMetrics[string(pod.UID)])
+		beMemoryRequest += util.GetPodBEMemoryByteRequestIgnoreUnlimited(pod)
+	}
+
+	memoryHigh := util.MaxInt64(beMemoryUsed-memoryNeedRelease, beMemoryRequest) / system.PageSize * system.PageSize
+	if memoryHigh >= beMemoryUsed {
+		klog.V(5).Infof("skip memory relief stage %s, BE memory used(%v) is no more than the request(%v)",
+			memoryReliefStageThrottle, beMemoryUsed, beMemoryRequest)
+		return 0
+	}
+
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).Message("update BE group to memory.high: %v", memoryHigh)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, beCgroupDir, strconv.FormatInt(memoryHigh, 10), eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get BE memory.high updater, err: %v", err)
+		return 0
+	}
+	_, err = m.executor.Update(false, updater)
+	metrics.RecordMemoryReliefStageStatus(memoryReliefStageThrottle, err)
+	if err != nil {
+		klog.V(4).Infof("failed to update BE memory.high, err: %v", err)
+		return 0
+	}
+	m.isBEMemoryThrottled = true
+	metrics.RecordBEMemoryHighBytes(float64(memoryHigh))
+	return beMemoryUsed - memoryHigh
+}
+
+// recoverBEMemoryHigh resets the memory.high of the BE parent cgroup to unlimited if it is throttled.
+func (m *memoryEvictor) recoverBEMemoryHigh() {
+	if !m.isBEMemoryThrottled {
+		return
+	}
+	if !m.resetBEMemoryHigh() {
+		return
+	}
+	m.isBEMemoryThrottled = false
+	klog.V(4).Infof("memory relief recovers the BE memory.high")
+}
+
+// resetBEMemoryHigh resets the memory.high of the BE parent cgroup to unlimited, and returns true if it succeeds.
+func (m *memoryEvictor) resetBEMemoryHigh() bool {
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeMemoryUsage).Message("recover BE group memory.high to unlimited")
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.MemoryHighName, beCgroupDir, system.CgroupMaxValueStr, eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get BE memory.high updater, err: %v", err)
+		return false
+	}
+	if _, err = m.executor.Update(false, updater); err != nil {
+		klog.Warningf("failed to recover BE memory.high, err: %v", err)
+		return false
+	}
+	metrics.RecordBEMemoryHighBytes(0)
+	return true
+}
+
+// isMemoryHighSupported checks if the memory.high is available, which is always true on cgroups-v2.
+func isMemoryHighSupported(parentDir string) (bool, string) {
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		return true, ""
+	}
+	r, err := system.GetCgroupResource(system.MemoryHighName)
+	if err != nil {
+		return false, err.Error()
+	}
+	return r.IsSupported(parentDir)
+}
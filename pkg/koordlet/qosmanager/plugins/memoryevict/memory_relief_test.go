@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func Test_parseMemoryReliefStage(t *testing.T) {
+	tests := []struct {
+		name                    string
+		stage                   *slov1alpha1.MemoryReliefStage
+		defaultThresholdPercent int64
+		want                    *memoryReliefStage
+	}{
+		{
+			name:                    "use default config",
+			defaultThresholdPercent: 64,
+			want: &memoryReliefStage{
+				enabled:           true,
+				thresholdPercent:  64,
+				maxReleasePercent: defaultMemoryReliefMaxReleasePercent,
+				coolTime:          defaultMemoryReliefCoolTimeSeconds * time.Second,
+			},
+		},
+		{
+			name: "use custom config",
+			stage: &slov1alpha1.MemoryReliefStage{
+				Enable:            pointer.Bool(false),
+				ThresholdPercent:  pointer.Int64(60),
+				MaxReleasePercent: pointer.Int64(2),
+				CoolTimeSeconds:   pointer.Int64(30),
+			},
+			defaultThresholdPercent: 64,
+			want: &memoryReliefStage{
+				enabled:           false,
+				thresholdPercent:  60,
+				maxReleasePercent: 2,
+				coolTime:          30 * time.Second,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMemoryReliefStage(tt.stage, tt.defaultThresholdPercent)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_memoryEvictor_relieveMemoryPressure(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	bePod := createMemoryEvictTestPod("test_be_pod", apiext.QoSBE, 100)
+	bePod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+		apiext.BatchMemory: resource.MustParse("4G"),
+	}
+	lsPod := createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500)
+	bePodCgroupDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-podtest_be_pod.slice"
+	lsPodCgroupDir := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-podtest_ls_pod.slice"
+	beCgroupDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	helper.CreateCgroupFile(bePodCgroupDir, system.MemoryReclaimV2)
+	helper.CreateCgroupFile(lsPodCgroupDir, system.MemoryReclaimV2)
+	helper.WriteCgroupFileContents(beCgroupDir, system.MemoryHighV2, "max")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{Pod: bePod, CgroupDir: bePodCgroupDir},
+		{Pod: lsPod, CgroupDir: lsPodCgroupDir},
+	}).AnyTimes()
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+	sampleTime := time.Now().Add(-20 * time.Second)
+	var samples []metriccache.MetricSample
+	for uid, value := range map[string]float64{"test_be_pod": 2 << 30, "test_ls_pod": 1 << 30} {
+		s, err := metriccache.PodMemoryColdPageSizeMetric.GenerateSample(metriccache.MetricPropertiesFunc.Pod(uid), sampleTime, value)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	appender := metricCache.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	m := New(&framework.Options{
+		StatesInformer:      mockStatesInformer,
+		MetricCache:         metricCache,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}).(*memoryEvictor)
+	m.executor = &resourceexecutor.ResourceUpdateExecutorImpl{
+		Config:        resourceexecutor.NewDefaultConfig(),
+		ResourceCache: cache.NewCacheDefault(),
+	}
+	m.coldPageCollectInterval = 30 * time.Second
+
+	memoryCapacity := int64(100 << 30)
+	podMetrics := map[string]float64{
+		"test_be_pod": 10 << 30,
+		"test_ls_pod": 50 << 30,
+	}
+	thresholdConfig := &slov1alpha1.ResourceThresholdStrategy{
+		MemoryEvictThresholdPercent: pointer.Int64(80),
+		MemoryPressureRelief: &slov1alpha1.MemoryPressureReliefStrategy{
+			Enable: pointer.Bool(true),
+			Reclaim: &slov1alpha1.MemoryReclaimStage{
+				MemoryReliefStage: slov1alpha1.MemoryReliefStage{
+					ThresholdPercent: pointer.Int64(70),
+				},
+			},
+			Throttle: &slov1alpha1.MemoryReliefStage{
+				ThresholdPercent: pointer.Int64(75),
+			},
+		},
+	}
+
+	// disabled
+	disabledConfig := &slov1alpha1.ResourceThresholdStrategy{MemoryEvictThresholdPercent: pointer.Int64(80)}
+	assert.False(t, m.relieveMemoryPressure(disabledConfig, memoryCapacity, 90, podMetrics, false))
+
+	// below the thresholds of all stages
+	assert.False(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 60, podMetrics, false))
+
+	// reclaim the cold pages of the BE pod, and LS pods are excluded by default
+	assert.True(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 71, podMetrics, false))
+	assert.Equal(t, "2147483648", helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryReclaimV2))
+	assert.Equal(t, "", helper.ReadCgroupFileContents(lsPodCgroupDir, system.MemoryReclaimV2))
+
+	// the reclaim stage is cooling, then the throttle stage lowers the BE memory.high
+	assert.True(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 76, podMetrics, false))
+	assert.Equal(t, "7516192768", helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+	assert.True(t, m.isBEMemoryThrottled)
+
+	// all stages are cooling, the eviction waits for the effect of the relief
+	assert.True(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 85, podMetrics, false))
+
+	// recover the BE memory.high when the pressure is relieved
+	assert.False(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 60, podMetrics, false))
+	assert.Equal(t, system.CgroupMaxValueStr, helper.ReadCgroupFileContents(beCgroupDir, system.MemoryHighV2))
+	assert.False(t, m.isBEMemoryThrottled)
+
+	// the reclaim stage has cooled down, but the cold pages sampled before the last reclaim are stale
+	helper.WriteCgroupFileContents(bePodCgroupDir, system.MemoryReclaimV2, "0")
+	m.lastReclaimTime = sampleTime.Add(time.Second)
+	assert.False(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 71, podMetrics, false))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryReclaimV2))
+
+	// the throttle stage is not supported on cgroups-v1 without memory.high
+	helper.SetCgroupsV2(false)
+	m.lastReclaimTime, m.lastThrottleTime = time.Time{}, time.Time{}
+	throttleOnlyConfig := thresholdConfig.DeepCopy()
+	throttleOnlyConfig.MemoryPressureRelief.Reclaim.Enable = pointer.Bool(false)
+	assert.False(t, m.relieveMemoryPressure(throttleOnlyConfig, memoryCapacity, 85, podMetrics, false))
+
+	// the reclaim stage uses memory.force_empty on cgroups-v1
+	helper.CreateCgroupFile(bePodCgroupDir, system.MemoryForceEmpty)
+	helper.WriteCgroupFileContents(bePodCgroupDir, system.MemoryForceEmpty, "1")
+	m.lastReclaimTime = sampleTime.Add(-time.Minute)
+	assert.True(t, m.relieveMemoryPressure(thresholdConfig, memoryCapacity, 85, podMetrics, false))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(bePodCgroupDir, system.MemoryForceEmpty))
+	assert.Equal(t, int64(85), m.reliefMemoryUsage)
+}
@@ -0,0 +1,418 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+var (
+	// dynamicAdjustInterval is the interval to adjust the resctrl policies when the resctrl policy is "dynamic"
+	dynamicAdjustInterval = 30 * time.Second
+	// dynamicAdjustStepPercent is the percent of a CAT range end or an MBA percent changed in one adjustment
+	dynamicAdjustStepPercent int64 = 10
+	// dynamicRelaxRounds is the number of the calm rounds before relaxing one step back to the configured policies
+	dynamicRelaxRounds = 3
+	// dynamicMetricWindow is the window to query the last resctrl and CPI metrics
+	dynamicMetricWindow = 2 * time.Minute
+	// dynamicLLCPressureRatio is the ratio of the LLC occupancy to the allocated cache to regard a group as pressured
+	dynamicLLCPressureRatio = 0.9
+	// dynamicMBPressureRatio is the ratio of the memory bandwidth to the LS allocation to regard a domain as pressured
+	dynamicMBPressureRatio = 0.9
+	// dynamicCPIDegradeRatio is the ratio of the CPI increase over the baseline to regard the LS pods as degraded
+	dynamicCPIDegradeRatio = 0.1
+	// dynamicCPIBaselineAlpha is the smoothing factor of the CPI baseline
+	dynamicCPIBaselineAlpha = 0.2
+)
+
+// resctrlPressure is the contention signals of the LS resctrl groups.
+type resctrlPressure struct {
+	// llc indicates the LLC occupancy of a LS group hits its allocation.
+	llc bool
+	// mb indicates the memory bandwidth hits the allocation of the LS groups.
+	mb bool
+	// cpiDegraded indicates the CPI of the LS pods worsens from the baseline.
+	cpiDegraded bool
+}
+
+/*
+dynamicResctrlState is the closed-loop state of the "dynamic" resctrl policy. The configured CAT ranges and MBA
+percents of the QoS classes are the baseline, and the state keeps the deltas to them:
+ 1. When the LLC occupancy of LS/LSR hits its allocation and the LS CPI worsens, the CAT range ends of LS/LSR grow
+    and the CAT range end of BE shrinks by one step.
+ 2. When the memory bandwidth hits the LS allocation and the LS CPI worsens, the MBA percent of BE shrinks by one step.
+ 3. After the pressure goes away for several rounds, the deltas relax by one step towards the configured policies.
+
+The adjusted values are always bounded by the min/max percents of the QoS classes.
+*/
+type dynamicResctrlState struct {
+	lastAdjustTime time.Time
+	// catEndDelta is the delta of the CAT range end to the configured one for each group
+	catEndDelta map[string]int64
+	// beMBADelta is the delta of the BE MBA percent to the configured one, which is not positive
+	beMBADelta int64
+	calmRounds int
+	// cpiBaseline is the smoothed CPI of the LS pods without the pressure
+	cpiBaseline float64
+
+	// lastMBBytes is the total memory bandwidth counter of each group and cache id in the last round
+	lastMBBytes map[string]map[int32]float64
+	lastMBTime  time.Time
+}
+
+func isResctrlPolicyDynamic(qosStrategy *slov1alpha1.ResourceQOSStrategy) bool {
+	return qosStrategy != nil && qosStrategy.Policies != nil && qosStrategy.Policies.ResctrlPolicy != nil &&
+		*qosStrategy.Policies.ResctrlPolicy == slov1alpha1.ResctrlQOSPolicyDynamic
+}
+
+func getResctrlQOSForGroup(qosStrategy *slov1alpha1.ResourceQOSStrategy, group string) *slov1alpha1.ResctrlQOSCfg {
+	resourceQoS := getResourceQOSForResctrlGroup(qosStrategy, group)
+	if resourceQoS == nil {
+		return nil
+	}
+	return resourceQoS.ResctrlQOS
+}
+
+// catRangeEndBounds returns the configured CAT range end and its lower and upper bounds for the dynamic policy.
+func catRangeEndBounds(resctrlQoS *slov1alpha1.ResctrlQOSCfg) (end, lower, upper int64, ok bool) {
+	if resctrlQoS == nil || resctrlQoS.CATRangeStartPercent == nil || resctrlQoS.CATRangeEndPercent == nil {
+		return 0, 0, 0, false
+	}
+	start, end := *resctrlQoS.CATRangeStartPercent, *resctrlQoS.CATRangeEndPercent
+	lower, upper = end, end
+	if resctrlQoS.CATRangeEndMinPercent != nil && *resctrlQoS.CATRangeEndMinPercent < lower {
+		lower = *resctrlQoS.CATRangeEndMinPercent
+	}
+	if resctrlQoS.CATRangeEndMaxPercent != nil && *resctrlQoS.CATRangeEndMaxPercent > upper {
+		upper = *resctrlQoS.CATRangeEndMaxPercent
+	}
+	// the range must keep at least 1 percent and not exceed the whole cache
+	if lower <= start {
+		lower = start + 1
+	}
+	if upper > 100 {
+		upper = 100
+	}
+	if lower > end || upper < end {
+		return 0, 0, 0, false
+	}
+	return end, lower, upper, true
+}
+
+// mbaPercentBounds returns the configured MBA percent and its lower bound for the dynamic policy.
+func mbaPercentBounds(resctrlQoS *slov1alpha1.ResctrlQOSCfg) (mba, lower int64, ok bool) {
+	if resctrlQoS == nil || resctrlQoS.MBAPercent == nil || *resctrlQoS.MBAPercent <= 0 || *resctrlQoS.MBAPercent > 100 {
+		return 0, 0, false
+	}
+	mba, lower = *resctrlQoS.MBAPercent, *resctrlQoS.MBAPercent
+	if resctrlQoS.MBAMinPercent != nil && *resctrlQoS.MBAMinPercent > 0 && *resctrlQoS.MBAMinPercent < lower {
+		lower = *resctrlQoS.MBAMinPercent
+	}
+	return mba, lower, true
+}
+
+func boundDelta(value, delta, lower, upper int64) int64 {
+	adjusted := value + delta
+	if adjusted < lower {
+		adjusted = lower
+	}
+	if adjusted > upper {
+		adjusted = upper
+	}
+	return adjusted - value
+}
+
+// relaxDelta moves the delta one step towards zero.
+func relaxDelta(delta, step int64) int64 {
+	if delta > step {
+		return delta - step
+	}
+	if delta < -step {
+		return delta + step
+	}
+	return 0
+}
+
+// adjust updates the deltas with the pressure of the current round.
+func (s *dynamicResctrlState) adjust(qosStrategy *slov1alpha1.ResourceQOSStrategy, pressure resctrlPressure) {
+	if s.catEndDelta == nil {
+		s.catEndDelta = map[string]int64{}
+	}
+	step := dynamicAdjustStepPercent
+
+	if (pressure.llc || pressure.mb) && pressure.cpiDegraded {
+		s.calmRounds = 0
+		if pressure.llc {
+			for _, group := range resctrlGroupList {
+				end, lower, upper, ok := catRangeEndBounds(getResctrlQOSForGroup(qosStrategy, group))
+				if !ok {
+					continue
+				}
+				groupStep := step
+				if group == BEResctrlGroup {
+					groupStep = -step
+				}
+				s.catEndDelta[group] = boundDelta(end, s.catEndDelta[group]+groupStep, lower, upper)
+			}
+		}
+		if pressure.mb {
+			if mba, lower, ok := mbaPercentBounds(getResctrlQOSForGroup(qosStrategy, BEResctrlGroup)); ok {
+				s.beMBADelta = boundDelta(mba, s.beMBADelta-step, lower, mba)
+			}
+		}
+		klog.V(4).Infof("resctrl dynamic policy tightened under pressure %+v, cat end delta %v, BE mba delta %v",
+			pressure, s.catEndDelta, s.beMBADelta)
+		return
+	}
+
+	s.calmRounds++
+	if s.calmRounds < dynamicRelaxRounds {
+		return
+	}
+	s.calmRounds = 0
+	relaxed := s.beMBADelta != 0
+	for group, delta := range s.catEndDelta {
+		relaxed = relaxed || delta != 0
+		s.catEndDelta[group] = relaxDelta(delta, step)
+	}
+	s.beMBADelta = relaxDelta(s.beMBADelta, step)
+	if relaxed {
+		klog.V(4).Infof("resctrl dynamic policy relaxed, cat end delta %v, BE mba delta %v", s.catEndDelta, s.beMBADelta)
+	}
+}
+
+// apply returns a copy of the qos strategy whose CAT range ends and MBA percents are adjusted by the deltas.
+func (s *dynamicResctrlState) apply(qosStrategy *slov1alpha1.ResourceQOSStrategy) *slov1alpha1.ResourceQOSStrategy {
+	adjusted := qosStrategy.DeepCopy()
+	for _, group := range resctrlGroupList {
+		resctrlQoS := getResctrlQOSForGroup(adjusted, group)
+		if end, lower, upper, ok := catRangeEndBounds(resctrlQoS); ok && s.catEndDelta[group] != 0 {
+			adjustedEnd := end + boundDelta(end, s.catEndDelta[group], lower, upper)
+			resctrlQoS.CATRangeEndPercent = &adjustedEnd
+		}
+	}
+	resctrlQoS := getResctrlQOSForGroup(adjusted, BEResctrlGroup)
+	if mba, lower, ok := mbaPercentBounds(resctrlQoS); ok && s.beMBADelta != 0 {
+		adjustedMBA := mba + boundDelta(mba, s.beMBADelta, lower, mba)
+		resctrlQoS.MBAPercent = &adjustedMBA
+	}
+	return adjusted
+}
+
+func (s *dynamicResctrlState) reset() {
+	*s = dynamicResctrlState{}
+}
+
+// getDynamicResctrlPolicy returns the qos strategy adjusted by the dynamic policy, and adjusts the policy once in
+// the dynamicAdjustInterval.
+func (r *resctrlReconcile) getDynamicResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	nodeCPUInfo *metriccache.NodeCPUInfo, now time.Time) *slov1alpha1.ResourceQOSStrategy {
+	s := &r.dynamicState
+	if now.Sub(s.lastAdjustTime) >= dynamicAdjustInterval {
+		s.lastAdjustTime = now
+		pressure := r.collectResctrlPressure(qosStrategy, nodeCPUInfo, now)
+		s.adjust(qosStrategy, pressure)
+	}
+	return s.apply(qosStrategy)
+}
+
+func (r *resctrlReconcile) collectResctrlPressure(qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	nodeCPUInfo *metriccache.NodeCPUInfo, now time.Time) resctrlPressure {
+	querier, err := r.metricCache.Querier(now.Add(-dynamicMetricWindow), now)
+	if err != nil {
+		klog.Warningf("failed to get querier for resctrl dynamic policy, err: %v", err)
+		return resctrlPressure{}
+	}
+	defer querier.Close()
+
+	cacheIDs := make([]int32, 0, len(nodeCPUInfo.TotalInfo.L3ToCPU))
+	for cacheID := range nodeCPUInfo.TotalInfo.L3ToCPU {
+		cacheIDs = append(cacheIDs, cacheID)
+	}
+
+	pressure := resctrlPressure{
+		llc: r.isLLCPressured(querier, qosStrategy, cacheIDs),
+		mb:  r.isMBPressured(querier, qosStrategy, nodeCPUInfo.BasicInfo, cacheIDs, now),
+	}
+	pressure.cpiDegraded = r.isLSCPIDegraded(querier, pressure.llc || pressure.mb)
+	return pressure
+}
+
+// isLLCPressured checks if the LLC occupancy of LSR or LS group hits the cache allocated on any cache id.
+func (r *resctrlReconcile) isLLCPressured(querier metriccache.Querier, qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	cacheIDs []int32) bool {
+	l3Size, err := system.GetL3CacheSize()
+	if err != nil || l3Size <= 0 {
+		klog.V(5).Infof("failed to get l3 cache size for resctrl dynamic policy, err: %v", err)
+		return false
+	}
+	for _, group := range []string{LSRResctrlGroup, LSResctrlGroup} {
+		resctrlQoS := getResctrlQOSForGroup(qosStrategy, group)
+		end, lower, upper, ok := catRangeEndBounds(resctrlQoS)
+		if !ok {
+			continue
+		}
+		end += boundDelta(end, r.dynamicState.catEndDelta[group], lower, upper)
+		allocated := float64(l3Size) * float64(end-*resctrlQoS.CATRangeStartPercent) / 100
+		for _, cacheID := range cacheIDs {
+			occupancy, err := queryLast(querier, metriccache.ResctrlLLCMetric,
+				metriccache.MetricPropertiesFunc.ResctrlLLC(group, int(cacheID)))
+			if err != nil {
+				continue
+			}
+			if occupancy >= dynamicLLCPressureRatio*allocated {
+				klog.V(5).Infof("resctrl group %s llc pressured on cache %d, occupancy %v, allocated %v",
+					group, cacheID, occupancy, allocated)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isMBPressured checks if the memory bandwidth of all groups hits the allocation of the LS groups on any cache id.
+// The bandwidth is calculated by the total counters of the current and the last rounds. The capacity is the known
+// maximum of the AMD CCD, or the configured capacity of a cache id on other vendors. Without a known capacity, the
+// memory bandwidth is never regarded as pressured.
+func (r *resctrlReconcile) isMBPressured(querier metriccache.Querier, qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	cpuBasicInfo extension.CPUBasicInfo, cacheIDs []int32, now time.Time) bool {
+	s := &r.dynamicState
+	curMBBytes := map[string]map[int32]float64{}
+	for _, group := range resctrlGroupList {
+		curMBBytes[group] = map[int32]float64{}
+		for _, cacheID := range cacheIDs {
+			value, err := queryLast(querier, metriccache.ResctrlMBMetric,
+				metriccache.MetricPropertiesFunc.ResctrlMB(group, int(cacheID), system.ResctrlMBMTotalName))
+			if err == nil {
+				curMBBytes[group][cacheID] = value
+			}
+		}
+	}
+	lastMBBytes, duration := s.lastMBBytes, now.Sub(s.lastMBTime).Seconds()
+	s.lastMBBytes, s.lastMBTime = curMBBytes, now
+	if lastMBBytes == nil || duration <= 0 {
+		return false
+	}
+	// the capacity is in bytes per second
+	capacity := r.mbCapacityGbps * 1e9 / 8
+	if cpuBasicInfo.VendorID == system.AMD_VENDOR_ID {
+		capacity = AMDCCDMaxMBGbps * 1e9 / 8
+	}
+	if capacity <= 0 {
+		klog.V(5).Infof("unknown memory bandwidth capacity of vendor %s for resctrl dynamic policy, skip the mb pressure",
+			cpuBasicInfo.VendorID)
+		return false
+	}
+
+	// the LS allocation is the share of the larger MBA percent of LSR and LS
+	lsMBAPercent := int64(0)
+	for _, group := range []string{LSRResctrlGroup, LSResctrlGroup} {
+		if mba, _, ok := mbaPercentBounds(getResctrlQOSForGroup(qosStrategy, group)); ok && mba > lsMBAPercent {
+			lsMBAPercent = mba
+		}
+	}
+	if lsMBAPercent <= 0 {
+		lsMBAPercent = 100
+	}
+
+	isPressured := false
+	for _, cacheID := range cacheIDs {
+		bandwidth := 0.0
+		for _, group := range resctrlGroupList {
+			cur, curOK := curMBBytes[group][cacheID]
+			last, lastOK := lastMBBytes[group][cacheID]
+			// skip the counters missing or reset
+			if curOK && lastOK && cur >= last {
+				bandwidth += (cur - last) / duration
+			}
+		}
+		allocated := capacity * float64(lsMBAPercent) / 100
+		if bandwidth > 0 && allocated > 0 && bandwidth >= dynamicMBPressureRatio*allocated {
+			klog.V(5).Infof("resctrl mb pressured on cache %d, bandwidth %v, allocated %v", cacheID, bandwidth, allocated)
+			isPressured = true
+		}
+	}
+	return isPressured
+}
+
+// isLSCPIDegraded checks if the CPI of the LS pods worsens from the baseline. The baseline is only updated without
+// the pressure. If there is no CPI collected, the LS pods are not regarded as degraded, so the partitions are not
+// tightened without the evidence of the LS performance.
+func (r *resctrlReconcile) isLSCPIDegraded(querier metriccache.Querier, isPressured bool) bool {
+	var cycles, instructions float64
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if group := getPodResctrlGroup(pod); group != LSRResctrlGroup && group != LSResctrlGroup {
+			continue
+		}
+		for _, containerStat := range pod.Status.ContainerStatuses {
+			if containerStat.ContainerID == "" {
+				continue
+			}
+			cycle, err := queryLast(querier, metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(pod.UID), containerStat.ContainerID, string(metriccache.CPIResourceCycle)))
+			if err != nil {
+				continue
+			}
+			instruction, err := queryLast(querier, metriccache.ContainerCPI, metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(pod.UID), containerStat.ContainerID, string(metriccache.CPIResourceInstruction)))
+			if err != nil {
+				continue
+			}
+			cycles += cycle
+			instructions += instruction
+		}
+	}
+	if instructions <= 0 {
+		klog.V(5).Infof("no CPI of LS pods for resctrl dynamic policy, regard as not degraded")
+		return false
+	}
+
+	s := &r.dynamicState
+	cpi := cycles / instructions
+	if s.cpiBaseline <= 0 {
+		s.cpiBaseline = cpi
+		return false
+	}
+	isDegraded := cpi > s.cpiBaseline*(1+dynamicCPIDegradeRatio)
+	if !isPressured {
+		s.cpiBaseline = (1-dynamicCPIBaselineAlpha)*s.cpiBaseline + dynamicCPIBaselineAlpha*cpi
+	}
+	klog.V(6).Infof("LS CPI %v, baseline %v, degraded %v", cpi, s.cpiBaseline, isDegraded)
+	return isDegraded
+}
+
+func queryLast(querier metriccache.Querier, resource metriccache.MetricResource,
+	properties map[metriccache.MetricProperty]string) (float64, error) {
+	result, err := helpers.Query(querier, resource, properties)
+	if err != nil {
+		return 0, err
+	}
+	return result.Value(metriccache.AggregationTypeLast)
+}
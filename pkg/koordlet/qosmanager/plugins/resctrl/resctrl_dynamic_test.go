@@ -0,0 +1,322 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func testingDynamicResctrlQOSStrategy() *slov1alpha1.ResourceQOSStrategy {
+	dynamicPolicy := slov1alpha1.ResctrlQOSPolicyDynamic
+	return &slov1alpha1.ResourceQOSStrategy{
+		Policies: &slov1alpha1.ResourceQOSPolicies{
+			ResctrlPolicy: &dynamicPolicy,
+		},
+		LSRClass: &slov1alpha1.ResourceQOS{
+			ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent: pointer.Int64(0),
+					CATRangeEndPercent:   pointer.Int64(100),
+					MBAPercent:           pointer.Int64(100),
+				},
+			},
+		},
+		LSClass: &slov1alpha1.ResourceQOS{
+			ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent:  pointer.Int64(0),
+					CATRangeEndPercent:    pointer.Int64(60),
+					CATRangeEndMaxPercent: pointer.Int64(80),
+					MBAPercent:            pointer.Int64(100),
+				},
+			},
+		},
+		BEClass: &slov1alpha1.ResourceQOS{
+			ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent:  pointer.Int64(0),
+					CATRangeEndPercent:    pointer.Int64(50),
+					CATRangeEndMinPercent: pointer.Int64(30),
+					MBAPercent:            pointer.Int64(100),
+					MBAMinPercent:         pointer.Int64(80),
+				},
+			},
+		},
+	}
+}
+
+func testingGetDynamicResctrlValues(qosStrategy *slov1alpha1.ResourceQOSStrategy) (lsEnd, beEnd, beMBA int64) {
+	return *qosStrategy.LSClass.ResctrlQOS.CATRangeEndPercent, *qosStrategy.BEClass.ResctrlQOS.CATRangeEndPercent,
+		*qosStrategy.BEClass.ResctrlQOS.MBAPercent
+}
+
+func Test_catRangeEndBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		arg        *slov1alpha1.ResctrlQOSCfg
+		wantEnd    int64
+		wantLower  int64
+		wantUpper  int64
+		wantStatus bool
+	}{
+		{
+			name:       "nil config",
+			wantStatus: false,
+		},
+		{
+			name: "no bounds",
+			arg: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent: pointer.Int64(0),
+					CATRangeEndPercent:   pointer.Int64(50),
+				},
+			},
+			wantEnd:    50,
+			wantLower:  50,
+			wantUpper:  50,
+			wantStatus: true,
+		},
+		{
+			name: "bounds are limited by the range start and the whole cache",
+			arg: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent:  pointer.Int64(10),
+					CATRangeEndPercent:    pointer.Int64(50),
+					CATRangeEndMinPercent: pointer.Int64(0),
+					CATRangeEndMaxPercent: pointer.Int64(100),
+				},
+			},
+			wantEnd:    50,
+			wantLower:  11,
+			wantUpper:  100,
+			wantStatus: true,
+		},
+		{
+			name: "bounds excluding the configured end are ignored",
+			arg: &slov1alpha1.ResctrlQOSCfg{
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeStartPercent:  pointer.Int64(0),
+					CATRangeEndPercent:    pointer.Int64(50),
+					CATRangeEndMinPercent: pointer.Int64(60),
+					CATRangeEndMaxPercent: pointer.Int64(40),
+				},
+			},
+			wantEnd:    50,
+			wantLower:  50,
+			wantUpper:  50,
+			wantStatus: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotEnd, gotLower, gotUpper, gotStatus := catRangeEndBounds(tt.arg)
+			assert.Equal(t, tt.wantStatus, gotStatus)
+			assert.Equal(t, tt.wantEnd, gotEnd)
+			assert.Equal(t, tt.wantLower, gotLower)
+			assert.Equal(t, tt.wantUpper, gotUpper)
+		})
+	}
+}
+
+func Test_dynamicResctrlState_adjust(t *testing.T) {
+	qosStrategy := testingDynamicResctrlQOSStrategy()
+	s := &dynamicResctrlState{}
+
+	// no adjustment without the pressure
+	s.adjust(qosStrategy, resctrlPressure{cpiDegraded: true})
+	lsEnd, beEnd, beMBA := testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// no adjustment if the CPI does not worsen
+	s.adjust(qosStrategy, resctrlPressure{llc: true, mb: true})
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// LLC pressure grows the LS ways and shrinks the BE ways, within the bounds
+	s.adjust(qosStrategy, resctrlPressure{llc: true, cpiDegraded: true})
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{70, 40, 100}, []int64{lsEnd, beEnd, beMBA})
+	s.adjust(qosStrategy, resctrlPressure{llc: true, cpiDegraded: true})
+	s.adjust(qosStrategy, resctrlPressure{llc: true, cpiDegraded: true})
+	adjusted := s.apply(qosStrategy)
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(adjusted)
+	assert.Equal(t, []int64{80, 30, 100}, []int64{lsEnd, beEnd, beMBA})
+	// LSR has no room to grow
+	assert.Equal(t, int64(100), *adjusted.LSRClass.ResctrlQOS.CATRangeEndPercent)
+	// the configured strategy is not modified
+	assert.Equal(t, int64(60), *qosStrategy.LSClass.ResctrlQOS.CATRangeEndPercent)
+
+	// MB pressure shrinks the BE MBA
+	s.adjust(qosStrategy, resctrlPressure{mb: true, cpiDegraded: true})
+	s.adjust(qosStrategy, resctrlPressure{mb: true, cpiDegraded: true})
+	s.adjust(qosStrategy, resctrlPressure{mb: true, cpiDegraded: true})
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{80, 30, 80}, []int64{lsEnd, beEnd, beMBA})
+
+	// relax one step after the calm rounds
+	for i := 0; i < dynamicRelaxRounds-1; i++ {
+		s.adjust(qosStrategy, resctrlPressure{})
+	}
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{80, 30, 80}, []int64{lsEnd, beEnd, beMBA})
+	s.adjust(qosStrategy, resctrlPressure{})
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{70, 40, 90}, []int64{lsEnd, beEnd, beMBA})
+	for i := 0; i < 3*dynamicRelaxRounds; i++ {
+		s.adjust(qosStrategy, resctrlPressure{})
+	}
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// the deltas are bounded again when the bounds change
+	s.adjust(qosStrategy, resctrlPressure{llc: true, cpiDegraded: true})
+	s.adjust(qosStrategy, resctrlPressure{llc: true, cpiDegraded: true})
+	qosStrategy.LSClass.ResctrlQOS.CATRangeEndMaxPercent = pointer.Int64(65)
+	lsEnd, beEnd, _ = testingGetDynamicResctrlValues(s.apply(qosStrategy))
+	assert.Equal(t, []int64{65, 30}, []int64{lsEnd, beEnd})
+}
+
+func TestResctrlReconcile_getDynamicResctrlPolicy(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	// 10 MiB l3 cache, so the LS group is allocated 6 MiB
+	helper.WriteFileContents(system.GetSysCPUL3CacheSizePath(), "10240K")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfg := metriccache.NewDefaultConfig()
+	cfg.TSDBPath = t.TempDir()
+	cfg.TSDBEnablePromMetrics = false
+	metricCache, err := metriccache.NewMetricCache(cfg)
+	assert.NoError(t, err)
+
+	lsPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-ls-pod",
+			UID:    "test-ls-pod-uid",
+			Labels: map[string]string{extension.LabelPodQoS: string(extension.QoSLS)},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "test-container", ContainerID: "containerd://test-container-id"},
+			},
+		},
+	}
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsPod}}).AnyTimes()
+
+	r := &resctrlReconcile{
+		statesInformer: statesInformer,
+		metricCache:    metricCache,
+		// 1e9 bytes per second
+		mbCapacityGbps: 8,
+	}
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "3ff", VendorID: system.INTEL_VENDOR_ID},
+		TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}}},
+	}
+	qosStrategy := testingDynamicResctrlQOSStrategy()
+
+	appendSamples := func(ts time.Time, llc float64, mbCounter float64, cycles, instructions *float64) {
+		var samples []metriccache.MetricSample
+		s, err := metriccache.ResctrlLLCMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlLLC(LSResctrlGroup, 0), ts, llc)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+		s, err = metriccache.ResctrlMBMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.ResctrlMB(BEResctrlGroup, 0, system.ResctrlMBMTotalName), ts, mbCounter)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+		if cycles != nil && instructions != nil {
+			s, err = metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(lsPod.UID), "containerd://test-container-id", string(metriccache.CPIResourceCycle)), ts, *cycles)
+			assert.NoError(t, err)
+			samples = append(samples, s)
+			s, err = metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(
+				string(lsPod.UID), "containerd://test-container-id", string(metriccache.CPIResourceInstruction)), ts, *instructions)
+			assert.NoError(t, err)
+			samples = append(samples, s)
+		}
+		appender := metricCache.Appender()
+		assert.NoError(t, appender.Append(samples))
+		assert.NoError(t, appender.Commit())
+	}
+
+	now := time.Now().Add(-10 * time.Minute)
+	const gib = 1024 * 1024 * 1024
+
+	// round 1: the LS llc is pressured but there is no CPI, so the policy is not tightened
+	appendSamples(now, 5.8*1024*1024, 0, nil, nil)
+	got := r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, now)
+	lsEnd, beEnd, beMBA := testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// round 2: not adjusted in the interval
+	got = r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, now.Add(time.Second))
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// round 3: the CPI baseline is initialized, and the bandwidth is below the capacity
+	now = now.Add(dynamicAdjustInterval)
+	appendSamples(now, 6.5*1024*1024, 30*gib, pointer.Float64(1000), pointer.Float64(1000))
+	got = r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, now)
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// round 4: the CPI worsens, and both llc and bandwidth are pressured
+	now = now.Add(dynamicAdjustInterval)
+	appendSamples(now, 6.5*1024*1024, 60*gib, pointer.Float64(1500), pointer.Float64(1000))
+	got = r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, now)
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{70, 40, 90}, []int64{lsEnd, beEnd, beMBA})
+
+	// relax after the pressure goes away
+	for i := 0; i < dynamicRelaxRounds; i++ {
+		now = now.Add(dynamicAdjustInterval)
+		appendSamples(now, 1024*1024, 60*gib, pointer.Float64(1500), pointer.Float64(1000))
+		got = r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, now)
+	}
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+
+	// the memory bandwidth is not pressured without a known capacity
+	r.mbCapacityGbps = 0
+	now = now.Add(dynamicAdjustInterval)
+	appendSamples(now, 1024*1024, 120*gib, pointer.Float64(1500), pointer.Float64(1000))
+	assert.False(t, r.collectResctrlPressure(qosStrategy, nodeCPUInfo, now).mb)
+
+	// the state is reset for the static policy
+	r.dynamicState.reset()
+	got = r.dynamicState.apply(qosStrategy)
+	lsEnd, beEnd, beMBA = testingGetDynamicResctrlValues(got)
+	assert.Equal(t, []int64{60, 50, 100}, []int64{lsEnd, beEnd, beMBA})
+}
@@ -77,6 +77,10 @@ type resctrlReconcile struct {
 	metricCache       metriccache.MetricCache
 	cgroupReader      resourceexecutor.CgroupReader
 	eventRecorder     record.EventRecorder
+	// mbCapacityGbps is the configured memory bandwidth capacity of each cache id for the "dynamic" resctrl policy
+	mbCapacityGbps float64
+	// dynamicState is the state of the "dynamic" resctrl policy
+	dynamicState dynamicResctrlState
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -87,6 +91,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		executor:          resourceexecutor.NewResourceUpdateExecutor(),
 		cgroupReader:      opt.CgroupReader,
 		eventRecorder:     opt.EventRecorder,
+		mbCapacityGbps:    opt.Config.ResctrlMBCapacityGbps,
 	}
 }
 
@@ -424,6 +429,13 @@ func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.Re
 		return
 	}
 
+	// adjust the policies by the resctrl monitoring if the resctrl policy is dynamic
+	if isResctrlPolicyDynamic(qosStrategy) {
+		qosStrategy = r.getDynamicResctrlPolicy(qosStrategy, nodeCPUInfo, time.Now())
+	} else {
+		r.dynamicState.reset()
+	}
+
 	// calculate and apply l3 cat policy for each group
 	for _, group := range resctrlGroupList {
 		resQoSStrategy := getResourceQOSForResctrlGroup(qosStrategy, group)
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotewrite
+
+import (
+	"flag"
+	"time"
+
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+type Config struct {
+	// URL is the prometheus remote-write endpoint, the exporter is disabled if it is empty
+	URL             string
+	BearerTokenFile string
+	// Metrics maps the metric kinds to export to the min interval between the exported samples of a series,
+	// e.g. pod_cpu_usage=10s. An empty or zero interval exports every sample.
+	Metrics           map[string]string
+	FlushInterval     time.Duration
+	MaxSamplesPerSend int
+	Timeout           time.Duration
+	WALPath           string
+	WALMaxBytes       int64
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		URL:               "",
+		Metrics:           map[string]string{},
+		FlushInterval:     15 * time.Second,
+		MaxSamplesPerSend: 2000,
+		Timeout:           10 * time.Second,
+		WALPath:           "/metric-data/remote-write/",
+		WALMaxBytes:       32 * 1024 * 1024, // 32 MB
+	}
+}
+
+func (c *Config) InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.URL, "remote-write-url", c.URL, "The prometheus remote-write endpoint to export metric samples to. Disabled if empty.")
+	fs.StringVar(&c.BearerTokenFile, "remote-write-bearer-token-file", c.BearerTokenFile, "The file containing the bearer token of the remote-write endpoint.")
+	fs.Var(cliflag.NewMapStringString(&c.Metrics), "remote-write-metrics", "The metric kinds to export and the min sample interval of each series, e.g. pod_cpu_usage=10s,container_cpi=0s.")
+	fs.DurationVar(&c.FlushInterval, "remote-write-flush-interval", c.FlushInterval, "The interval to batch and send the exported samples.")
+	fs.IntVar(&c.MaxSamplesPerSend, "remote-write-max-samples-per-send", c.MaxSamplesPerSend, "The max number of samples in a batch, a batch is sent before the flush interval if it is full.")
+	fs.DurationVar(&c.Timeout, "remote-write-timeout", c.Timeout, "The timeout of a remote-write request.")
+	fs.StringVar(&c.WALPath, "remote-write-wal-path", c.WALPath, "The directory to persist the batches not yet sent to the remote-write endpoint.")
+	fs.Int64Var(&c.WALMaxBytes, "remote-write-wal-max-bytes", c.WALMaxBytes, "The max bytes of the pending batches, the oldest batches are dropped beyond it.")
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+const (
+	metricNameLabel = "__name__"
+
+	LabelNode         = "node"
+	LabelPod          = "pod"
+	LabelPodNamespace = "namespace"
+	LabelPodQoS       = "qos"
+)
+
+// exportedSeries records the last exported sample of a series.
+type exportedSeries struct {
+	timestamp int64
+	interval  int64
+}
+
+type sample struct {
+	labels    map[string]string
+	timestamp int64
+	value     float64
+}
+
+// Exporter streams the selected metric samples appended to the metric cache to a prometheus remote-write endpoint.
+// The samples are batched, relabelled with the pod meta from the states informer, persisted in the wal and then sent,
+// and the batches failed to send are retried in order.
+type Exporter struct {
+	config          *Config
+	nodeName        string
+	client          *http.Client
+	token           string
+	metricIntervals map[string]int64
+	wal             *wal
+	statesInformer  statesinformer.StatesInformer
+
+	lock          sync.Mutex
+	pending       []sample
+	lastExported  map[string]exportedSeries
+	flushCh       chan struct{}
+	retryInterval time.Duration
+	nextRetry     time.Time
+}
+
+func NewExporter(cfg *Config, nodeName string) (*Exporter, error) {
+	metricIntervals := map[string]int64{}
+	for kind, interval := range cfg.Metrics {
+		var d time.Duration
+		if interval != "" {
+			var err error
+			if d, err = time.ParseDuration(interval); err != nil {
+				return nil, fmt.Errorf("invalid sample interval of metric %s, err: %w", kind, err)
+			}
+		}
+		metricIntervals[kind] = d.Milliseconds()
+	}
+	var token string
+	if cfg.BearerTokenFile != "" {
+		b, err := os.ReadFile(cfg.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the remote-write bearer token file, err: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	w, err := openWAL(cfg.WALPath, cfg.WALMaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the remote-write wal, err: %w", err)
+	}
+	return &Exporter{
+		config:          cfg,
+		nodeName:        nodeName,
+		client:          &http.Client{Timeout: cfg.Timeout},
+		token:           token,
+		metricIntervals: metricIntervals,
+		wal:             w,
+		lastExported:    map[string]exportedSeries{},
+		flushCh:         make(chan struct{}, 1),
+	}, nil
+}
+
+// Wrap returns the metric cache whose appenders export the committed samples.
+func (e *Exporter) Wrap(metricCache metriccache.MetricCache) metriccache.MetricCache {
+	return &exportedMetricCache{MetricCache: metricCache, exporter: e}
+}
+
+func (e *Exporter) Setup(statesInformer statesinformer.StatesInformer) {
+	e.statesInformer = statesInformer
+}
+
+func (e *Exporter) Run(stopCh <-chan struct{}) error {
+	klog.Infof("starting remote-write exporter to %s, pending batches %d", e.config.URL, e.wal.len())
+	ticker := time.NewTicker(e.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flushCh:
+		case <-stopCh:
+			// persist the pending samples so that they are sent after restarting
+			if err := e.flush(); err != nil {
+				klog.Warningf("failed to flush remote-write samples before stopping, err: %v", err)
+			}
+			return nil
+		}
+		if err := e.flush(); err != nil {
+			klog.Warningf("failed to flush remote-write samples, err: %v", err)
+		}
+		e.sendPending()
+	}
+}
+
+// export queues the samples of the selected metrics, the samples of a series within its sample interval are skipped.
+func (e *Exporter) export(samples []metriccache.MetricSample) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	queued, limited := 0, 0
+	for _, s := range samples {
+		interval, ok := e.metricIntervals[s.GetKind()]
+		if !ok {
+			continue
+		}
+		labels := make(map[string]string, len(s.GetProperties())+1)
+		for k, v := range s.GetProperties() {
+			labels[k] = v
+		}
+		labels[metricNameLabel] = s.GetKind()
+		ts, value := metriccache.GetSamplePoint(s)
+		key := seriesKey(labels)
+		if last, ok := e.lastExported[key]; ok && ts < last.timestamp+interval {
+			limited++
+			continue
+		}
+		e.lastExported[key] = exportedSeries{timestamp: ts, interval: interval}
+		e.pending = append(e.pending, sample{labels: labels, timestamp: ts, value: value})
+		queued++
+	}
+	metrics.RecordRemoteWriteSamples(metrics.RemoteWriteStatusQueued, queued)
+	metrics.RecordRemoteWriteSamples(metrics.RemoteWriteStatusLimited, limited)
+	if len(e.pending) >= e.config.MaxSamplesPerSend {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// flush encodes the pending samples into batches and persists them in the wal.
+func (e *Exporter) flush() error {
+	e.lock.Lock()
+	pending := e.pending
+	e.pending = nil
+	// forget the series not updated in the max of its sample interval and two flush intervals, so that the series
+	// sampled less often than flushed are still rate limited
+	now := time.Now().UnixMilli()
+	minExpiration := (2 * e.config.FlushInterval).Milliseconds()
+	for key, last := range e.lastExported {
+		expiration := last.interval
+		if expiration < minExpiration {
+			expiration = minExpiration
+		}
+		if last.timestamp < now-expiration {
+			delete(e.lastExported, key)
+		}
+	}
+	e.lock.Unlock()
+
+	podLabels := e.getPodLabels()
+	for len(pending) > 0 {
+		n := len(pending)
+		if n > e.config.MaxSamplesPerSend {
+			n = e.config.MaxSamplesPerSend
+		}
+		data, err := e.encode(pending[:n], podLabels)
+		if err != nil {
+			return err
+		}
+		pending = pending[n:]
+		dropped, err := e.wal.append(data)
+		metrics.RecordRemoteWriteDroppedBatches(dropped)
+		if err != nil {
+			return err
+		}
+	}
+	metrics.RecordRemoteWriteWALBytes(e.wal.bytes())
+	return nil
+}
+
+// sendPending sends the batches in the wal in order until one fails to send.
+func (e *Exporter) sendPending() {
+	if time.Now().Before(e.nextRetry) {
+		return
+	}
+	defer func() {
+		metrics.RecordRemoteWriteWALBytes(e.wal.bytes())
+	}()
+	for {
+		seq, data, ok, err := e.wal.oldest()
+		if !ok {
+			e.retryInterval = 0
+			return
+		}
+		if err == nil {
+			err = e.send(data)
+		}
+		if err == nil {
+			if err = e.wal.remove(seq); err != nil {
+				klog.Warningf("failed to remove the remote-write wal segment %d, err: %v", seq, err)
+				return
+			}
+			e.retryInterval = 0
+			continue
+		}
+		metrics.RecordRemoteWriteFailure()
+		if _, recoverable := err.(recoverableError); !recoverable {
+			klog.Warningf("drop the remote-write batch %d, err: %v", seq, err)
+			metrics.RecordRemoteWriteDroppedBatches(1)
+			if err = e.wal.remove(seq); err != nil {
+				klog.Warningf("failed to remove the remote-write wal segment %d, err: %v", seq, err)
+				return
+			}
+			continue
+		}
+		// back off the retries exponentially up to 16 flush intervals
+		if e.retryInterval == 0 {
+			e.retryInterval = e.config.FlushInterval
+		} else if e.retryInterval < 16*e.config.FlushInterval {
+			e.retryInterval *= 2
+		}
+		e.nextRetry = time.Now().Add(e.retryInterval)
+		klog.V(4).Infof("failed to send the remote-write batch %d, retry after %v, err: %v", seq, e.retryInterval, err)
+		return
+	}
+}
+
+type recoverableError struct {
+	error
+}
+
+func (e *Exporter) send(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "koordlet")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return recoverableError{err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("remote-write server returned HTTP status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	// the same as prometheus, only the server errors and the rate limiting are retried
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		return recoverableError{err}
+	}
+	return err
+}
+
+// encode groups the samples by series and encodes them as a snappy compressed WriteRequest.
+func (e *Exporter) encode(samples []sample, podLabels map[string]map[string]string) ([]byte, error) {
+	seriesMap := map[string]*prompb.TimeSeries{}
+	var keys []string
+	for _, s := range samples {
+		key := seriesKey(s.labels)
+		series, ok := seriesMap[key]
+		if !ok {
+			series = &prompb.TimeSeries{Labels: e.relabel(s.labels, podLabels)}
+			seriesMap[key] = series
+			keys = append(keys, key)
+		}
+		series.Samples = append(series.Samples, prompb.Sample{Timestamp: s.timestamp, Value: s.value})
+	}
+	req := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(keys))}
+	for _, key := range keys {
+		series := seriesMap[key]
+		sort.Slice(series.Samples, func(i, j int) bool {
+			return series.Samples[i].Timestamp < series.Samples[j].Timestamp
+		})
+		req.Timeseries = append(req.Timeseries, *series)
+	}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+// relabel adds the node label and the pod meta labels to the sample labels, the existing labels are not overwritten.
+func (e *Exporter) relabel(sampleLabels map[string]string, podLabels map[string]map[string]string) []prompb.Label {
+	labels := make(map[string]string, len(sampleLabels)+4)
+	labels[LabelNode] = e.nodeName
+	if podUID, ok := sampleLabels[string(metriccache.MetricPropertyPodUID)]; ok {
+		for k, v := range podLabels[podUID] {
+			labels[k] = v
+		}
+	}
+	for k, v := range sampleLabels {
+		labels[k] = v
+	}
+	result := make([]prompb.Label, 0, len(labels))
+	for k, v := range labels {
+		result = append(result, prompb.Label{Name: k, Value: v})
+	}
+	// the labels of a series must be sorted by name
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (e *Exporter) getPodLabels() map[string]map[string]string {
+	if e.statesInformer == nil {
+		return nil
+	}
+	podLabels := map[string]map[string]string{}
+	for _, podMeta := range e.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		pod := podMeta.Pod
+		podLabels[string(pod.UID)] = map[string]string{
+			LabelPod:          pod.Name,
+			LabelPodNamespace: pod.Namespace,
+			LabelPodQoS:       string(apiext.GetPodQoSClassWithDefault(pod)),
+		}
+	}
+	return podLabels
+}
+
+func seriesKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k)
+		b.WriteByte(0xff)
+		b.WriteString(labels[k])
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
+
+// exportedMetricCache exports the samples committed by its appenders.
+type exportedMetricCache struct {
+	metriccache.MetricCache
+	exporter *Exporter
+}
+
+func (m *exportedMetricCache) Appender() metriccache.Appender {
+	return &exportedAppender{Appender: m.MetricCache.Appender(), exporter: m.exporter}
+}
+
+type exportedAppender struct {
+	metriccache.Appender
+	exporter *Exporter
+	samples  []metriccache.MetricSample
+}
+
+func (a *exportedAppender) Append(samples []metriccache.MetricSample) error {
+	if err := a.Appender.Append(samples); err != nil {
+		return err
+	}
+	a.samples = append(a.samples, samples...)
+	return nil
+}
+
+func (a *exportedAppender) Commit() error {
+	if err := a.Appender.Commit(); err != nil {
+		return err
+	}
+	a.exporter.export(a.samples)
+	a.samples = nil
+	return nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotewrite
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+type testRemoteWriteServer struct {
+	*httptest.Server
+	lock       sync.Mutex
+	statusCode int
+	requests   []*prompb.WriteRequest
+}
+
+func newTestRemoteWriteServer(t *testing.T) *testRemoteWriteServer {
+	s := &testRemoteWriteServer{statusCode: http.StatusNoContent}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.statusCode/100 != 2 {
+			w.WriteHeader(s.statusCode)
+			return
+		}
+		compressed, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		assert.NoError(t, err)
+		req := &prompb.WriteRequest{}
+		assert.NoError(t, req.Unmarshal(data))
+		s.requests = append(s.requests, req)
+		w.WriteHeader(s.statusCode)
+	}))
+	return s
+}
+
+func (s *testRemoteWriteServer) setStatusCode(code int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.statusCode = code
+}
+
+func newTestExporter(t *testing.T, url string) *Exporter {
+	tokenFile := t.TempDir() + "/token"
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("test-token\n"), 0644))
+	cfg := NewDefaultConfig()
+	cfg.URL = url
+	cfg.BearerTokenFile = tokenFile
+	cfg.WALPath = t.TempDir()
+	cfg.Metrics = map[string]string{
+		string(metriccache.PodMetricCPUUsage):  "10s",
+		string(metriccache.NodeMetricCPUUsage): "",
+	}
+	e, err := NewExporter(cfg, "test-node")
+	assert.NoError(t, err)
+	return e
+}
+
+func TestExporter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	server := newTestRemoteWriteServer(t)
+	defer server.Close()
+
+	e := newTestExporter(t, server.URL)
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "test-pod",
+				UID:       "test-pod-uid",
+				Labels:    map[string]string{apiext.LabelPodQoS: string(apiext.QoSLS)},
+			},
+		}},
+	}).AnyTimes()
+	e.Setup(si)
+
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	mockAppender := mock_metriccache.NewMockAppender(ctrl)
+	mockMetricCache.EXPECT().Appender().Return(mockAppender).AnyTimes()
+	mockAppender.EXPECT().Append(gomock.Any()).Return(nil).AnyTimes()
+	mockAppender.EXPECT().Commit().Return(nil).AnyTimes()
+	metricCache := e.Wrap(mockMetricCache)
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	var samples []metriccache.MetricSample
+	for i := 0; i < 3; i++ {
+		ts := now.Add(time.Duration(i) * 5 * time.Second)
+		s, err := metriccache.PodCPUUsageMetric.GenerateSample(map[metriccache.MetricProperty]string{
+			metriccache.MetricPropertyPodUID: "test-pod-uid",
+		}, ts, float64(i))
+		assert.NoError(t, err)
+		samples = append(samples, s)
+		s, err = metriccache.NodeCPUUsageMetric.GenerateSample(nil, ts, float64(i))
+		assert.NoError(t, err)
+		samples = append(samples, s)
+		s, err = metriccache.NodeMemoryUsageMetric.GenerateSample(nil, ts, float64(i))
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	appender := metricCache.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	// the endpoint is unavailable, the batch is kept in the wal
+	server.setStatusCode(http.StatusServiceUnavailable)
+	assert.NoError(t, e.flush())
+	e.sendPending()
+	assert.Equal(t, 1, e.wal.len())
+	assert.Empty(t, server.requests)
+
+	// the batch is retried after recovering
+	server.setStatusCode(http.StatusNoContent)
+	e.nextRetry = time.Time{}
+	e.sendPending()
+	assert.Equal(t, 0, e.wal.len())
+	assert.Len(t, server.requests, 1)
+	assert.Equal(t, []prompb.TimeSeries{
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: string(metriccache.PodMetricCPUUsage)},
+				{Name: LabelPodNamespace, Value: "default"},
+				{Name: LabelNode, Value: "test-node"},
+				{Name: LabelPod, Value: "test-pod"},
+				{Name: string(metriccache.MetricPropertyPodUID), Value: "test-pod-uid"},
+				{Name: LabelPodQoS, Value: string(apiext.QoSLS)},
+			},
+			// the sample at 5s is limited by the sample interval of 10s
+			Samples: []prompb.Sample{
+				{Timestamp: now.UnixMilli(), Value: 0},
+				{Timestamp: now.Add(10 * time.Second).UnixMilli(), Value: 2},
+			},
+		},
+		{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: string(metriccache.NodeMetricCPUUsage)},
+				{Name: LabelNode, Value: "test-node"},
+			},
+			Samples: []prompb.Sample{
+				{Timestamp: now.UnixMilli(), Value: 0},
+				{Timestamp: now.Add(5 * time.Second).UnixMilli(), Value: 1},
+				{Timestamp: now.Add(10 * time.Second).UnixMilli(), Value: 2},
+			},
+		},
+	}, server.requests[0].Timeseries)
+
+	// the rejected batch is dropped
+	s, err := metriccache.NodeCPUUsageMetric.GenerateSample(nil, now.Add(time.Minute), 1)
+	assert.NoError(t, err)
+	e.export([]metriccache.MetricSample{s})
+	assert.NoError(t, e.flush())
+	server.setStatusCode(http.StatusBadRequest)
+	e.sendPending()
+	assert.Equal(t, 0, e.wal.len())
+	assert.Len(t, server.requests, 1)
+}
+
+func TestExporterExpireSeries(t *testing.T) {
+	e := newTestExporter(t, "http://localhost")
+	e.config.Metrics[string(metriccache.NodeMetricMemoryUsage)] = "5m"
+	e.metricIntervals[string(metriccache.NodeMetricMemoryUsage)] = (5 * time.Minute).Milliseconds()
+	now := time.Now()
+	var samples []metriccache.MetricSample
+	for _, metric := range []metriccache.MetricResource{metriccache.NodeCPUUsageMetric, metriccache.NodeMemoryUsageMetric} {
+		s, err := metric.GenerateSample(nil, now.Add(-time.Minute), 1)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	e.export(samples)
+	assert.Len(t, e.lastExported, 2)
+
+	// the series sampled every 5m is kept longer than two flush intervals, so that it is still rate limited
+	assert.NoError(t, e.flush())
+	assert.Len(t, e.lastExported, 1)
+	s, err := metriccache.NodeMemoryUsageMetric.GenerateSample(nil, now, 2)
+	assert.NoError(t, err)
+	e.export([]metriccache.MetricSample{s})
+	assert.Empty(t, e.pending)
+}
+
+func TestExporterRecoverWAL(t *testing.T) {
+	server := newTestRemoteWriteServer(t)
+	defer server.Close()
+	server.setStatusCode(http.StatusInternalServerError)
+
+	e := newTestExporter(t, server.URL)
+	s, err := metriccache.NodeCPUUsageMetric.GenerateSample(nil, time.Now(), 1)
+	assert.NoError(t, err)
+	e.export([]metriccache.MetricSample{s})
+	assert.NoError(t, e.flush())
+	e.sendPending()
+	assert.Equal(t, 1, e.wal.len())
+
+	// the pending batches are sent after restarting
+	server.setStatusCode(http.StatusOK)
+	restarted, err := NewExporter(e.config, "test-node")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, restarted.wal.len())
+	restarted.sendPending()
+	assert.Equal(t, 0, restarted.wal.len())
+	assert.Len(t, server.requests, 1)
+}
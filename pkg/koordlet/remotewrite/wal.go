@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const walSegmentSuffix = ".seg"
+
+type walSegment struct {
+	seq  uint64
+	size int64
+}
+
+// wal persists each encoded batch as a segment file before it is sent, and removes the segment once the
+// remote endpoint accepts it, so the batches not yet sent survive the failures of the endpoint and koordlet restarts.
+type wal struct {
+	dir      string
+	maxBytes int64
+
+	lock     sync.Mutex
+	segments []walSegment
+	size     int64
+	nextSeq  uint64
+}
+
+func openWAL(dir string, maxBytes int64) (*wal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	w := &wal{dir: dir, maxBytes: maxBytes}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, walSegmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, walSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		w.segments = append(w.segments, walSegment{seq: seq, size: info.Size()})
+		w.size += info.Size()
+	}
+	sort.Slice(w.segments, func(i, j int) bool {
+		return w.segments[i].seq < w.segments[j].seq
+	})
+	if len(w.segments) > 0 {
+		w.nextSeq = w.segments[len(w.segments)-1].seq + 1
+	}
+	return w, nil
+}
+
+// append persists the data as a new segment, and drops the oldest segments if the wal exceeds the max bytes.
+// It returns the number of the dropped segments.
+func (w *wal) append(data []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	seq := w.nextSeq
+	path := w.segmentPath(seq)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	w.nextSeq++
+	w.segments = append(w.segments, walSegment{seq: seq, size: int64(len(data))})
+	w.size += int64(len(data))
+
+	dropped := 0
+	for w.maxBytes > 0 && w.size > w.maxBytes && len(w.segments) > 1 {
+		if err := w.removeLocked(w.segments[0].seq); err != nil {
+			return dropped, err
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+// oldest returns the sequence and the data of the oldest segment.
+func (w *wal) oldest() (uint64, []byte, bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.segments) == 0 {
+		return 0, nil, false, nil
+	}
+	seq := w.segments[0].seq
+	data, err := os.ReadFile(w.segmentPath(seq))
+	if err != nil {
+		return seq, nil, true, err
+	}
+	return seq, data, true, nil
+}
+
+func (w *wal) remove(seq uint64) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.removeLocked(seq)
+}
+
+func (w *wal) removeLocked(seq uint64) error {
+	for i, segment := range w.segments {
+		if segment.seq != seq {
+			continue
+		}
+		if err := os.Remove(w.segmentPath(seq)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		w.segments = append(w.segments[:i], w.segments[i+1:]...)
+		w.size -= segment.size
+		return nil
+	}
+	return nil
+}
+
+func (w *wal) len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return len(w.segments)
+}
+
+func (w *wal) bytes() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.size
+}
+
+func (w *wal) segmentPath(seq uint64) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", seq, walSegmentSuffix))
+}
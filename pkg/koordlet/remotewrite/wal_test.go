@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotewrite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWAL(t *testing.T) {
+	dir := t.TempDir()
+	w, err := openWAL(dir, 10)
+	assert.NoError(t, err)
+
+	dropped, err := w.append([]byte("aaaa"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	dropped, err = w.append([]byte("bbbb"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	// the oldest segment is dropped beyond the max bytes
+	dropped, err = w.append([]byte("cccc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, 2, w.len())
+	assert.Equal(t, int64(8), w.bytes())
+
+	seq, data, ok, err := w.oldest()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), seq)
+	assert.Equal(t, []byte("bbbb"), data)
+	assert.NoError(t, w.remove(seq))
+
+	// reopen the wal
+	w, err = openWAL(dir, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, w.len())
+	seq, data, ok, err = w.oldest()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), seq)
+	assert.Equal(t, []byte("cccc"), data)
+	_, err = w.append([]byte("dddd"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), w.nextSeq)
+
+	assert.NoError(t, w.remove(2))
+	assert.NoError(t, w.remove(3))
+	_, _, ok, err = w.oldest()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
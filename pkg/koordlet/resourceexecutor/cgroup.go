@@ -58,7 +58,8 @@ func cgroupFileWriteIfDifferent(cgroupTaskDir string, r sysutil.Resource, value
 	}
 	// FIXME(saintube): Instead of handling cpuset resource in writing function, we should use a updater and do
 	//  MergeUpdate in resourceexecutor's LeveledUpdateBatch.
-	if r.ResourceType() == sysutil.CPUSetCPUSName && cpuset.IsEqualStrCpus(currentValue, value) {
+	if (r.ResourceType() == sysutil.CPUSetCPUSName || r.ResourceType() == sysutil.CPUSetMemsName) &&
+		cpuset.IsEqualStrCpus(currentValue, value) {
 		return false, nil
 	}
 	if value == currentValue || value == CgroupMaxValueStr && currentValue == CgroupMaxSymbolStr {
@@ -27,7 +27,9 @@ const (
 	EvictPodByNodeMemoryUsage   = "EvictPodByNodeMemoryUsage"
 	EvictPodByBECPUSatisfaction = "EvictPodByBECPUSatisfaction"
 
-	AdjustBEByNodeCPUUsage = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeCPUUsage    = "AdjustBEByNodeCPUUsage"
+	AdjustBEByNodeMemoryUsage = "AdjustBEByNodeMemoryUsage"
+	ReclaimByNodeMemoryUsage  = "ReclaimByNodeMemoryUsage"
 )
 
 var Conf = NewDefaultConfig()
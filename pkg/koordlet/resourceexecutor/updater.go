@@ -56,6 +56,7 @@ func init() {
 		sysutil.MemoryUsePriorityOomName,
 		sysutil.MemoryOomGroupName,
 		sysutil.NetClsClassIdName,
+		sysutil.CPUSetMemoryMigrateName,
 	)
 	// special cases
 	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupUpdateCPUSharesFunc), sysutil.CPUSharesName)
@@ -69,6 +70,11 @@ func init() {
 	)
 	DefaultCgroupUpdaterFactory.Register(NewMergeableCgroupUpdaterWithConditionFunc(CommonCgroupUpdateFunc, MergeConditionIfCPUSetIsLooser),
 		sysutil.CPUSetCPUSName,
+		sysutil.CPUSetMemsName,
+	)
+	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupWriteOnlyUpdateFunc),
+		sysutil.MemoryForceEmptyName,
+		sysutil.MemoryReclaimName,
 	)
 	DefaultCgroupUpdaterFactory.Register(NewBlkIOResourceUpdater,
 		sysutil.BlkioTRIopsName,
@@ -383,6 +389,13 @@ func CgroupUpdateWithUnlimitedFunc(resource ResourceUpdater) error {
 	return cgroupWriteIfDifferentWithLog(c)
 }
 
+// CgroupWriteOnlyUpdateFunc writes the cgroup file without the read-before-write check. It is for the interfaces
+// like `memory.force_empty` and `memory.reclaim` which trigger an action on each write.
+func CgroupWriteOnlyUpdateFunc(resource ResourceUpdater) error {
+	c := resource.(*CgroupResourceUpdater)
+	return cgroupWriteWithLog(c)
+}
+
 func CgroupUpdateCPUSharesFunc(resource ResourceUpdater) error {
 	c := resource.(*CgroupResourceUpdater)
 	// convert values in `cpu.shares` (v1) into values in `cpu.weight` (v2)
@@ -535,6 +548,18 @@ func cgroupWriteIfDifferentWithLog(c *CgroupResourceUpdater) error {
 	return nil
 }
 
+func cgroupWriteWithLog(c *CgroupResourceUpdater) error {
+	if err := cgroupFileWrite(c.parentDir, c.file, c.value); err != nil {
+		return err
+	}
+	if c.eventHelper != nil {
+		_ = c.eventHelper.Do()
+	} else {
+		_ = audit.V(3).Reason(ReasonUpdateCgroups).Message("update %v to %v", c.Path(), c.Value()).Do()
+	}
+	return nil
+}
+
 func commonWriteIfDifferentWithLog(c *DefaultResourceUpdater) error {
 	updated, err := sysutil.CommonFileWriteIfDifferent(c.Path(), c.value)
 	if err != nil {
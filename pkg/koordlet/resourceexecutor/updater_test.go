@@ -179,6 +179,59 @@ func TestCgroupResourceUpdater_Update(t *testing.T) {
 	}
 }
 
+func TestCgroupWriteOnlyUpdateFunc(t *testing.T) {
+	tests := []struct {
+		name         string
+		useCgroupsV2 bool
+		resource     sysutil.ResourceType
+		initialValue string
+		value        string
+		want         string
+	}{
+		{
+			name:         "write memory.force_empty",
+			resource:     sysutil.MemoryForceEmptyName,
+			initialValue: "0",
+			value:        "0",
+			want:         "0",
+		},
+		{
+			name:         "write memory.reclaim",
+			useCgroupsV2: true,
+			resource:     sysutil.MemoryReclaimName,
+			initialValue: "0",
+			value:        "1048576",
+			want:         "1048576",
+		},
+		{
+			name:         "write memory.reclaim even if the value is the same",
+			useCgroupsV2: true,
+			resource:     sysutil.MemoryReclaimName,
+			initialValue: "1048576",
+			value:        "1048576",
+			want:         "1048576",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupsV2)
+
+			parentDir := "/kubepods.slice/kubepods.slice-podxxx"
+			u, gotErr := DefaultCgroupUpdaterFactory.New(tt.resource, parentDir, tt.value, nil)
+			assert.NoError(t, gotErr)
+			c, ok := u.(*CgroupResourceUpdater)
+			assert.True(t, ok)
+			helper.WriteCgroupFileContents(parentDir, c.file, tt.initialValue)
+
+			gotErr = u.update()
+			assert.NoError(t, gotErr)
+			assert.Equal(t, tt.want, helper.ReadCgroupFileContents(parentDir, c.file))
+		})
+	}
+}
+
 func TestCgroupResourceUpdater_MergeUpdate(t *testing.T) {
 	type fields struct {
 		UseCgroupsV2 bool
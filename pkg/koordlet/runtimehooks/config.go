@@ -34,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/groupidentity"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/rdma"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/resctrl"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/staticdevice"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/tc"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/terwayqos"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -67,6 +68,12 @@ const (
 	// alpha: v1.6
 	RDMADeviceInject featuregate.Feature = "RDMADeviceInject"
 
+	// StaticDeviceInject injects the device nodes and envs declared by the device descriptors according to allocate
+	// result from koord-scheduler.
+	//
+	// alpha: v1.6
+	StaticDeviceInject featuregate.Feature = "StaticDeviceInject"
+
 	// BatchResource sets request and limits of cpu and memory on cgroup file according batch resources.
 	//
 	// owner: @saintube @zwzhang0107
@@ -105,29 +112,31 @@ const (
 
 var (
 	defaultRuntimeHooksFG = map[featuregate.Feature]featuregate.FeatureSpec{
-		GroupIdentity:    {Default: true, PreRelease: featuregate.Beta},
-		CPUSetAllocator:  {Default: true, PreRelease: featuregate.Beta},
-		GPUEnvInject:     {Default: false, PreRelease: featuregate.Alpha},
-		RDMADeviceInject: {Default: false, PreRelease: featuregate.Alpha},
-		BatchResource:    {Default: true, PreRelease: featuregate.Beta},
-		CPUNormalization: {Default: false, PreRelease: featuregate.Alpha},
-		CoreSched:        {Default: false, PreRelease: featuregate.Alpha},
-		TerwayQoS:        {Default: false, PreRelease: featuregate.Alpha},
-		TCNetworkQoS:     {Default: false, PreRelease: featuregate.Alpha},
-		Resctrl:          {Default: false, PreRelease: featuregate.Alpha},
+		GroupIdentity:      {Default: true, PreRelease: featuregate.Beta},
+		CPUSetAllocator:    {Default: true, PreRelease: featuregate.Beta},
+		GPUEnvInject:       {Default: false, PreRelease: featuregate.Alpha},
+		RDMADeviceInject:   {Default: false, PreRelease: featuregate.Alpha},
+		StaticDeviceInject: {Default: false, PreRelease: featuregate.Alpha},
+		BatchResource:      {Default: true, PreRelease: featuregate.Beta},
+		CPUNormalization:   {Default: false, PreRelease: featuregate.Alpha},
+		CoreSched:          {Default: false, PreRelease: featuregate.Alpha},
+		TerwayQoS:          {Default: false, PreRelease: featuregate.Alpha},
+		TCNetworkQoS:       {Default: false, PreRelease: featuregate.Alpha},
+		Resctrl:            {Default: false, PreRelease: featuregate.Alpha},
 	}
 
 	runtimeHookPlugins = map[featuregate.Feature]HookPlugin{
-		GroupIdentity:    groupidentity.Object(),
-		CPUSetAllocator:  cpuset.Object(),
-		GPUEnvInject:     gpu.Object(),
-		RDMADeviceInject: rdma.Object(),
-		BatchResource:    batchresource.Object(),
-		CPUNormalization: cpunormalization.Object(),
-		CoreSched:        coresched.Object(),
-		TerwayQoS:        terwayqos.Object(),
-		TCNetworkQoS:     tc.Object(),
-		Resctrl:          resctrl.Object(),
+		GroupIdentity:      groupidentity.Object(),
+		CPUSetAllocator:    cpuset.Object(),
+		GPUEnvInject:       gpu.Object(),
+		RDMADeviceInject:   rdma.Object(),
+		StaticDeviceInject: staticdevice.Object(),
+		BatchResource:      batchresource.Object(),
+		CPUNormalization:   cpunormalization.Object(),
+		CoreSched:          coresched.Object(),
+		TerwayQoS:          terwayqos.Object(),
+		TCNetworkQoS:       tc.Object(),
+		Resctrl:            resctrl.Object(),
 	}
 )
 
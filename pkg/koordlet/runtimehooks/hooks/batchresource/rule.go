@@ -101,10 +101,11 @@ func (p *plugin) parseRuleForNodeSLO(mergedNodeSLOIf interface{}) (bool, error)
 	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
 
 	enableCFSQuota := true
-	// NOTE: If CPU Suppress Policy `CPUCfsQuotaPolicy` is enabled for batch pods, batch pods' cfs_quota should be unset
+	// NOTE: If CPU Suppress Policy `CPUCfsQuotaPolicy` or `CPUAdaptivePolicy` is enabled for batch pods, batch pods' cfs_quota should be unset
 	// since the cfs quota of `kubepods-besteffort` is required to be no less than the children's. Then the cpu usage
 	// of Batch is limited by pod-level cpu.shares and qos-level cfs_quota.
-	if enable, policy := getCPUSuppressPolicy(mergedNodeSLO); enable && policy == slov1alpha1.CPUCfsQuotaPolicy {
+	if enable, policy := getCPUSuppressPolicy(mergedNodeSLO); enable && (policy == slov1alpha1.CPUCfsQuotaPolicy ||
+		policy == slov1alpha1.CPUAdaptivePolicy) {
 		enableCFSQuota = false
 	}
 
@@ -67,6 +67,12 @@ func (c *CookieCacheEntry) IsEntryInvalid() bool {
 	return c.cookieID <= sysutil.DefaultCoreSchedCookieID || c.pidCache.Len() <= 0
 }
 
+func (c *CookieCacheEntry) GetPIDNum() int {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+	return c.pidCache.Len()
+}
+
 func (c *CookieCacheEntry) HasPID(pid uint32) bool {
 	c.rwMutex.RLock()
 	defer c.rwMutex.RUnlock()
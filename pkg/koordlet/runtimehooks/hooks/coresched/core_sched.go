@@ -47,6 +47,9 @@ const (
 
 	defaultCacheExpiration     = 300 * time.Second
 	defaultCacheDeleteInterval = 600 * time.Second
+	// defaultForceIdleRecordInterval is the minimal interval to read the cpu.stat and record the force idle time
+	// for a container.
+	defaultForceIdleRecordInterval = 60 * time.Second
 
 	// ExpellerGroupSuffix is the default suffix of the expeller core sched group.
 	ExpellerGroupSuffix = "-expeller"
@@ -74,10 +77,14 @@ type Plugin struct {
 	cookieCache        *gocache.Cache // core-sched-group-id -> cookie id, set<pid>; if the group has had cookie
 	cookieCacheRWMutex sync.RWMutex
 	groupCache         *gocache.Cache // pod-uid+container-id -> core-sched-group-id (note that it caches the last state); if the container has had cookie of the group
-
-	reader   resourceexecutor.CgroupReader
-	executor resourceexecutor.ResourceUpdateExecutor
-	cse      sysutil.CoreSchedExtendedInterface
+	containerCache     *gocache.Cache // pod-uid+container-name -> container-id; the last container ID to reconcile the restarted containers
+	workloadCache      *gocache.Cache // pod-uid -> workload ID; the pods looked up from the states informer
+	forceIdleCache     *gocache.Cache // pod-uid+container-id -> struct{}; the containers whose force idle is recorded recently
+
+	reader         resourceexecutor.CgroupReader
+	executor       resourceexecutor.ResourceUpdateExecutor
+	cse            sysutil.CoreSchedExtendedInterface
+	statesInformer statesinformer.StatesInformer
 }
 
 var singleton *Plugin
@@ -94,6 +101,9 @@ func newPlugin() *Plugin {
 		rule:            newRule(),
 		cookieCache:     gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
 		groupCache:      gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		containerCache:  gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		workloadCache:   gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		forceIdleCache:  gocache.New(defaultForceIdleRecordInterval, defaultCacheDeleteInterval),
 		initialized:     atomic.NewBool(false),
 		allPodsSyncOnce: sync.Once{},
 	}
@@ -126,6 +136,7 @@ func (p *Plugin) Setup(op hooks.Options) {
 	p.reader = op.Reader
 	p.executor = op.Executor
 	p.cse = sysutil.NewCoreSchedExtended()
+	p.statesInformer = op.StatesInformer
 }
 
 func (p *Plugin) SystemSupported() bool {
@@ -210,10 +221,13 @@ func (p *Plugin) SetContainerCookie(proto protocol.HooksProtocol) error {
 	}
 
 	isEnabled, groupID := p.getPodEnabledAndGroup(containerCtx.Request.PodAnnotations, containerCtx.Request.PodLabels,
-		util.GetKubeQoSByCgroupParent(containerCtx.Request.CgroupParent), podUID)
+		util.GetKubeQoSByCgroupParent(containerCtx.Request.CgroupParent), &containerCtx.Request.PodMeta)
 	klog.V(6).Infof("manage cookie for container %s/%s, isEnabled %v, groupID %s",
 		containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name, isEnabled, groupID)
 
+	// the cookie states of the last container should be cleaned up if the container restarted
+	p.reconcileRestartedContainer(containerCtx)
+
 	// expect enabled
 	// 1. disabled -> enabled: Add or Assign.
 	// 2. keep enabled: Check the differences of cookie, group ID and the PIDs, and do Assign.
@@ -227,7 +241,9 @@ func (p *Plugin) SetContainerCookie(proto protocol.HooksProtocol) error {
 			return nil
 		}
 
-		return p.enableContainerCookie(containerCtx, groupID)
+		err := p.enableContainerCookie(containerCtx, groupID)
+		p.recordContainerForceIdle(containerCtx, groupID)
+		return err
 	}
 	// else pod disables
 
@@ -262,7 +278,9 @@ func (p *Plugin) loadAllCookies(podMetas []*statesinformer.PodMeta) bool {
 			continue
 		}
 
-		isEnabled, groupID := p.getPodEnabledAndGroup(podAnnotations, podLabels, extension.GetKubeQosClass(pod), podUID)
+		podMetaCtx := &protocol.PodMeta{}
+		podMetaCtx.FromReconciler(pod.ObjectMeta)
+		isEnabled, groupID := p.getPodEnabledAndGroup(podAnnotations, podLabels, extension.GetKubeQosClass(pod), podMetaCtx)
 
 		containerPIDs := p.getAllContainerPIDs(podMeta)
 
@@ -310,6 +328,10 @@ func (p *Plugin) loadAllCookies(podMetas []*statesinformer.PodMeta) bool {
 			p.cookieCache.SetDefault(groupID, cookieEntry)
 			containerUID := p.getContainerUID(podUID, containerID)
 			p.groupCache.SetDefault(containerUID, groupID)
+			if len(cPID.ContainerName) > 0 {
+				p.containerCache.SetDefault(p.getContainerUID(podUID, cPID.ContainerName), containerID)
+			}
+			metrics.RecordCoreSchedGroupCookie(groupID, cookieID, cookieEntry.GetPIDNum())
 			klog.V(4).Infof("sync cookie for container %s/%s finished, isEnabled %v, groupID %s, cookie %v",
 				podMeta.Key(), containerID, isEnabled, groupID, cookieID)
 			metrics.RecordContainerCoreSchedCookie(pod.Namespace, pod.Name, podUID, cPID.ContainerName, containerID,
@@ -529,8 +551,10 @@ func (p *Plugin) updateCookieCacheForContainer(groupID, containerUID string, coo
 	p.groupCache.SetDefault(containerUID, groupID)
 	if cookieEntry.IsEntryInvalid() {
 		p.cookieCache.Delete(groupID)
+		metrics.ResetCoreSchedGroupCookie(groupID, cookieEntry.GetCookieID())
 	} else {
 		p.cookieCache.SetDefault(groupID, cookieEntry)
+		metrics.RecordCoreSchedGroupCookie(groupID, cookieEntry.GetCookieID(), cookieEntry.GetPIDNum())
 	}
 }
 
@@ -540,7 +564,73 @@ func (p *Plugin) cleanCookieCacheForContainer(groupID, containerUID string, cook
 	p.groupCache.Delete(containerUID)
 	if cookieEntry.IsEntryInvalid() {
 		p.cookieCache.Delete(groupID)
+		metrics.ResetCoreSchedGroupCookie(groupID, cookieEntry.GetCookieID())
 	} else {
 		p.cookieCache.SetDefault(groupID, cookieEntry)
+		metrics.RecordCoreSchedGroupCookie(groupID, cookieEntry.GetCookieID(), cookieEntry.GetPIDNum())
 	}
 }
+
+// reconcileRestartedContainer cleans up the cached group and the metrics of the last container when the container
+// restarts with a new container ID. The PIDs of the last container are left in the cookie entry, which are removed
+// when they are found invalid during the Assign of the siblings.
+func (p *Plugin) reconcileRestartedContainer(containerCtx *protocol.ContainerContext) {
+	podUID := containerCtx.Request.PodMeta.UID
+	containerName := containerCtx.Request.ContainerMeta.Name
+	containerID := containerCtx.Request.ContainerMeta.ID
+	containerKey := p.getContainerUID(podUID, containerName)
+
+	p.cookieCacheRWMutex.Lock()
+	lastContainerIDIf, hasLastContainer := p.containerCache.Get(containerKey)
+	p.containerCache.SetDefault(containerKey, containerID)
+	if !hasLastContainer || lastContainerIDIf.(string) == containerID {
+		p.cookieCacheRWMutex.Unlock()
+		return
+	}
+	lastContainerID := lastContainerIDIf.(string)
+	lastContainerUID := p.getContainerUID(podUID, lastContainerID)
+	lastGroupIDIf, lastContainerHasGroup := p.groupCache.Get(lastContainerUID)
+	p.groupCache.Delete(lastContainerUID)
+	lastCookieID := sysutil.DefaultCoreSchedCookieID
+	if lastContainerHasGroup {
+		if lastCookieEntryIf, ok := p.cookieCache.Get(lastGroupIDIf.(string)); ok {
+			lastCookieID = lastCookieEntryIf.(*CookieCacheEntry).GetCookieID()
+		}
+	}
+	p.cookieCacheRWMutex.Unlock()
+
+	if !lastContainerHasGroup {
+		return
+	}
+	lastGroupID := lastGroupIDIf.(string)
+	podMeta := containerCtx.Request.PodMeta
+	metrics.ResetContainerCoreSchedCookie(podMeta.Namespace, podMeta.Name, podUID, containerName, lastContainerID,
+		lastGroupID, lastCookieID)
+	metrics.ResetContainerCoreSchedForceIdleSeconds(podMeta.Namespace, podMeta.Name, podUID, containerName,
+		lastContainerID, lastGroupID)
+	klog.V(4).Infof("clean cookie cache for restarted container %s/%s finished, last container %s, last group %s, last cookie %v",
+		podMeta.String(), containerName, lastContainerID, lastGroupID, lastCookieID)
+}
+
+// recordContainerForceIdle records the accumulated force idle time of the SMT siblings for the container.
+// It is only available on cgroup v2 where the cpu.stat provides the core scheduling statistics, and the cpu.stat of
+// a container is read at most once per defaultForceIdleRecordInterval.
+func (p *Plugin) recordContainerForceIdle(containerCtx *protocol.ContainerContext, groupID string) {
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV2 || p.reader == nil {
+		return
+	}
+	containerUID := p.getContainerUID(containerCtx.Request.PodMeta.UID, containerCtx.Request.ContainerMeta.ID)
+	if err := p.forceIdleCache.Add(containerUID, struct{}{}, defaultForceIdleRecordInterval); err != nil {
+		return // recorded recently
+	}
+	cpuStat, err := p.reader.ReadCPUStat(containerCtx.Request.CgroupParent)
+	if err != nil {
+		klog.V(6).Infof("failed to read cpu stat for container %s/%s, err: %s",
+			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name, err)
+		return
+	}
+	metrics.RecordContainerCoreSchedForceIdleSeconds(containerCtx.Request.PodMeta.Namespace,
+		containerCtx.Request.PodMeta.Name, containerCtx.Request.PodMeta.UID,
+		containerCtx.Request.ContainerMeta.Name, containerCtx.Request.ContainerMeta.ID,
+		groupID, float64(cpuStat.CoreSchedForceIdleNanoSeconds)/float64(time.Second))
+}
@@ -2210,6 +2210,9 @@ func testGetEnabledPlugin() *Plugin {
 		rule:            testGetEnabledRule(),
 		cookieCache:     gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
 		groupCache:      gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		containerCache:  gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		workloadCache:   gocache.New(defaultCacheExpiration, defaultCacheDeleteInterval),
+		forceIdleCache:  gocache.New(defaultForceIdleRecordInterval, defaultCacheDeleteInterval),
 		reader:          resourceexecutor.NewCgroupReader(),
 		executor:        resourceexecutor.NewTestResourceExecutor(),
 		sysSupported:    pointer.Bool(true),
@@ -2217,3 +2220,76 @@ func testGetEnabledPlugin() *Plugin {
 		initialized:     atomic.NewBool(true),
 	}
 }
+
+func TestPlugin_reconcileRestartedContainer(t *testing.T) {
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta: protocol.PodMeta{
+				Name:      "test-pod",
+				Namespace: "test-ns",
+				UID:       "xxxxxx",
+			},
+			ContainerMeta: protocol.ContainerMeta{
+				Name: "test-container",
+				ID:   "containerd://yyyyyy",
+			},
+		},
+	}
+	p := testGetEnabledPlugin()
+	p.groupCache.SetDefault("xxxxxx/containerd://yyyyyy", "group-xxx")
+	p.cookieCache.SetDefault("group-xxx", newCookieCacheEntry(1000000, 1000, 1001))
+
+	// first seen, nothing to clean
+	p.reconcileRestartedContainer(containerCtx)
+	_, ok := p.groupCache.Get("xxxxxx/containerd://yyyyyy")
+	assert.True(t, ok)
+	lastContainerID, ok := p.containerCache.Get("xxxxxx/test-container")
+	assert.True(t, ok)
+	assert.Equal(t, "containerd://yyyyyy", lastContainerID)
+
+	// container restarted with a new ID
+	containerCtx.Request.ContainerMeta.ID = "containerd://zzzzzz"
+	p.reconcileRestartedContainer(containerCtx)
+	_, ok = p.groupCache.Get("xxxxxx/containerd://yyyyyy")
+	assert.False(t, ok)
+	lastContainerID, ok = p.containerCache.Get("xxxxxx/test-container")
+	assert.True(t, ok)
+	assert.Equal(t, "containerd://zzzzzz", lastContainerID)
+	// the cookie of the group is retained for the restarted container and its siblings
+	cookieEntryIf, ok := p.cookieCache.Get("group-xxx")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1000000), cookieEntryIf.(*CookieCacheEntry).GetCookieID())
+}
+
+func TestPlugin_recordContainerForceIdle(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	cgroupParent := "kubepods.slice/kubepods-podxxxxxx.slice/cri-containerd-yyyyyy.scope"
+	helper.WriteCgroupFileContents(cgroupParent, sysutil.CPUStatV2, "nr_periods 10\nnr_throttled 2\nthrottled_usec 5\ncore_sched.force_idle_usec 300")
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta: protocol.PodMeta{
+				Name:      "test-pod",
+				Namespace: "test-ns",
+				UID:       "xxxxxx",
+			},
+			ContainerMeta: protocol.ContainerMeta{
+				Name: "test-container",
+				ID:   "containerd://yyyyyy",
+			},
+			CgroupParent: cgroupParent,
+		},
+	}
+	p := testGetEnabledPlugin()
+
+	p.recordContainerForceIdle(containerCtx, "group-xxx")
+	_, ok := p.forceIdleCache.Get("xxxxxx/containerd://yyyyyy")
+	assert.True(t, ok)
+
+	// the cpu.stat is not read again within the record interval
+	helper.WriteCgroupFileContents(cgroupParent, sysutil.CPUStatV2, "invalid content")
+	p.recordContainerForceIdle(containerCtx, "group-xxx")
+	_, ok = p.forceIdleCache.Get("xxxxxx/containerd://yyyyyy")
+	assert.True(t, ok)
+}
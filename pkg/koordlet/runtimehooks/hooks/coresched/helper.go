@@ -18,8 +18,11 @@ package coresched
 
 import (
 	"fmt"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
@@ -234,7 +237,8 @@ func (p *Plugin) clearCookie(pids []uint32, groupID string, lastCookieID uint64)
 }
 
 // getPodEnabledAndGroup gets whether the pod enables the core scheduling and the group ID if it does.
-func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]string, podKubeQOS corev1.PodQOSClass, podUID string) (bool, string) {
+func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]string, podKubeQOS corev1.PodQOSClass, podMeta *protocol.PodMeta) (bool, string) {
+	podUID := podMeta.UID
 	groupID := slov1alpha1.GetCoreSchedGroupID(podLabels)
 	policy := slov1alpha1.GetCoreSchedPolicy(podLabels)
 	podQOS := extension.QoSNone
@@ -245,6 +249,12 @@ func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]stri
 
 	if policy == slov1alpha1.CoreSchedPolicyExclusive {
 		groupID = podUID
+	} else if policy == slov1alpha1.CoreSchedPolicyWorkload {
+		// fallback to the exclusive policy when the workload is unknown, so the pod never shares the cookie with others
+		groupID = p.getPodWorkloadID(podMeta, podLabels)
+		if len(groupID) <= 0 {
+			groupID = podUID
+		}
 	} else if policy == slov1alpha1.CoreSchedPolicyNone {
 		isEnabled = false
 	}
@@ -255,6 +265,53 @@ func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]stri
 	return isEnabled, groupID
 }
 
+// getPodWorkloadID gets the workload ID of the pod according to its controller reference.
+// The owner references are taken from the hook context if available. Otherwise, the pod is looked up from the
+// states informer and the workload ID is cached by the pod UID.
+// It returns empty if the pod is not found or has no controller.
+func (p *Plugin) getPodWorkloadID(podMeta *protocol.PodMeta, podLabels map[string]string) string {
+	if podMeta.OwnerReferences != nil {
+		return getWorkloadID(podMeta.Namespace, podLabels, podMeta.OwnerReferences)
+	}
+	if p.statesInformer == nil {
+		return ""
+	}
+	if workloadIDIf, ok := p.workloadCache.Get(podMeta.UID); ok {
+		return workloadIDIf.(string)
+	}
+	for _, m := range p.statesInformer.GetAllPods() {
+		if m == nil || m.Pod == nil || string(m.Pod.UID) != podMeta.UID {
+			continue
+		}
+		workloadID := getWorkloadID(m.Pod.Namespace, m.Pod.Labels, m.Pod.OwnerReferences)
+		p.workloadCache.SetDefault(podMeta.UID, workloadID)
+		return workloadID
+	}
+	return ""
+}
+
+// getWorkloadID returns the workload ID in the format of `namespace/kind/name` by the controller reference.
+// The ReplicaSet controlled by a Deployment is resolved to the Deployment without the pod-template-hash so that the
+// pods of the different revisions still share the same group, and it never collides with a standalone ReplicaSet.
+func getWorkloadID(namespace string, podLabels map[string]string, ownerRefs []metav1.OwnerReference) string {
+	var owner *metav1.OwnerReference
+	for i := range ownerRefs {
+		if ownerRefs[i].Controller != nil && *ownerRefs[i].Controller {
+			owner = &ownerRefs[i]
+			break
+		}
+	}
+	if owner == nil {
+		return ""
+	}
+	ownerKind, ownerName := owner.Kind, owner.Name
+	if podTemplateHash := podLabels[appsv1.DefaultDeploymentUniqueLabelKey]; owner.Kind == "ReplicaSet" &&
+		len(podTemplateHash) > 0 && strings.HasSuffix(ownerName, "-"+podTemplateHash) {
+		ownerKind, ownerName = "Deployment", strings.TrimSuffix(ownerName, "-"+podTemplateHash)
+	}
+	return fmt.Sprintf("%s/%s/%s", namespace, ownerKind, ownerName)
+}
+
 func (p *Plugin) getContainerUID(podUID string, containerID string) string {
 	return podUID + "/" + containerID
 }
@@ -19,13 +19,18 @@ package coresched
 import (
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -795,6 +800,22 @@ func Test_isPodEnabled(t *testing.T) {
 			want:  true,
 			want1: "xxx-expeller",
 		},
+		{
+			name: "pod enabled with policy workload but workload unknown",
+			field: field{
+				rule: testGetEnabledRule(),
+			},
+			args: args{
+				podAnnotations: map[string]string{},
+				podLabels: map[string]string{
+					extension.LabelPodQoS:            string(extension.QoSLS),
+					slov1alpha1.LabelCoreSchedPolicy: string(slov1alpha1.CoreSchedPolicyWorkload),
+				},
+				podUID: "xxx",
+			},
+			want:  true,
+			want1: "xxx-expeller",
+		},
 		{
 			name: "pod disabled",
 			field: field{
@@ -971,13 +992,153 @@ func Test_isPodEnabled(t *testing.T) {
 			p := &Plugin{
 				rule: tt.field.rule,
 			}
-			got, got1 := p.getPodEnabledAndGroup(tt.args.podAnnotations, tt.args.podLabels, tt.args.podKubeQOS, tt.args.podUID)
+			got, got1 := p.getPodEnabledAndGroup(tt.args.podAnnotations, tt.args.podLabels, tt.args.podKubeQOS,
+				&protocol.PodMeta{UID: tt.args.podUID})
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.want1, got1)
 		})
 	}
 }
 
+func Test_getPodWorkloadID(t *testing.T) {
+	isController := true
+	stsOwnerRefs := []metav1.OwnerReference{
+		{
+			Kind:       "StatefulSet",
+			Name:       "test-sts",
+			Controller: &isController,
+		},
+	}
+	rsOwnerRefs := []metav1.OwnerReference{
+		{
+			Kind:       "ReplicaSet",
+			Name:       "test-deploy-5d8f9b7c4",
+			Controller: &isController,
+		},
+	}
+	tests := []struct {
+		name      string
+		pods      []*statesinformer.PodMeta
+		podMeta   *protocol.PodMeta
+		podLabels map[string]string
+		want      string
+	}{
+		{
+			name:    "pod not found",
+			pods:    []*statesinformer.PodMeta{},
+			podMeta: &protocol.PodMeta{Namespace: "test-ns", Name: "test-pod", UID: "xxx"},
+			want:    "",
+		},
+		{
+			name: "pod has no controller",
+			pods: []*statesinformer.PodMeta{
+				{
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-pod",
+							Namespace: "test-ns",
+							UID:       "xxx",
+						},
+					},
+				},
+			},
+			podMeta: &protocol.PodMeta{Namespace: "test-ns", Name: "test-pod", UID: "xxx"},
+			want:    "",
+		},
+		{
+			name: "pod controlled by statefulset",
+			podMeta: &protocol.PodMeta{
+				Namespace:       "test-ns",
+				Name:            "test-sts-0",
+				UID:             "xxx",
+				OwnerReferences: stsOwnerRefs,
+			},
+			want: "test-ns/StatefulSet/test-sts",
+		},
+		{
+			name: "pod controlled by replicaset of deployment",
+			podMeta: &protocol.PodMeta{
+				Namespace:       "test-ns",
+				Name:            "test-deploy-5d8f9b7c4-abcde",
+				UID:             "yyy",
+				OwnerReferences: rsOwnerRefs,
+			},
+			podLabels: map[string]string{
+				"pod-template-hash": "5d8f9b7c4",
+			},
+			want: "test-ns/Deployment/test-deploy",
+		},
+		{
+			name: "pod controlled by standalone replicaset",
+			podMeta: &protocol.PodMeta{
+				Namespace: "test-ns",
+				Name:      "test-deploy-abcde",
+				UID:       "zzz",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						Kind:       "ReplicaSet",
+						Name:       "test-deploy",
+						Controller: &isController,
+					},
+				},
+			},
+			want: "test-ns/ReplicaSet/test-deploy",
+		},
+		{
+			name: "pod without owner references in context is looked up from states informer",
+			pods: []*statesinformer.PodMeta{
+				{
+					Pod: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "test-sts-0",
+							Namespace:       "test-ns",
+							UID:             "xxx",
+							OwnerReferences: stsOwnerRefs,
+						},
+					},
+				},
+			},
+			podMeta: &protocol.PodMeta{Namespace: "test-ns", Name: "test-sts-0", UID: "xxx"},
+			want:    "test-ns/StatefulSet/test-sts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			p := newPlugin()
+			p.rule = testGetEnabledRule()
+			p.statesInformer = si
+			if tt.podMeta.OwnerReferences == nil {
+				// the pods found are cached so the states informer is looked up only once
+				times := 2
+				if len(tt.pods) > 0 {
+					times = 1
+				}
+				si.EXPECT().GetAllPods().Return(tt.pods).Times(times)
+			}
+			got := p.getPodWorkloadID(tt.podMeta, tt.podLabels)
+			assert.Equal(t, tt.want, got)
+
+			podLabels := map[string]string{
+				extension.LabelPodQoS:            string(extension.QoSBE),
+				slov1alpha1.LabelCoreSchedPolicy: string(slov1alpha1.CoreSchedPolicyWorkload),
+			}
+			for k, v := range tt.podLabels {
+				podLabels[k] = v
+			}
+			isEnabled, groupID := p.getPodEnabledAndGroup(nil, podLabels, corev1.PodQOSBestEffort, tt.podMeta)
+			assert.True(t, isEnabled)
+			if len(tt.want) > 0 {
+				assert.Equal(t, tt.want, groupID)
+			} else {
+				assert.Equal(t, tt.podMeta.UID, groupID)
+			}
+		})
+	}
+}
+
 func Test_getContainerPIDs(t *testing.T) {
 	type fields struct {
 		prepareFn   func(helper *sysutil.FileTestUtil)
@@ -24,6 +24,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -55,7 +56,7 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	klog.V(5).Infof("register hook %v", name)
 	hooks.Register(rmconfig.PreCreateContainer, name, description, p.SetContainerCPUSetAndUnsetCFS)
 	hooks.Register(rmconfig.PreUpdateContainerResources, name, description, p.SetContainerCPUSetAndUnsetCFS)
-	hooks.Register(rmconfig.PreRunPodSandbox, name, "unset pod cpu quota if needed", UnsetPodCPUQuota)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, "set pod cpuset.mems and unset pod cpu quota if needed", p.SetPodCPUSetMemsAndUnsetCFS)
 	rule.Register(name, description,
 		rule.WithParseFunc(statesinformer.RegisterTypeNodeTopology, p.parseRule),
 		rule.WithUpdateCallback(p.ruleUpdateCb))
@@ -66,6 +67,9 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	reconciler.RegisterCgroupReconciler(reconciler.SandboxLevel, sysutil.CPUSet,
 		"set sandbox container cpuset and unset container cpu quota if needed for cpuset pod",
 		p.SetContainerCPUSetAndUnsetCFS, reconciler.PodQOSFilter(), cpusetPodQOSConditions...)
+	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.CPUSetMems,
+		"set pod cpuset.mems for NUMA-aligned pod", p.SetPodCPUSetMems,
+		reconciler.PodQOSFilter(), append(cpusetPodQOSConditions, cpusharePodQOSConditions...)...)
 	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.CPUCFSQuota,
 		"unset pod cpu quota if needed for cpuset pod", UnsetPodCPUQuota,
 		reconciler.PodQOSFilter(), cpusetPodQOSConditions...)
@@ -91,8 +95,55 @@ func Object() *cpusetPlugin {
 	return singleton
 }
 
+func (p *cpusetPlugin) SetPodCPUSetMemsAndUnsetCFS(proto protocol.HooksProtocol) error {
+	// set pod-level cpuset.mems
+	err := p.SetPodCPUSetMems(proto)
+	if err != nil {
+		return err
+	}
+
+	// unset pod-level cpu.cfs_quota_us if needed
+	return UnsetPodCPUQuota(proto)
+}
+
+// SetPodCPUSetMems sets the pod-level cpuset.mems to the NUMA nodes which the containers are bound to. It should be
+// set before the containers since the cpuset.mems of a child cgroup cannot exceed its parent's.
+func (p *cpusetPlugin) SetPodCPUSetMems(proto protocol.HooksProtocol) error {
+	podCtx, _ := proto.(*protocol.PodContext)
+	if podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %v", name)
+	}
+	podReq := podCtx.Request
+
+	// bind the memory to all the allocated NUMA nodes for the cpuset pod (LSE, LSR)
+	if cpusetVal, err := util.GetCPUSetFromPod(podReq.Annotations); err != nil {
+		return err
+	} else if cpusetVal != "" {
+		memsVal, err := getNUMANodesCPUSetMems(podReq.Annotations)
+		if err != nil {
+			return err
+		}
+		setPodCPUSetMems(podCtx, memsVal)
+		return nil
+	}
+
+	r := p.getRule()
+	if r == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+	memsValue, err := r.getPodCPUSetMems(&podReq)
+	if err != nil {
+		return err
+	}
+	if memsValue != nil {
+		setPodCPUSetMems(podCtx, *memsValue)
+	}
+	return nil
+}
+
 func (p *cpusetPlugin) SetContainerCPUSetAndUnsetCFS(proto protocol.HooksProtocol) error {
-	// set container-level cpuset.cpus
+	// set container-level cpuset.cpus and cpuset.mems
 	err := p.SetContainerCPUSet(proto)
 	if err != nil {
 		return err
@@ -117,6 +168,12 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 		containerCtx.Response.Resources.CPUSet = pointer.String(cpusetVal)
 		klog.V(5).Infof("get cpuset %v for container %v/%v from pod annotation", cpusetVal,
 			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+		// bind the memory to the allocated NUMA nodes
+		memsVal, err := getNUMANodesCPUSetMems(containerReq.PodAnnotations)
+		if err != nil {
+			return err
+		}
+		setContainerCPUSetMems(containerCtx, memsVal)
 		return nil
 	}
 
@@ -139,9 +196,50 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
 	}
 	containerCtx.Response.Resources.CPUSet = cpusetValue
+
+	memsValue, err := r.getContainerCPUSetMems(&containerReq)
+	if err != nil {
+		return err
+	}
+	if memsValue != nil {
+		setContainerCPUSetMems(containerCtx, *memsValue)
+	}
 	return nil
 }
 
+// setContainerCPUSetMems sets the cpuset.mems of the container if the mems is not empty, and enables the memory
+// migration on the cgroups v1 when the CPUSetMemoryMigrate is enabled.
+func setContainerCPUSetMems(containerCtx *protocol.ContainerContext, mems string) {
+	if mems == "" {
+		return
+	}
+	containerCtx.Response.Resources.CPUSetMems = pointer.String(mems)
+	klog.V(5).Infof("get cpuset.mems %v for container %v/%v", mems,
+		containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+	if isCPUSetMemoryMigrateNeeded() {
+		containerCtx.Response.Resources.CPUSetMemoryMigrate = pointer.Bool(true)
+	}
+}
+
+// setPodCPUSetMems sets the cpuset.mems of the pod if the mems is not empty, and enables the memory migration on the
+// cgroups v1 when the CPUSetMemoryMigrate is enabled.
+func setPodCPUSetMems(podCtx *protocol.PodContext, mems string) {
+	if mems == "" {
+		return
+	}
+	podCtx.Response.Resources.CPUSetMems = pointer.String(mems)
+	klog.V(5).Infof("get cpuset.mems %v for pod %v", mems, podCtx.Request.PodMeta.String())
+	if isCPUSetMemoryMigrateNeeded() {
+		podCtx.Response.Resources.CPUSetMemoryMigrate = pointer.Bool(true)
+	}
+}
+
+func isCPUSetMemoryMigrateNeeded() bool {
+	// the kernel always migrates the pages on the cgroups v2
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPUSetMemoryMigrate) &&
+		sysutil.GetCurrentCgroupVersion() == sysutil.CgroupVersionV1
+}
+
 func (p *cpusetPlugin) SetHostAppCPUSet(proto protocol.HooksProtocol) error {
 	hostAppCtx, _ := proto.(*protocol.HostAppContext)
 	if hostAppCtx == nil {
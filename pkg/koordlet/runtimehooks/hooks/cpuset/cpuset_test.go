@@ -26,6 +26,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -378,6 +379,199 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 	}
 }
 
+func Test_cpusetPlugin_SetContainerCPUSetMems(t *testing.T) {
+	numaAlloc := &ext.ResourceStatus{
+		CPUSet: "2-3,18-19",
+		NUMANodeResources: []ext.NUMANodeResource{
+			{
+				Node: 1,
+				Resources: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+				},
+			},
+			{
+				Node: 0,
+				Resources: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name                 string
+		podAlloc             *ext.ResourceStatus
+		memoryMigrateEnabled bool
+		wantCPUSetMems       *string
+		wantMemoryMigrate    *bool
+	}{
+		{
+			name:           "no numa allocation",
+			podAlloc:       &ext.ResourceStatus{CPUSet: "2-4"},
+			wantCPUSetMems: nil,
+		},
+		{
+			name:           "bind mems to the allocated numa nodes",
+			podAlloc:       numaAlloc,
+			wantCPUSetMems: pointer.String("0-1"),
+		},
+		{
+			name:                 "bind mems and migrate pages",
+			podAlloc:             numaAlloc,
+			memoryMigrateEnabled: true,
+			wantCPUSetMems:       pointer.String("0-1"),
+			wantMemoryMigrate:    pointer.Bool(true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+			defer features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.CPUSetMemoryMigrate): false})
+			features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.CPUSetMemoryMigrate): tt.memoryMigrateEnabled})
+
+			containerCtx := &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					CgroupParent: "kubepods/test-pod/test-container/",
+					PodAnnotations: map[string]string{
+						ext.AnnotationResourceStatus: util.DumpJSON(tt.podAlloc),
+					},
+				},
+			}
+			initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+			testHelper.WriteCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMems, "")
+			testHelper.WriteCgroupFileContents(system.CgroupPathFormatter.ParentDir, system.CPUSetMemoryMigrate, "0")
+			testHelper.WriteCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMemoryMigrate, "0")
+
+			p := &cpusetPlugin{
+				executor: resourceexecutor.NewTestResourceExecutor(),
+			}
+			err := p.SetContainerCPUSet(containerCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCPUSetMems, containerCtx.Response.Resources.CPUSetMems)
+			assert.Equal(t, tt.wantMemoryMigrate, containerCtx.Response.Resources.CPUSetMemoryMigrate)
+
+			stop := make(chan struct{})
+			defer close(stop)
+			p.executor.Run(stop)
+			containerCtx.ReconcilerDone(p.executor)
+			if tt.wantCPUSetMems != nil {
+				assert.Equal(t, *tt.wantCPUSetMems, testHelper.ReadCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMems))
+			}
+			if tt.wantMemoryMigrate != nil {
+				assert.Equal(t, "1", testHelper.ReadCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMemoryMigrate))
+			}
+		})
+	}
+}
+
+func Test_cpusetPlugin_SetPodCPUSetMems(t *testing.T) {
+	numaResources := []ext.NUMANodeResource{
+		{
+			Node: 1,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+			},
+		},
+		{
+			Node: 0,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+			},
+		},
+	}
+	tests := []struct {
+		name                 string
+		rule                 *cpusetRule
+		podLabels            map[string]string
+		podAlloc             *ext.ResourceStatus
+		memoryMigrateEnabled bool
+		wantCPUSetMems       *string
+		wantMemoryMigrate    *bool
+	}{
+		{
+			name:           "no numa allocation",
+			podAlloc:       &ext.ResourceStatus{CPUSet: "2-4"},
+			wantCPUSetMems: nil,
+		},
+		{
+			name: "bind mems of cpuset pod to the allocated numa nodes",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet:            "2-3,18-19",
+				NUMANodeResources: numaResources,
+			},
+			wantCPUSetMems: pointer.String("0-1"),
+		},
+		{
+			name: "bind mems of cpushare pod to the numa nodes with share pools",
+			rule: &cpusetRule{
+				sharePools: []ext.CPUSharedPool{
+					{Socket: 0, Node: 0, CPUSet: "0-7"},
+					{Socket: 1, Node: 1, CPUSet: ""},
+				},
+			},
+			podLabels:      map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			podAlloc:       &ext.ResourceStatus{NUMANodeResources: numaResources},
+			wantCPUSetMems: pointer.String("0"),
+		},
+		{
+			name:      "skip cpushare pod without rule",
+			podLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			podAlloc:  &ext.ResourceStatus{NUMANodeResources: numaResources},
+		},
+		{
+			name: "bind mems and migrate pages",
+			podAlloc: &ext.ResourceStatus{
+				CPUSet:            "2-3,18-19",
+				NUMANodeResources: numaResources,
+			},
+			memoryMigrateEnabled: true,
+			wantCPUSetMems:       pointer.String("0-1"),
+			wantMemoryMigrate:    pointer.Bool(true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHelper := system.NewFileTestUtil(t)
+			defer testHelper.Cleanup()
+			defer features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.CPUSetMemoryMigrate): false})
+			features.DefaultMutableKoordletFeatureGate.SetFromMap(map[string]bool{string(features.CPUSetMemoryMigrate): tt.memoryMigrateEnabled})
+
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					CgroupParent: "kubepods/test-pod/",
+					Labels:       tt.podLabels,
+					Annotations: map[string]string{
+						ext.AnnotationResourceStatus: util.DumpJSON(tt.podAlloc),
+					},
+				},
+			}
+			testHelper.WriteCgroupFileContents(podCtx.Request.CgroupParent, system.CPUSetMems, "")
+			testHelper.WriteCgroupFileContents(system.CgroupPathFormatter.ParentDir, system.CPUSetMemoryMigrate, "0")
+			testHelper.WriteCgroupFileContents(podCtx.Request.CgroupParent, system.CPUSetMemoryMigrate, "0")
+
+			p := &cpusetPlugin{
+				rule:     tt.rule,
+				executor: resourceexecutor.NewTestResourceExecutor(),
+			}
+			err := p.SetPodCPUSetMems(podCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCPUSetMems, podCtx.Response.Resources.CPUSetMems)
+			assert.Equal(t, tt.wantMemoryMigrate, podCtx.Response.Resources.CPUSetMemoryMigrate)
+
+			stop := make(chan struct{})
+			defer close(stop)
+			p.executor.Run(stop)
+			podCtx.ReconcilerDone(p.executor)
+			if tt.wantCPUSetMems != nil {
+				assert.Equal(t, *tt.wantCPUSetMems, testHelper.ReadCgroupFileContents(podCtx.Request.CgroupParent, system.CPUSetMems))
+			}
+			if tt.wantMemoryMigrate != nil {
+				assert.Equal(t, "1", testHelper.ReadCgroupFileContents(podCtx.Request.CgroupParent, system.CPUSetMemoryMigrate))
+			}
+		})
+	}
+}
+
 func TestUnsetPodCPUQuota(t *testing.T) {
 	type args struct {
 		podAlloc *ext.ResourceStatus
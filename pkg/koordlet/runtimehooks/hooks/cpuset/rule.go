@@ -63,20 +63,7 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 
 	podQOSClass := extension.GetQoSClassByAttrs(podLabels, podAnnotations)
 
-	// check if numa-aware
-	isNUMAAware := false
-	for _, numaNode := range podAlloc.NUMANodeResources {
-		if numaNode.Resources == nil {
-			continue
-		}
-		// check if cpu resource is allocated in numa-level since there can be numa allocation without cpu
-		if !numaNode.Resources.Cpu().IsZero() ||
-			util.GetBatchMilliCPUFromResourceList(numaNode.Resources) > 0 {
-			isNUMAAware = true
-			break
-		}
-	}
-	if isNUMAAware {
+	if isNUMAAware(podAlloc) {
 		getCPUFromSharePoolByAllocFn := func(sharePools []extension.CPUSharedPool, alloc *extension.ResourceStatus) string {
 			cpusetList := make([]string, 0, len(alloc.NUMANodeResources))
 			for _, numaNode := range alloc.NUMANodeResources {
@@ -145,6 +132,111 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 }
 
+// getContainerCPUSetMems returns the cpuset.mems of the NUMA-aware container which uses the cpu share pools. The mems
+// are the allocated NUMA nodes whose share pool is not empty, so they keep aligned with the cpuset.cpus when the
+// share pools change. The NUMA nodes where the hugepages are allocated are always kept, even if the cpus are not
+// allocated in NUMA-level, since the hugetlb cgroup has no per-node limit and the hugetlb pages can only be faulted
+// from the nodes in the cpuset.mems.
+func (r *cpusetRule) getContainerCPUSetMems(containerReq *protocol.ContainerRequest) (*string, error) {
+	if containerReq == nil {
+		return nil, nil
+	}
+	return r.getCPUSetMems(containerReq.PodLabels, containerReq.PodAnnotations)
+}
+
+// getPodCPUSetMems returns the cpuset.mems of the pod-level cgroup, which are the same as the containers', so the
+// page cache and the memory shared in the pod are also allocated from the bound NUMA nodes.
+func (r *cpusetRule) getPodCPUSetMems(podReq *protocol.PodRequest) (*string, error) {
+	if podReq == nil {
+		return nil, nil
+	}
+	return r.getCPUSetMems(podReq.Labels, podReq.Annotations)
+}
+
+func (r *cpusetRule) getCPUSetMems(podLabels, podAnnotations map[string]string) (*string, error) {
+	podAlloc, err := extension.GetResourceStatus(podAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	hugePagesNodes := getHugePagesNUMANodes(podAlloc)
+	if !isNUMAAware(podAlloc) {
+		if len(hugePagesNodes) == 0 {
+			return nil, nil
+		}
+		return pointer.String(cpuset.NewCPUSet(hugePagesNodes...).String()), nil
+	}
+
+	var sharePools []extension.CPUSharedPool
+	podQOSClass := extension.GetQoSClassByAttrs(podLabels, podAnnotations)
+	if podQOSClass == extension.QoSBE && features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
+		sharePools = r.beSharePools
+	} else if podQOSClass != extension.QoSBE {
+		sharePools = r.sharePools
+	} else if len(hugePagesNodes) > 0 {
+		return pointer.String(cpuset.NewCPUSet(hugePagesNodes...).String()), nil
+	} else {
+		return nil, nil
+	}
+
+	nodes := append(make([]int, 0, len(podAlloc.NUMANodeResources)), hugePagesNodes...)
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		for _, nodeSharePool := range sharePools {
+			if numaNode.Node == nodeSharePool.Node && len(nodeSharePool.CPUSet) > 0 {
+				nodes = append(nodes, int(numaNode.Node))
+				break
+			}
+		}
+	}
+	return pointer.String(cpuset.NewCPUSet(nodes...).String()), nil
+}
+
+// isNUMAAware checks if the cpu resource is allocated in numa-level since there can be numa allocation without cpu.
+func isNUMAAware(podAlloc *extension.ResourceStatus) bool {
+	if podAlloc == nil {
+		return false
+	}
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		if numaNode.Resources == nil {
+			continue
+		}
+		if !numaNode.Resources.Cpu().IsZero() ||
+			util.GetBatchMilliCPUFromResourceList(numaNode.Resources) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getHugePagesNUMANodes returns the NUMA nodes where the hugepages are allocated.
+func getHugePagesNUMANodes(podAlloc *extension.ResourceStatus) []int {
+	if podAlloc == nil {
+		return nil
+	}
+	var nodes []int
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		for resourceName, quantity := range numaNode.Resources {
+			if strings.HasPrefix(string(resourceName), corev1.ResourceHugePagesPrefix) && !quantity.IsZero() {
+				nodes = append(nodes, int(numaNode.Node))
+				break
+			}
+		}
+	}
+	return nodes
+}
+
+// getNUMANodesCPUSetMems returns the cpuset.mems of all the allocated NUMA nodes.
+func getNUMANodesCPUSetMems(podAnnotations map[string]string) (string, error) {
+	podAlloc, err := extension.GetResourceStatus(podAnnotations)
+	if err != nil {
+		return "", err
+	}
+	nodes := make([]int, 0, len(podAlloc.NUMANodeResources))
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		nodes = append(nodes, int(numaNode.Node))
+	}
+	return cpuset.NewCPUSet(nodes...).String(), nil
+}
+
 func (r *cpusetRule) getHostAppCpuset(hostAppReq *protocol.HostAppRequest) (*string, error) {
 	if hostAppReq == nil {
 		return nil, nil
@@ -208,6 +300,15 @@ func (p *cpusetPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error
 		return nil
 	}
 	for _, podMeta := range target.Pods {
+		// update the pod-level cpuset.mems before the containers
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		if err := p.SetPodCPUSetMems(podCtx); err != nil {
+			klog.V(4).Infof("set cpuset.mems failed for pod %v during callback, error: %v", podMeta.Key(), err)
+		} else {
+			podCtx.ReconcilerDone(p.executor)
+		}
+
 		allContainersSpec := make(map[string]*corev1.Container, len(podMeta.Pod.Spec.Containers)+len(podMeta.Pod.Spec.InitContainers))
 		for i := range podMeta.Pod.Spec.InitContainers {
 			initContainer := &podMeta.Pod.Spec.InitContainers[i]
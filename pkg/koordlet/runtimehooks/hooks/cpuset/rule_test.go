@@ -514,6 +514,195 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 	// scheduling.koordinator.sh/resource-status: '{"cpuset":"0-1"}'
 }
 
+func Test_cpusetRule_getContainerCPUSetMems(t *testing.T) {
+	sharePools := []ext.CPUSharedPool{
+		{
+			Socket: 0,
+			Node:   0,
+			CPUSet: "1-7",
+		},
+		{
+			Socket: 0,
+			Node:   1,
+			CPUSet: "",
+		},
+		{
+			Socket: 1,
+			Node:   2,
+			CPUSet: "17-23",
+		},
+	}
+	beSharePools := []ext.CPUSharedPool{
+		{
+			Socket: 0,
+			Node:   0,
+			CPUSet: "0-7",
+		},
+		{
+			Socket: 0,
+			Node:   1,
+			CPUSet: "8-15",
+		},
+	}
+	numaCPUAlloc := func(nodes ...int32) *ext.ResourceStatus {
+		alloc := &ext.ResourceStatus{}
+		for _, node := range nodes {
+			alloc.NUMANodeResources = append(alloc.NUMANodeResources, ext.NUMANodeResource{
+				Node: node,
+				Resources: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+				},
+			})
+		}
+		return alloc
+	}
+	tests := []struct {
+		name                string
+		podQoS              ext.QoSClass
+		podAlloc            *ext.ResourceStatus
+		badAlloc            bool
+		beCPUManagerEnabled bool
+		want                *string
+		wantErr             bool
+	}{
+		{
+			name:     "bad annotation",
+			podQoS:   ext.QoSLS,
+			badAlloc: true,
+			wantErr:  true,
+		},
+		{
+			name:   "not numa-aware",
+			podQoS: ext.QoSLS,
+			want:   nil,
+		},
+		{
+			name:   "numa allocation without cpu",
+			podQoS: ext.QoSLS,
+			podAlloc: &ext.ResourceStatus{
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 1,
+						Resources: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceMemory: resource.MustParse("2Gi"),
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name:   "bind the numa node with allocated hugepages without cpu",
+			podQoS: ext.QoSLS,
+			podAlloc: &ext.ResourceStatus{
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 1,
+						Resources: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("2Gi"),
+						},
+					},
+				},
+			},
+			want: pointer.String("1"),
+		},
+		{
+			name:     "LS pod bound to the allocated numa nodes",
+			podQoS:   ext.QoSLS,
+			podAlloc: numaCPUAlloc(2, 0),
+			want:     pointer.String("0,2"),
+		},
+		{
+			name:     "skip the numa node whose share pool is empty",
+			podQoS:   ext.QoSLS,
+			podAlloc: numaCPUAlloc(0, 1),
+			want:     pointer.String("0"),
+		},
+		{
+			name:     "all share pools of the allocated numa nodes are empty",
+			podQoS:   ext.QoSLS,
+			podAlloc: numaCPUAlloc(1),
+			want:     pointer.String(""),
+		},
+		{
+			name:   "keep the numa node with allocated hugepages",
+			podQoS: ext.QoSLS,
+			podAlloc: &ext.ResourceStatus{
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 1,
+						Resources: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceCPU:                     *resource.NewQuantity(2, resource.DecimalSI),
+							corev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("2Gi"),
+						},
+					},
+				},
+			},
+			want: pointer.String("1"),
+		},
+		{
+			name:                "BE pod bound to the be share pools",
+			podQoS:              ext.QoSBE,
+			podAlloc:            numaCPUAlloc(0, 1),
+			beCPUManagerEnabled: true,
+			want:                pointer.String("0-1"),
+		},
+		{
+			name:     "BE pod without BECPUManager",
+			podQoS:   ext.QoSBE,
+			podAlloc: numaCPUAlloc(0, 1),
+			want:     nil,
+		},
+		{
+			name:   "BE pod without BECPUManager bound to the hugepages numa node",
+			podQoS: ext.QoSBE,
+			podAlloc: &ext.ResourceStatus{
+				NUMANodeResources: []ext.NUMANodeResource{
+					{
+						Node: 0,
+						Resources: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+						},
+					},
+					{
+						Node: 1,
+						Resources: map[corev1.ResourceName]resource.Quantity{
+							corev1.ResourceCPU:                     *resource.NewQuantity(2, resource.DecimalSI),
+							corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("64Mi"),
+						},
+					},
+				},
+			},
+			want: pointer.String("1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &cpusetRule{
+				sharePools:   sharePools,
+				beSharePools: beSharePools,
+			}
+			containerReq := &protocol.ContainerRequest{
+				PodLabels: map[string]string{
+					ext.LabelPodQoS: string(tt.podQoS),
+				},
+				PodAnnotations: map[string]string{},
+				CgroupParent:   "burstable/test-pod/test-container",
+			}
+			if tt.badAlloc {
+				containerReq.PodAnnotations[ext.AnnotationResourceStatus] = "bad-alloc-fmt"
+			} else if tt.podAlloc != nil {
+				containerReq.PodAnnotations[ext.AnnotationResourceStatus] = util.DumpJSON(tt.podAlloc)
+			}
+			features.DefaultMutableKoordletFeatureGate.SetFromMap(
+				map[string]bool{string(features.BECPUManager): tt.beCPUManagerEnabled})
+			got, err := r.getContainerCPUSetMems(containerReq)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_cpusetPlugin_parseRuleBadIf(t *testing.T) {
 	type fields struct {
 		rule *cpusetRule
@@ -23,6 +23,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -41,6 +42,7 @@ type Options struct {
 	Reader         resourceexecutor.CgroupReader
 	Executor       resourceexecutor.ResourceUpdateExecutor
 	StatesInformer statesinformer.StatesInformer
+	MetricCache    metriccache.MetricCache
 	EventRecorder  record.EventRecorder
 }
 
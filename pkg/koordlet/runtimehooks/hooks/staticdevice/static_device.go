@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staticdevice
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+type staticDevicePlugin struct {
+	metricCache metriccache.MetricCache
+}
+
+func (p *staticDevicePlugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", "static device inject")
+	p.metricCache = op.MetricCache
+	hooks.Register(rmconfig.PreCreateContainer, "static device inject", "inject device nodes and envs declared by the device descriptors into container", p.InjectDevice)
+}
+
+var singleton *staticDevicePlugin
+
+func Object() *staticDevicePlugin {
+	if singleton == nil {
+		singleton = &staticDevicePlugin{}
+	}
+	return singleton
+}
+
+// InjectDevice injects the device nodes and envs of the allocated devices which are declared by the device descriptors.
+func (p *staticDevicePlugin) InjectDevice(proto protocol.HooksProtocol) error {
+	containerCtx, _ := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin static device")
+	}
+	containerReq := containerCtx.Request
+	alloc, err := ext.GetDeviceAllocations(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	if len(alloc) == 0 {
+		klog.V(5).Infof("no device alloc info in pod anno, %s", containerReq.PodMeta.Name)
+		return nil
+	}
+
+	staticDevices := p.getStaticDevices()
+	if len(staticDevices) == 0 {
+		return nil
+	}
+	declared := map[schedulingv1alpha1.DeviceType]map[int32]*util.StaticDeviceInfo{}
+	for i := range staticDevices {
+		d := &staticDevices[i]
+		if declared[d.Type] == nil {
+			declared[d.Type] = map[int32]*util.StaticDeviceInfo{}
+		}
+		declared[d.Type][d.Minor] = d
+	}
+
+	deviceTypes := make([]schedulingv1alpha1.DeviceType, 0, len(alloc))
+	for deviceType := range alloc {
+		deviceTypes = append(deviceTypes, deviceType)
+	}
+	sort.Slice(deviceTypes, func(i, j int) bool { return deviceTypes[i] < deviceTypes[j] })
+
+	envs := map[string][]string{}
+	for _, deviceType := range deviceTypes {
+		for _, allocation := range alloc[deviceType] {
+			d, ok := declared[deviceType][allocation.Minor]
+			if !ok {
+				continue
+			}
+			for _, deviceNode := range d.DeviceNodes {
+				deviceType, err := system.GetDeviceType(deviceNode)
+				if err != nil {
+					klog.Errorf("InjectDevice: GetDeviceType from %s error:%v", deviceNode, err)
+					return err
+				}
+				deviceNumbers, err := system.GetDeviceNumbers(deviceNode)
+				if err != nil {
+					klog.Errorf("InjectDevice: GetDeviceNumbers from %s error:%v", deviceNode, err)
+					return err
+				}
+				containerCtx.Response.AddContainerDevices = append(containerCtx.Response.AddContainerDevices,
+					&protocol.LinuxDevice{
+						Path:          deviceNode,
+						Major:         deviceNumbers[0],
+						Minor:         deviceNumbers[1],
+						Type:          deviceType,
+						FileModeValue: 0666,
+					})
+			}
+			for name, value := range d.Envs {
+				envs[name] = append(envs[name], value)
+			}
+		}
+	}
+	if len(envs) > 0 {
+		if containerCtx.Response.AddContainerEnvs == nil {
+			containerCtx.Response.AddContainerEnvs = make(map[string]string)
+		}
+		for name, values := range envs {
+			containerCtx.Response.AddContainerEnvs[name] = strings.Join(values, ",")
+		}
+	}
+	klog.V(4).Infof("InjectDevice: AddContainerDevices: %v, AddContainerEnvs: %v",
+		containerCtx.Response.AddContainerDevices, containerCtx.Response.AddContainerEnvs)
+	return nil
+}
+
+// getStaticDevices returns the static devices collected by the metrics advisor, excluding the ones conflicting with
+// the discovered devices, which is consistent with the devices reported in the Device CR.
+func (p *staticDevicePlugin) getStaticDevices() util.StaticDevices {
+	if p.metricCache == nil {
+		return nil
+	}
+	rawStaticDevices, exist := p.metricCache.Get(util.StaticDeviceType)
+	if !exist {
+		klog.V(5).Infof("static device not exist")
+		return nil
+	}
+	staticDevices, ok := rawStaticDevices.(util.StaticDevices)
+	if !ok {
+		klog.Errorf("value type error, expect: %T, got %T", util.StaticDevices{}, rawStaticDevices)
+		return nil
+	}
+
+	var gpus util.GPUDevices
+	if rawGPUs, exist := p.metricCache.Get(util.GPUDeviceType); exist {
+		gpus, _ = rawGPUs.(util.GPUDevices)
+	}
+	var rdmas util.RDMADevices
+	if rawRDMAs, exist := p.metricCache.Get(util.RDMADeviceType); exist {
+		rdmas, _ = rawRDMAs.(util.RDMADevices)
+	}
+	staticDevices, _ = util.FilterStaticDevices(staticDevices, util.GetDiscoveredDeviceMinors(gpus, rdmas))
+	return staticDevices
+}
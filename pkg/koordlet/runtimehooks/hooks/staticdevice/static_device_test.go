@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staticdevice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_InjectDevice(t *testing.T) {
+	for _, deviceNode := range []string{"/dev/null", "/dev/zero"} {
+		if _, err := system.GetDeviceType(deviceNode); err != nil {
+			t.Skipf("char device %s is not available, err: %v", deviceNode, err)
+		}
+	}
+	nullNumbers, err := system.GetDeviceNumbers("/dev/null")
+	assert.NoError(t, err)
+	zeroNumbers, err := system.GetDeviceNumbers("/dev/zero")
+	assert.NoError(t, err)
+	regularFile := filepath.Join(t.TempDir(), "xdma3_user")
+	assert.NoError(t, os.WriteFile(regularFile, nil, 0644))
+
+	staticDevices := util.StaticDevices{
+		{Type: "fpga", UUID: "fpga-0", Minor: 0, DeviceNodes: []string{"/dev/null"}, Envs: map[string]string{"XILINX_VISIBLE_DEVICES": "0"}},
+		{Type: "fpga", UUID: "fpga-1", Minor: 1, DeviceNodes: []string{"/dev/zero"}, Envs: map[string]string{"XILINX_VISIBLE_DEVICES": "1"}},
+		{Type: "fpga", UUID: "fpga-2", Minor: 2, DeviceNodes: []string{"/dev/not-exist"}},
+		{Type: "fpga", UUID: "fpga-3", Minor: 3, DeviceNodes: []string{regularFile}},
+		{Type: schedulingv1alpha1.GPU, UUID: "gpu-0", Minor: 0, DeviceNodes: []string{"/dev/null"}, Envs: map[string]string{"STATIC_GPU": "0"}},
+	}
+	gpus := util.GPUDevices{{UUID: "GPU-0", Minor: 0}}
+
+	tests := []struct {
+		name            string
+		proto           protocol.HooksProtocol
+		expectedError   bool
+		expectedDevices []*protocol.LinuxDevice
+		expectedEnvs    map[string]string
+	}{
+		{
+			name:          "test empty proto",
+			proto:         nil,
+			expectedError: true,
+		},
+		{
+			name: "no device allocated",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{},
+			},
+		},
+		{
+			name: "device not declared",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"rdma": [{"minor": 0}], "fpga": [{"minor": 4}]}`,
+					},
+				},
+			},
+		},
+		{
+			name: "inject declared devices",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"fpga": [{"minor": 0}, {"minor": 1}]}`,
+					},
+				},
+			},
+			expectedDevices: []*protocol.LinuxDevice{
+				{Path: "/dev/null", Major: nullNumbers[0], Minor: nullNumbers[1], Type: "c", FileModeValue: 0666},
+				{Path: "/dev/zero", Major: zeroNumbers[0], Minor: zeroNumbers[1], Type: "c", FileModeValue: 0666},
+			},
+			expectedEnvs: map[string]string{"XILINX_VISIBLE_DEVICES": "0,1"},
+		},
+		{
+			name: "discovered device takes precedence",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"gpu": [{"minor": 0}]}`,
+					},
+				},
+			},
+		},
+		{
+			name: "device node not exist",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"fpga": [{"minor": 2}]}`,
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name: "device node is not a device file",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"fpga": [{"minor": 3}]}`,
+					},
+				},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+			mockMetricCache.EXPECT().Get(util.StaticDeviceType).Return(staticDevices, true).AnyTimes()
+			mockMetricCache.EXPECT().Get(util.GPUDeviceType).Return(gpus, true).AnyTimes()
+			mockMetricCache.EXPECT().Get(util.RDMADeviceType).Return(nil, false).AnyTimes()
+			plugin := staticDevicePlugin{metricCache: mockMetricCache}
+
+			err := plugin.InjectDevice(tt.proto)
+			assert.Equal(t, tt.expectedError, err != nil, err)
+			if tt.proto != nil && !tt.expectedError {
+				containerCtx := tt.proto.(*protocol.ContainerContext)
+				assert.Equal(t, tt.expectedDevices, containerCtx.Response.AddContainerDevices)
+				assert.Equal(t, tt.expectedEnvs, containerCtx.Response.AddContainerEnvs)
+			}
+		})
+	}
+}
@@ -46,8 +46,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -165,6 +167,9 @@ func (p *tcPlugin) SetPodNetCls(proto protocol.HooksProtocol) error {
 	ing, egress, err := getIngressAndEgress(podCtx.Request.Annotations)
 	if err != nil {
 		klog.Errorf("failed to get net config from annotation in pod(%s/%s/%v)", podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, podCtx.Request.PodMeta.UID)
+	} else {
+		ing, egress = koordletutil.GetPodNetworkBandwidthLimit(ing, egress,
+			util.GetNetworkBandwidthRequestFromExtendedResourceSpec(podCtx.Request.ExtendedResources))
 	}
 
 	r := p.getRule()
@@ -325,6 +330,8 @@ func (p *tcPlugin) refreshForAllPods(pods []*statesinformer.PodMeta, rule *tcRul
 		ing, egress, err := getIngressAndEgress(pod.Pod.Annotations)
 		if err != nil {
 			klog.Errorf("failed to get net config from annotation in pod(%s)", format.Pod(pod.Pod))
+		} else {
+			ing, egress = koordletutil.GetPodNetworkBandwidthLimit(ing, egress, util.GetPodNetworkBandwidthRequest(pod.Pod))
 		}
 		needLimitAtPodLevel := ing != 0 || egress != 0
 
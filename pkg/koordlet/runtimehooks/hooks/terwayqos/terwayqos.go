@@ -35,6 +35,8 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -166,6 +168,7 @@ func (p *Plugin) update(target *statesinformer.CallbackTarget) error {
 			klog.Errorf("get pod qos failed, err: %v", err)
 			continue
 		}
+		ing, egress = koordletutil.GetPodNetworkBandwidthLimit(ing, egress, util.GetPodNetworkBandwidthRequest(meta.Pod))
 		pods[string(meta.Pod.UID)] = &Pod{
 			PodName:      meta.Pod.Name,
 			PodNamespace: meta.Pod.Namespace,
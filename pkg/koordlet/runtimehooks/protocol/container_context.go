@@ -229,6 +229,9 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.CPUSet != nil {
 		resp.ContainerResources.CpusetCpus = *c.Resources.CPUSet
 	}
+	if c.Resources.CPUSetMems != nil {
+		resp.ContainerResources.CpusetMems = *c.Resources.CPUSetMems
+	}
 	if c.Resources.CFSQuota != nil {
 		resp.ContainerResources.CpuQuota = *c.Resources.CFSQuota
 	}
@@ -289,6 +292,11 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		update.SetLinuxCPUSetCPUs(*c.Response.Resources.CPUSet)
 	}
 
+	if c.Response.Resources.CPUSetMems != nil && *c.Response.Resources.CPUSetMems != "" {
+		adjust.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+		update.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+	}
+
 	if c.Response.Resources.CFSQuota != nil {
 		adjust.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
 		update.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
@@ -377,7 +385,7 @@ func (c *ContainerContext) Update() {
 }
 
 // Inject valid parameters in ContainerContext.Response.Resources,
-// such as CPUShares, CPUSet, CFSQuota, MemoryLimit...
+// such as CPUShares, CPUSet, CPUSetMems, CFSQuota, MemoryLimit...
 func (c *ContainerContext) injectForOrigin() {
 	// If CPUShares is not nil, set container cpu share
 	if c.Response.Resources.CPUShares != nil {
@@ -408,6 +416,20 @@ func (c *ContainerContext) injectForOrigin() {
 				*c.Response.Resources.CPUSet, c.Request.CgroupParent)
 		}
 	}
+	// If CPUSetMems is not nil and is not an empty string, set container cpuset.mems
+	if c.Response.Resources.CPUSetMems != nil && *c.Response.Resources.CPUSetMems != "" {
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message("set container cpuset.mems to %v", *c.Response.Resources.CPUSetMems)
+		updater, err := injectCPUSetMems(c.Request.CgroupParent, *c.Response.Resources.CPUSetMems, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set container %v/%v/%v cpuset.mems %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set container %v/%v/%v cpuset.mems %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUSetMems, c.Request.CgroupParent)
+		}
+	}
 	// If CFSQuota is not nil, set container cfs quota
 	if c.Response.Resources.CFSQuota != nil {
 		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
@@ -442,7 +464,21 @@ func (c *ContainerContext) injectForOrigin() {
 }
 
 func (c *ContainerContext) injectForExt() {
-	// TODO
+	// the memory migrate should be set before the cpuset.mems are updated in injectForOrigin
+	if c.Response.Resources.CPUSetMemoryMigrate != nil {
+		eventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
+			"set container cpuset memory migrate to %v", *c.Response.Resources.CPUSetMemoryMigrate)
+		updater, err := injectCPUSetMemoryMigrate(c.Request.CgroupParent, *c.Response.Resources.CPUSetMemoryMigrate, eventHelper, c.executor)
+		if err != nil {
+			klog.Infof("set container %v/%v/%v cpuset memory migrate %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMemoryMigrate, c.Request.CgroupParent, err)
+		} else {
+			c.updaters = append(c.updaters, updater)
+			klog.V(5).Infof("set container %v/%v/%v cpuset memory migrate %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUSetMemoryMigrate, c.Request.CgroupParent)
+		}
+	}
 }
 
 func getContainerID(podAnnotations map[string]string, containerUID string) string {
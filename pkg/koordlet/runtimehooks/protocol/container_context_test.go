@@ -136,6 +136,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 						CPUShares:   pointer.Int64(1024 * 500 / 1000),
 						CFSQuota:    pointer.Int64(1024 * 500 / 1000),
 						CPUSet:      pointer.String("0,1,2"),
+						CPUSetMems:  pointer.String("0"),
 						MemoryLimit: pointer.Int64(2 * 1024 * 1024 * 1024),
 					},
 					AddContainerEnvs: map[string]string{"test": "test"},
@@ -158,6 +159,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
@@ -178,6 +180,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
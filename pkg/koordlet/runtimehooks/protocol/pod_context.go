@@ -41,6 +41,8 @@ type PodMeta struct {
 	Namespace string
 	Name      string
 	UID       string
+	// OwnerReferences is only available from the reconciler since the runtime requests do not carry it.
+	OwnerReferences []metav1.OwnerReference
 }
 
 func (p *PodMeta) String() string {
@@ -63,6 +65,7 @@ func (p *PodMeta) FromReconciler(meta metav1.ObjectMeta) {
 	p.Namespace = meta.Namespace
 	p.Name = meta.Name
 	p.UID = string(meta.UID)
+	p.OwnerReferences = meta.OwnerReferences
 }
 
 type PodRequest struct {
@@ -185,6 +188,9 @@ func (p *PodResponse) ProxyDone(resp *runtimeapi.PodSandboxHookResponse) {
 	if p.Resources.CPUSet != nil {
 		resp.Resources.CpusetCpus = *p.Resources.CPUSet
 	}
+	if p.Resources.CPUSetMems != nil {
+		resp.Resources.CpusetMems = *p.Resources.CPUSetMems
+	}
 	if p.Resources.CPUShares != nil {
 		resp.Resources.CpuShares = *p.Resources.CPUShares
 	}
@@ -290,6 +296,33 @@ func (p *PodContext) injectForExt() {
 
 	// some of pod-level cgroups are manually updated since pod-stage hooks do not support it;
 	// kubelet may set the cgroups when pod is created or restarted, so we need to update the cgroups repeatedly
+	// the memory migrate should be set before the cpuset.mems are updated
+	if p.Response.Resources.CPUSetMemoryMigrate != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod cpuset memory migrate to %v", *p.Response.Resources.CPUSetMemoryMigrate)
+		updater, err := injectCPUSetMemoryMigrate(p.Request.CgroupParent, *p.Response.Resources.CPUSetMemoryMigrate, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v cpuset memory migrate %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.CPUSetMemoryMigrate, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v cpuset memory migrate %v on cgroup parent %v",
+				p.Request.PodMeta.Namespace, p.Request.PodMeta.Name, *p.Response.Resources.CPUSetMemoryMigrate, p.Request.CgroupParent)
+		}
+	}
+	if p.Response.Resources.CPUSetMems != nil && *p.Response.Resources.CPUSetMems != "" {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod cpuset.mems to %v", *p.Response.Resources.CPUSetMems)
+		updater, err := injectCPUSetMems(p.Request.CgroupParent, *p.Response.Resources.CPUSetMems, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v cpuset.mems %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.CPUSetMems, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v cpuset.mems %v on cgroup parent %v",
+				p.Request.PodMeta.Namespace, p.Request.PodMeta.Name, *p.Response.Resources.CPUSetMems, p.Request.CgroupParent)
+		}
+	}
 	if p.Response.Resources.CPUShares != nil {
 		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
 			"set pod cpu shares to %v", *p.Response.Resources.CPUShares)
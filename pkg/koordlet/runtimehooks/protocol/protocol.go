@@ -84,6 +84,7 @@ type Resources struct {
 	CPUShares     *int64
 	CFSQuota      *int64
 	CPUSet        *string
+	CPUSetMems    *string
 	MemoryLimit   *int64
 	NetClsClassId *uint32
 
@@ -91,10 +92,12 @@ type Resources struct {
 	CPUBvt  *int64
 	CPUIdle *int64
 	Resctrl *Resctrl
+	// CPUSetMemoryMigrate migrates the existing pages to the cpuset.mems when the mems are changed
+	CPUSetMemoryMigrate *bool
 }
 
 func (r *Resources) IsOriginResSet() bool {
-	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.MemoryLimit != nil
+	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.CPUSetMems != nil || r.MemoryLimit != nil
 }
 
 func (r *Resources) FromPod(pod *corev1.Pod) {
@@ -159,6 +162,26 @@ func injectCPUSet(cgroupParent string, cpuset string, a *audit.EventHelper, e re
 	return updater, nil
 }
 
+func injectCPUSetMems(cgroupParent string, mems string, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemsName, cgroupParent, mems, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
+func injectCPUSetMemoryMigrate(cgroupParent string, migrate bool, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	migrateStr := "0"
+	if migrate {
+		migrateStr = "1"
+	}
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemoryMigrateName, cgroupParent, migrateStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectCPUQuota(cgroupParent string, cpuQuota int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	cpuQuotaStr := strconv.FormatInt(cpuQuota, 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, cgroupParent, cpuQuotaStr, a)
@@ -276,6 +276,7 @@ func TestContainerResponse_ProxyDone(t *testing.T) {
 		CFSQuota    *int64
 		MemoryLimit *int64
 		CPUBvt      *int64
+		CpusetMems  string
 	}
 	tests := []struct {
 		name   string
@@ -290,6 +291,7 @@ func TestContainerResponse_ProxyDone(t *testing.T) {
 					CPUShares:   pointer.Int64(15),
 					CFSQuota:    pointer.Int64(1000),
 					CPUSet:      pointer.String("0,1,2"),
+					CPUSetMems:  pointer.String("0"),
 					MemoryLimit: pointer.Int64(1048576),
 					CPUBvt:      pointer.Int64(10),
 				},
@@ -306,6 +308,7 @@ func TestContainerResponse_ProxyDone(t *testing.T) {
 				CFSQuota:    pointer.Int64(1000),
 				MemoryLimit: pointer.Int64(1048576),
 				CPUBvt:      pointer.Int64(10),
+				CpusetMems:  "0",
 			},
 		},
 	}
@@ -321,6 +324,7 @@ func TestContainerResponse_ProxyDone(t *testing.T) {
 			assert.Equal(t, tt.wants.CPUShares, c.Resources.CPUShares, "cpu shares equal")
 			assert.Equal(t, tt.wants.CFSQuota, c.Resources.CFSQuota, "cfs quota equal")
 			assert.Equal(t, tt.wants.MemoryLimit, c.Resources.MemoryLimit, "memory limit equal")
+			assert.Equal(t, tt.wants.CpusetMems, tt.args.resp.ContainerResources.CpusetMems, "cpuset mems equal")
 		})
 	}
 }
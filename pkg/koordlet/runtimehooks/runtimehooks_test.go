@@ -121,7 +121,7 @@ func Test_runtimeHook_Run(t *testing.T) {
 			scheme := apiruntime.NewScheme()
 			kubeClient := &kubernetes.Clientset{}
 			nodeName := "test-node"
-			r, err := NewRuntimeHook(si, tt.fields.config, scheme, kubeClient, nodeName)
+			r, err := NewRuntimeHook(si, nil, tt.fields.config, scheme, kubeClient, nodeName)
 			assert.NoError(t, err)
 			stop := make(chan struct{})
 
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
@@ -45,12 +46,17 @@ func (s *statesInformer) reportDevice() {
 	}
 	device := s.buildBasicDevice(node)
 	func() {
-		gpuDevices := s.buildGPUDevice()
+		gpus := s.getGPUDevices()
+		gpuDevices := s.buildGPUDevice(gpus)
 		if len(gpuDevices) == 0 {
 			return
 		}
-		gpuModel, gpuDriverVer := s.getGPUDriverAndModelFunc()
-		s.fillGPUDevice(device, gpuDevices, gpuModel, gpuDriverVer)
+		gpuVendor, gpuModel, gpuDriverVer := getGPUVendorModelAndDriver(gpus)
+		if gpuModel == "" {
+			// the model and driver of NVIDIA gpus are reported by the nvml
+			gpuModel, gpuDriverVer = s.getGPUDriverAndModelFunc()
+		}
+		s.fillGPUDevice(device, gpuDevices, gpuVendor, gpuModel, gpuDriverVer)
 	}()
 	func() {
 		rdmaDevices := s.buildRDMADevice()
@@ -58,6 +64,12 @@ func (s *statesInformer) reportDevice() {
 			device.Spec.Devices = append(device.Spec.Devices, rdmaDevices...)
 		}
 	}()
+	func() {
+		staticDevices := s.buildStaticDevice(device.Spec.Devices)
+		if len(staticDevices) != 0 {
+			device.Spec.Devices = append(device.Spec.Devices, staticDevices...)
+		}
+	}()
 
 	err := s.updateDevice(device)
 	if err == nil {
@@ -99,12 +111,15 @@ func (s *statesInformer) buildBasicDevice(node *corev1.Node) *schedulingv1alpha1
 }
 
 func (s *statesInformer) fillGPUDevice(device *schedulingv1alpha1.Device,
-	gpuDevices []schedulingv1alpha1.DeviceInfo, gpuModel string, gpuDriverVer string) {
+	gpuDevices []schedulingv1alpha1.DeviceInfo, gpuVendor string, gpuModel string, gpuDriverVer string) {
 
 	device.Spec.Devices = append(device.Spec.Devices, gpuDevices...)
 	if device.Labels == nil {
 		device.Labels = make(map[string]string)
 	}
+	if gpuVendor != "" {
+		device.Labels[extension.LabelGPUVendor] = gpuVendor
+	}
 	if gpuModel != "" {
 		device.Labels[extension.LabelGPUModel] = gpuModel
 	}
@@ -150,8 +165,7 @@ func (s *statesInformer) updateDevice(device *schedulingv1alpha1.Device) error {
 	})
 }
 
-func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
-	//queryParam := generateQueryParam()
+func (s *statesInformer) getGPUDevices() koordletuti.GPUDevices {
 	gpuDeviceInfo, exist := s.metricsCache.Get(koordletuti.GPUDeviceType)
 	if !exist {
 		klog.V(4).Infof("gpu device not exist")
@@ -162,7 +176,26 @@ func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
 		klog.Errorf("value type error, expect: %T, got %T", koordletuti.GPUDevices{}, gpuDeviceInfo)
 		return nil
 	}
+	return gpus
+}
+
+// getGPUVendorModelAndDriver returns the vendor, model and driver version reported along with the gpus.
+// They are returned only if all gpus are the same.
+func getGPUVendorModelAndDriver(gpus koordletuti.GPUDevices) (string, string, string) {
+	if len(gpus) == 0 {
+		return "", "", ""
+	}
+	vendor, model, driverVersion := gpus[0].Vendor, gpus[0].Model, gpus[0].DriverVersion
+	for _, gpu := range gpus[1:] {
+		if gpu.Vendor != vendor || gpu.Model != model || gpu.DriverVersion != driverVersion {
+			klog.Errorf("device vendor, model or driver invalid: %v", gpus)
+			return "", "", ""
+		}
+	}
+	return vendor, model, driverVersion
+}
 
+func (s *statesInformer) buildGPUDevice(gpus koordletuti.GPUDevices) []schedulingv1alpha1.DeviceInfo {
 	var deviceInfos []schedulingv1alpha1.DeviceInfo
 	for idx := range gpus {
 		gpu := gpus[idx]
@@ -252,6 +285,53 @@ func (s *statesInformer) buildRDMADevice() []schedulingv1alpha1.DeviceInfo {
 	return deviceInfos
 }
 
+// buildStaticDevice builds the devices declared by the device descriptors.
+// The discovered devices take precedence over the declared devices with the same type and minor.
+func (s *statesInformer) buildStaticDevice(discoveredDevices []schedulingv1alpha1.DeviceInfo) []schedulingv1alpha1.DeviceInfo {
+	rawStaticDevices, exist := s.metricsCache.Get(koordletuti.StaticDeviceType)
+	if !exist {
+		klog.V(4).Infof("static device not exist")
+		return nil
+	}
+	staticDevices, ok := rawStaticDevices.(koordletuti.StaticDevices)
+	if !ok {
+		klog.Errorf("value type error, expect: %T, got %T", koordletuti.StaticDevices{}, rawStaticDevices)
+		return nil
+	}
+
+	discovered := map[schedulingv1alpha1.DeviceType]sets.Int32{}
+	for _, d := range discoveredDevices {
+		if d.Minor == nil {
+			continue
+		}
+		if discovered[d.Type] == nil {
+			discovered[d.Type] = sets.NewInt32()
+		}
+		discovered[d.Type].Insert(*d.Minor)
+	}
+	staticDevices, conflicted := koordletuti.FilterStaticDevices(staticDevices, discovered)
+	for _, d := range conflicted {
+		klog.Warningf("static device %s minor %d conflicts with the discovered device, skip it", d.Type, d.Minor)
+	}
+
+	var deviceInfos []schedulingv1alpha1.DeviceInfo
+	for idx := range staticDevices {
+		d := staticDevices[idx]
+		deviceInfos = append(deviceInfos, schedulingv1alpha1.DeviceInfo{
+			Type:      d.Type,
+			Labels:    d.Labels,
+			UUID:      d.UUID,
+			Minor:     pointer.Int32(d.Minor),
+			ModuleID:  d.ModuleID,
+			Health:    d.IsHealthy(),
+			Resources: d.Resources,
+			Topology:  d.Topology,
+			VFGroups:  d.VFGroups,
+		})
+	}
+	return deviceInfos
+}
+
 func (s *statesInformer) initGPU() bool {
 	if ret := nvml.Init(); ret != nvml.SUCCESS {
 		if ret == nvml.ERROR_LIBRARY_NOT_FOUND {
@@ -52,6 +52,7 @@ func Test_reportGPUDevice(t *testing.T) {
 	}
 	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(gpuDeviceInfo, true)
 	mockMetricCache.EXPECT().Get(koordletutil.RDMADeviceType).Return(nil, false)
+	mockMetricCache.EXPECT().Get(koordletutil.StaticDeviceType).Return(nil, false)
 	r := &statesInformer{
 		deviceClient: fakeClient,
 		metricsCache: mockMetricCache,
@@ -130,6 +131,7 @@ func Test_reportGPUDevice(t *testing.T) {
 	}
 	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(gpuDeviceInfo, true)
 	mockMetricCache.EXPECT().Get(koordletutil.RDMADeviceType).Return(rdmaDeviceInfo, true)
+	mockMetricCache.EXPECT().Get(koordletutil.StaticDeviceType).Return(nil, false)
 	r.reportDevice()
 
 	expectedDevices = append(expectedDevices, schedulingv1alpha1.DeviceInfo{
@@ -164,3 +166,191 @@ func Test_reportGPUDevice(t *testing.T) {
 	assert.Equal(t, device.Labels[extension.LabelGPUModel], "A100")
 	assert.Equal(t, device.Labels[extension.LabelGPUDriverVersion], "470")
 }
+
+func Test_reportSysfsGPUDevice(t *testing.T) {
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	fakeClient := schedulingfake.NewSimpleClientset().SchedulingV1alpha1().Devices()
+	ctl := gomock.NewController(t)
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	gpuDeviceInfo := koordletutil.GPUDevices{
+		{UUID: "GPU-1", Minor: 0, MemoryTotal: 8000, Vendor: "amd", Model: "AMD-Instinct-MI210", DriverVersion: "6.2.4"},
+		{UUID: "GPU-2", Minor: 1, MemoryTotal: 8000, Vendor: "amd", Model: "AMD-Instinct-MI210", DriverVersion: "6.2.4"},
+	}
+	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(gpuDeviceInfo, true)
+	mockMetricCache.EXPECT().Get(koordletutil.RDMADeviceType).Return(nil, false)
+	mockMetricCache.EXPECT().Get(koordletutil.StaticDeviceType).Return(nil, false)
+	r := &statesInformer{
+		deviceClient: fakeClient,
+		metricsCache: mockMetricCache,
+		states: &PluginState{
+			informerPlugins: map[PluginName]informerPlugin{
+				nodeInformerName: &nodeInformer{
+					node: testNode,
+				},
+			},
+		},
+		getGPUDriverAndModelFunc: func() (string, string) {
+			t.Error("nvml should not be called for the gpus reporting the model")
+			return "", ""
+		},
+	}
+	r.reportDevice()
+	device, err := fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, device.Spec.Devices, 2)
+	assert.Equal(t, map[string]string{
+		extension.LabelGPUVendor:        "amd",
+		extension.LabelGPUModel:         "AMD-Instinct-MI210",
+		extension.LabelGPUDriverVersion: "6.2.4",
+	}, device.Labels)
+}
+
+func Test_getGPUVendorModelAndDriver(t *testing.T) {
+	tests := []struct {
+		name       string
+		gpus       koordletutil.GPUDevices
+		wantVendor string
+		wantModel  string
+		wantDriver string
+	}{
+		{
+			name: "no gpu",
+		},
+		{
+			name: "not reported",
+			gpus: koordletutil.GPUDevices{{UUID: "1"}, {UUID: "2"}},
+		},
+		{
+			name: "same gpus",
+			gpus: koordletutil.GPUDevices{
+				{UUID: "1", Vendor: "hygon", Model: "0x54b7", DriverVersion: "5.16"},
+				{UUID: "2", Vendor: "hygon", Model: "0x54b7", DriverVersion: "5.16"},
+			},
+			wantVendor: "hygon",
+			wantModel:  "0x54b7",
+			wantDriver: "5.16",
+		},
+		{
+			name: "different models",
+			gpus: koordletutil.GPUDevices{
+				{UUID: "1", Vendor: "amd", Model: "AMD-Instinct-MI210", DriverVersion: "6.2.4"},
+				{UUID: "2", Vendor: "amd", Model: "AMD-Instinct-MI250", DriverVersion: "6.2.4"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendor, model, driver := getGPUVendorModelAndDriver(tt.gpus)
+			assert.Equal(t, tt.wantVendor, vendor)
+			assert.Equal(t, tt.wantModel, model)
+			assert.Equal(t, tt.wantDriver, driver)
+		})
+	}
+}
+
+func Test_reportStaticDevice(t *testing.T) {
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	fakeClient := schedulingfake.NewSimpleClientset().SchedulingV1alpha1().Devices()
+	ctl := gomock.NewController(t)
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	gpuDeviceInfo := koordletutil.GPUDevices{
+		{UUID: "GPU-1", Minor: 0, MemoryTotal: 8000},
+	}
+	staticDeviceInfo := koordletutil.StaticDevices{
+		{
+			Type:  schedulingv1alpha1.FPGA,
+			UUID:  "fpga-0",
+			Minor: 0,
+			Resources: corev1.ResourceList{
+				extension.ResourceFPGA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: 0,
+				NodeID:   0,
+				PCIEID:   "pci0000:3a",
+				BusID:    "0000:3b:00.0",
+			},
+		},
+		{
+			Type:   schedulingv1alpha1.FPGA,
+			UUID:   "fpga-1",
+			Minor:  1,
+			Health: pointer.Bool(false),
+			Resources: corev1.ResourceList{
+				extension.ResourceFPGA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+		},
+		{
+			// conflicts with the discovered gpu
+			Type:  schedulingv1alpha1.GPU,
+			UUID:  "GPU-static",
+			Minor: 0,
+		},
+	}
+	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(gpuDeviceInfo, true)
+	mockMetricCache.EXPECT().Get(koordletutil.RDMADeviceType).Return(nil, false)
+	mockMetricCache.EXPECT().Get(koordletutil.StaticDeviceType).Return(staticDeviceInfo, true)
+	r := &statesInformer{
+		deviceClient: fakeClient,
+		metricsCache: mockMetricCache,
+		states: &PluginState{
+			informerPlugins: map[PluginName]informerPlugin{
+				nodeInformerName: &nodeInformer{
+					node: testNode,
+				},
+			},
+		},
+		getGPUDriverAndModelFunc: func() (string, string) {
+			return "A100", "470"
+		},
+	}
+	r.reportDevice()
+	device, err := fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	expectedDevices := []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:   "fpga-0",
+			Minor:  pointer.Int32(0),
+			Type:   schedulingv1alpha1.FPGA,
+			Health: true,
+			Resources: corev1.ResourceList{
+				extension.ResourceFPGA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: 0,
+				NodeID:   0,
+				PCIEID:   "pci0000:3a",
+				BusID:    "0000:3b:00.0",
+			},
+		},
+		{
+			UUID:   "fpga-1",
+			Minor:  pointer.Int32(1),
+			Type:   schedulingv1alpha1.FPGA,
+			Health: false,
+			Resources: corev1.ResourceList{
+				extension.ResourceFPGA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+		},
+		{
+			UUID:   "GPU-1",
+			Minor:  pointer.Int32(0),
+			Type:   schedulingv1alpha1.GPU,
+			Health: true,
+			Resources: corev1.ResourceList{
+				extension.ResourceGPUCore:        *resource.NewQuantity(100, resource.DecimalSI),
+				extension.ResourceGPUMemory:      *resource.NewQuantity(8000, resource.BinarySI),
+				extension.ResourceGPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+		},
+	}
+	assert.Equal(t, expectedDevices, device.Spec.Devices)
+}
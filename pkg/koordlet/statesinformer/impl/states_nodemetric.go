@@ -256,18 +256,19 @@ func (r *nodeMetricInformer) sync() {
 		return
 	}
 
-	nodeMetricInfo, podMetricInfo, hostAppMetricInfo, prodReclaimableMetric := r.collectMetric()
+	nodeMetricInfo, podMetricInfo, hostAppMetricInfo, prodReclaimableMetric, prodSeasonalReclaimableMetric := r.collectMetric()
 	if nodeMetricInfo == nil {
 		klog.Warningf("node metric is not ready, skip this round.")
 		return
 	}
 
 	newStatus := &slov1alpha1.NodeMetricStatus{
-		UpdateTime:            &metav1.Time{Time: time.Now()},
-		NodeMetric:            nodeMetricInfo,
-		PodsMetric:            podMetricInfo,
-		HostApplicationMetric: hostAppMetricInfo,
-		ProdReclaimableMetric: prodReclaimableMetric,
+		UpdateTime:                    &metav1.Time{Time: time.Now()},
+		NodeMetric:                    nodeMetricInfo,
+		PodsMetric:                    podMetricInfo,
+		HostApplicationMetric:         hostAppMetricInfo,
+		ProdReclaimableMetric:         prodReclaimableMetric,
+		ProdSeasonalReclaimableMetric: prodSeasonalReclaimableMetric,
 	}
 	retErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		nodeMetric, err := r.nodeMetricLister.Get(r.nodeName)
@@ -339,7 +340,7 @@ func (r *nodeMetricInformer) generateQueryDuration() (start time.Time, end time.
 }
 
 func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*slov1alpha1.PodMetricInfo,
-	[]*slov1alpha1.HostApplicationMetricInfo, *slov1alpha1.ReclaimableMetric) {
+	[]*slov1alpha1.HostApplicationMetricInfo, *slov1alpha1.ReclaimableMetric, *slov1alpha1.ReclaimableMetric) {
 	spec := r.getNodeMetricSpec()
 	startTime, endTime := r.generateQueryDuration()
 	nodeMetricInfo := &slov1alpha1.NodeMetricInfo{
@@ -369,6 +370,7 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 	}
 	node := r.nodeInformer.GetNode()
 	prodPredictor := r.predictorFactory.New(prediction.ProdReclaimablePredictor, prediction.PredictorContext{Node: node})
+	prodSeasonalPredictor := r.predictorFactory.New(prediction.ProdSeasonalReclaimablePredictor, prediction.PredictorContext{Node: node})
 	for _, podMeta := range podsMeta {
 		podMetric, err := r.collectPodMetric(podMeta, queryParam)
 		if err != nil {
@@ -380,6 +382,10 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		if err != nil {
 			klog.V(4).Infof("predictor add pod aborted, pod %s, err: %v", podMeta.Key(), err)
 		}
+		err = prodSeasonalPredictor.AddPod(podMeta.Pod)
+		if err != nil {
+			klog.V(4).Infof("seasonal predictor add pod aborted, pod %s, err: %v", podMeta.Key(), err)
+		}
 
 		r.fillExtensionMap(podMetric, podMeta.Pod)
 		if len(gpus) > 0 {
@@ -410,7 +416,17 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		metrics.RecordNodeResourcePriorityReclaimableStatus(string(apiext.PriorityProd), 0)
 	}
 
-	return nodeMetricInfo, podsMetricInfo, hostAppMetricInfo, prodReclaimable
+	// the seasonal forecast is missing until the models have learned the daily cycle, so it is not reported then
+	var prodSeasonalReclaimable *slov1alpha1.ReclaimableMetric
+	if p, err := prodSeasonalPredictor.GetResult(); err != nil {
+		klog.V(4).Infof("failed to get seasonal prediction, err %v", err)
+	} else {
+		prodSeasonalReclaimable = &slov1alpha1.ReclaimableMetric{
+			Resource: slov1alpha1.ResourceMap{ResourceList: p},
+		}
+	}
+
+	return nodeMetricInfo, podsMetricInfo, hostAppMetricInfo, prodReclaimable, prodSeasonalReclaimable
 }
 
 func (r *nodeMetricInformer) queryNodeMetric(start time.Time, end time.Time, aggregateType metriccache.AggregationType,
@@ -21,9 +21,12 @@ import (
 	"encoding/json"
 	rawerrors "errors"
 	"fmt"
+	"math/bits"
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -51,6 +54,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/kubelet"
+	resctrlutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
@@ -58,6 +62,9 @@ import (
 
 const (
 	nodeTopoInformerName PluginName = "nodeTopoInformer"
+
+	// beSharePoolMinCPUs is the minimum cpus of the be share pool of a NUMA node sized by the batch resources
+	beSharePoolMinCPUs = 2
 )
 
 type nodeTopologyStatus struct {
@@ -362,6 +369,10 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 	// remove cpus that exclusive for system qos from annotation
 	lsSharePools = removeSystemQOSCPUs(lsSharePools, systemQOSRes)
 	beSharePools = removeSystemQOSCPUs(beSharePools, systemQOSRes)
+	// size the be share pools by the NUMA-level batch resources calculated by the slo-controller
+	if oldTopo := s.GetNodeTopo(); oldTopo != nil && features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
+		beSharePools = sizeBESharePoolsByNUMABatch(beSharePools, lsSharePools, oldTopo.Zones, cpuTopology)
+	}
 	lsCPUSharePoolsJSON, err := json.Marshal(lsSharePools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal cpushare pools of node, err: %v", err)
@@ -386,11 +397,54 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 	if len(systemQOSJson) != 0 {
 		nodeTopoStatus.Annotations[extension.AnnotationNodeSystemQOSResource] = string(systemQOSJson)
 	}
+	// report the resctrl capacity if the resctrl is mounted
+	if resctrlInfo, err := getNodeResctrlInfo(); err != nil {
+		klog.V(5).Infof("skip reporting the node resctrl info, err: %v", err)
+	} else if resctrlInfoJSON, err := json.Marshal(resctrlInfo); err == nil {
+		nodeTopoStatus.Annotations[extension.AnnotationNodeResctrlInfo] = string(resctrlInfoJSON)
+	}
 
 	klog.V(6).Infof("calculate node topology status: %+v", nodeTopoStatus)
 	return nodeTopoStatus, nil
 }
 
+// getNodeResctrlInfo returns the CLOS IDs available for the pod-level resctrl groups and the l3 cache ways.
+// The CLOS IDs taken by the root group and the other groups not created for pods are excluded.
+// It returns an error if the resctrl filesystem is not mounted.
+func getNodeResctrlInfo() (*extension.NodeResctrlInfo, error) {
+	numClosIDs, err := system.ReadResctrlNumClosIDs()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := system.ListResctrlCtrlGroups()
+	if err != nil {
+		return nil, err
+	}
+	// the root group takes a CLOS ID
+	closIDs := numClosIDs - 1
+	for _, group := range groups {
+		if !strings.HasPrefix(group, resctrlutil.ClosdIdPrefix) {
+			closIDs--
+		}
+	}
+	if closIDs < 0 {
+		closIDs = 0
+	}
+
+	cbmStr, err := system.ReadCatL3CbmString()
+	if err != nil {
+		return nil, err
+	}
+	cbm, err := strconv.ParseUint(cbmStr, 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse l3 cbm %s, err: %v", cbmStr, err)
+	}
+	return &extension.NodeResctrlInfo{
+		ClosIDs:   closIDs,
+		CacheWays: bits.Len64(cbm),
+	}, nil
+}
+
 // removeNodeReservedCPUs filter out cpus that reserved by annotation of node.
 func removeNodeReservedCPUs(cpuSharePools []extension.CPUSharedPool, reservedCPUs cpuset.CPUSet) []extension.CPUSharedPool {
 	newCPUSharePools := make([]extension.CPUSharedPool, len(cpuSharePools))
@@ -636,6 +690,7 @@ func isEqualNRTAnnotations(oldAnno, newAnno map[string]string) (bool, string) {
 		extension.AnnotationNodeCPUAllocs,
 		extension.AnnotationNodeReservation,
 		extension.AnnotationNodeSystemQOSResource,
+		extension.AnnotationNodeResctrlInfo,
 	}
 	for _, key := range keys {
 		oldValue, oldExist := oldAnno[key]
@@ -703,6 +758,70 @@ func (s *nodeTopoInformer) calCPUSharePools(lsSharedPoolCPUs map[int32]*extensio
 	return
 }
 
+// sizeBESharePoolsByNUMABatch shrinks the be share pool of each NUMA node to the batch cpu allocatable of the zone,
+// so the BE pods bound to a NUMA node use no more cpus than the batch resources of it. The cpus of the ls share pool
+// are preferred than the cpus of the LSR pods, and the hyper-threads of a core are kept together. The pool keeps at
+// least beSharePoolMinCPUs cpus, and it is not changed if the zone has no batch cpu reported.
+func sizeBESharePoolsByNUMABatch(beSharePools, lsSharePools []extension.CPUSharedPool, zones v1alpha1.ZoneList,
+	cpuTopology *extension.CPUTopology) []extension.CPUSharedPool {
+	zoneBatchMilliCPU := map[string]int64{}
+	for _, zone := range zones {
+		for _, resourceInfo := range zone.Resources {
+			if resourceInfo.Name == string(extension.BatchCPU) {
+				zoneBatchMilliCPU[zone.Name] = resourceInfo.Allocatable.Value()
+			}
+		}
+	}
+	lsCPUs := cpuset.NewCPUSet()
+	for _, pool := range lsSharePools {
+		if set, err := cpuset.Parse(pool.CPUSet); err == nil {
+			lsCPUs = lsCPUs.Union(set)
+		}
+	}
+	cpuCores := map[int]int32{}
+	if cpuTopology != nil {
+		for _, cpuInfo := range cpuTopology.Detail {
+			cpuCores[int(cpuInfo.ID)] = cpuInfo.Core
+		}
+	}
+
+	sizedPools := make([]extension.CPUSharedPool, 0, len(beSharePools))
+	for _, pool := range beSharePools {
+		batchMilliCPU, ok := zoneBatchMilliCPU[util.GenNodeZoneName(int(pool.Node))]
+		poolCPUs, err := cpuset.Parse(pool.CPUSet)
+		if !ok || err != nil {
+			sizedPools = append(sizedPools, pool)
+			continue
+		}
+		size := int((batchMilliCPU + 999) / 1000)
+		if size < beSharePoolMinCPUs {
+			size = beSharePoolMinCPUs
+		}
+		if size >= poolCPUs.Size() {
+			sizedPools = append(sizedPools, pool)
+			continue
+		}
+
+		cpus := poolCPUs.ToSlice()
+		sort.SliceStable(cpus, func(i, j int) bool {
+			iLS, jLS := lsCPUs.Contains(cpus[i]), lsCPUs.Contains(cpus[j])
+			if iLS != jLS {
+				return iLS
+			}
+			if cpuCores[cpus[i]] != cpuCores[cpus[j]] {
+				return cpuCores[cpus[i]] < cpuCores[cpus[j]]
+			}
+			return cpus[i] < cpus[j]
+		})
+		sizedPool := pool
+		sizedPool.CPUSet = cpuset.NewCPUSet(cpus[:size]...).String()
+		sizedPools = append(sizedPools, sizedPool)
+		klog.V(5).Infof("size be share pool of NUMA node %v from %v to %v by batch cpu %vm",
+			pool.Node, pool.CPUSet, sizedPool.CPUSet, batchMilliCPU)
+	}
+	return sizedPools
+}
+
 func covertCPUsToSharePool(cpuIDMap map[int32]*extension.CPUInfo) (sharePools []extension.CPUSharedPool) {
 	// nodeID -> cpulist
 	nodeIDToCpus := make(map[int32][]int)
@@ -779,6 +898,11 @@ func (s *nodeTopoInformer) calTopologyZoneList(nodeCPUInfo *metriccache.NodeCPUI
 		return nil, fmt.Errorf("NUMA node number not matched")
 	}
 
+	var numaHugePagesAllocated map[int32]corev1.ResourceList
+	if features.DefaultKoordletFeatureGate.Enabled(features.HugePageReport) {
+		numaHugePagesAllocated = s.calNUMAHugePagesAllocated()
+	}
+
 	zoneResourceList := map[string]corev1.ResourceList{}
 	for i := 0; i < nodeNum; i++ {
 		var cpuQuant resource.Quantity
@@ -799,11 +923,13 @@ func (s *nodeTopoInformer) calTopologyZoneList(nodeCPUInfo *metriccache.NodeCPUI
 			if ok {
 				if _, ok := hugepageInfos[koordletutil.Hugepage2Mkbyte]; ok {
 					hugepage2Mbyte = hugepageInfos[koordletutil.Hugepage2Mkbyte].MemTotalBytes()
-					hugepage2MQuant = *resource.NewQuantity(int64(hugepage2Mbyte), resource.BinarySI)
+					hugepage2MQuant = calNUMAHugePagesAllocatable(hugepageInfos[koordletutil.Hugepage2Mkbyte],
+						numaHugePagesAllocated[int32(i)][corev1.ResourceHugePagesPrefix+"2Mi"])
 				}
 				if _, ok := hugepageInfos[koordletutil.Hugepage1Gkbyte]; ok {
 					hugepage1Gbyte = hugepageInfos[koordletutil.Hugepage1Gkbyte].MemTotalBytes()
-					hugepage1GQuant = *resource.NewQuantity(int64(hugepage1Gbyte), resource.BinarySI)
+					hugepage1GQuant = calNUMAHugePagesAllocatable(hugepageInfos[koordletutil.Hugepage1Gkbyte],
+						numaHugePagesAllocated[int32(i)][corev1.ResourceHugePagesPrefix+"1Gi"])
 				}
 			}
 		}
@@ -830,6 +956,54 @@ func (s *nodeTopoInformer) calTopologyZoneList(nodeCPUInfo *metriccache.NodeCPUI
 	return zoneList, nil
 }
 
+// calNUMAHugePagesAllocated returns the hugepages allocated by the scheduler on each NUMA node.
+func (s *nodeTopoInformer) calNUMAHugePagesAllocated() map[int32]corev1.ResourceList {
+	if s.podsInformer == nil {
+		return nil
+	}
+	numaHugePagesAllocated := map[int32]corev1.ResourceList{}
+	for _, podMeta := range s.podsInformer.GetAllPods() {
+		if !podMeta.IsRunningOrPending() {
+			continue
+		}
+		status, err := extension.GetResourceStatus(podMeta.Pod.Annotations)
+		if err != nil {
+			klog.V(5).Infof("failed to get resource status of pod %s, err: %v", podMeta.Key(), err)
+			continue
+		}
+		for _, numaNode := range status.NUMANodeResources {
+			for resourceName, quantity := range numaNode.Resources {
+				if !strings.HasPrefix(string(resourceName), corev1.ResourceHugePagesPrefix) {
+					continue
+				}
+				if numaHugePagesAllocated[numaNode.Node] == nil {
+					numaHugePagesAllocated[numaNode.Node] = corev1.ResourceList{}
+				}
+				allocated := numaHugePagesAllocated[numaNode.Node][resourceName]
+				allocated.Add(quantity)
+				numaHugePagesAllocated[numaNode.Node][resourceName] = allocated
+			}
+		}
+	}
+	return numaHugePagesAllocated
+}
+
+// calNUMAHugePagesAllocatable returns the hugepages allocatable of a NUMA node. Since the hugetlb cgroup has no
+// per-node limit, the pages can be consumed on the node beyond the allocations of the scheduler, e.g. by the pods
+// without NUMA-level allocation or the host processes. The pages used beyond the allocations are excluded, so the
+// scheduler does not place the pods on the node without free hugepages.
+func calNUMAHugePagesAllocatable(hugePagesInfo *koordletutil.HugePagesInfo, allocated resource.Quantity) resource.Quantity {
+	total := int64(hugePagesInfo.MemTotalBytes())
+	unallocatedUsed := int64(hugePagesInfo.MemUsedBytes()) - allocated.Value()
+	if unallocatedUsed <= 0 {
+		return *resource.NewQuantity(total, resource.BinarySI)
+	}
+	if unallocatedUsed > total {
+		unallocatedUsed = total
+	}
+	return *resource.NewQuantity(total-unallocatedUsed, resource.BinarySI)
+}
+
 func (s *nodeTopoInformer) calKubeletAllocatedCPUs(sharePoolCPUs map[int32]*extension.CPUInfo) ([]extension.PodCPUAlloc, error) {
 	// Users can specify the kubelet RootDirectory on the host in the koordlet DaemonSet,
 	// inside koordlet it is mounted to the path /var/lib/kubelet by default.
@@ -19,6 +19,7 @@ package impl
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -2888,3 +2889,215 @@ func Test_getTopologyPolicy(t *testing.T) {
 		})
 	}
 }
+
+func Test_getNodeResctrlInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		numClosIDs string
+		cbm        string
+		groups     []string
+		want       *extension.NodeResctrlInfo
+		wantErr    bool
+	}{
+		{
+			name:    "resctrl not mounted",
+			wantErr: true,
+		},
+		{
+			name:       "exclude the root and the non-pod groups",
+			numClosIDs: "16\n",
+			cbm:        "7ff\n",
+			groups:     []string{"LSR", "LS", "BE", "koordlet-pod-uid-1", "mon_groups", "info"},
+			want:       &extension.NodeResctrlInfo{ClosIDs: 12, CacheWays: 11},
+		},
+		{
+			name:       "no CLOS IDs left",
+			numClosIDs: "4",
+			cbm:        "fff",
+			groups:     []string{"LSR", "LS", "BE", "system"},
+			want:       &extension.NodeResctrlInfo{ClosIDs: 0, CacheWays: 12},
+		},
+		{
+			name:       "invalid num_closids",
+			numClosIDs: "invalid",
+			cbm:        "fff",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.numClosIDs != "" {
+				helper.WriteFileContents(system.ResctrlL3NumClosIDs.Path(""), tt.numClosIDs)
+				helper.WriteFileContents(system.ResctrlL3CbmMask.Path(""), tt.cbm)
+			}
+			for _, group := range tt.groups {
+				helper.MkDirAll(filepath.Join(system.GetResctrlGroupRootDirPath(group), system.ResctrlMonData))
+			}
+			got, gotErr := getNodeResctrlInfo()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_sizeBESharePoolsByNUMABatch(t *testing.T) {
+	cpuTopology := &extension.CPUTopology{}
+	for i := 0; i < 16; i++ {
+		cpuTopology.Detail = append(cpuTopology.Detail, extension.CPUInfo{
+			ID:     int32(i),
+			Core:   int32(i / 2),
+			Socket: int32(i / 8),
+			Node:   int32(i / 8),
+		})
+	}
+	beSharePools := []extension.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-7"},
+		{Socket: 1, Node: 1, CPUSet: "8-15"},
+	}
+	// cpus 0-1 are allocated to the LSR pods
+	lsSharePools := []extension.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "2-7"},
+		{Socket: 1, Node: 1, CPUSet: "8-15"},
+	}
+	genZones := func(batchCPUs ...string) topologyv1alpha1.ZoneList {
+		var zones topologyv1alpha1.ZoneList
+		for i, batchCPU := range batchCPUs {
+			zone := topologyv1alpha1.Zone{Name: util.GenNodeZoneName(i), Type: util.NodeZoneType}
+			if batchCPU != "" {
+				zone.Resources = topologyv1alpha1.ResourceInfoList{
+					{Name: string(extension.BatchCPU), Allocatable: resource.MustParse(batchCPU)},
+				}
+			}
+			zones = append(zones, zone)
+		}
+		return zones
+	}
+	tests := []struct {
+		name  string
+		zones topologyv1alpha1.ZoneList
+		want  []extension.CPUSharedPool
+	}{
+		{
+			name:  "no batch cpu reported",
+			zones: genZones("", ""),
+			want:  beSharePools,
+		},
+		{
+			name:  "shrink pools by batch cpu and prefer ls share cpus",
+			zones: genZones("3500", "4000"),
+			want: []extension.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "2-5"},
+				{Socket: 1, Node: 1, CPUSet: "8-11"},
+			},
+		},
+		{
+			name:  "keep the minimum cpus",
+			zones: genZones("0", "500"),
+			want: []extension.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "2-3"},
+				{Socket: 1, Node: 1, CPUSet: "8-9"},
+			},
+		},
+		{
+			name:  "batch cpu not less than the pool",
+			zones: genZones("8000", "12000"),
+			want:  beSharePools,
+		},
+		{
+			name:  "only shrink the pool of the zone reported",
+			zones: genZones("", "2000"),
+			want: []extension.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "0-7"},
+				{Socket: 1, Node: 1, CPUSet: "8-9"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sizeBESharePoolsByNUMABatch(beSharePools, lsSharePools, tt.zones, cpuTopology)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_calNUMAHugePagesAllocatable(t *testing.T) {
+	hugepages1Gi := corev1.ResourceName(corev1.ResourceHugePagesPrefix + "1Gi")
+	newPod := func(name string, phase corev1.PodPhase, numaNodeResources ...extension.NUMANodeResource) *statesinformer.PodMeta {
+		return &statesinformer.PodMeta{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+					Annotations: map[string]string{
+						extension.AnnotationResourceStatus: util.DumpJSON(&extension.ResourceStatus{NUMANodeResources: numaNodeResources}),
+					},
+				},
+				Status: corev1.PodStatus{Phase: phase},
+			},
+		}
+	}
+	s := &nodeTopoInformer{
+		podsInformer: &podsInformer{
+			podMap: map[string]*statesinformer.PodMeta{
+				"pod-1": newPod("pod-1", corev1.PodRunning,
+					extension.NUMANodeResource{Node: 0, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("2Gi")}},
+					extension.NUMANodeResource{Node: 1, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("2Gi")}}),
+				"pod-2": newPod("pod-2", corev1.PodPending,
+					extension.NUMANodeResource{Node: 0, Resources: corev1.ResourceList{
+						hugepages1Gi:       resource.MustParse("1Gi"),
+						corev1.ResourceCPU: resource.MustParse("2"),
+					}}),
+				"pod-3": newPod("pod-3", corev1.PodSucceeded,
+					extension.NUMANodeResource{Node: 1, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("4Gi")}}),
+			},
+		},
+	}
+	allocated := s.calNUMAHugePagesAllocated()
+	assert.Equal(t, 2, len(allocated))
+	assert.True(t, allocated[0][hugepages1Gi].Equal(resource.MustParse("3Gi")), allocated[0])
+	assert.True(t, allocated[1][hugepages1Gi].Equal(resource.MustParse("2Gi")), allocated[1])
+
+	tests := []struct {
+		name      string
+		usedPages uint64
+		allocated resource.Quantity
+		want      resource.Quantity
+	}{
+		{
+			name:      "no pages used",
+			usedPages: 0,
+			allocated: resource.MustParse("2Gi"),
+			want:      resource.MustParse("8Gi"),
+		},
+		{
+			name:      "pages used within the allocations",
+			usedPages: 2,
+			allocated: resource.MustParse("3Gi"),
+			want:      resource.MustParse("8Gi"),
+		},
+		{
+			name:      "exclude the pages used beyond the allocations",
+			usedPages: 5,
+			allocated: resource.MustParse("2Gi"),
+			want:      resource.MustParse("5Gi"),
+		},
+		{
+			name:      "all pages used without allocation",
+			usedPages: 8,
+			want:      resource.MustParse("0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hugePagesInfo := &koordletutil.HugePagesInfo{
+				NumPages:  8,
+				PageSize:  koordletutil.Hugepage1Gkbyte,
+				UsedPages: tt.usedPages,
+			}
+			got := calNUMAHugePagesAllocatable(hugePagesInfo, tt.allocated)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want.String(), got.String())
+		})
+	}
+}
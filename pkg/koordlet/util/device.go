@@ -42,6 +42,10 @@ type GPUDeviceInfo struct {
 	NodeID      int32  `json:"nodeID"`
 	PCIE        string `json:"pcie,omitempty"`
 	BusID       string `json:"busID,omitempty"`
+	// Vendor, Model and DriverVersion are reported by the device managers discovering devices from the sysfs
+	Vendor        string `json:"vendor,omitempty"`
+	Model         string `json:"model,omitempty"`
+	DriverVersion string `json:"driverVersion,omitempty"`
 }
 
 type RDMADevices []RDMADeviceInfo
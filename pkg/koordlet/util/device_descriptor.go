@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// StaticDeviceType is the type of the devices declared by the device descriptor files rather than discovered by
+// the vendor libraries, e.g. FPGAs, NPUs.
+const StaticDeviceType DeviceType = "Static"
+
+type StaticDevices []StaticDeviceInfo
+
+func (s StaticDevices) Type() DeviceType {
+	return StaticDeviceType
+}
+
+// DeviceDescriptorFile is the content of a device descriptor file in YAML or JSON.
+//
+//	devices:
+//	- type: fpga
+//	  id: xilinx-u250-0
+//	  minor: 0
+//	  resources:
+//	    koordinator.sh/fpga: 100
+//	  topology:
+//	    socketID: 0
+//	    nodeID: 0
+//	    pcieID: pci0000:3a
+//	    busID: 0000:3b:00.0
+//	  deviceNodes:
+//	  - /dev/xdma0_user
+//	  envs:
+//	    XILINX_VISIBLE_DEVICES: "0"
+type DeviceDescriptorFile struct {
+	Devices []StaticDeviceInfo `json:"devices,omitempty"`
+}
+
+type StaticDeviceInfo struct {
+	// Type represents the type of device, e.g. fpga
+	Type schedulingv1alpha1.DeviceType `json:"type"`
+	// UUID represents the UUID of device
+	UUID string `json:"id"`
+	// Minor represents the Minor number of Device, starting from 0
+	Minor int32 `json:"minor"`
+	// ModuleID represents the physical id of Device
+	ModuleID *int32 `json:"moduleID,omitempty"`
+	// Labels are copied to the labels of the device in the Device CR
+	Labels map[string]string `json:"labels,omitempty"`
+	// Health indicates whether the device is normal, the device is healthy if not set
+	Health *bool `json:"health,omitempty"`
+	// Resources is a set of (resource name, quantity) pairs
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// Topology represents the topology information about the device
+	Topology *schedulingv1alpha1.DeviceTopology `json:"topology,omitempty"`
+	// VFGroups represents the virtual function devices
+	VFGroups []schedulingv1alpha1.VirtualFunctionGroup `json:"vfGroups,omitempty"`
+	// DeviceNodes are the device files injected into the containers which are allocated the device
+	DeviceNodes []string `json:"deviceNodes,omitempty"`
+	// Envs are injected into the containers which are allocated the device.
+	// The values of the same env of multiple allocated devices are joined by commas.
+	Envs map[string]string `json:"envs,omitempty"`
+}
+
+func (d *StaticDeviceInfo) IsHealthy() bool {
+	return d.Health == nil || *d.Health
+}
+
+func (d *StaticDeviceInfo) validate() error {
+	if d.Type == "" {
+		return fmt.Errorf("device type is empty")
+	}
+	if d.UUID == "" {
+		return fmt.Errorf("device id is empty")
+	}
+	if d.Minor < 0 {
+		return fmt.Errorf("device minor %d is invalid", d.Minor)
+	}
+	return nil
+}
+
+// LoadDeviceDescriptors reads the device descriptor files (*.yaml, *.yml, *.json) in the dir.
+// An invalid file is skipped as a whole, and a device whose type and minor or type and id duplicate with a previous
+// device is skipped, where the files are read in the lexical order. The errors of the skipped files and devices are
+// aggregated and returned along with the valid devices.
+// It returns nothing if the dir does not exist.
+func LoadDeviceDescriptors(dir string) (StaticDevices, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device descriptor dir %s, err: %w", dir, err)
+	}
+
+	var errs []error
+	devices := StaticDevices{}
+	minors := map[schedulingv1alpha1.DeviceType]map[int32]string{}
+	ids := map[schedulingv1alpha1.DeviceType]map[string]string{}
+	for _, entry := range entries { // sorted by filename
+		if entry.IsDir() || !isDeviceDescriptorFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		descriptor, err := readDeviceDescriptorFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i := range descriptor.Devices {
+			d := descriptor.Devices[i]
+			if err = d.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("invalid device %d in %s, err: %w", i, path, err))
+				continue
+			}
+			if minors[d.Type] == nil {
+				minors[d.Type] = map[int32]string{}
+				ids[d.Type] = map[string]string{}
+			}
+			if prev, ok := minors[d.Type][d.Minor]; ok {
+				errs = append(errs, fmt.Errorf("device %s minor %d in %s is already declared in %s", d.Type, d.Minor, path, prev))
+				continue
+			}
+			if prev, ok := ids[d.Type][d.UUID]; ok {
+				errs = append(errs, fmt.Errorf("device %s id %s in %s is already declared in %s", d.Type, d.UUID, path, prev))
+				continue
+			}
+			minors[d.Type][d.Minor] = path
+			ids[d.Type][d.UUID] = path
+			devices = append(devices, d)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Type != devices[j].Type {
+			return devices[i].Type < devices[j].Type
+		}
+		return devices[i].Minor < devices[j].Minor
+	})
+	return devices, utilerrors.NewAggregate(errs)
+}
+
+// GetDiscoveredDeviceMinors returns the minors of the devices discovered by the vendor libraries or the sysfs, which
+// are consistent with the minors reported in the Device CR.
+func GetDiscoveredDeviceMinors(gpus GPUDevices, rdmas RDMADevices) map[schedulingv1alpha1.DeviceType]sets.Int32 {
+	discovered := map[schedulingv1alpha1.DeviceType]sets.Int32{}
+	if len(gpus) > 0 {
+		discovered[schedulingv1alpha1.GPU] = sets.NewInt32()
+		for i := range gpus {
+			discovered[schedulingv1alpha1.GPU].Insert(gpus[i].Minor)
+		}
+	}
+	if len(rdmas) > 0 {
+		// the minors of the rdma devices are assigned by the order of their IDs
+		discovered[schedulingv1alpha1.RDMA] = sets.NewInt32()
+		for i := range rdmas {
+			discovered[schedulingv1alpha1.RDMA].Insert(int32(i))
+		}
+	}
+	return discovered
+}
+
+// FilterStaticDevices filters out the static devices whose type and minor conflict with the discovered devices, since
+// the discovered devices take precedence. It returns the devices kept and the devices filtered out.
+func FilterStaticDevices(devices StaticDevices, discovered map[schedulingv1alpha1.DeviceType]sets.Int32) (StaticDevices, StaticDevices) {
+	var kept, conflicted StaticDevices
+	for i := range devices {
+		if discovered[devices[i].Type].Has(devices[i].Minor) {
+			conflicted = append(conflicted, devices[i])
+			continue
+		}
+		kept = append(kept, devices[i])
+	}
+	return kept, conflicted
+}
+
+func readDeviceDescriptorFile(path string) (*DeviceDescriptorFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device descriptor %s, err: %w", path, err)
+	}
+	descriptor := &DeviceDescriptorFile{}
+	// json is a subset of yaml
+	if err = yaml.UnmarshalStrict(data, descriptor); err != nil {
+		return nil, fmt.Errorf("failed to parse device descriptor %s, err: %w", path, err)
+	}
+	return descriptor, nil
+}
+
+// isDeviceDescriptorFile checks the extension of the file, and the hidden files are ignored, e.g. the "..data" of
+// the ConfigMap volumes.
+func isDeviceDescriptorFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestLoadDeviceDescriptors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    StaticDevices
+		wantErr bool
+	}{
+		{
+			name: "dir not exist",
+		},
+		{
+			name:  "no descriptor",
+			files: map[string]string{"README.md": "# devices"},
+			want:  StaticDevices{},
+		},
+		{
+			name: "yaml and json descriptors",
+			files: map[string]string{
+				"fpga.yaml": `
+devices:
+- type: fpga
+  id: fpga-1
+  minor: 1
+  health: false
+  resources:
+    koordinator.sh/fpga: 100
+  deviceNodes:
+  - /dev/xdma1_user
+- type: fpga
+  id: fpga-0
+  minor: 0
+  labels:
+    vendor: xilinx
+  resources:
+    koordinator.sh/fpga: 100
+  topology:
+    socketID: 0
+    nodeID: 0
+    pcieID: pci0000:3a
+    busID: 0000:3b:00.0
+  vfGroups:
+  - vfs:
+    - minor: 0
+      busID: 0000:3b:00.1
+  envs:
+    XILINX_VISIBLE_DEVICES: "0"
+`,
+				"npu.json": `{"devices": [{"type": "npu", "id": "npu-0", "minor": 0, "moduleID": 3}]}`,
+			},
+			want: StaticDevices{
+				{
+					Type:   schedulingv1alpha1.FPGA,
+					UUID:   "fpga-0",
+					Minor:  0,
+					Labels: map[string]string{"vendor": "xilinx"},
+					Resources: corev1.ResourceList{
+						extension.ResourceFPGA: resource.MustParse("100"),
+					},
+					Topology: &schedulingv1alpha1.DeviceTopology{
+						SocketID: 0,
+						NodeID:   0,
+						PCIEID:   "pci0000:3a",
+						BusID:    "0000:3b:00.0",
+					},
+					VFGroups: []schedulingv1alpha1.VirtualFunctionGroup{
+						{VFs: []schedulingv1alpha1.VirtualFunction{{Minor: 0, BusID: "0000:3b:00.1"}}},
+					},
+					Envs: map[string]string{"XILINX_VISIBLE_DEVICES": "0"},
+				},
+				{
+					Type:   schedulingv1alpha1.FPGA,
+					UUID:   "fpga-1",
+					Minor:  1,
+					Health: pointer.Bool(false),
+					Resources: corev1.ResourceList{
+						extension.ResourceFPGA: resource.MustParse("100"),
+					},
+					DeviceNodes: []string{"/dev/xdma1_user"},
+				},
+				{
+					Type:     "npu",
+					UUID:     "npu-0",
+					Minor:    0,
+					ModuleID: pointer.Int32(3),
+				},
+			},
+		},
+		{
+			name: "skip invalid files and devices",
+			files: map[string]string{
+				"a.yaml":      "devices:\n- type: fpga\n  id: fpga-0\n  minor: 0\n",
+				"b.yaml":      "devices:\n- type: fpga\n  id: fpga-1\n  minor: 0\n- type: fpga\n  id: fpga-0\n  minor: 1\n- type: fpga\n  minor: 2\n- type: fpga\n  id: fpga-3\n  minor: 3\n",
+				"c.json":      "{\"devices\": [}",
+				"d.yaml":      "devices:\n- type: fpga\n  id: fpga-4\n  minor: 4\n  unknownField: 1\n",
+				".hidden.yml": "devices:\n- type: fpga\n  id: fpga-5\n  minor: 5\n",
+			},
+			want: StaticDevices{
+				{Type: schedulingv1alpha1.FPGA, UUID: "fpga-0", Minor: 0},
+				{Type: schedulingv1alpha1.FPGA, UUID: "fpga-3", Minor: 3},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "devices.d")
+			if tt.files != nil {
+				assert.NoError(t, os.MkdirAll(dir, 0755))
+			}
+			for name, content := range tt.files {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+			}
+			got, err := LoadDeviceDescriptors(dir)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetDiscoveredDeviceMinors(t *testing.T) {
+	gpus := GPUDevices{{UUID: "GPU-1", Minor: 1}, {UUID: "GPU-3", Minor: 3}}
+	rdmas := RDMADevices{{ID: "0000:1f:00.0"}, {ID: "0000:90:00.0"}}
+	expected := map[schedulingv1alpha1.DeviceType]sets.Int32{
+		schedulingv1alpha1.GPU:  sets.NewInt32(1, 3),
+		schedulingv1alpha1.RDMA: sets.NewInt32(0, 1),
+	}
+	assert.Equal(t, expected, GetDiscoveredDeviceMinors(gpus, rdmas))
+	assert.Equal(t, map[schedulingv1alpha1.DeviceType]sets.Int32{}, GetDiscoveredDeviceMinors(nil, nil))
+}
+
+func TestFilterStaticDevices(t *testing.T) {
+	devices := StaticDevices{
+		{Type: "fpga", UUID: "fpga-0", Minor: 0},
+		{Type: schedulingv1alpha1.GPU, UUID: "gpu-0", Minor: 0},
+		{Type: schedulingv1alpha1.GPU, UUID: "gpu-2", Minor: 2},
+	}
+	discovered := map[schedulingv1alpha1.DeviceType]sets.Int32{
+		schedulingv1alpha1.GPU: sets.NewInt32(0, 1),
+	}
+	kept, conflicted := FilterStaticDevices(devices, discovered)
+	assert.Equal(t, StaticDevices{devices[0], devices[2]}, kept)
+	assert.Equal(t, StaticDevices{devices[1]}, conflicted)
+}
@@ -86,12 +86,19 @@ type MemInfo struct {
 type HugePagesInfo struct {
 	NumPages uint64 `json:"numPages,omitempty"`
 	PageSize uint64 `json:"pageSize,omitempty"`
+	// UsedPages is the number of the pages in use, including the reserved pages
+	UsedPages uint64 `json:"usedPages,omitempty"`
 }
 
 func (i *HugePagesInfo) MemTotalBytes() uint64 {
 	return i.PageSize * i.NumPages * 1024
 }
 
+// MemUsedBytes returns the bytes of the hugepages in use, including the reserved pages.
+func (i *HugePagesInfo) MemUsedBytes() uint64 {
+	return i.PageSize * i.UsedPages * 1024
+}
+
 // MemTotalBytes returns the mem info's total bytes.
 func (i *MemInfo) MemTotalBytes() uint64 {
 	return i.MemTotal * 1024
@@ -219,6 +226,21 @@ func GetHugePagesInfo(nodeDir string) (map[uint64]*HugePagesInfo, error) {
 		}
 
 		hugePagesInfo[pageSize].NumPages = numPages
+		freePath := system.GetNUMAHugepagesFreePath(nodeDir, st.Name())
+		val, err = os.ReadFile(freePath)
+		if err != nil {
+			klog.V(5).Infof("failed to read file free_hugepages for %s, consider all pages unused, err: %v", st.Name(), err)
+			continue
+		}
+		var freePages uint64
+		n, err = fmt.Sscanf(string(val), "%d", &freePages)
+		if err != nil || n != 1 {
+			klog.Warningf("could not parse file free_hugepages for %s, contents %q", st.Name(), string(val))
+			continue
+		}
+		if freePages < numPages {
+			hugePagesInfo[pageSize].UsedPages = numPages - freePages
+		}
 	}
 
 	return hugePagesInfo, nil
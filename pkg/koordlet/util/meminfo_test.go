@@ -501,6 +501,8 @@ func TestGetNodeHugePagesInfo(t *testing.T) {
 	helper.WriteFileContents(numaHugePage1GPath1, "10")
 	numaHugePage2MPath1 := system.GetNUMAHugepagesNrPath("node1", "hugepages-2048kB")
 	helper.WriteFileContents(numaHugePage2MPath1, "20")
+	numaHugePage2MFreePath1 := system.GetNUMAHugepagesFreePath("node1", "hugepages-2048kB")
+	helper.WriteFileContents(numaHugePage2MFreePath1, "5")
 
 	expected := map[int32]map[uint64]*HugePagesInfo{
 		0: {
@@ -519,8 +521,9 @@ func TestGetNodeHugePagesInfo(t *testing.T) {
 				PageSize: Hugepage1Gkbyte,
 			},
 			Hugepage2Mkbyte: {
-				NumPages: 20,
-				PageSize: Hugepage2Mkbyte,
+				NumPages:  20,
+				PageSize:  Hugepage2Mkbyte,
+				UsedPages: 15,
 			},
 		},
 	}
@@ -528,6 +531,7 @@ func TestGetNodeHugePagesInfo(t *testing.T) {
 	got, err := GetNodeHugePagesInfo()
 	assert.NoError(t, err)
 	assert.Equal(t, expected, got)
+	assert.Equal(t, uint64(15*Hugepage2Mkbyte*1024), got[1][Hugepage2Mkbyte].MemUsedBytes())
 
 	// test partial failure
 	numaMemInfoPath2 := system.GetNUMAMemInfoPath("node2")
@@ -113,3 +113,20 @@ func GetPodSandboxContainerID(pod *corev1.Pod) (string, error) {
 	}
 	return fmt.Sprintf("%s://%s", containerRuntime, containerHashID), nil
 }
+
+// GetPodNetworkBandwidthLimit returns the ingress and egress bandwidth limits of the pod in bytes per second. The
+// limits parsed from the network qos annotation are kept, and the unlimited directions are limited by the network
+// bandwidth requested by the pod in bps.
+func GetPodNetworkBandwidthLimit(ingress, egress uint64, requestBps int64) (uint64, uint64) {
+	if requestBps <= 0 {
+		return ingress, egress
+	}
+	requestBytes := uint64(requestBps) / 8
+	if ingress == 0 {
+		ingress = requestBytes
+	}
+	if egress == 0 {
+		egress = requestBytes
+	}
+	return ingress, egress
+}
@@ -353,3 +353,46 @@ func TestGetPodSandboxContainerID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPodNetworkBandwidthLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		ingress     uint64
+		egress      uint64
+		requestBps  int64
+		wantIngress uint64
+		wantEgress  uint64
+	}{
+		{
+			name: "no limit",
+		},
+		{
+			name:        "limited by annotation",
+			ingress:     125,
+			egress:      250,
+			requestBps:  8000,
+			wantIngress: 125,
+			wantEgress:  250,
+		},
+		{
+			name:        "limited by requested bandwidth",
+			requestBps:  8000,
+			wantIngress: 1000,
+			wantEgress:  1000,
+		},
+		{
+			name:        "egress limited by requested bandwidth",
+			ingress:     125,
+			requestBps:  8000,
+			wantIngress: 125,
+			wantEgress:  1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIngress, gotEgress := GetPodNetworkBandwidthLimit(tt.ingress, tt.egress, tt.requestBps)
+			assert.Equal(t, tt.wantIngress, gotIngress)
+			assert.Equal(t, tt.wantEgress, gotEgress)
+		})
+	}
+}
@@ -47,6 +47,9 @@ type CPUStatRaw struct {
 	NrPeriods            int64
 	NrThrottled          int64
 	ThrottledNanoSeconds int64
+	// CoreSchedForceIdleNanoSeconds is the accumulated time that the SMT siblings are forced idle by the core
+	// scheduling. It is only available on the cgroup v2 with the core scheduling supported.
+	CoreSchedForceIdleNanoSeconds int64
 }
 
 type MemoryStatRaw struct {
@@ -31,6 +31,8 @@ type CPUStatV2Raw struct {
 	NrPeriods     int64
 	NrThrottled   int64
 	ThrottledUSec int64
+
+	CoreSchedForceIdleUSec int64
 }
 
 func initCgroupsVersion() {
@@ -174,11 +176,20 @@ func ParseCPUStatRawV2(content string) (*CPUStatRaw, error) {
 		}
 		*t.value = v
 	}
+	// optional field, only exists when the core scheduling is supported
+	if valueStr, ok := m["core_sched.force_idle_usec"]; ok {
+		v, err := strconv.ParseInt(valueStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse cpu.stat failed, raw content %s, field %s, err: %v", content, "core_sched.force_idle_usec", err)
+		}
+		cpuStatRawV2.CoreSchedForceIdleUSec = v
+	}
 
 	return &CPUStatRaw{
-		NrPeriods:            cpuStatRawV2.NrPeriods,
-		NrThrottled:          cpuStatRawV2.NrThrottled,
-		ThrottledNanoSeconds: cpuStatRawV2.ThrottledUSec * 1000, // assert no overflow
+		NrPeriods:                     cpuStatRawV2.NrPeriods,
+		NrThrottled:                   cpuStatRawV2.NrThrottled,
+		ThrottledNanoSeconds:          cpuStatRawV2.ThrottledUSec * 1000, // assert no overflow
+		CoreSchedForceIdleNanoSeconds: cpuStatRawV2.CoreSchedForceIdleUSec * 1000,
 	}, nil
 }
 
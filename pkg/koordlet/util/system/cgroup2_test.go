@@ -176,6 +176,16 @@ func TestParseCPUStatRawV2(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			input: "nr_periods 10\nnr_throttled 2\nthrottled_usec 5\ncore_sched.force_idle_usec 300",
+			want: &CPUStatRaw{
+				NrPeriods:                     10,
+				NrThrottled:                   2,
+				ThrottledNanoSeconds:          5000,
+				CoreSchedForceIdleNanoSeconds: 300000,
+			},
+			wantErr: false,
+		},
 		{
 			input:   "nr_periods not_a_number\nnr_throttled 2\nthrottled_usec 5",
 			want:    nil,
@@ -51,6 +51,7 @@ type Config struct {
 	DefaultRuntimeType           string
 	HAMICoreLibraryDirectoryPath string
 	PodResourcesProxyPath        string
+	DeviceDescriptorDir          string
 }
 
 func init() {
@@ -95,6 +96,7 @@ func NewHostModeConfig() *Config {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		DeviceDescriptorDir:          "/etc/koordlet/devices.d",
 	}
 }
 
@@ -112,6 +114,7 @@ func NewDsModeConfig() *Config {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		DeviceDescriptorDir:          "/etc/koordlet/devices.d",
 	}
 }
 
@@ -136,4 +139,5 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.HAMICoreLibraryDirectoryPath, "hami-core-library-directory-path", c.HAMICoreLibraryDirectoryPath, "path of hami core library")
 
 	fs.StringVar(&c.PodResourcesProxyPath, "pod-resources-proxy-path", c.PodResourcesProxyPath, "The path of the socket file for the pod resource proxy")
+	fs.StringVar(&c.DeviceDescriptorDir, "device-descriptor-dir", c.DeviceDescriptorDir, "The directory of the YAML/JSON device descriptors which declare the devices not discovered by the device collectors")
 }
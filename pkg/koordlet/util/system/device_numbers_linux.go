@@ -40,3 +40,19 @@ func GetDeviceNumbers(devicePath string) ([]int64, error) {
 	minor := minor(deviceNumber)
 	return []int64{major, minor}, nil
 }
+
+// GetDeviceType returns the type of the device file, "c" for a char device and "b" for a block device.
+func GetDeviceType(devicePath string) (string, error) {
+	fileInfo, err := os.Stat(devicePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat device file: %v", err)
+	}
+	mode := fileInfo.Mode()
+	if mode&os.ModeDevice == 0 {
+		return "", fmt.Errorf("%s is not a device file", devicePath)
+	}
+	if mode&os.ModeCharDevice != 0 {
+		return "c", nil
+	}
+	return "b", nil
+}
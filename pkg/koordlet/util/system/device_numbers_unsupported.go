@@ -23,3 +23,8 @@ func GetDeviceNumbers(devicePath string) ([]int64, error) {
 	// TODO implement it
 	return []int64{0, 0}, nil
 }
+
+func GetDeviceType(devicePath string) (string, error) {
+	// TODO implement it
+	return "c", nil
+}
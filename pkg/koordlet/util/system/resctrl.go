@@ -42,9 +42,11 @@ const (
 	RdtInfoDir string = "info"
 	L3CatDir   string = "L3"
 
-	ResctrlSchemataName string = "schemata"
-	ResctrlCbmMaskName  string = "cbm_mask"
-	ResctrlTasksName    string = "tasks"
+	ResctrlSchemataName   string = "schemata"
+	ResctrlCbmMaskName    string = "cbm_mask"
+	ResctrlTasksName      string = "tasks"
+	ResctrlNumClosIDsName string = "num_closids"
+	ResctrlMonGroupsDir   string = "mon_groups"
 
 	// L3SchemataPrefix is the prefix of l3 cat schemata
 	L3SchemataPrefix = "L3"
@@ -150,6 +152,7 @@ var (
 	ResctrlSchemata     = NewCommonResctrlResource(ResctrlSchemataName, "")
 	ResctrlTasks        = NewCommonResctrlResource(ResctrlTasksName, "")
 	ResctrlL3CbmMask    = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L3CatDir))
+	ResctrlL3NumClosIDs = NewCommonResctrlResource(ResctrlNumClosIDsName, filepath.Join(RdtInfoDir, L3CatDir))
 	ResctrlLLCOccupancy = NewCommonResctrlResource(ResctrlLLCOccupancyName, "")
 	ResctrlMBLocal      = NewCommonResctrlResource(ResctrlMBMLocalName, "")
 	ResctrlMBTotal      = NewCommonResctrlResource(ResctrlMBMTotalName, "")
@@ -515,6 +518,28 @@ func GetCacheIds() ([]int, error) {
 	return r.CacheIds(), nil
 }
 
+// GetL3CacheSize returns the size in bytes of the L3 cache which the cpu0 belongs to.
+// e.g. `/sys/devices/system/cpu/cpu0/cache/index3/size`=`32768K` -> 33554432
+func GetL3CacheSize() (int64, error) {
+	content, err := os.ReadFile(GetSysCPUL3CacheSizePath())
+	if err != nil {
+		return 0, err
+	}
+	sizeStr := strings.TrimSpace(string(content))
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(sizeStr, "K"):
+		unit, sizeStr = 1024, strings.TrimSuffix(sizeStr, "K")
+	case strings.HasSuffix(sizeStr, "M"):
+		unit, sizeStr = 1024*1024, strings.TrimSuffix(sizeStr, "M")
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse l3 cache size %s, err: %w", string(content), err)
+	}
+	return size * unit, nil
+}
+
 // ParseResctrlSchemataMap parses the content of resctrl schemata.
 // e.g. schemata=`L3:0=fff;1=fff\nMB:0=100;1=100\n` -> `{"L3": {0: "fff", 1: "fff"}, "MB": {0: "100", 1: "100"}}`
 func ParseResctrlSchemataMap(content string) map[string]map[int]string {
@@ -563,6 +588,40 @@ func ReadCatL3CbmString() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// ReadResctrlNumClosIDs reads and returns the number of CLOS IDs supported by the l3 cat, including the root group
+func ReadResctrlNumClosIDs() (int, error) {
+	numClosIDsFile := ResctrlL3NumClosIDs.Path("")
+	out, err := os.ReadFile(numClosIDsFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read l3 num_closids, path %s, err: %v", numClosIDsFile, err)
+	}
+	numClosIDs, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse l3 num_closids %s, err: %v", string(out), err)
+	}
+	return numClosIDs, nil
+}
+
+// ListResctrlCtrlGroups returns the names of the control groups under the resctrl root, excluding the root group.
+func ListResctrlCtrlGroups() ([]string, error) {
+	files, err := os.ReadDir(GetResctrlSubsystemDirPath())
+	if err != nil {
+		return nil, err
+	}
+	var groups []string
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		switch file.Name() {
+		case RdtInfoDir, ResctrlMonGroupsDir, ResctrlMonData:
+			continue
+		}
+		groups = append(groups, file.Name())
+	}
+	return groups, nil
+}
+
 // ReadResctrlTasksMap reads and returns the map of given resctrl group's task ids
 func ReadResctrlTasksMap(groupPath string) (map[int32]struct{}, error) {
 	tasksPath := GetResctrlTasksFilePath(groupPath)
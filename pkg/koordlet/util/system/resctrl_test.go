@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
 )
 
 func Test_ReadResctrlTasksMap(t *testing.T) {
@@ -591,3 +592,49 @@ func TestIsVendorSupportResctrl(t *testing.T) {
 		})
 	}
 }
+
+func TestGetL3CacheSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "failed when size file not exist",
+			wantErr: true,
+		},
+		{
+			name:    "parse size in KiB",
+			content: pointer.String("32768K\n"),
+			want:    32768 * 1024,
+		},
+		{
+			name:    "parse size in MiB",
+			content: pointer.String("64M"),
+			want:    64 * 1024 * 1024,
+		},
+		{
+			name:    "parse size in bytes",
+			content: pointer.String("1048576"),
+			want:    1048576,
+		},
+		{
+			name:    "failed when size is invalid",
+			content: pointer.String("xxK"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.content != nil {
+				helper.WriteFileContents(GetSysCPUL3CacheSizePath(), *tt.content)
+			}
+			got, gotErr := GetL3CacheSize()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
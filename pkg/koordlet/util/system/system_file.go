@@ -35,15 +35,20 @@ const (
 	KernelCmdlineFileName = "cmdline"
 	HugepageDir           = "hugepages"
 	nrPath                = "nr_hugepages"
+	freePath              = "free_hugepages"
 
 	KernelSchedGroupIdentityEnable = "kernel/sched_group_identity_enabled"
 	KernelSchedCore                = "kernel/sched_core"
 
 	SysNUMASubDir   = "bus/node/devices"
 	SysPCIDeviceDir = "bus/pci/devices"
+	SysDRMClassDir  = "class/drm"
+	SysKFDDir       = "class/kfd/kfd"
+	SysModuleDir    = "module"
 
 	SysCPUSMTActiveSubPath       = "devices/system/cpu/smt/active"
 	SysIntelPStateNoTurboSubPath = "devices/system/cpu/intel_pstate/no_turbo"
+	SysCPUL3CacheSizeSubPath     = "devices/system/cpu/cpu0/cache/index3/size"
 )
 
 var (
@@ -96,6 +101,10 @@ func GetNUMAHugepagesNrPath(numaNodeSubDir string, page string) string {
 	return filepath.Join(Conf.SysRootDir, SysNUMASubDir, numaNodeSubDir, HugepageDir, page, nrPath)
 }
 
+func GetNUMAHugepagesFreePath(numaNodeSubDir string, page string) string {
+	return filepath.Join(Conf.SysRootDir, SysNUMASubDir, numaNodeSubDir, HugepageDir, page, freePath)
+}
+
 func GetCPUInfoPath() string {
 	return filepath.Join(Conf.ProcRootDir, ProcCPUInfoName)
 }
@@ -108,12 +117,28 @@ func GetSysIntelPStateNoTurboPath() string {
 	return filepath.Join(Conf.SysRootDir, SysIntelPStateNoTurboSubPath)
 }
 
+func GetSysCPUL3CacheSizePath() string {
+	return filepath.Join(Conf.SysRootDir, SysCPUL3CacheSizeSubPath)
+}
+
 func GetProcSysFilePath(file string) string {
 	return filepath.Join(Conf.ProcRootDir, SysctlSubDir, file)
 }
 
 func GetPCIDeviceDir() string { return filepath.Join(Conf.SysRootDir, SysPCIDeviceDir) }
 
+func GetDRMClassDir() string { return filepath.Join(Conf.SysRootDir, SysDRMClassDir) }
+
+func GetKFDTopologyNodesDir() string {
+	return filepath.Join(Conf.SysRootDir, SysKFDDir, "topology/nodes")
+}
+
+func GetKFDProcDir() string { return filepath.Join(Conf.SysRootDir, SysKFDDir, "proc") }
+
+func GetKernelModuleDir(module string) string {
+	return filepath.Join(Conf.SysRootDir, SysModuleDir, module)
+}
+
 var _ utilsysctl.Interface = &ProcSysctl{}
 
 // ProcSysctl implements Interface by reading and writing files under /proc/sys
@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,6 +46,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/quota/v1alpha1"
 	schedv1alpha1 "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
 )
 
@@ -53,6 +55,16 @@ const Name = "quotaprofile"
 const (
 	ReasonCreateQuotaFailed = "CreateQuotaFailed"
 	ReasonUpdateQuotaFailed = "UpdateQuotaFailed"
+	ReasonQuotaShrunk       = "QuotaShrunk"
+
+	ReasonQuotaSynced          = "QuotaSynced"
+	ReasonNodesMatched         = "NodesMatched"
+	ReasonNoNodeMatched        = "NoNodeMatched"
+	ReasonInvalidNodeSelector  = "InvalidNodeSelector"
+	ReasonValidResourceRatio   = "ValidResourceRatio"
+	ReasonInvalidResourceRatio = "InvalidResourceRatio"
+	ReasonParentQuotaFit       = "ParentQuotaFit"
+	ReasonParentQuotaConflict  = "ParentQuotaConflict"
 )
 
 var resourceDecorators = []func(profile *v1alpha1.ElasticQuotaProfile, total corev1.ResourceList){
@@ -127,9 +139,17 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	oldQuota := quota.DeepCopy()
 
+	newStatus := profile.Status.DeepCopy()
+	newStatus.ObservedGeneration = profile.Generation
+	newStatus.QuotaName = profile.Spec.QuotaName
+
 	selector, err := metav1.LabelSelectorAsSelector(profile.Spec.NodeSelector)
 	if err != nil {
 		klog.Errorf("failed to convert profile %v nodeSelector, error: %v", req.NamespacedName, err)
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionNodesMatched, false, ReasonInvalidNodeSelector, err.Error())
+		if updateErr := r.updateProfileStatus(profile, newStatus); updateErr != nil {
+			klog.Errorf("failed to update profile %v status, error: %v", req.NamespacedName, updateErr)
+		}
 		return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, err
 	}
 	nodeList := &corev1.NodeList{}
@@ -140,14 +160,32 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// TODO: consider node status.
 	totalResource := corev1.ResourceList{}
 	unschedulableResource := corev1.ResourceList{}
+	unschedulableNodes := 0
 	for _, node := range nodeList.Items {
 		totalResource = quotav1.Add(totalResource, GetNodeAllocatable(node))
 		if node.Spec.Unschedulable || !nodeutil.IsNodeReady(&node) {
 			unschedulableResource = quotav1.Add(unschedulableResource, GetNodeAllocatable(node))
+			unschedulableNodes++
 		}
 	}
+	newStatus.MatchedNodes = int32(len(nodeList.Items))
+	newStatus.UnschedulableNodes = int32(unschedulableNodes)
+	newStatus.TotalResource = totalResource.DeepCopy()
+	if len(nodeList.Items) > 0 {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionNodesMatched, true, ReasonNodesMatched, "")
+	} else {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionNodesMatched, false, ReasonNoNodeMatched,
+			"no node matches the nodeSelector, the min of the quota is zero")
+	}
+	if _, err := parseResourceRatio(profile); err != nil {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionResourceRatioValid, false, ReasonInvalidResourceRatio,
+			fmt.Sprintf("%v, the resource ratio 1.0 is used", err))
+	} else {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionResourceRatioValid, true, ReasonValidResourceRatio, "")
+	}
 
 	decorateTotalResource(profile, totalResource)
+	newStatus.DecoratedTotalResource = totalResource.DeepCopy()
 	decorateTotalResource(profile, unschedulableResource)
 
 	resourceKeys := []string{"cpu", "memory"}
@@ -211,12 +249,28 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 	quota.Annotations[extension.AnnotationUnschedulableResource] = string(data)
 
+	conflict, err := r.checkParentQuota(quota)
+	if err != nil {
+		klog.Errorf("failed to check parent quota for profile %v, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if conflict != "" {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionParentQuotaFit, false, ReasonParentQuotaConflict, conflict)
+	} else {
+		setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionParentQuotaFit, true, ReasonParentQuotaFit, "")
+	}
+
+	result := ctrl.Result{RequeueAfter: 10 * time.Second}
 	if !quotaExist {
 		err = r.Client.Create(context.TODO(), quota)
 		if err != nil {
 			r.Recorder.Eventf(profile, "Warning", ReasonCreateQuotaFailed, "failed to create quota, err: %s", err)
 			klog.Errorf("failed create quota for profile %v, error: %v", req.NamespacedName, err)
-			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+			setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionQuotaSynced, false, ReasonCreateQuotaFailed, err.Error())
+			result = ctrl.Result{RequeueAfter: 2 * time.Second}
+		} else {
+			newStatus.LastSyncTime = &metav1.Time{Time: time.Now()}
+			setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionQuotaSynced, true, ReasonQuotaSynced, "")
 		}
 	} else {
 		if !reflect.DeepEqual(quota.Labels, oldQuota.Labels) || !reflect.DeepEqual(quota.Annotations, oldQuota.Annotations) || !reflect.DeepEqual(quota.Spec, oldQuota.Spec) {
@@ -224,12 +278,100 @@ func (r *QuotaProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			if err != nil {
 				r.Recorder.Eventf(profile, "Warning", ReasonUpdateQuotaFailed, "failed to update quota, err: %s", err)
 				klog.Errorf("failed update quota for profile %v, error: %v", req.NamespacedName, err)
-				return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+				setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionQuotaSynced, false, ReasonUpdateQuotaFailed, err.Error())
+				result = ctrl.Result{RequeueAfter: 2 * time.Second}
+			} else {
+				if notShrunk, _ := quotav1.LessThanOrEqual(oldQuota.Spec.Min, quota.Spec.Min); !notShrunk {
+					r.Recorder.Eventf(profile, "Normal", ReasonQuotaShrunk, "min of quota %s shrinks from %s to %s, matched nodes %d, unschedulable nodes %d",
+						quota.Name, util.DumpJSON(oldQuota.Spec.Min), util.DumpJSON(quota.Spec.Min), newStatus.MatchedNodes, newStatus.UnschedulableNodes)
+				}
+				newStatus.LastSyncTime = &metav1.Time{Time: time.Now()}
+				setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionQuotaSynced, true, ReasonQuotaSynced, "")
 			}
+		} else {
+			setProfileCondition(profile, newStatus, v1alpha1.ElasticQuotaProfileConditionQuotaSynced, true, ReasonQuotaSynced, "")
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	if err = r.updateProfileStatus(profile, newStatus); err != nil {
+		klog.Errorf("failed to update profile %v status, error: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return result, nil
+}
+
+// checkParentQuota returns the reason why the generated quota conflicts with its parent quota,
+// which makes the quota webhook reject the changes of the quota.
+func (r *QuotaProfileReconciler) checkParentQuota(quota *schedv1alpha1.ElasticQuota) (string, error) {
+	parentName := quota.Labels[extension.LabelQuotaParent]
+	if parentName == "" || parentName == extension.RootQuotaName {
+		return "", nil
+	}
+
+	quotaList := &schedv1alpha1.ElasticQuotaList{}
+	if err := r.Client.List(context.TODO(), quotaList, utilclient.DisableDeepCopy); err != nil {
+		return "", err
+	}
+	var parent *schedv1alpha1.ElasticQuota
+	siblingsMin := corev1.ResourceList{}
+	for i := range quotaList.Items {
+		q := &quotaList.Items[i]
+		if q.Name == parentName {
+			parent = q
+		} else if q.Name != quota.Name && q.Labels[extension.LabelQuotaParent] == parentName {
+			siblingsMin = quotav1.Add(siblingsMin, q.Spec.Min)
+		}
+	}
+	if parent == nil {
+		return fmt.Sprintf("parent quota %s not found", parentName), nil
+	}
+	if !extension.IsParentQuota(parent) {
+		return fmt.Sprintf("quota %s is not a parent quota", parentName), nil
+	}
+	if treeID := parent.Labels[extension.LabelQuotaTreeID]; treeID != quota.Labels[extension.LabelQuotaTreeID] {
+		return fmt.Sprintf("parent quota %s belongs to another quota tree %s", parentName, treeID), nil
+	}
+	childrenMin := quotav1.Add(siblingsMin, quota.Spec.Min)
+	if fit, exceeded := quotav1.LessThanOrEqual(quotav1.Mask(childrenMin, quotav1.ResourceNames(parent.Spec.Min)), parent.Spec.Min); !fit {
+		return fmt.Sprintf("min of the children %s exceeds min of parent quota %s %s, exceeded %v",
+			util.DumpJSON(childrenMin), parentName, util.DumpJSON(parent.Spec.Min), exceeded), nil
+	}
+	return "", nil
+}
+
+func setProfileCondition(profile *v1alpha1.ElasticQuotaProfile, status *v1alpha1.ElasticQuotaProfileStatus, conditionType string, ok bool, reason, message string) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: profile.Generation,
+		Reason:             reason,
+		Message:            message,
+	}
+	if ok {
+		condition.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+func (r *QuotaProfileReconciler) updateProfileStatus(profile *v1alpha1.ElasticQuotaProfile, newStatus *v1alpha1.ElasticQuotaProfileStatus) error {
+	if isProfileStatusEqual(&profile.Status, newStatus) {
+		return nil
+	}
+	newProfile := profile.DeepCopy()
+	newProfile.Status = *newStatus
+	return r.Client.Status().Update(context.TODO(), newProfile)
+}
+
+func isProfileStatusEqual(old, new *v1alpha1.ElasticQuotaProfileStatus) bool {
+	// the quantities are compared by values since their formats may change after the serialization
+	return old.ObservedGeneration == new.ObservedGeneration &&
+		old.QuotaName == new.QuotaName &&
+		old.MatchedNodes == new.MatchedNodes &&
+		old.UnschedulableNodes == new.UnschedulableNodes &&
+		quotav1.Equals(old.TotalResource, new.TotalResource) &&
+		quotav1.Equals(old.DecoratedTotalResource, new.DecoratedTotalResource) &&
+		reflect.DeepEqual(old.LastSyncTime, new.LastSyncTime) &&
+		reflect.DeepEqual(old.Conditions, new.Conditions)
 }
 
 func Add(mgr ctrl.Manager) error {
@@ -275,13 +417,27 @@ func DecorateResourceByResourceRatio(profile *v1alpha1.ElasticQuotaProfile, tota
 		return
 	}
 
-	ratio := 1.0
-	val, err := strconv.ParseFloat(*profile.Spec.ResourceRatio, 64)
-	if err == nil && val > 0 && val <= 1.0 {
-		ratio = val
+	ratio, err := parseResourceRatio(profile)
+	if err != nil {
+		ratio = 1.0
 	}
 
 	for resourceName, quantity := range total {
 		total[resourceName] = MultiplyQuantity(quantity, resourceName, ratio)
 	}
 }
+
+// parseResourceRatio returns the resource ratio of the profile, which should be in (0, 1].
+func parseResourceRatio(profile *v1alpha1.ElasticQuotaProfile) (float64, error) {
+	if profile.Spec.ResourceRatio == nil {
+		return 1.0, nil
+	}
+	val, err := strconv.ParseFloat(*profile.Spec.ResourceRatio, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resource ratio %q, err: %w", *profile.Spec.ResourceRatio, err)
+	}
+	if val <= 0 || val > 1.0 {
+		return 0, fmt.Errorf("resource ratio %q is out of range (0, 1]", *profile.Spec.ResourceRatio)
+	}
+	return val, nil
+}
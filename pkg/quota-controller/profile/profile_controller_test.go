@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -314,8 +315,9 @@ func TestQuotaProfileReconciler_Reconciler_CreateQuota(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &QuotaProfileReconciler{
-				Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
-				Scheme: scheme,
+				Client:   fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&quotav1alpha1.ElasticQuotaProfile{}).Build(),
+				Scheme:   scheme,
+				Recorder: record.NewFakeRecorder(10),
 			}
 			// create node
 			for _, node := range nodes {
@@ -333,7 +335,8 @@ func TestQuotaProfileReconciler_Reconciler_CreateQuota(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			r.Reconcile(context.TODO(), profileReq)
+			_, err = r.Reconcile(context.TODO(), profileReq)
+			assert.NoError(t, err)
 			quota := &schedv1alpha1.ElasticQuota{}
 			err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: tc.profile.Namespace, Name: tc.profile.Spec.QuotaName}, quota)
 			assert.NoError(t, err)
@@ -353,6 +356,104 @@ func TestQuotaProfileReconciler_Reconciler_CreateQuota(t *testing.T) {
 	}
 }
 
+func TestQuotaProfileReconciler_Reconciler_Status(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	quotav1alpha1.AddToScheme(scheme)
+	schedv1alpha1.AddToScheme(scheme)
+
+	resourceRatio := "1.5"
+	profile := &quotav1alpha1.ElasticQuotaProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "profile1",
+			Generation: 1,
+			Labels:     map[string]string{extension.LabelQuotaTreeID: "tree1"},
+		},
+		Spec: quotav1alpha1.ElasticQuotaProfileSpec{
+			QuotaName:     "profile1-root",
+			ResourceRatio: &resourceRatio,
+			QuotaLabels:   map[string]string{extension.LabelQuotaParent: "parent"},
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"topology.kubernetes.io/zone": "cn-hangzhou-a"},
+			},
+		},
+	}
+	parent := &schedv1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "parent",
+			Labels: map[string]string{
+				extension.LabelQuotaIsParent: "true",
+				extension.LabelQuotaTreeID:   "tree1",
+			},
+		},
+		Spec: schedv1alpha1.ElasticQuotaSpec{
+			Min: createResourceList(15, 1500),
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &QuotaProfileReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&quotav1alpha1.ElasticQuotaProfile{}).WithObjects(profile, parent).Build(),
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	profileReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: profile.Name}}
+	getCondition := func(profile *quotav1alpha1.ElasticQuotaProfile, conditionType string) *metav1.Condition {
+		for i := range profile.Status.Conditions {
+			if profile.Status.Conditions[i].Type == conditionType {
+				return &profile.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// no node matched
+	_, err := r.Reconcile(context.TODO(), profileReq)
+	assert.NoError(t, err)
+	got := &quotav1alpha1.ElasticQuotaProfile{}
+	assert.NoError(t, r.Client.Get(context.TODO(), profileReq.NamespacedName, got))
+	assert.Equal(t, int64(1), got.Status.ObservedGeneration)
+	assert.Equal(t, "profile1-root", got.Status.QuotaName)
+	assert.Equal(t, int32(0), got.Status.MatchedNodes)
+	assert.NotNil(t, got.Status.LastSyncTime)
+	assert.Equal(t, ReasonNoNodeMatched, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionNodesMatched).Reason)
+	assert.Equal(t, ReasonInvalidResourceRatio, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionResourceRatioValid).Reason)
+	assert.Equal(t, ReasonParentQuotaFit, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionParentQuotaFit).Reason)
+	assert.Equal(t, metav1.ConditionTrue, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionQuotaSynced).Status)
+
+	// the generated quota exceeds the parent
+	for _, node := range []*corev1.Node{
+		defaultCreateNode("node1", map[string]string{"topology.kubernetes.io/zone": "cn-hangzhou-a"}, createResourceList(10, 1000)),
+		defaultCreateUnreadyNode("node2", map[string]string{"topology.kubernetes.io/zone": "cn-hangzhou-a"}, createResourceList(10, 1000)),
+	} {
+		assert.NoError(t, r.Client.Create(context.TODO(), node))
+	}
+	_, err = r.Reconcile(context.TODO(), profileReq)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(context.TODO(), profileReq.NamespacedName, got))
+	assert.Equal(t, int32(2), got.Status.MatchedNodes)
+	assert.Equal(t, int32(1), got.Status.UnschedulableNodes)
+	assert.True(t, quotav1.Equals(createResourceList(20, 2000), got.Status.TotalResource))
+	assert.True(t, quotav1.Equals(createResourceList(20, 2000), got.Status.DecoratedTotalResource))
+	assert.Equal(t, ReasonNodesMatched, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionNodesMatched).Reason)
+	assert.Equal(t, ReasonParentQuotaConflict, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionParentQuotaFit).Reason)
+
+	// the generated quota shrinks with the valid ratio
+	newProfile := got.DeepCopy()
+	resourceRatio = "0.5"
+	newProfile.Spec.ResourceRatio = &resourceRatio
+	newProfile.Generation = 2
+	assert.NoError(t, r.Client.Update(context.TODO(), newProfile))
+	_, err = r.Reconcile(context.TODO(), profileReq)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(context.TODO(), profileReq.NamespacedName, got))
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.True(t, quotav1.Equals(createResourceList(10, 1000), got.Status.DecoratedTotalResource))
+	assert.Equal(t, ReasonValidResourceRatio, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionResourceRatioValid).Reason)
+	assert.Equal(t, ReasonParentQuotaFit, getCondition(got, quotav1alpha1.ElasticQuotaProfileConditionParentQuotaFit).Reason)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, ReasonQuotaShrunk)
+}
+
 func TestMultiplyQuantity(t *testing.T) {
 	tests := []struct {
 		name         string
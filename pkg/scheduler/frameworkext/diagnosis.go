@@ -0,0 +1,332 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+var (
+	diagnosisMaxPods     = 1000
+	diagnosisMaxAttempts = 5
+	diagnosisEvent       = false
+)
+
+func init() {
+	pflag.IntVar(&diagnosisMaxPods, "diagnosis-max-pods", diagnosisMaxPods, "The maximum number of pods whose scheduling diagnoses are kept in memory, disable if set to 0")
+	pflag.IntVar(&diagnosisMaxAttempts, "diagnosis-max-attempts", diagnosisMaxAttempts, "The maximum number of the last scheduling attempts kept for each pod")
+	pflag.BoolVar(&diagnosisEvent, "diagnosis-event", diagnosisEvent, "Summarize the scheduling diagnosis of the unschedulable pod into an event")
+}
+
+const (
+	diagnosisServiceName = "diagnoses"
+
+	SchedulingAttemptScheduled     = "Scheduled"
+	SchedulingAttemptUnschedulable = "Unschedulable"
+	SchedulingAttemptError         = "Error"
+
+	ReasonSchedulingDiagnosis = "SchedulingDiagnosis"
+)
+
+// SchedulingDiagnosis records the last scheduling attempts of a pending pod.
+type SchedulingDiagnosis struct {
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`
+	UID       types.UID           `json:"uid,omitempty"`
+	Attempts  []SchedulingAttempt `json:"attempts,omitempty"`
+}
+
+type SchedulingAttempt struct {
+	Timestamp metav1.Time `json:"timestamp"`
+	// QueueAttempts is the number of the attempts the pod has been tried to schedule by the queue.
+	QueueAttempts int    `json:"queueAttempts,omitempty"`
+	Result        string `json:"result"`
+	NodeName      string `json:"nodeName,omitempty"`
+	FailedPlugin  string `json:"failedPlugin,omitempty"`
+	Message       string `json:"message,omitempty"`
+	// NumAllNodes is the number of the nodes evaluated when the pod is unschedulable.
+	NumAllNodes          int                  `json:"numAllNodes,omitempty"`
+	UnschedulablePlugins []string             `json:"unschedulablePlugins,omitempty"`
+	PreFilterMessage     string               `json:"preFilterMessage,omitempty"`
+	FilterStatuses       []PluginFilterStatus `json:"filterStatuses,omitempty"`
+	// PostFilterMessage and NominatedNodeName are the preemption outcome.
+	PostFilterMessage string `json:"postFilterMessage,omitempty"`
+	NominatedNodeName string `json:"nominatedNodeName,omitempty"`
+	// NominatedReservation is the reservation nominated for the pod on the scheduled node.
+	NominatedReservation string `json:"nominatedReservation,omitempty"`
+}
+
+// PluginFilterStatus counts the nodes rejected by a plugin with the same reason.
+type PluginFilterStatus struct {
+	Plugin string `json:"plugin,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Count  int    `json:"count"`
+}
+
+// SchedulingDiagnosisStore keeps the scheduling diagnoses of the most recently attempted pending pods.
+// A scheduled attempt is recorded at Reserve, and the diagnosis of a pod is dropped once the pod is bound or deleted.
+type SchedulingDiagnosisStore struct {
+	lock        sync.Mutex
+	maxPods     int
+	maxAttempts int
+	// lru holds the diagnoses ordered by the last attempt, the most recent one is at the front.
+	lru       *list.List
+	diagnoses map[string]*list.Element
+}
+
+func NewSchedulingDiagnosisStore(maxPods, maxAttempts int) *SchedulingDiagnosisStore {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &SchedulingDiagnosisStore{
+		maxPods:     maxPods,
+		maxAttempts: maxAttempts,
+		lru:         list.New(),
+		diagnoses:   map[string]*list.Element{},
+	}
+}
+
+func diagnosisKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (s *SchedulingDiagnosisStore) Record(pod *corev1.Pod, attempt SchedulingAttempt) {
+	if s == nil || s.maxPods <= 0 || pod == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := diagnosisKey(pod.Namespace, pod.Name)
+	var diagnosis *SchedulingDiagnosis
+	if elem, ok := s.diagnoses[key]; ok {
+		diagnosis = elem.Value.(*SchedulingDiagnosis)
+		s.lru.MoveToFront(elem)
+		if diagnosis.UID != pod.UID {
+			// the pod is recreated with the same name
+			diagnosis.UID = pod.UID
+			diagnosis.Attempts = nil
+		}
+	} else {
+		diagnosis = &SchedulingDiagnosis{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+		}
+		s.diagnoses[key] = s.lru.PushFront(diagnosis)
+		for s.lru.Len() > s.maxPods {
+			oldest := s.lru.Back()
+			s.lru.Remove(oldest)
+			evicted := oldest.Value.(*SchedulingDiagnosis)
+			delete(s.diagnoses, diagnosisKey(evicted.Namespace, evicted.Name))
+		}
+	}
+	diagnosis.Attempts = append(diagnosis.Attempts, attempt)
+	if len(diagnosis.Attempts) > s.maxAttempts {
+		diagnosis.Attempts = diagnosis.Attempts[len(diagnosis.Attempts)-s.maxAttempts:]
+	}
+}
+
+func (s *SchedulingDiagnosisStore) Get(namespace, name string) (*SchedulingDiagnosis, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	elem, ok := s.diagnoses[diagnosisKey(namespace, name)]
+	if !ok {
+		return nil, false
+	}
+	diagnosis := *elem.Value.(*SchedulingDiagnosis)
+	diagnosis.Attempts = append([]SchedulingAttempt(nil), diagnosis.Attempts...)
+	return &diagnosis, true
+}
+
+func (s *SchedulingDiagnosisStore) Delete(namespace, name string) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := diagnosisKey(namespace, name)
+	if elem, ok := s.diagnoses[key]; ok {
+		s.lru.Remove(elem)
+		delete(s.diagnoses, key)
+	}
+}
+
+// List returns the keys of the diagnosed pods, the most recently attempted one first.
+func (s *SchedulingDiagnosisStore) List() []string {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	keys := make([]string, 0, s.lru.Len())
+	for elem := s.lru.Front(); elem != nil; elem = elem.Next() {
+		diagnosis := elem.Value.(*SchedulingDiagnosis)
+		keys = append(keys, diagnosisKey(diagnosis.Namespace, diagnosis.Name))
+	}
+	return keys
+}
+
+var _ services.APIServiceProvider = &SchedulingDiagnosisStore{}
+
+func (s *SchedulingDiagnosisStore) RegisterEndpoints(group *gin.RouterGroup) {
+	group.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.List())
+	})
+	group.GET("/:namespace/:name", func(c *gin.Context) {
+		namespace, name := c.Param("namespace"), c.Param("name")
+		diagnosis, ok := s.Get(namespace, name)
+		if !ok {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "cannot find scheduling diagnosis of pod %s/%s", namespace, name)
+			return
+		}
+		c.JSON(http.StatusOK, diagnosis)
+	})
+}
+
+func newScheduledAttempt(nodeName string, nominatedReservation *ReservationInfo) SchedulingAttempt {
+	attempt := SchedulingAttempt{
+		Timestamp: metav1.Now(),
+		Result:    SchedulingAttemptScheduled,
+		NodeName:  nodeName,
+	}
+	if nominatedReservation != nil {
+		attempt.NominatedReservation = nominatedReservation.GetName()
+	}
+	return attempt
+}
+
+func newFailedAttempt(podInfo *framework.QueuedPodInfo, status *framework.Status, nominatingInfo *framework.NominatingInfo) SchedulingAttempt {
+	attempt := SchedulingAttempt{
+		Timestamp:     metav1.Now(),
+		QueueAttempts: podInfo.Attempts,
+		Result:        SchedulingAttemptError,
+		FailedPlugin:  status.FailedPlugin(),
+		Message:       status.Message(),
+	}
+	if status.IsUnschedulable() {
+		attempt.Result = SchedulingAttemptUnschedulable
+	}
+	if nominatingInfo != nil && nominatingInfo.Mode() == framework.ModeOverride {
+		attempt.NominatedNodeName = nominatingInfo.NominatedNodeName
+	}
+
+	var fitErr *framework.FitError
+	if err := status.AsError(); err != nil && errors.As(err, &fitErr) {
+		attempt.Result = SchedulingAttemptUnschedulable
+		attempt.Message = fitErr.Error()
+		attempt.NumAllNodes = fitErr.NumAllNodes
+		attempt.UnschedulablePlugins = sets.List(fitErr.Diagnosis.UnschedulablePlugins)
+		attempt.PreFilterMessage = fitErr.Diagnosis.PreFilterMsg
+		attempt.PostFilterMessage = fitErr.Diagnosis.PostFilterMsg
+		attempt.FilterStatuses = countFilterStatuses(fitErr.Diagnosis.NodeToStatusMap)
+	}
+	return attempt
+}
+
+func countFilterStatuses(nodeToStatusMap framework.NodeToStatusMap) []PluginFilterStatus {
+	counts := map[PluginFilterStatus]int{}
+	for _, status := range nodeToStatusMap {
+		if status.IsSuccess() {
+			continue
+		}
+		for _, reason := range status.Reasons() {
+			counts[PluginFilterStatus{Plugin: status.FailedPlugin(), Reason: reason}]++
+		}
+	}
+	statuses := make([]PluginFilterStatus, 0, len(counts))
+	for k, count := range counts {
+		k.Count = count
+		statuses = append(statuses, k)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Count != statuses[j].Count {
+			return statuses[i].Count > statuses[j].Count
+		}
+		if statuses[i].Plugin != statuses[j].Plugin {
+			return statuses[i].Plugin < statuses[j].Plugin
+		}
+		return statuses[i].Reason < statuses[j].Reason
+	})
+	return statuses
+}
+
+// summarizeDiagnosis summarizes the failed attempts of the diagnosis into a short message.
+func summarizeDiagnosis(diagnosis *SchedulingDiagnosis) string {
+	var failed int
+	plugins := map[string]int{}
+	for _, attempt := range diagnosis.Attempts {
+		if attempt.Result == SchedulingAttemptScheduled {
+			continue
+		}
+		failed++
+		if attempt.FailedPlugin != "" {
+			plugins[attempt.FailedPlugin]++
+		}
+		for _, status := range attempt.FilterStatuses {
+			plugins[status.Plugin] += status.Count
+		}
+	}
+	if failed == 0 {
+		return ""
+	}
+	pluginNames := make([]string, 0, len(plugins))
+	for name := range plugins {
+		if name != "" {
+			pluginNames = append(pluginNames, name)
+		}
+	}
+	sort.Slice(pluginNames, func(i, j int) bool {
+		if plugins[pluginNames[i]] != plugins[pluginNames[j]] {
+			return plugins[pluginNames[i]] > plugins[pluginNames[j]]
+		}
+		return pluginNames[i] < pluginNames[j]
+	})
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "failed %d of the last %d scheduling attempts", failed, len(diagnosis.Attempts))
+	if len(pluginNames) > 0 {
+		parts := make([]string, 0, len(pluginNames))
+		for _, name := range pluginNames {
+			parts = append(parts, fmt.Sprintf("%s(%d)", name, plugins[name]))
+		}
+		fmt.Fprintf(&sb, ", rejected by: %s", strings.Join(parts, ", "))
+	}
+	last := diagnosis.Attempts[len(diagnosis.Attempts)-1]
+	if last.Message != "" {
+		fmt.Fprintf(&sb, "; last: %s", last.Message)
+	}
+	return sb.String()
+}
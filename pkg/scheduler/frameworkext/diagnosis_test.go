@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+func TestSchedulingDiagnosisStore(t *testing.T) {
+	store := NewSchedulingDiagnosisStore(2, 2)
+	newPod := func(name, uid string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: k8stypes.UID("uid-" + uid)}}
+	}
+
+	pod1 := newPod("pod-1", "1")
+	for i := 0; i < 3; i++ {
+		store.Record(pod1, SchedulingAttempt{QueueAttempts: i, Result: SchedulingAttemptUnschedulable})
+	}
+	diagnosis, ok := store.Get("default", "pod-1")
+	assert.True(t, ok)
+	assert.Len(t, diagnosis.Attempts, 2)
+	assert.Equal(t, 1, diagnosis.Attempts[0].QueueAttempts)
+	assert.Equal(t, 2, diagnosis.Attempts[1].QueueAttempts)
+
+	store.Record(newPod("pod-2", "2"), SchedulingAttempt{Result: SchedulingAttemptUnschedulable})
+	assert.Equal(t, []string{"default/pod-2", "default/pod-1"}, store.List())
+
+	// pod-1 is the least recently attempted pod and is evicted
+	store.Record(newPod("pod-3", "3"), SchedulingAttempt{Result: SchedulingAttemptError})
+	_, ok = store.Get("default", "pod-1")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"default/pod-3", "default/pod-2"}, store.List())
+
+	// the recreated pod starts from a clean history
+	store.Record(newPod("pod-3", "4"), SchedulingAttempt{Result: SchedulingAttemptUnschedulable})
+	diagnosis, ok = store.Get("default", "pod-3")
+	assert.True(t, ok)
+	assert.Equal(t, "uid-4", string(diagnosis.UID))
+	assert.Len(t, diagnosis.Attempts, 1)
+
+	store.Delete("default", "pod-3")
+	_, ok = store.Get("default", "pod-3")
+	assert.False(t, ok)
+
+	var disabled *SchedulingDiagnosisStore
+	disabled.Record(pod1, SchedulingAttempt{})
+	disabled.Delete("default", "pod-1")
+	assert.Nil(t, disabled.List())
+}
+
+func TestNewFailedAttempt(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	fitErr := &framework.FitError{
+		Pod:         pod,
+		NumAllNodes: 3,
+		Diagnosis: framework.Diagnosis{
+			NodeToStatusMap: framework.NodeToStatusMap{
+				"node-1": framework.NewStatus(framework.Unschedulable, "Insufficient cpu").WithFailedPlugin("NodeResourcesFit"),
+				"node-2": framework.NewStatus(framework.Unschedulable, "Insufficient cpu").WithFailedPlugin("NodeResourcesFit"),
+				"node-3": framework.NewStatus(framework.UnschedulableAndUnresolvable, "node(s) didn't match reservation").WithFailedPlugin("Reservation"),
+			},
+			UnschedulablePlugins: sets.New[string]("NodeResourcesFit", "Reservation"),
+			PostFilterMsg:        "preemption: 0/3 nodes are available",
+		},
+	}
+	status := framework.NewStatus(framework.Unschedulable).WithError(fitErr)
+	attempt := newFailedAttempt(&framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}, Attempts: 2}, status, &framework.NominatingInfo{NominatingMode: framework.ModeOverride, NominatedNodeName: "node-1"})
+	assert.Equal(t, SchedulingAttemptUnschedulable, attempt.Result)
+	assert.Equal(t, 2, attempt.QueueAttempts)
+	assert.Equal(t, 3, attempt.NumAllNodes)
+	assert.Equal(t, []string{"NodeResourcesFit", "Reservation"}, attempt.UnschedulablePlugins)
+	assert.Equal(t, "preemption: 0/3 nodes are available", attempt.PostFilterMessage)
+	assert.Equal(t, "node-1", attempt.NominatedNodeName)
+	assert.Equal(t, []PluginFilterStatus{
+		{Plugin: "NodeResourcesFit", Reason: "Insufficient cpu", Count: 2},
+		{Plugin: "Reservation", Reason: "node(s) didn't match reservation", Count: 1},
+	}, attempt.FilterStatuses)
+
+	attempt = newFailedAttempt(&framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}}, framework.NewStatus(framework.Unschedulable, "gang is not ready").WithFailedPlugin("Coscheduling"), nil)
+	assert.Equal(t, SchedulingAttemptUnschedulable, attempt.Result)
+	assert.Equal(t, "Coscheduling", attempt.FailedPlugin)
+	assert.Equal(t, "gang is not ready", attempt.Message)
+
+	scheduled := newScheduledAttempt("node-1", NewReservationInfo(&schedulingv1alpha1.Reservation{ObjectMeta: metav1.ObjectMeta{Name: "reservation-1"}}))
+	assert.Equal(t, SchedulingAttemptScheduled, scheduled.Result)
+	assert.Equal(t, "node-1", scheduled.NodeName)
+	assert.Equal(t, "reservation-1", scheduled.NominatedReservation)
+	assert.Empty(t, newScheduledAttempt("node-1", nil).NominatedReservation)
+
+	diagnosis := &SchedulingDiagnosis{
+		Attempts: []SchedulingAttempt{
+			newFailedAttempt(&framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}}, status, nil),
+			scheduled,
+			attempt,
+		},
+	}
+	assert.Equal(t, "failed 2 of the last 3 scheduling attempts, rejected by: NodeResourcesFit(2), Coscheduling(1), Reservation(1); last: gang is not ready", summarizeDiagnosis(diagnosis))
+	assert.Empty(t, summarizeDiagnosis(&SchedulingDiagnosis{Attempts: []SchedulingAttempt{scheduled}}))
+}
+
+func TestSchedulingDiagnosisService(t *testing.T) {
+	store := NewSchedulingDiagnosisStore(10, 1)
+	store.Record(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}, SchedulingAttempt{Result: SchedulingAttemptUnschedulable, Message: "failed"})
+	engine := services.NewEngine(gin.New())
+	engine.RegisterService(diagnosisServiceName, store)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis/v1/diagnoses/default/test", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	diagnosis := &SchedulingDiagnosis{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), diagnosis))
+	assert.Equal(t, "failed", diagnosis.Attempts[0].Message)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/apis/v1/diagnoses/default/not-found", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
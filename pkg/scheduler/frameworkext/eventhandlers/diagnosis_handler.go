@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventhandlers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+// AddDiagnosisEventHandler drops the scheduling diagnoses of the deleted pods.
+func AddDiagnosisEventHandler(sharedInformerFactory informers.SharedInformerFactory, store *frameworkext.SchedulingDiagnosisStore) {
+	if store == nil {
+		return
+	}
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			deletePodDiagnosis(store, obj)
+		},
+	})
+}
+
+func deletePodDiagnosis(store *frameworkext.SchedulingDiagnosisStore, obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = t.Obj.(*corev1.Pod)
+	}
+	if pod == nil {
+		return
+	}
+	store.Delete(pod.Namespace, pod.Name)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventhandlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+func Test_deletePodDiagnosis(t *testing.T) {
+	store := frameworkext.NewSchedulingDiagnosisStore(10, 1)
+	pod1 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"}}
+	pod2 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2"}}
+	store.Record(pod1, frameworkext.SchedulingAttempt{Result: frameworkext.SchedulingAttemptUnschedulable})
+	store.Record(pod2, frameworkext.SchedulingAttempt{Result: frameworkext.SchedulingAttemptUnschedulable})
+
+	deletePodDiagnosis(store, pod1)
+	assert.Equal(t, []string{"default/pod-2"}, store.List())
+	deletePodDiagnosis(store, cache.DeletedFinalStateUnknown{Key: "default/pod-2", Obj: pod2})
+	assert.Empty(t, store.List())
+	deletePodDiagnosis(store, "unknown")
+}
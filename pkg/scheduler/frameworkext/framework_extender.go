@@ -49,6 +49,7 @@ type frameworkExtenderImpl struct {
 	schedulerFn       func() Scheduler
 	configuredPlugins *schedconfig.Plugins
 	monitor           *SchedulerMonitor
+	diagnosisStore    *SchedulingDiagnosisStore
 
 	koordinatorClientSet             koordinatorclientset.Interface
 	koordinatorSharedInformerFactory koordinatorinformers.SharedInformerFactory
@@ -69,6 +70,9 @@ type frameworkExtenderImpl struct {
 	resizePodPlugins         []ResizePodPlugin
 	preBindExtensionsPlugins map[string]PreBindExtensions
 
+	simulationReservePlugins        map[string]SimulationReservePlugin
+	simulationReservePluginsEnabled []SimulationReservePlugin
+
 	numaTopologyHintProviders []topologymanager.NUMATopologyHintProvider
 	topologyManager           topologymanager.Interface
 
@@ -85,6 +89,7 @@ func NewFrameworkExtender(f *FrameworkExtenderFactory, fw framework.Framework) F
 		errorHandlerDispatcher:           f.errorHandlerDispatcher,
 		schedulerFn:                      schedulerFn,
 		monitor:                          f.monitor,
+		diagnosisStore:                   f.diagnosisStore,
 		koordinatorClientSet:             f.KoordinatorClientSet(),
 		koordinatorSharedInformerFactory: f.koordinatorSharedInformerFactory,
 		reservationNominator:             f.reservationNominator,
@@ -92,6 +97,7 @@ func NewFrameworkExtender(f *FrameworkExtenderFactory, fw framework.Framework) F
 		filterTransformers:               map[string]FilterTransformer{},
 		scoreTransformers:                map[string]ScoreTransformer{},
 		preBindExtensionsPlugins:         map[string]PreBindExtensions{},
+		simulationReservePlugins:         map[string]SimulationReservePlugin{},
 		metricsRecorder:                  f.metricsRecorder,
 	}
 	frameworkExtender.topologyManager = topologymanager.New(frameworkExtender)
@@ -144,6 +150,9 @@ func (ext *frameworkExtenderImpl) updatePlugins(pl framework.Plugin) {
 	if p, ok := pl.(PreBindExtensions); ok {
 		ext.preBindExtensionsPlugins[p.Name()] = p
 	}
+	if p, ok := pl.(SimulationReservePlugin); ok {
+		ext.simulationReservePlugins[p.Name()] = p
+	}
 	if p, ok := pl.(topologymanager.NUMATopologyHintProvider); ok {
 		ext.numaTopologyHintProviders = append(ext.numaTopologyHintProviders, p)
 	}
@@ -170,6 +179,11 @@ func (ext *frameworkExtenderImpl) SetConfiguredPlugins(plugins *schedconfig.Plug
 			ext.scoreTransformersEnabled = append(ext.scoreTransformersEnabled, transformer)
 		}
 	}
+	for _, pl := range ext.configuredPlugins.Reserve.Enabled {
+		if p := ext.simulationReservePlugins[pl.Name]; p != nil {
+			ext.simulationReservePluginsEnabled = append(ext.simulationReservePluginsEnabled, p)
+		}
+	}
 	klog.V(5).InfoS("Set configured transformer plugins",
 		"PreFilterTransformer", len(ext.preFilterTransformersEnabled),
 		"FilterTransformer", len(ext.filterTransformersEnabled),
@@ -197,6 +211,10 @@ func (ext *frameworkExtenderImpl) GetReservationNominator() ReservationNominator
 
 // RunPreFilterPlugins transforms the PreFilter phase of framework with pre-filter transformers.
 func (ext *frameworkExtenderImpl) RunPreFilterPlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	if simState := getSimulationState(cycleState); simState != nil {
+		ext.applySimulatedPods(simState.simulatedPods)
+	}
+
 	for _, transformer := range ext.preFilterTransformersEnabled {
 		startTime := time.Now()
 		newPod, transformed, status := transformer.BeforePreFilter(ctx, cycleState, pod)
@@ -274,6 +292,9 @@ func (ext *frameworkExtenderImpl) RunScorePlugins(ctx context.Context, state *fr
 	if status.IsSuccess() && debugTopNScores > 0 {
 		debugScores(debugTopNScores, pod, pluginToNodeScores, nodes)
 	}
+	if simState := getSimulationState(state); simState != nil && status.IsSuccess() {
+		simState.pluginToNodeScores = pluginToNodeScores
+	}
 	return pluginToNodeScores, status
 }
 
@@ -345,6 +366,8 @@ func (ext *frameworkExtenderImpl) RunPostBindPlugins(ctx context.Context, state
 	if ext.monitor != nil {
 		defer ext.monitor.Complete(pod, nil)
 	}
+	// the pod is no longer pending, the failures of binding are recorded by the FailureHandler after the scheduled attempt
+	ext.diagnosisStore.Delete(pod.Namespace, pod.Name)
 	ext.Framework.RunPostBindPlugins(ctx, state, pod, nodeName)
 }
 
@@ -506,6 +529,13 @@ func (ext *frameworkExtenderImpl) RunReservePluginsReserve(ctx context.Context,
 	schedulingphase.RecordPhase(cycleState, schedulingphase.Reserve)
 	defer func() { schedulingphase.RecordPhase(cycleState, "") }()
 	status := ext.Framework.RunReservePluginsReserve(ctx, cycleState, pod, nodeName)
+	if status.IsSuccess() {
+		var nominatedReservation *ReservationInfo
+		if nominator := ext.GetReservationNominator(); nominator != nil {
+			nominatedReservation = nominator.GetNominatedReservation(pod, nodeName)
+		}
+		ext.diagnosisStore.Record(pod, newScheduledAttempt(nodeName, nominatedReservation))
+	}
 	ext.GetReservationNominator().RemoveNominatedReservations(pod)
 	ext.GetReservationNominator().DeleteNominatedReservePod(pod)
 	return status
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -94,10 +96,18 @@ type FrameworkExtenderFactory struct {
 	nextPodPlugin                    NextPodPlugin
 	profiles                         map[string]FrameworkExtender
 	monitor                          *SchedulerMonitor
+	diagnosisStore                   *SchedulingDiagnosisStore
 	scheduler                        Scheduler
 	schedulePod                      func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error)
 	*errorHandlerDispatcher
 
+	// schedulingCycleLock is held by the scheduling cycle from a pod is dequeued until the next pod is requested,
+	// which serializes the scheduling simulations with the scheduling cycle since they share the snapshot.
+	// It is acquired after NextPod returns, so the simulations are not blocked while the scheduler waits for pods.
+	schedulingCycleLock sync.Mutex
+	// schedulingCycleLocked indicates if the schedulingCycleLock is held by the scheduling cycle.
+	schedulingCycleLocked atomic.Bool
+
 	metricsRecorder *metrics.MetricAsyncRecorder
 }
 
@@ -111,6 +121,14 @@ func NewFrameworkExtenderFactory(options ...Option) (*FrameworkExtenderFactory,
 		return nil, err
 	}
 
+	var diagnosisStore *SchedulingDiagnosisStore
+	if diagnosisMaxPods > 0 {
+		diagnosisStore = NewSchedulingDiagnosisStore(diagnosisMaxPods, diagnosisMaxAttempts)
+		if handleOptions.servicesEngine != nil {
+			handleOptions.servicesEngine.RegisterService(diagnosisServiceName, diagnosisStore)
+		}
+	}
+
 	return &FrameworkExtenderFactory{
 		controllerMaps:                   NewControllersMap(),
 		servicesEngine:                   handleOptions.servicesEngine,
@@ -119,6 +137,7 @@ func NewFrameworkExtenderFactory(options ...Option) (*FrameworkExtenderFactory,
 		reservationNominator:             handleOptions.reservationNominator,
 		profiles:                         map[string]FrameworkExtender{},
 		monitor:                          NewSchedulerMonitor(schedulerMonitorPeriod, schedulingTimeout),
+		diagnosisStore:                   diagnosisStore,
 		errorHandlerDispatcher:           newErrorHandlerDispatcher(),
 		metricsRecorder:                  metrics.NewMetricsAsyncRecorder(1000, time.Second, wait.NeverStop),
 	}, nil
@@ -156,17 +175,26 @@ func (f *FrameworkExtenderFactory) Scheduler() Scheduler {
 	return f.scheduler
 }
 
+// DiagnosisStore returns the scheduling diagnoses of the pending pods, nil if disabled.
+func (f *FrameworkExtenderFactory) DiagnosisStore() *SchedulingDiagnosisStore {
+	return f.diagnosisStore
+}
+
 func (f *FrameworkExtenderFactory) InitScheduler(sched Scheduler) {
 	f.scheduler = sched
 	adaptor, ok := sched.(*SchedulerAdapter)
 	if ok {
+		schedulePod := adaptor.Scheduler.SchedulePod
+		if f.servicesEngine != nil {
+			f.servicesEngine.RegisterSimulator(newSchedulingSimulator(f, adaptor.Scheduler, schedulePod))
+		}
 		if k8sfeature.DefaultFeatureGate.Enabled(features.ResizePod) {
-			schedulePod := adaptor.Scheduler.SchedulePod
 			f.schedulePod = schedulePod
 			adaptor.Scheduler.SchedulePod = f.scheduleOne
 		}
 		nextPod := adaptor.Scheduler.NextPod
 		adaptor.Scheduler.NextPod = func() (*framework.QueuedPodInfo, error) {
+			f.unlockSchedulingCycle()
 			podInfo, err := f.runNextPodPlugin()
 			if err != nil {
 				klog.Errorf("run next pod plugin failed, err: %v", err)
@@ -186,11 +214,23 @@ func (f *FrameworkExtenderFactory) InitScheduler(sched Scheduler) {
 				// Deep copy podInfo to allow pod modification during scheduling
 				podInfo = podInfo.DeepCopy()
 			}
+			f.lockSchedulingCycle()
 			return podInfo, nil
 		}
 	}
 }
 
+func (f *FrameworkExtenderFactory) lockSchedulingCycle() {
+	f.schedulingCycleLock.Lock()
+	f.schedulingCycleLocked.Store(true)
+}
+
+func (f *FrameworkExtenderFactory) unlockSchedulingCycle() {
+	if f.schedulingCycleLocked.CompareAndSwap(true, false) {
+		f.schedulingCycleLock.Unlock()
+	}
+}
+
 func (f *FrameworkExtenderFactory) runNextPodPlugin() (*framework.QueuedPodInfo, error) {
 	if f.nextPodPlugin != nil {
 		startTime := time.Now()
@@ -305,6 +345,22 @@ func (f *FrameworkExtenderFactory) InterceptSchedulerError(sched *scheduler.Sche
 	sched.FailureHandler = func(ctx context.Context, fwk framework.Framework, podInfo *framework.QueuedPodInfo, status *framework.Status, nominatingInfo *framework.NominatingInfo, start time.Time) {
 		f.errorHandlerDispatcher.Error(ctx, fwk, podInfo, status, nominatingInfo, start)
 		f.monitor.Complete(podInfo.Pod, status)
+		f.recordSchedulingFailure(fwk, podInfo, status, nominatingInfo)
+	}
+}
+
+func (f *FrameworkExtenderFactory) recordSchedulingFailure(fwk framework.Framework, podInfo *framework.QueuedPodInfo, status *framework.Status, nominatingInfo *framework.NominatingInfo) {
+	if f.diagnosisStore == nil || podInfo == nil || podInfo.Pod == nil {
+		return
+	}
+	pod := podInfo.Pod
+	f.diagnosisStore.Record(pod, newFailedAttempt(podInfo, status, nominatingInfo))
+	if diagnosisEvent && fwk != nil && fwk.EventRecorder() != nil {
+		if diagnosis, ok := f.diagnosisStore.Get(pod.Namespace, pod.Name); ok {
+			if message := summarizeDiagnosis(diagnosis); message != "" {
+				fwk.EventRecorder().Eventf(pod, nil, corev1.EventTypeWarning, ReasonSchedulingDiagnosis, "Scheduling", message)
+			}
+		}
 	}
 }
 
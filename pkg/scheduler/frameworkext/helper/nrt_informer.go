@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"sync"
+
+	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	nrtInformerFactoriesLock sync.Mutex
+	nrtInformerFactories     = map[framework.Handle]nrtinformers.SharedInformerFactory{}
+)
+
+// GetNRTInformerFactory returns the NodeResourceTopology informer factory shared by the plugins of the framework,
+// so the plugins watching the NodeResourceTopologies share the same informer.
+// The NodeResourceTopology client is taken from the handle if it implements one, otherwise it is created from the
+// kubeconfig of the handle.
+func GetNRTInformerFactory(handle framework.Handle) (nrtinformers.SharedInformerFactory, error) {
+	nrtInformerFactoriesLock.Lock()
+	defer nrtInformerFactoriesLock.Unlock()
+	if factory := nrtInformerFactories[handle]; factory != nil {
+		return factory, nil
+	}
+
+	nrtClient, ok := handle.(nrtclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		var err error
+		nrtClient, err = nrtclientset.NewForConfig(&kubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	factory := nrtinformers.NewSharedInformerFactoryWithOptions(nrtClient, 0)
+	nrtInformerFactories[handle] = factory
+	return factory, nil
+}
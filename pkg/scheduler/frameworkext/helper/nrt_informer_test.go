@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helper
+
+import (
+	"testing"
+
+	nrtfake "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type nrtClientHandle struct {
+	framework.Handle
+	*nrtfake.Clientset
+}
+
+func TestGetNRTInformerFactory(t *testing.T) {
+	handle := &nrtClientHandle{Clientset: nrtfake.NewSimpleClientset()}
+	factory, err := GetNRTInformerFactory(handle)
+	assert.NoError(t, err)
+	assert.NotNil(t, factory)
+
+	// the plugins of the same framework share the factory
+	shared, err := GetNRTInformerFactory(handle)
+	assert.NoError(t, err)
+	assert.Same(t, factory, shared)
+
+	other, err := GetNRTInformerFactory(&nrtClientHandle{Clientset: nrtfake.NewSimpleClientset()})
+	assert.NoError(t, err)
+	assert.NotSame(t, factory, other)
+}
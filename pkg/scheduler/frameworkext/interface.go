@@ -191,6 +191,16 @@ type ResizePodPlugin interface {
 	ResizePod(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status
 }
 
+// SimulationReservePlugin is a ReservePlugin whose Reserve only records the allocation in the cache of the plugin
+// and whose Unreserve releases it, such as the NUMA and device allocations. A scheduling simulation runs the Reserve
+// of these plugins for the placed pods on a cloned CycleState, so the following simulated pods account the
+// allocations, and runs the Unreserve when the simulation completes.
+type SimulationReservePlugin interface {
+	framework.ReservePlugin
+	// SupportSimulationReserve marks the Reserve and Unreserve of the plugin safe to run in a scheduling simulation.
+	SupportSimulationReserve()
+}
+
 // ReservationPreBindPlugin performs special binding logic specifically for Reservation in the PreBind phase.
 // Similar to the built-in VolumeBinding plugin of kube-scheduler, it does not support Reservation,
 // and how Reservation itself uses PVC reserved resources also needs special handling.
@@ -87,6 +87,12 @@ func (e *Engine) RegisterPluginService(plugin framework.Plugin) {
 	}
 }
 
+// RegisterService registers the endpoints of the provider under the relative path of the services.
+func (e *Engine) RegisterService(name string, provider APIServiceProvider) {
+	serviceGroup := e.Engine.Group(servicesBaseRelativePath + name)
+	provider.RegisterEndpoints(serviceGroup)
+}
+
 func listRegisteredServices(e *gin.Engine) gin.HandlerFunc {
 	return func(context *gin.Context) {
 		routes := e.Routes()
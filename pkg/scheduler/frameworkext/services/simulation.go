@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	simulationsRelativePath = servicesBaseRelativePath + "simulations"
+
+	// MaxSimulationPods limits the number of pods simulated in one request.
+	MaxSimulationPods = 1000
+	// DefaultSimulationTopN is the default number of scored nodes returned for each pod.
+	DefaultSimulationTopN = 10
+)
+
+// Simulator runs the scheduling cycle of pods without assuming or binding them.
+type Simulator interface {
+	Simulate(ctx context.Context, request *SimulationRequest) (*SimulationResponse, error)
+}
+
+// SimulationRequest describes the pods to be simulated.
+// Either Pods or Template with Replicas should be specified.
+type SimulationRequest struct {
+	// SchedulerName selects the scheduling profile if the pod does not specify one.
+	SchedulerName string                  `json:"schedulerName,omitempty"`
+	Pods          []*corev1.Pod           `json:"pods,omitempty"`
+	Template      *corev1.PodTemplateSpec `json:"template,omitempty"`
+	Replicas      int                     `json:"replicas,omitempty"`
+	// TopN is the number of the highest scored nodes returned for each pod.
+	TopN int `json:"topN,omitempty"`
+}
+
+type SimulationResponse struct {
+	Results []PodSimulationResult `json:"results"`
+}
+
+type PodSimulationResult struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// NodeName is the node the pod would be scheduled to, empty if the pod is unschedulable.
+	NodeName       string            `json:"nodeName,omitempty"`
+	Message        string            `json:"message,omitempty"`
+	EvaluatedNodes int               `json:"evaluatedNodes,omitempty"`
+	FeasibleNodes  int               `json:"feasibleNodes,omitempty"`
+	Scores         []NodeScore       `json:"scores,omitempty"`
+	Rejections     []PluginRejection `json:"rejections,omitempty"`
+}
+
+type NodeScore struct {
+	Node       string        `json:"node"`
+	TotalScore int64         `json:"totalScore"`
+	Scores     []PluginScore `json:"scores,omitempty"`
+}
+
+type PluginScore struct {
+	Plugin string `json:"plugin"`
+	Score  int64  `json:"score"`
+}
+
+// PluginRejection aggregates the nodes rejected by a plugin with the same reasons.
+type PluginRejection struct {
+	Plugin  string   `json:"plugin,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+	Nodes   []string `json:"nodes,omitempty"`
+}
+
+// RegisterSimulator registers the simulation endpoint served by the simulator.
+func (e *Engine) RegisterSimulator(simulator Simulator) {
+	e.Engine.POST(simulationsRelativePath, simulate(simulator))
+}
+
+func simulate(simulator Simulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := &SimulationRequest{}
+		if err := c.ShouldBindJSON(request); err != nil {
+			ResponseErrorMessage(c, http.StatusBadRequest, "invalid simulation request: %v", err)
+			return
+		}
+		if err := ValidateSimulationRequest(request); err != nil {
+			ResponseErrorMessage(c, http.StatusBadRequest, "invalid simulation request: %v", err)
+			return
+		}
+		response, err := simulator.Simulate(c.Request.Context(), request)
+		if err != nil {
+			ResponseErrorMessage(c, http.StatusInternalServerError, "failed to simulate: %v", err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func ValidateSimulationRequest(request *SimulationRequest) error {
+	if len(request.Pods) > 0 && request.Template != nil {
+		return fmt.Errorf("pods and template are mutually exclusive")
+	}
+	if len(request.Pods) == 0 && request.Template == nil {
+		return fmt.Errorf("either pods or template must be specified")
+	}
+	if request.Template != nil && request.Replicas <= 0 {
+		return fmt.Errorf("replicas must be positive")
+	}
+	if len(request.Pods) > MaxSimulationPods || request.Replicas > MaxSimulationPods {
+		return fmt.Errorf("at most %d pods can be simulated", MaxSimulationPods)
+	}
+	for i, pod := range request.Pods {
+		if pod == nil {
+			return fmt.Errorf("pods[%d] is empty", i)
+		}
+	}
+	if request.TopN < 0 {
+		return fmt.Errorf("topN must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+const simulationStateKey = "koordinator.sh/simulation"
+
+// simulationState marks a scheduling cycle as a simulation.
+type simulationState struct {
+	// simulatedPods are the pods placed by the previous simulations of the same request.
+	simulatedPods      []*corev1.Pod
+	pluginToNodeScores []framework.NodePluginScores
+}
+
+func (s *simulationState) Clone() framework.StateData {
+	return s
+}
+
+func getSimulationState(cycleState *framework.CycleState) *simulationState {
+	if cycleState == nil {
+		return nil
+	}
+	s, _ := cycleState.Read(simulationStateKey)
+	state, _ := s.(*simulationState)
+	return state
+}
+
+// NewSimulationCycleState returns the CycleState of a scheduling simulation following the pods placed by the
+// previous simulations of the same request.
+func NewSimulationCycleState(simulatedPods []*corev1.Pod) *framework.CycleState {
+	cycleState := framework.NewCycleState()
+	cycleState.Write(framework.PodsToActivateKey, framework.NewPodsToActivate())
+	cycleState.Write(simulationStateKey, &simulationState{simulatedPods: simulatedPods})
+	return cycleState
+}
+
+// IsSimulation checks if the scheduling cycle is a scheduling simulation. The simulation runs the PreFilter, Filter,
+// PreScore and Score plugins of the live framework, so the plugins keeping state across the scheduling cycles
+// must not change it in a simulation.
+func IsSimulation(cycleState *framework.CycleState) bool {
+	return getSimulationState(cycleState) != nil
+}
+
+// GetSimulatedPods returns the pods placed by the previous simulations of the same request. They are added into the
+// NodeInfos of the snapshot, and the plugins accounting the pods in their own caches can account them by this.
+func GetSimulatedPods(cycleState *framework.CycleState) []*corev1.Pod {
+	if simState := getSimulationState(cycleState); simState != nil {
+		return simState.simulatedPods
+	}
+	return nil
+}
+
+type schedulePodFunc func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error)
+
+var _ services.Simulator = &schedulingSimulator{}
+
+// schedulingSimulator runs the scheduling algorithm (PreFilter/Filter/PreScore/Score) of the scheduler
+// for the simulated pods, but never assumes or binds them.
+// The simulation shares the snapshot with the scheduler, so it is serialized with the scheduling cycle.
+// The pods placed by the previous simulations of the same request are added into the snapshot and accounted by
+// the ElasticQuota, and the SimulationReservePlugins reserve their NUMA and device allocations until the simulation
+// completes. The Coscheduling plugin does not change the gang scheduling state in a simulation. The node index where
+// the next scheduling cycle starts searching feasible nodes is restored after the simulation.
+type schedulingSimulator struct {
+	factory     *FrameworkExtenderFactory
+	profiles    func() map[string]framework.Framework
+	schedulePod schedulePodFunc
+	// nextStartNodeIndex points to the node index of the scheduler, nil if it is unknown.
+	nextStartNodeIndex *int
+}
+
+func newSchedulingSimulator(factory *FrameworkExtenderFactory, sched *scheduler.Scheduler, schedulePod schedulePodFunc) *schedulingSimulator {
+	return &schedulingSimulator{
+		factory: factory,
+		profiles: func() map[string]framework.Framework {
+			return sched.Profiles
+		},
+		schedulePod:        schedulePod,
+		nextStartNodeIndex: nextStartNodeIndexOf(sched),
+	}
+}
+
+// nextStartNodeIndexOf returns the pointer to the unexported node index where the next scheduling cycle of the
+// scheduler starts searching feasible nodes, or nil if the scheduler does not have it.
+func nextStartNodeIndexOf(sched *scheduler.Scheduler) *int {
+	if sched == nil {
+		return nil
+	}
+	field := reflect.ValueOf(sched).Elem().FieldByName("nextStartNodeIndex")
+	if !field.IsValid() || field.Kind() != reflect.Int {
+		klog.Warningf("The scheduler has no nextStartNodeIndex, the scheduling simulation may move the node index")
+		return nil
+	}
+	return (*int)(unsafe.Pointer(field.UnsafeAddr()))
+}
+
+func (s *schedulingSimulator) Simulate(ctx context.Context, request *services.SimulationRequest) (*services.SimulationResponse, error) {
+	if err := services.ValidateSimulationRequest(request); err != nil {
+		return nil, err
+	}
+	pods := makeSimulationPods(request)
+	topN := request.TopN
+	if topN == 0 {
+		topN = services.DefaultSimulationTopN
+	}
+
+	s.factory.schedulingCycleLock.Lock()
+	defer s.factory.schedulingCycleLock.Unlock()
+	if s.nextStartNodeIndex != nil {
+		nextStartNodeIndex := *s.nextStartNodeIndex
+		defer func() {
+			*s.nextStartNodeIndex = nextStartNodeIndex
+		}()
+	}
+
+	profiles := s.profiles()
+	response := &services.SimulationResponse{
+		Results: make([]services.PodSimulationResult, 0, len(pods)),
+	}
+	var simulatedPods []*corev1.Pod
+	var unreserveFns []func()
+	defer func() {
+		for i := len(unreserveFns) - 1; i >= 0; i-- {
+			unreserveFns[i]()
+		}
+	}()
+	for _, pod := range pods {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := services.PodSimulationResult{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		}
+		fwk, ok := profiles[pod.Spec.SchedulerName]
+		if !ok {
+			result.Message = fmt.Sprintf("profile not found for scheduler name %q", pod.Spec.SchedulerName)
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		cycleState := NewSimulationCycleState(simulatedPods)
+		simState := getSimulationState(cycleState)
+
+		scheduleResult, err := s.schedulePod(ctx, fwk, cycleState, pod)
+		if err != nil {
+			result.Message = err.Error()
+			var fitErr *framework.FitError
+			if errors.As(err, &fitErr) {
+				result.EvaluatedNodes = fitErr.NumAllNodes
+				result.Rejections = convertRejections(fitErr.Diagnosis.NodeToStatusMap)
+			}
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		result.EvaluatedNodes = scheduleResult.EvaluatedNodes
+		result.FeasibleNodes = scheduleResult.FeasibleNodes
+		result.Scores = topNNodeScores(simState.pluginToNodeScores, topN)
+		if ext, ok := fwk.(*frameworkExtenderImpl); ok {
+			unreserve, status := ext.runSimulationReservePlugins(ctx, cycleState, pod, scheduleResult.SuggestedHost)
+			if !status.IsSuccess() {
+				result.Message = status.AsError().Error()
+				response.Results = append(response.Results, result)
+				continue
+			}
+			unreserveFns = append(unreserveFns, unreserve)
+		}
+		result.NodeName = scheduleResult.SuggestedHost
+		response.Results = append(response.Results, result)
+
+		simulatedPod := pod.DeepCopy()
+		simulatedPod.Spec.NodeName = scheduleResult.SuggestedHost
+		simulatedPods = append(simulatedPods, simulatedPod)
+	}
+	return response, nil
+}
+
+func makeSimulationPods(request *services.SimulationRequest) []*corev1.Pod {
+	var pods []*corev1.Pod
+	if request.Template != nil {
+		baseName := request.Template.Name
+		if baseName == "" {
+			baseName = strings.TrimSuffix(request.Template.GenerateName, "-")
+		}
+		if baseName == "" {
+			baseName = "simulation"
+		}
+		for i := 0; i < request.Replicas; i++ {
+			pod := &corev1.Pod{
+				ObjectMeta: *request.Template.ObjectMeta.DeepCopy(),
+				Spec:       *request.Template.Spec.DeepCopy(),
+			}
+			pod.Name = fmt.Sprintf("%s-%d", baseName, i)
+			pod.GenerateName = ""
+			pods = append(pods, pod)
+		}
+	} else {
+		for _, pod := range request.Pods {
+			pods = append(pods, pod.DeepCopy())
+		}
+	}
+
+	for i, pod := range pods {
+		if pod.Namespace == "" {
+			pod.Namespace = corev1.NamespaceDefault
+		}
+		if pod.Name == "" {
+			pod.Name = fmt.Sprintf("simulation-%d", i)
+		}
+		// the simulated pods must not conflict with the pods in the cache
+		pod.UID = uuid.NewUUID()
+		if pod.Spec.SchedulerName == "" {
+			pod.Spec.SchedulerName = request.SchedulerName
+		}
+		if pod.Spec.SchedulerName == "" {
+			pod.Spec.SchedulerName = corev1.DefaultSchedulerName
+		}
+		pod.Spec.NodeName = ""
+		pod.Status = corev1.PodStatus{Phase: corev1.PodPending}
+	}
+	return pods
+}
+
+func convertRejections(nodeToStatusMap framework.NodeToStatusMap) []services.PluginRejection {
+	rejections := map[string]*services.PluginRejection{}
+	for nodeName, status := range nodeToStatusMap {
+		if status.IsSuccess() {
+			continue
+		}
+		key := status.FailedPlugin() + "/" + strings.Join(status.Reasons(), ";")
+		rejection := rejections[key]
+		if rejection == nil {
+			rejection = &services.PluginRejection{
+				Plugin:  status.FailedPlugin(),
+				Reasons: status.Reasons(),
+			}
+			rejections[key] = rejection
+		}
+		rejection.Nodes = append(rejection.Nodes, nodeName)
+	}
+
+	result := make([]services.PluginRejection, 0, len(rejections))
+	for _, rejection := range rejections {
+		sort.Strings(rejection.Nodes)
+		result = append(result, *rejection)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Nodes) != len(result[j].Nodes) {
+			return len(result[i].Nodes) > len(result[j].Nodes)
+		}
+		if result[i].Plugin != result[j].Plugin {
+			return result[i].Plugin < result[j].Plugin
+		}
+		return strings.Join(result[i].Reasons, ";") < strings.Join(result[j].Reasons, ";")
+	})
+	return result
+}
+
+func topNNodeScores(pluginToNodeScores []framework.NodePluginScores, topN int) []services.NodeScore {
+	nodeScores := make([]services.NodeScore, 0, len(pluginToNodeScores))
+	for _, nodeScore := range pluginToNodeScores {
+		score := services.NodeScore{
+			Node:       nodeScore.Name,
+			TotalScore: nodeScore.TotalScore,
+		}
+		for _, pluginScore := range nodeScore.Scores {
+			score.Scores = append(score.Scores, services.PluginScore{
+				Plugin: pluginScore.Name,
+				Score:  pluginScore.Score,
+			})
+		}
+		nodeScores = append(nodeScores, score)
+	}
+	sort.Slice(nodeScores, func(i, j int) bool {
+		if nodeScores[i].TotalScore != nodeScores[j].TotalScore {
+			return nodeScores[i].TotalScore > nodeScores[j].TotalScore
+		}
+		return nodeScores[i].Node < nodeScores[j].Node
+	})
+	if len(nodeScores) > topN {
+		nodeScores = nodeScores[:topN]
+	}
+	return nodeScores
+}
+
+// runSimulationReservePlugins runs the Reserve of the SimulationReservePlugins for the pod placed by a scheduling
+// simulation on a clone of the CycleState, and returns the function running the Unreserve to release the allocations.
+func (ext *frameworkExtenderImpl) runSimulationReservePlugins(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (func(), *framework.Status) {
+	reserveState := cycleState.Clone()
+	var reserved []SimulationReservePlugin
+	unreserve := func() {
+		for i := len(reserved) - 1; i >= 0; i-- {
+			reserved[i].Unreserve(ctx, reserveState, pod, nodeName)
+		}
+	}
+	for _, pl := range ext.simulationReservePluginsEnabled {
+		reserved = append(reserved, pl)
+		if status := pl.Reserve(ctx, reserveState, pod, nodeName); !status.IsSuccess() {
+			unreserve()
+			return nil, status.WithFailedPlugin(pl.Name())
+		}
+	}
+	return unreserve, nil
+}
+
+// applySimulatedPods adds the pods placed by the previous simulations into the snapshot,
+// and invalidates the nodes to make the next scheduling cycle refresh them from the cache.
+func (ext *frameworkExtenderImpl) applySimulatedPods(pods []*corev1.Pod) {
+	nodeInfoLister := ext.SnapshotSharedLister().NodeInfos()
+	for _, pod := range pods {
+		nodeInfo, err := nodeInfoLister.Get(pod.Spec.NodeName)
+		if err != nil {
+			klog.V(4).InfoS("Failed to get simulated node", "pod", klog.KObj(pod), "node", pod.Spec.NodeName, "err", err)
+			continue
+		}
+		nodeInfo.AddPod(pod)
+		if sched := ext.Scheduler(); sched != nil {
+			if err := sched.GetCache().InvalidNodeInfo(klog.Background(), pod.Spec.NodeName); err != nil {
+				klog.ErrorS(err, "Failed to InvalidNodeInfo", "node", pod.Spec.NodeName)
+			}
+		}
+	}
+}
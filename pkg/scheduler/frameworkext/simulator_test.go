@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkfake "k8s.io/kubernetes/pkg/scheduler/framework/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulertesting "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+type testSimulationReservePlugin struct {
+	reserved   []string
+	unreserved []string
+}
+
+func (p *testSimulationReservePlugin) Name() string { return "testSimulationReservePlugin" }
+
+func (p *testSimulationReservePlugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	if !IsSimulation(cycleState) {
+		return framework.AsStatus(fmt.Errorf("not a simulation"))
+	}
+	p.reserved = append(p.reserved, pod.Name+"/"+nodeName)
+	return nil
+}
+
+func (p *testSimulationReservePlugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	p.unreserved = append(p.unreserved, pod.Name+"/"+nodeName)
+}
+
+func (p *testSimulationReservePlugin) SupportSimulationReserve() {}
+
+func TestSchedulingSimulator(t *testing.T) {
+	var nodeInfos []*framework.NodeInfo
+	for _, name := range []string{"node-1", "node-2"} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	extenderFactory, _ := NewFrameworkExtenderFactory()
+	reservePlugin := &testSimulationReservePlugin{}
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		schedulertesting.RegisterScorePlugin("T1", PluginFactoryProxy(extenderFactory, func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+			return &TestTransformer{name: "T1", index: 1}, nil
+		}), 1),
+		schedulertesting.RegisterReservePlugin("testSimulationReservePlugin", PluginFactoryProxy(extenderFactory, func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+			return reservePlugin, nil
+		})),
+	}
+	fh, err := schedulertesting.NewFramework(
+		context.TODO(),
+		registeredPlugins,
+		"koord-scheduler",
+		frameworkruntime.WithSnapshotSharedLister(fakeNodeInfoLister{NodeInfoLister: frameworkfake.NodeInfoLister(nodeInfos)}),
+	)
+	assert.NoError(t, err)
+	extender := extenderFactory.NewFrameworkExtender(fh)
+	extender.SetConfiguredPlugins(fh.ListPlugins())
+
+	// schedulePod places the pod on the node with the fewest pods, and rejects the pod requesting GPUs.
+	nextStartNodeIndex := 1
+	var accountedReservations []int
+	schedulePod := func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error) {
+		nextStartNodeIndex++
+		accountedReservations = append(accountedReservations, len(reservePlugin.reserved)-len(reservePlugin.unreserved))
+		// refresh the snapshot like the scheduler does since no pod is assumed in the cache
+		for _, nodeInfo := range nodeInfos {
+			fresh := framework.NewNodeInfo()
+			fresh.SetNode(nodeInfo.Node())
+			*nodeInfo = *fresh
+		}
+		if _, status := fwk.RunPreFilterPlugins(ctx, state, pod); !status.IsSuccess() {
+			return scheduler.ScheduleResult{}, status.AsError()
+		}
+		if _, ok := pod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"]; ok {
+			return scheduler.ScheduleResult{}, &framework.FitError{
+				Pod:         pod,
+				NumAllNodes: len(nodeInfos),
+				Diagnosis: framework.Diagnosis{
+					NodeToStatusMap: framework.NodeToStatusMap{
+						"node-1": framework.NewStatus(framework.Unschedulable, "Insufficient nvidia.com/gpu").WithFailedPlugin("DeviceShare"),
+						"node-2": framework.NewStatus(framework.Unschedulable, "Insufficient nvidia.com/gpu").WithFailedPlugin("DeviceShare"),
+					},
+					UnschedulablePlugins: sets.New[string]("DeviceShare"),
+				},
+			}
+		}
+		var nodes []*corev1.Node
+		selected := nodeInfos[0]
+		for _, nodeInfo := range nodeInfos {
+			nodes = append(nodes, nodeInfo.Node())
+			if len(nodeInfo.Pods) < len(selected.Pods) {
+				selected = nodeInfo
+			}
+		}
+		if _, status := fwk.RunScorePlugins(ctx, state, pod, nodes); !status.IsSuccess() {
+			return scheduler.ScheduleResult{}, status.AsError()
+		}
+		return scheduler.ScheduleResult{
+			SuggestedHost:  selected.Node().Name,
+			EvaluatedNodes: len(nodes),
+			FeasibleNodes:  len(nodes),
+		}, nil
+	}
+	simulator := &schedulingSimulator{
+		factory: extenderFactory,
+		profiles: func() map[string]framework.Framework {
+			return map[string]framework.Framework{"koord-scheduler": extender}
+		},
+		schedulePod:        schedulePod,
+		nextStartNodeIndex: &nextStartNodeIndex,
+	}
+
+	response, err := simulator.Simulate(context.TODO(), &services.SimulationRequest{
+		SchedulerName: "koord-scheduler",
+		Template: &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{GenerateName: "web-"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main"}},
+			},
+		},
+		Replicas: 3,
+		TopN:     1,
+	})
+	assert.NoError(t, err)
+	expectedScores := []services.NodeScore{
+		{Node: "node-1", Scores: []services.PluginScore{{Plugin: "T1"}}},
+	}
+	assert.Equal(t, []services.PodSimulationResult{
+		{Namespace: "default", Name: "web-0", NodeName: "node-1", EvaluatedNodes: 2, FeasibleNodes: 2, Scores: expectedScores},
+		{Namespace: "default", Name: "web-1", NodeName: "node-2", EvaluatedNodes: 2, FeasibleNodes: 2, Scores: expectedScores},
+		{Namespace: "default", Name: "web-2", NodeName: "node-1", EvaluatedNodes: 2, FeasibleNodes: 2, Scores: expectedScores},
+	}, response.Results)
+	// the allocations of the previous simulated pods are reserved and released after the simulation
+	assert.Equal(t, []int{0, 1, 2}, accountedReservations)
+	assert.Equal(t, []string{"web-0/node-1", "web-1/node-2", "web-2/node-1"}, reservePlugin.reserved)
+	assert.Equal(t, []string{"web-2/node-1", "web-1/node-2", "web-0/node-1"}, reservePlugin.unreserved)
+	assert.Equal(t, 1, nextStartNodeIndex)
+
+	response, err = simulator.Simulate(context.TODO(), &services.SimulationRequest{
+		Pods: []*corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "gpu"},
+				Spec: corev1.PodSpec{
+					SchedulerName: "koord-scheduler",
+					Containers: []corev1.Container{
+						{
+							Name: "main",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+							},
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "unknown-profile"},
+				Spec: corev1.PodSpec{
+					SchedulerName: "unknown",
+					Containers:    []corev1.Container{{Name: "main"}},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, response.Results, 2)
+	assert.Empty(t, response.Results[0].NodeName)
+	assert.Equal(t, 2, response.Results[0].EvaluatedNodes)
+	assert.Equal(t, []services.PluginRejection{
+		{Plugin: "DeviceShare", Reasons: []string{"Insufficient nvidia.com/gpu"}, Nodes: []string{"node-1", "node-2"}},
+	}, response.Results[0].Rejections)
+	assert.Equal(t, `profile not found for scheduler name "unknown"`, response.Results[1].Message)
+
+	_, err = simulator.Simulate(context.TODO(), &services.SimulationRequest{})
+	assert.Error(t, err)
+}
+
+func TestNextStartNodeIndexOf(t *testing.T) {
+	assert.Nil(t, nextStartNodeIndexOf(nil))
+	sched := &scheduler.Scheduler{}
+	index := nextStartNodeIndexOf(sched)
+	assert.NotNil(t, index)
+	*index = 3
+	assert.Equal(t, 3, *nextStartNodeIndexOf(sched))
+}
+
+func TestSchedulingCycleLock(t *testing.T) {
+	extenderFactory, _ := NewFrameworkExtenderFactory()
+	extenderFactory.lockSchedulingCycle()
+	assert.False(t, extenderFactory.schedulingCycleLock.TryLock())
+	extenderFactory.unlockSchedulingCycle()
+	assert.True(t, extenderFactory.schedulingCycleLock.TryLock())
+	extenderFactory.schedulingCycleLock.Unlock()
+	// unlock without holding the lock is a no-op
+	extenderFactory.unlockSchedulingCycle()
+}
@@ -37,6 +37,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedv1alpha1 "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 	schedclientset "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/generated/clientset/versioned"
 	schedinformer "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/generated/informers/externalversions/scheduling/v1alpha1"
@@ -270,6 +271,13 @@ func (ctrl *PodGroupController) syncHandler(key string) error {
 		pgCopy.Status.Succeeded = succeeded
 		pgCopy.Status.Running = running
 		pgCopy.Status.Scheduled = ctrl.pgManager.GetBoundPodNumber(util.GetId(pg.Namespace, pg.Name))
+		// the domain is kept in the annotation after the gang finishes and the scheduler releases it
+		if gangSummary, ok := ctrl.pgManager.GetGangSummary(util.GetId(pg.Namespace, pg.Name)); ok && gangSummary.TopologyDomain != nil {
+			if pgCopy.Annotations == nil {
+				pgCopy.Annotations = map[string]string{}
+			}
+			pgCopy.Annotations[extension.AnnotationGangTopologyDomain] = gangSummary.TopologyDomain.String()
+		}
 
 		if pgCopy.Status.Scheduled < pgCopy.Spec.MinMember {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupScheduling
@@ -75,6 +75,10 @@ type Manager interface {
 	GetGangSummaries() map[string]*GangSummary
 
 	GetBoundPodNumber(gangId string) int32
+	GetTopologyDomainNodes(*framework.CycleState, *corev1.Pod) (sets.Set[string], *extension.GangNetworkTopologySpec, error)
+
+	GetGangSize(gangId string) (*GangSize, bool)
+	IsElasticExtraMember(pod *corev1.Pod) bool
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -192,7 +196,7 @@ func (pgMgr *PodGroupManager) PreEnqueue(ctx context.Context, pod *corev1.Pod) (
 			util.GetId(pod.Namespace, pod.Name))
 	}
 	// resourceSatisfied means pod will directly pass the PreFilter
-	if gang.getGangMatchPolicy() == extension.GangMatchPolicyOnceSatisfied && gang.isGangOnceResourceSatisfied() {
+	if isGangSatisfied(gang) {
 		return nil
 	}
 	err = pgMgr.basicGangRequirementsCheck(gang, pod)
@@ -246,7 +250,7 @@ func (pgMgr *PodGroupManager) basicGangRequirementsCheck(gang *Gang, pod *corev1
 	return nil
 }
 
-func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, _ *framework.CycleState, pod *corev1.Pod) (err error) {
+func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) (err error) {
 	if !util.IsPodNeedGang(pod) {
 		return nil
 	}
@@ -262,13 +266,17 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, _ *framework.CycleS
 			util.GetId(pod.Namespace, pod.Name))
 	}
 	// resourceSatisfied means pod will directly pass the PreFilter
-	if gang.getGangMatchPolicy() == extension.GangMatchPolicyOnceSatisfied && gang.isGangOnceResourceSatisfied() {
+	if isGangSatisfied(gang) {
 		return nil
 	}
 	err = pgMgr.basicGangRequirementsCheck(gang, pod)
 	if err != nil {
 		return err
 	}
+	// the simulation must not start or fail the gang scheduling round
+	if frameworkext.IsSimulation(cycleState) {
+		return nil
+	}
 	gangSchedulingContext := pgMgr.holder.getCurrentGangSchedulingContext()
 	if gangSchedulingContext == nil {
 		gangSchedulingContext = &GangSchedulingContext{firstPod: pod, gangGroup: sets.New[string](gang.GangGroup...)}
@@ -294,9 +302,15 @@ func (pgMgr *PodGroupManager) PostFilter(ctx context.Context, state *framework.C
 		klog.Warningf(message)
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable, message)
 	}
-	if gang.getGangMatchPolicy() == extension.GangMatchPolicyOnceSatisfied && gang.isGangOnceResourceSatisfied() {
+	if isGangSatisfied(gang) {
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable)
 	}
+	// the gang has not reached its min-available, try to preempt the elastic members of other gangs
+	preemptResult, preemptStatus := pgMgr.preemptElasticMembers(ctx, state, pod, gang, handle, pluginName, filteredNodeStatusMap)
+	if preemptStatus.IsSuccess() {
+		klog.V(4).InfoS("Pod preempts elastic members of other gangs", "pod", klog.KObj(pod), "gang", gang.Name,
+			"nominatedNode", preemptResult.NominatedNodeName)
+	}
 
 	nodeInfos, _ := handle.SnapshotSharedLister().NodeInfos().List()
 	fitErr := &framework.FitError{
@@ -316,11 +330,18 @@ func (pgMgr *PodGroupManager) PostFilter(ctx context.Context, state *framework.C
 
 	if gang.getGangMode() == extension.GangModeStrict {
 		gang.clearWaitingGang()
+		gang.GangGroupInfo.SetTopologyDomain(nil)
 		pgMgr.rejectGangGroupById(handle, pluginName, gang.Name, message)
+		if preemptStatus.IsSuccess() {
+			return preemptResult, preemptStatus
+		}
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
 			fmt.Sprintf("Gang %q gets rejected due to pod is unschedulable", gang.Name))
 	}
 
+	if preemptStatus.IsSuccess() {
+		return preemptResult, preemptStatus
+	}
 	return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable)
 }
 
@@ -339,6 +360,10 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, pod *corev1.Pod) (time
 	}
 	// first add pod to the gang's WaitingPodsMap
 	gang.addAssumedPod(pod)
+	// the members beyond min-available of the elastic gang don't wait for others
+	if gang.isElasticSatisfied() {
+		return 0, Success
+	}
 
 	allGangGroupAssumed := true
 	gangGroup := gang.getGangGroup()
@@ -374,13 +399,21 @@ func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, state *framework.Cy
 
 	// TODO we should record failed message when current pod is the first failed pod of gang, now we just let it go, so quick fail is not supported
 
-	if !(gang.getGangMatchPolicy() == extension.GangMatchPolicyOnceSatisfied && gang.isGangOnceResourceSatisfied()) &&
-		gang.getGangMode() == extension.GangModeStrict {
+	if !isGangSatisfied(gang) && gang.getGangMode() == extension.GangModeStrict {
 		message := fmt.Sprintf("Gang %q gets rejected due to Pod %q in Unreserve", gang.Name, pod.Name)
+		// the gang group is rejected or waiting for the others timed out, select the domain again in the next round
+		gang.GangGroupInfo.SetTopologyDomain(nil)
 		pgMgr.rejectGangGroupById(handle, pluginName, gang.Name, message)
 	}
 }
 
+// isGangSatisfied returns true if the members of the gang can be scheduled like ordinary pods,
+// i.e. the gang is once resource satisfied with the once-satisfied match policy, or the elastic gang has reached min-available.
+func isGangSatisfied(gang *Gang) bool {
+	return (gang.getGangMatchPolicy() == extension.GangMatchPolicyOnceSatisfied && gang.isGangOnceResourceSatisfied()) ||
+		gang.isElasticSatisfied()
+}
+
 func (pgMgr *PodGroupManager) rejectGangGroupById(handle framework.Handle, pluginName, gangId, message string) {
 	gang := pgMgr.cache.getGangFromCacheByGangId(gangId, false)
 	if gang == nil {
@@ -421,6 +454,7 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 	}
 	// update gang in cache
 	gang.addBoundPod(pod)
+	pgMgr.releaseTopologyDomainIfBound(gang)
 }
 
 func (pgMgr *PodGroupManager) AllowGangGroup(pod *corev1.Pod, handle framework.Handle, pluginName string) {
@@ -432,6 +466,12 @@ func (pgMgr *PodGroupManager) AllowGangGroup(pod *corev1.Pod, handle framework.H
 
 	gangSlices := gang.getGangGroup()
 
+	if gangSchedulingContext := pgMgr.holder.getCurrentGangSchedulingContext(); gangSchedulingContext != nil &&
+		gangSchedulingContext.gangGroup.Has(gang.Name) && gangSchedulingContext.topologyDomain != nil {
+		// all gangs in the gang group share the GangGroupInfo
+		gang.GangGroupInfo.SetTopologyDomain(gangSchedulingContext.topologyDomain)
+	}
+
 	handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
 		podGangId := util.GetId(waitingPod.GetPod().Namespace, util.GetGangNameByPod(waitingPod.GetPod()))
 		for _, gangIdTmp := range gangSlices {
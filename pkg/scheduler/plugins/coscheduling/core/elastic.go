@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+// IsElasticExtraMember returns true if the pod is scheduled beyond min-available of its elastic gang.
+// Such members are labeled with extension.LabelGangElasticMember and can be preempted by other gangs.
+func (pgMgr *PodGroupManager) IsElasticExtraMember(pod *corev1.Pod) bool {
+	if !util.IsPodNeedGang(pod) {
+		return false
+	}
+	gang := pgMgr.GetGangByPod(pod)
+	if gang == nil {
+		return false
+	}
+	return gang.isElasticExtraMember(pod)
+}
+
+func (pgMgr *PodGroupManager) GetGangSize(gangId string) (*GangSize, bool) {
+	gang := pgMgr.cache.getGangFromCacheByGangId(gangId, false)
+	if gang == nil {
+		return nil, false
+	}
+	return gang.GetGangSize(), true
+}
+
+func (pgMgr *PodGroupManager) hasElasticMembers() bool {
+	for _, gang := range pgMgr.cache.getAllGangsFromCache() {
+		if gang.isElastic() && len(gang.GetGangSize().ElasticMembers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// preemptElasticMembers tries to make room for the pod of a gang which has not reached its min-available
+// by preempting the elastic members of other gangs.
+func (pgMgr *PodGroupManager) preemptElasticMembers(ctx context.Context, state *framework.CycleState, pod *corev1.Pod,
+	gang *Gang, handle framework.Handle, pluginName string, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if !pgMgr.hasElasticMembers() {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+	pe := preemption.Evaluator{
+		PluginName: pluginName,
+		Handler:    handle,
+		PodLister:  pgMgr.podLister,
+		State:      state,
+		Interface: &elasticPreemption{
+			handle:    handle,
+			gangGroup: sets.New[string](gang.getGangGroup()...),
+		},
+	}
+	return pe.Preempt(ctx, pod, filteredNodeStatusMap)
+}
+
+// elasticPreemption only selects the elastic members of other gang groups with no higher priority as victims.
+type elasticPreemption struct {
+	handle    framework.Handle
+	gangGroup sets.Set[string]
+}
+
+var _ preemption.Interface = &elasticPreemption{}
+
+func (p *elasticPreemption) GetOffsetAndNumCandidates(nodes int32) (int32, int32) {
+	return 0, nodes
+}
+
+func (p *elasticPreemption) CandidatesToVictimsMap(candidates []preemption.Candidate) map[string]*extenderv1.Victims {
+	m := make(map[string]*extenderv1.Victims)
+	for _, c := range candidates {
+		m[c.Name()] = c.Victims()
+	}
+	return m
+}
+
+// PodEligibleToPreemptOthers returns false if the victims preempted by the pod before are still terminating on the nominated node.
+func (p *elasticPreemption) PodEligibleToPreemptOthers(pod *corev1.Pod, nominatedNodeStatus *framework.Status) (bool, string) {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == corev1.PreemptNever {
+		return false, "not eligible due to preemptionPolicy=Never."
+	}
+	nomNodeName := pod.Status.NominatedNodeName
+	if len(nomNodeName) == 0 || nominatedNodeStatus.Code() == framework.UnschedulableAndUnresolvable {
+		return true, ""
+	}
+	nodeInfo, _ := p.handle.SnapshotSharedLister().NodeInfos().Get(nomNodeName)
+	if nodeInfo == nil {
+		return true, ""
+	}
+	for _, pi := range nodeInfo.Pods {
+		if pi.Pod.DeletionTimestamp != nil && p.canPreempt(pod, pi.Pod) {
+			return false, "not eligible due to a terminating elastic member on the nominated node."
+		}
+	}
+	return true, ""
+}
+
+// SelectVictimsOnNode removes all the elastic members can be preempted from the node, and then reprieves
+// as many of them as possible from the most important one while the pod still fits.
+func (p *elasticPreemption) SelectVictimsOnNode(
+	ctx context.Context,
+	state *framework.CycleState,
+	pod *corev1.Pod,
+	nodeInfo *framework.NodeInfo,
+	pdbs []*policy.PodDisruptionBudget,
+) ([]*corev1.Pod, int, *framework.Status) {
+	removePod := func(rpi *framework.PodInfo) error {
+		if err := nodeInfo.RemovePod(rpi.Pod); err != nil {
+			return err
+		}
+		return p.handle.RunPreFilterExtensionRemovePod(ctx, state, pod, rpi, nodeInfo).AsError()
+	}
+	addPod := func(api *framework.PodInfo) error {
+		nodeInfo.AddPodInfo(api)
+		return p.handle.RunPreFilterExtensionAddPod(ctx, state, pod, api, nodeInfo).AsError()
+	}
+
+	var potentialVictims []*framework.PodInfo
+	for _, pi := range nodeInfo.Pods {
+		if p.canPreempt(pod, pi.Pod) {
+			potentialVictims = append(potentialVictims, pi)
+		}
+	}
+	if len(potentialVictims) == 0 {
+		message := fmt.Sprintf("No elastic members found on node %v for preemptor pod %v", nodeInfo.Node().Name, pod.Name)
+		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
+	}
+	for _, pi := range potentialVictims {
+		if err := removePod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+	}
+	if status := p.handle.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo); !status.IsSuccess() {
+		return nil, 0, status
+	}
+
+	var victims []*corev1.Pod
+	sort.Slice(potentialVictims, func(i, j int) bool {
+		return schedutil.MoreImportantPod(potentialVictims[i].Pod, potentialVictims[j].Pod)
+	})
+	for _, pi := range potentialVictims {
+		if err := addPod(pi); err != nil {
+			return nil, 0, framework.AsStatus(err)
+		}
+		if status := p.handle.RunFilterPluginsWithNominatedPods(ctx, state, pod, nodeInfo); !status.IsSuccess() {
+			if err := removePod(pi); err != nil {
+				return nil, 0, framework.AsStatus(err)
+			}
+			victims = append(victims, pi.Pod)
+			klog.V(5).InfoS("Elastic member is a potential preemption victim on node", "pod", klog.KObj(pi.Pod), "node", klog.KObj(nodeInfo.Node()))
+		}
+	}
+	return victims, 0, framework.NewStatus(framework.Success)
+}
+
+func (p *elasticPreemption) canPreempt(pod, victim *corev1.Pod) bool {
+	if !extension.IsGangElasticMember(victim) || extension.IsPodNonPreemptible(victim) {
+		return false
+	}
+	victimGangId := util.GetId(victim.Namespace, util.GetGangNameByPod(victim))
+	if p.gangGroup.Has(victimGangId) {
+		return false
+	}
+	return corev1helpers.PodPriority(victim) <= corev1helpers.PodPriority(pod)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+func TestElasticGang(t *testing.T) {
+	mgr := NewManagerForTest().pgMgr
+	pg := makePg("gang", "default", 2, nil, nil)
+	pg.Annotations = map[string]string{
+		extension.AnnotationGangTotalNum: "3",
+		extension.AnnotationGangElastic:  "true",
+	}
+	mgr.cache.onPodGroupAdd(pg)
+	var pods []*corev1.Pod
+	for _, name := range []string{"pod-a", "pod-b", "pod-c"} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{v1alpha1.PodGroupLabel: "gang"},
+		}}
+		mgr.cache.onPodAdd(pod)
+		pods = append(pods, pod)
+	}
+	gang := mgr.cache.getGangFromCacheByGangId(util.GetId("default", "gang"), false)
+	assert.True(t, gang.isElastic())
+
+	// the first min-available members wait for each other
+	assert.False(t, mgr.IsElasticExtraMember(pods[0]))
+	_, status := mgr.Permit(context.TODO(), pods[0])
+	assert.Equal(t, Wait, status)
+	assert.False(t, mgr.IsElasticExtraMember(pods[1]))
+	_, status = mgr.Permit(context.TODO(), pods[1])
+	assert.Equal(t, Success, status)
+	assert.True(t, mgr.IsElasticExtraMember(pods[2]))
+	assert.False(t, isGangSatisfied(gang))
+
+	for _, pod := range pods[:2] {
+		bound := pod.DeepCopy()
+		bound.Spec.NodeName = "node-1"
+		mgr.cache.onPodUpdate(pod, bound)
+	}
+	assert.True(t, gang.isElasticSatisfied())
+	assert.True(t, isGangSatisfied(gang))
+	// the extra member doesn't wait once the gang reaches min-available
+	assert.NoError(t, mgr.PreFilter(context.TODO(), nil, pods[2]))
+	_, status = mgr.Permit(context.TODO(), pods[2])
+	assert.Equal(t, Success, status)
+
+	gangSize, ok := mgr.GetGangSize(util.GetId("default", "gang"))
+	assert.True(t, ok)
+	assert.Equal(t, &GangSize{
+		Name:          "default/gang",
+		Elastic:       true,
+		MinNumber:     2,
+		CurrentNumber: 3,
+		TargetNumber:  3,
+	}, gangSize)
+	assert.False(t, mgr.hasElasticMembers())
+
+	bound := pods[2].DeepCopy()
+	bound.Spec.NodeName = "node-2"
+	bound.Labels[extension.LabelGangElasticMember] = "true"
+	mgr.cache.onPodUpdate(pods[2], bound)
+	gangSize, _ = mgr.GetGangSize(util.GetId("default", "gang"))
+	assert.Equal(t, sets.New[string]("default/pod-c"), gangSize.ElasticMembers)
+	assert.True(t, mgr.hasElasticMembers())
+}
+
+func TestElasticPreemptionCanPreempt(t *testing.T) {
+	p := &elasticPreemption{gangGroup: sets.New[string]("default/gang-a")}
+	newPod := func(gangName string, priority int32, elastic bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod",
+				Labels:    map[string]string{v1alpha1.PodGroupLabel: gangName},
+			},
+			Spec: corev1.PodSpec{Priority: pointer.Int32(priority)},
+		}
+		if elastic {
+			pod.Labels[extension.LabelGangElasticMember] = "true"
+		}
+		return pod
+	}
+	preemptor := newPod("gang-a", 10, false)
+	assert.True(t, p.canPreempt(preemptor, newPod("gang-b", 10, true)))
+	assert.False(t, p.canPreempt(preemptor, newPod("gang-b", 10, false)))
+	assert.False(t, p.canPreempt(preemptor, newPod("gang-b", 20, true)))
+	assert.False(t, p.canPreempt(preemptor, newPod("gang-a", 10, true)))
+}
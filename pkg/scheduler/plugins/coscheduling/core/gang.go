@@ -72,6 +72,11 @@ type Gang struct {
 	// once-satisfied, once gang is satisfied, no need to consider any status pods
 	GangMatchPolicy string
 
+	// NetworkTopology constrains the topology domains the gang members are placed in
+	NetworkTopology *extension.GangNetworkTopologySpec
+	// Elastic means the gang grows towards TotalChildrenNum opportunistically once MinRequiredNumber members are bound
+	Elastic bool
+
 	GangFrom    string
 	HasGangInit bool
 
@@ -162,6 +167,14 @@ func (gang *Gang) tryInitByPodConfig(pod *v1.Pod, args *config.CoschedulingArgs)
 	}
 	gang.GangGroup = groupSlice
 	gang.GangGroupId = util.GetGangGroupId(groupSlice)
+
+	networkTopology, err := extension.GetGangNetworkTopologySpec(pod.Annotations)
+	if err != nil {
+		klog.V(4).ErrorS(err, "pod's annotation GangNetworkTopologyAnnotation illegal, gangName: %v, value: %v",
+			gang.Name, pod.Annotations[extension.AnnotationGangNetworkTopology])
+	}
+	gang.NetworkTopology = networkTopology
+	gang.Elastic = pod.Annotations[extension.AnnotationGangElastic] == "true"
 	gang.GangFrom = GangFromPodAnnotation
 
 	gang.HasGangInit = true
@@ -224,6 +237,14 @@ func (gang *Gang) tryInitByPodGroup(pg *v1alpha1.PodGroup, args *config.Coschedu
 	gang.GangGroup = groupSlice
 	gang.GangGroupId = util.GetGangGroupId(groupSlice)
 
+	networkTopology, err := extension.GetGangNetworkTopologySpec(pg.Annotations)
+	if err != nil {
+		klog.V(4).ErrorS(err, "podGroup's annotation GangNetworkTopologyAnnotation illegal, gangName: %v, value: %v",
+			gang.Name, pg.Annotations[extension.AnnotationGangNetworkTopology])
+	}
+	gang.NetworkTopology = networkTopology
+	gang.Elastic = pg.Annotations[extension.AnnotationGangElastic] == "true"
+
 	gang.GangFrom = GangFromPodGroupCrd
 
 	gang.HasGangInit = true
@@ -306,6 +327,58 @@ func (gang *Gang) getBoundPodNum() int32 {
 	return int32(len(gang.BoundChildren))
 }
 
+func (gang *Gang) isAllChildrenBound() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	for key := range gang.Children {
+		if _, ok := gang.BoundChildren[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (gang *Gang) getNetworkTopology() *extension.GangNetworkTopologySpec {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return gang.NetworkTopology
+}
+
+func (gang *Gang) isElastic() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return gang.Elastic
+}
+
+// isElasticSatisfied returns true if the gang is elastic and min-available members are bound,
+// then the rest members are scheduled like ordinary pods.
+func (gang *Gang) isElasticSatisfied() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return gang.Elastic && gang.HasGangInit && len(gang.BoundChildren) >= gang.MinRequiredNumber
+}
+
+// isElasticExtraMember returns true if the pod is scheduled beyond min-available of the elastic gang,
+// which should be called before the pod is assumed.
+func (gang *Gang) isElasticExtraMember(pod *v1.Pod) bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if !gang.Elastic {
+		return false
+	}
+	podId := util.GetId(pod.Namespace, pod.Name)
+	assumed := len(gang.WaitingForBindChildren) + len(gang.BoundChildren)
+	if _, ok := gang.WaitingForBindChildren[podId]; ok {
+		assumed--
+	}
+	return assumed >= gang.MinRequiredNumber
+}
+
 func (gang *Gang) getGangMode() string {
 	gang.lock.Lock()
 	defer gang.lock.Unlock()
@@ -82,4 +82,9 @@ type GangSchedulingContext struct {
 	firstPod             *corev1.Pod
 	failedMessage        string
 	alreadyAttemptedPods sets.Set[string]
+
+	// topologyDomain is the network topology domain selected for the gang group in this round.
+	topologyDomainSelected bool
+	topologyDomain         *TopologyDomain
+	topologyDomainErr      error
 }
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
 type GangSummary struct {
@@ -23,6 +25,25 @@ type GangSummary struct {
 	GangGroupInfo          *GangGroupInfo   `json:"gangGroupInfo"`
 	GangFrom               string           `json:"gangFrom"`
 	HasGangInit            bool             `json:"hasGangInit"`
+	TopologyDomain         *TopologyDomain  `json:"topologyDomain,omitempty"`
+	Elastic                bool             `json:"elastic,omitempty"`
+}
+
+// GangSize reports how many members of the gang are placed and how many it grows towards,
+// so that the elastic training frameworks can scale the gang with the cluster.
+type GangSize struct {
+	Name    string `json:"name"`
+	Elastic bool   `json:"elastic"`
+	// MinNumber is the min-available of the gang
+	MinNumber int `json:"minNumber"`
+	// CurrentNumber is the number of members assumed or bound
+	CurrentNumber int `json:"currentNumber"`
+	// TargetNumber is the number of members the gang grows towards
+	TargetNumber int `json:"targetNumber"`
+	// PendingNumber is the number of members waiting to be scheduled
+	PendingNumber int `json:"pendingNumber"`
+	// ElasticMembers are the bound members beyond min-available, which can be preempted by other gangs
+	ElasticMembers sets.Set[string] `json:"elasticMembers,omitempty"`
 }
 
 func (gang *Gang) GetGangSummary() *GangSummary {
@@ -51,6 +72,8 @@ func (gang *Gang) GetGangSummary() *GangSummary {
 	gangSummary.GangGroupInfo = gang.GangGroupInfo
 	gangSummary.GangFrom = gang.GangFrom
 	gangSummary.HasGangInit = gang.HasGangInit
+	gangSummary.TopologyDomain = gang.GangGroupInfo.GetTopologyDomain()
+	gangSummary.Elastic = gang.Elastic
 	gangSummary.GangGroup = append(gangSummary.GangGroup, gang.GangGroup...)
 
 	for podName := range gang.Children {
@@ -68,3 +91,26 @@ func (gang *Gang) GetGangSummary() *GangSummary {
 
 	return gangSummary
 }
+
+func (gang *Gang) GetGangSize() *GangSize {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	gangSize := &GangSize{
+		Name:          gang.Name,
+		Elastic:       gang.Elastic,
+		MinNumber:     gang.MinRequiredNumber,
+		CurrentNumber: len(gang.WaitingForBindChildren) + len(gang.BoundChildren),
+		TargetNumber:  gang.TotalChildrenNum,
+		PendingNumber: len(gang.PendingChildren),
+	}
+	for podName, pod := range gang.BoundChildren {
+		if extension.IsGangElasticMember(pod) {
+			if gangSize.ElasticMembers == nil {
+				gangSize.ElasticMembers = sets.New[string]()
+			}
+			gangSize.ElasticMembers.Insert(podName)
+		}
+	}
+	return gangSize
+}
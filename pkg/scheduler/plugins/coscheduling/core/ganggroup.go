@@ -53,6 +53,9 @@ type GangGroupInfo struct {
 		- is replaced when some memberPod of gangGroup is firstly failed during some gangGroup schedulingContext
 	*/
 	RepresentativePodKey string
+
+	// TopologyDomain is the network topology domain the gang group is placed in.
+	TopologyDomain *TopologyDomain
 }
 
 func NewGangGroupInfo(gangGroupId string, gangGroup []string) *GangGroupInfo {
@@ -161,3 +164,15 @@ func (gg *GangGroupInfo) DeleteIfRepresentative(pod *corev1.Pod, reason string)
 		klog.Infof("gangGroupInfo: DeleteIfRepresentative, pod: %v, gangGroup: %v, reason: %s", podKey, gg.GangGroupId, reason)
 	}
 }
+
+func (gg *GangGroupInfo) SetTopologyDomain(domain *TopologyDomain) {
+	gg.lock.Lock()
+	defer gg.lock.Unlock()
+	gg.TopologyDomain = domain
+}
+
+func (gg *GangGroupInfo) GetTopologyDomain() *TopologyDomain {
+	gg.lock.Lock()
+	defer gg.lock.Unlock()
+	return gg.TopologyDomain
+}
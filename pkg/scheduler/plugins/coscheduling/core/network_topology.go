@@ -0,0 +1,322 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+// TopologyDomain is the network topology domain the gang group is placed in.
+type TopologyDomain struct {
+	// Layer is the node label key of the domain.
+	Layer string `json:"layer"`
+	// Name is the node label value of the domain.
+	Name string `json:"name"`
+}
+
+func (d *TopologyDomain) String() string {
+	return d.Layer + "=" + d.Name
+}
+
+// GetTopologyDomainNodes returns the nodes of the topology domain the pod's gang group should be placed in.
+// The domain is selected when the first pod of the gang group passes PreFilter, and is kept during the scheduling
+// round. It returns nil nodes if the pod has no network topology constraint or no domain is selected.
+// In a scheduling simulation, the domain is selected without changing the gang scheduling context.
+func (pgMgr *PodGroupManager) GetTopologyDomainNodes(cycleState *framework.CycleState, pod *corev1.Pod) (sets.Set[string], *extension.GangNetworkTopologySpec, error) {
+	if !util.IsPodNeedGang(pod) {
+		return nil, nil, nil
+	}
+	gang := pgMgr.GetGangByPod(pod)
+	if gang == nil {
+		return nil, nil, nil
+	}
+	spec := gang.getNetworkTopology()
+	if spec == nil {
+		return nil, nil, nil
+	}
+
+	domain := gang.GangGroupInfo.GetTopologyDomain()
+	gangSchedulingContext := pgMgr.holder.getCurrentGangSchedulingContext()
+	if frameworkext.IsSimulation(cycleState) {
+		if gangSchedulingContext != nil && gangSchedulingContext.gangGroup.Has(gang.Name) && gangSchedulingContext.topologyDomainSelected {
+			if gangSchedulingContext.topologyDomainErr != nil {
+				return nil, spec, gangSchedulingContext.topologyDomainErr
+			}
+			domain = gangSchedulingContext.topologyDomain
+		} else if domain == nil {
+			var err error
+			if domain, err = pgMgr.selectTopologyDomain(gang, spec); err != nil {
+				return nil, spec, err
+			}
+		}
+	} else if gangSchedulingContext != nil && gangSchedulingContext.gangGroup.Has(gang.Name) {
+		if !gangSchedulingContext.topologyDomainSelected {
+			gangSchedulingContext.topologyDomain, gangSchedulingContext.topologyDomainErr = pgMgr.selectTopologyDomain(gang, spec)
+			gangSchedulingContext.topologyDomainSelected = true
+			if gangSchedulingContext.topologyDomain != nil {
+				klog.V(4).InfoS("select topology domain for gang group", "gangGroup", gang.getGangGroup(), "domain", gangSchedulingContext.topologyDomain)
+			}
+		}
+		domain = gangSchedulingContext.topologyDomain
+		if gangSchedulingContext.topologyDomainErr != nil {
+			return nil, spec, gangSchedulingContext.topologyDomainErr
+		}
+	}
+	if domain == nil {
+		return nil, spec, nil
+	}
+
+	nodeInfos, err := pgMgr.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, spec, err
+	}
+	nodes := sets.New[string]()
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node != nil && node.Labels[domain.Layer] == domain.Name {
+			nodes.Insert(node.Name)
+		}
+	}
+	return nodes, spec, nil
+}
+
+// selectTopologyDomain chooses the domain of the innermost layer that can hold all the members of the gang group
+// not placed yet. If some members are already placed, only the domain holding them is a candidate.
+// Among the candidates in the same layer, the one with the least free CPU is chosen to pack the gangs.
+// A member is only placed on the nodes it fits by resources, taints and required node affinity.
+func (pgMgr *PodGroupManager) selectTopologyDomain(gang *Gang, spec *extension.GangNetworkTopologySpec) (*TopologyDomain, error) {
+	nodeInfos, err := pgMgr.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+	pods, placedNodes := pgMgr.getGangGroupPodsToPlace(gang)
+	podsToPlace := make([]*podToPlace, 0, len(pods))
+	for _, pod := range pods {
+		podsToPlace = append(podsToPlace, newPodToPlace(pod))
+	}
+	// place the larger pods first
+	sort.SliceStable(podsToPlace, func(i, j int) bool {
+		ri, rj := podsToPlace[i].requests, podsToPlace[j].requests
+		if ri.Cpu().MilliValue() != rj.Cpu().MilliValue() {
+			return ri.Cpu().MilliValue() > rj.Cpu().MilliValue()
+		}
+		return ri.Memory().Value() > rj.Memory().Value()
+	})
+	for _, layer := range spec.Layers {
+		domains := map[string][]*framework.NodeInfo{}
+		for _, nodeInfo := range nodeInfos {
+			node := nodeInfo.Node()
+			if node == nil {
+				continue
+			}
+			if name, ok := node.Labels[layer]; ok {
+				domains[name] = append(domains[name], nodeInfo)
+			}
+		}
+
+		var selected string
+		var selectedFreeMilliCPU int64
+		for name, domainNodes := range domains {
+			if !containsAllNodes(domainNodes, placedNodes) {
+				continue
+			}
+			freeMilliCPU, fit := fitInNodes(podsToPlace, domainNodes)
+			if !fit {
+				continue
+			}
+			if selected == "" || freeMilliCPU < selectedFreeMilliCPU || (freeMilliCPU == selectedFreeMilliCPU && name < selected) {
+				selected = name
+				selectedFreeMilliCPU = freeMilliCPU
+			}
+		}
+		if selected != "" {
+			return &TopologyDomain{Layer: layer, Name: selected}, nil
+		}
+	}
+	return nil, fmt.Errorf("no topology domain of %v can hold the %d pods of gang group %v", spec.Layers, len(pods), gang.getGangGroup())
+}
+
+// releaseTopologyDomainIfBound clears the domain of the gang group once all the members are bound,
+// the members created later select the domain again around the placed ones.
+func (pgMgr *PodGroupManager) releaseTopologyDomainIfBound(gang *Gang) {
+	if gang.GangGroupInfo.GetTopologyDomain() == nil {
+		return
+	}
+	for _, gangID := range gang.getGangGroup() {
+		groupGang := pgMgr.cache.getGangFromCacheByGangId(gangID, false)
+		if groupGang != nil && !groupGang.isAllChildrenBound() {
+			return
+		}
+	}
+	gang.GangGroupInfo.SetTopologyDomain(nil)
+}
+
+// getGangGroupPodsToPlace returns the members of the gang group that are not assumed or bound,
+// and the nodes the other members are placed on.
+func (pgMgr *PodGroupManager) getGangGroupPodsToPlace(gang *Gang) ([]*corev1.Pod, sets.Set[string]) {
+	var pods []*corev1.Pod
+	placedNodes := sets.New[string]()
+	for _, gangID := range gang.getGangGroup() {
+		groupGang := pgMgr.cache.getGangFromCacheByGangId(gangID, false)
+		if groupGang == nil {
+			continue
+		}
+		groupGang.lock.Lock()
+		for key, pod := range groupGang.Children {
+			if boundPod, ok := groupGang.BoundChildren[key]; ok {
+				placedNodes.Insert(boundPod.Spec.NodeName)
+				continue
+			}
+			if waitingPod, ok := groupGang.WaitingForBindChildren[key]; ok {
+				placedNodes.Insert(waitingPod.Spec.NodeName)
+				continue
+			}
+			pods = append(pods, pod)
+		}
+		groupGang.lock.Unlock()
+	}
+	placedNodes.Delete("")
+	return pods, placedNodes
+}
+
+func containsAllNodes(nodeInfos []*framework.NodeInfo, nodes sets.Set[string]) bool {
+	if nodes.Len() == 0 {
+		return true
+	}
+	count := 0
+	for _, nodeInfo := range nodeInfos {
+		if nodes.Has(nodeInfo.Node().Name) {
+			count++
+		}
+	}
+	return count == nodes.Len()
+}
+
+type podToPlace struct {
+	pod              *corev1.Pod
+	requests         corev1.ResourceList
+	requiredAffinity nodeaffinity.RequiredNodeAffinity
+}
+
+func newPodToPlace(pod *corev1.Pod) *podToPlace {
+	return &podToPlace{
+		pod:              pod,
+		requests:         resourceapi.PodRequests(pod, resourceapi.PodResourcesOptions{}),
+		requiredAffinity: nodeaffinity.GetRequiredNodeAffinity(pod),
+	}
+}
+
+// fitsNode checks the unschedulable node, the taints and the required node affinity like the framework Filter does.
+func (p *podToPlace) fitsNode(node *corev1.Node) bool {
+	if node.Spec.Unschedulable && !corev1helpers.TolerationsTolerateTaint(p.pod.Spec.Tolerations, &corev1.Taint{
+		Key:    corev1.TaintNodeUnschedulable,
+		Effect: corev1.TaintEffectNoSchedule,
+	}) {
+		return false
+	}
+	if _, untolerated := corev1helpers.FindMatchingUntoleratedTaint(node.Spec.Taints, p.pod.Spec.Tolerations, func(t *corev1.Taint) bool {
+		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+	}); untolerated {
+		return false
+	}
+	match, _ := p.requiredAffinity.Match(node)
+	return match
+}
+
+// fitInNodes places the pods into the nodes by first fit, and returns the free CPU left in the nodes.
+func fitInNodes(pods []*podToPlace, nodeInfos []*framework.NodeInfo) (int64, bool) {
+	free := make([]*framework.Resource, 0, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		r := nodeInfo.Allocatable.Clone()
+		r.MilliCPU -= nodeInfo.Requested.MilliCPU
+		r.Memory -= nodeInfo.Requested.Memory
+		r.EphemeralStorage -= nodeInfo.Requested.EphemeralStorage
+		r.AllowedPodNumber -= len(nodeInfo.Pods)
+		for name, quantity := range nodeInfo.Requested.ScalarResources {
+			r.SetScalar(name, r.ScalarResources[name]-quantity)
+		}
+		free = append(free, r)
+	}
+
+	for _, p := range pods {
+		placed := false
+		for i, r := range free {
+			if !p.fitsNode(nodeInfos[i].Node()) || !fitsIn(p.requests, r) {
+				continue
+			}
+			r.MilliCPU -= p.requests.Cpu().MilliValue()
+			r.Memory -= p.requests.Memory().Value()
+			r.EphemeralStorage -= p.requests.StorageEphemeral().Value()
+			r.AllowedPodNumber--
+			for name, quantity := range p.requests {
+				if name != corev1.ResourceCPU && name != corev1.ResourceMemory && name != corev1.ResourceEphemeralStorage {
+					r.SetScalar(name, r.ScalarResources[name]-quantity.Value())
+				}
+			}
+			placed = true
+			break
+		}
+		if !placed {
+			return 0, false
+		}
+	}
+
+	var freeMilliCPU int64
+	for _, r := range free {
+		freeMilliCPU += r.MilliCPU
+	}
+	return freeMilliCPU, true
+}
+
+func fitsIn(request corev1.ResourceList, free *framework.Resource) bool {
+	if free.AllowedPodNumber < 1 {
+		return false
+	}
+	for name, quantity := range request {
+		var available int64
+		switch name {
+		case corev1.ResourceCPU:
+			if quantity.MilliValue() > free.MilliCPU {
+				return false
+			}
+			continue
+		case corev1.ResourceMemory:
+			available = free.Memory
+		case corev1.ResourceEphemeralStorage:
+			available = free.EphemeralStorage
+		default:
+			available = free.ScalarResources[name]
+		}
+		if quantity.Value() > available {
+			return false
+		}
+	}
+	return true
+}
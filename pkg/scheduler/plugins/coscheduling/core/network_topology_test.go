@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkfake "k8s.io/kubernetes/pkg/scheduler/framework/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+type fakeSnapshotHandle struct {
+	framework.Handle
+	nodeInfos frameworkfake.NodeInfoLister
+}
+
+func (h *fakeSnapshotHandle) SnapshotSharedLister() framework.SharedLister {
+	return h
+}
+
+func (h *fakeSnapshotHandle) NodeInfos() framework.NodeInfoLister {
+	return h.nodeInfos
+}
+
+func (h *fakeSnapshotHandle) StorageInfos() framework.StorageInfoLister {
+	return nil
+}
+
+func makeTopologyNode(name, spine, block string, cpu string, pods ...*corev1.Pod) *framework.NodeInfo {
+	nodeInfo := framework.NewNodeInfo(pods...)
+	nodeInfo.SetNode(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"network.topology/spine": spine,
+				"network.topology/block": block,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	})
+	return nodeInfo
+}
+
+func makeTopologyPod(name, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFitInNodes(t *testing.T) {
+	nodeInfos := []*framework.NodeInfo{
+		makeTopologyNode("node-1", "s1", "b1", "8", makeTopologyPod("running", "4")),
+		makeTopologyNode("node-2", "s1", "b1", "8"),
+	}
+	podsToPlace := func(cpus ...string) []*podToPlace {
+		var pods []*podToPlace
+		for i, cpu := range cpus {
+			pods = append(pods, newPodToPlace(makeTopologyPod(fmt.Sprintf("pod-%d", i), cpu)))
+		}
+		return pods
+	}
+
+	free, fit := fitInNodes(podsToPlace("6", "4", "2"), nodeInfos)
+	assert.True(t, fit)
+	assert.Equal(t, int64(0), free)
+
+	_, fit = fitInNodes(podsToPlace("6", "6"), nodeInfos)
+	assert.False(t, fit)
+
+	gpuPod := makeTopologyPod("gpu", "1")
+	gpuPod.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse("1")
+	_, fit = fitInNodes([]*podToPlace{newPodToPlace(gpuPod)}, nodeInfos)
+	assert.False(t, fit)
+
+	// node-2 is tainted, the pods only fit node-1
+	taintedNode := nodeInfos[1].Node().DeepCopy()
+	taintedNode.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "infer", Effect: corev1.TaintEffectNoSchedule}}
+	nodeInfos[1].SetNode(taintedNode)
+	_, fit = fitInNodes(podsToPlace("4", "2"), nodeInfos)
+	assert.False(t, fit)
+	tolerating := podsToPlace("4", "2")
+	for _, p := range tolerating {
+		p.pod.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+	}
+	_, fit = fitInNodes(tolerating, nodeInfos)
+	assert.True(t, fit)
+
+	// the pod requires node-2 by node affinity
+	affinityPod := makeTopologyPod("affinity", "2")
+	affinityPod.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+	affinityPod.Spec.NodeSelector = map[string]string{"kubernetes.io/hostname": "node-2"}
+	_, fit = fitInNodes([]*podToPlace{newPodToPlace(affinityPod)}, nodeInfos)
+	assert.False(t, fit)
+	affinityNode := nodeInfos[1].Node().DeepCopy()
+	affinityNode.Labels["kubernetes.io/hostname"] = "node-2"
+	nodeInfos[1].SetNode(affinityNode)
+	_, fit = fitInNodes([]*podToPlace{newPodToPlace(affinityPod)}, nodeInfos)
+	assert.True(t, fit)
+
+	// the unschedulable node is skipped
+	unschedulableNode := nodeInfos[1].Node().DeepCopy()
+	unschedulableNode.Spec.Unschedulable = true
+	nodeInfos[1].SetNode(unschedulableNode)
+	_, fit = fitInNodes([]*podToPlace{newPodToPlace(affinityPod)}, nodeInfos)
+	assert.False(t, fit)
+}
+
+func TestSelectTopologyDomain(t *testing.T) {
+	tests := []struct {
+		name       string
+		members    int
+		placedOn   string
+		wantDomain *TopologyDomain
+		wantErr    bool
+		wantNodes  sets.Set[string]
+	}{
+		{
+			name:       "pack into the fullest block",
+			members:    2,
+			wantDomain: &TopologyDomain{Layer: "network.topology/block", Name: "b2"},
+			wantNodes:  sets.New[string]("node-3", "node-4"),
+		},
+		{
+			name:       "fall back to spine if no block can hold the gang",
+			members:    4,
+			wantDomain: &TopologyDomain{Layer: "network.topology/spine", Name: "s1"},
+			wantNodes:  sets.New[string]("node-1", "node-2", "node-3", "node-4"),
+		},
+		{
+			name:       "follow the placed members",
+			members:    2,
+			placedOn:   "node-1",
+			wantDomain: &TopologyDomain{Layer: "network.topology/block", Name: "b1"},
+			wantNodes:  sets.New[string]("node-1", "node-2"),
+		},
+		{
+			name:    "no domain can hold the gang",
+			members: 8,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfos := frameworkfake.NodeInfoLister{
+				makeTopologyNode("node-1", "s1", "b1", "8"),
+				makeTopologyNode("node-2", "s1", "b1", "8"),
+				makeTopologyNode("node-3", "s1", "b2", "8", makeTopologyPod("running", "2")),
+				makeTopologyNode("node-4", "s1", "b2", "8"),
+			}
+			mgr := NewManagerForTest().pgMgr
+			mgr.handle = &fakeSnapshotHandle{nodeInfos: nodeInfos}
+
+			pg := makePg("gang", "default", int32(tt.members), nil, nil)
+			pg.Annotations = map[string]string{
+				extension.AnnotationGangNetworkTopology: `{"layers":["network.topology/block","network.topology/spine"]}`,
+			}
+			mgr.cache.onPodGroupAdd(pg)
+			var pods []*corev1.Pod
+			for i := 0; i < tt.members; i++ {
+				pod := makeTopologyPod("member-"+string(rune('a'+i)), "6")
+				pod.Labels = map[string]string{v1alpha1.PodGroupLabel: "gang"}
+				mgr.cache.onPodAdd(pod)
+				pods = append(pods, pod)
+			}
+			gang := mgr.cache.getGangFromCacheByGangId(util.GetId("default", "gang"), false)
+			assert.NotNil(t, gang)
+			assert.NotNil(t, gang.getNetworkTopology())
+			if tt.placedOn != "" {
+				placed := pods[0].DeepCopy()
+				placed.Spec.NodeName = tt.placedOn
+				gang.addAssumedPod(placed)
+			}
+
+			domain, err := mgr.selectTopologyDomain(gang, gang.getNetworkTopology())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDomain, domain)
+
+			gang.GangGroupInfo.SetTopologyDomain(domain)
+			nodes, spec, err := mgr.GetTopologyDomainNodes(framework.NewCycleState(), pods[len(pods)-1])
+			assert.NoError(t, err)
+			assert.Equal(t, extension.GangNetworkTopologyModeRequired, spec.Mode)
+			assert.Equal(t, tt.wantNodes, nodes)
+		})
+	}
+}
+
+func TestReleaseTopologyDomain(t *testing.T) {
+	newGang := func(mgr *PodGroupManager, name string) (*Gang, []*corev1.Pod) {
+		pg := makePg(name, "default", 2, nil, nil)
+		pg.Annotations = map[string]string{
+			extension.AnnotationGangNetworkTopology: `{"layers":["network.topology/block"]}`,
+		}
+		mgr.cache.onPodGroupAdd(pg)
+		var pods []*corev1.Pod
+		for i := 0; i < 2; i++ {
+			pod := makeTopologyPod(fmt.Sprintf("%s-%d", name, i), "1")
+			pod.Labels = map[string]string{v1alpha1.PodGroupLabel: name}
+			mgr.cache.onPodAdd(pod)
+			pods = append(pods, pod)
+		}
+		gang := mgr.cache.getGangFromCacheByGangId(util.GetId("default", name), false)
+		assert.NotNil(t, gang)
+		gang.GangGroupInfo.SetTopologyDomain(&TopologyDomain{Layer: "network.topology/block", Name: "b1"})
+		return gang, pods
+	}
+	mgr := NewManagerForTest().pgMgr
+
+	// the domain is released once all the members are bound
+	gang, pods := newGang(mgr, "bound")
+	mgr.PostBind(context.TODO(), pods[0], "node-1")
+	assert.NotNil(t, gang.GangGroupInfo.GetTopologyDomain())
+	mgr.PostBind(context.TODO(), pods[1], "node-1")
+	assert.Nil(t, gang.GangGroupInfo.GetTopologyDomain())
+
+	// the domain is released when the gang is rejected in Unreserve, e.g. waiting for the others timed out
+	gang, pods = newGang(mgr, "rejected")
+	gang.addAssumedPod(pods[0])
+	mgr.Unreserve(context.TODO(), framework.NewCycleState(), pods[0], "node-1", nil, Name)
+	assert.Nil(t, gang.GangGroupInfo.GetTopologyDomain())
+}
@@ -57,14 +57,19 @@ var _ frameworkext.NextPodPlugin = &Coscheduling{}
 var _ frameworkext.PreFilterTransformer = &Coscheduling{}
 var _ framework.PreFilterPlugin = &Coscheduling{}
 var _ framework.PostFilterPlugin = &Coscheduling{}
+var _ framework.ScorePlugin = &Coscheduling{}
 var _ framework.PermitPlugin = &Coscheduling{}
 var _ framework.ReservePlugin = &Coscheduling{}
+var _ framework.PreBindPlugin = &Coscheduling{}
 var _ framework.PostBindPlugin = &Coscheduling{}
 var _ framework.EnqueueExtensions = &Coscheduling{}
 
 const (
 	// Name is the name of the plugin used in Registry and configurations.
 	Name = core.Name
+
+	topologyDomainStateKey = Name + "/topologyDomain"
+	elasticMemberStateKey  = Name + "/elasticMember"
 )
 
 // New initializes and returns a new Coscheduling plugin.
@@ -178,10 +183,56 @@ func (cs *Coscheduling) BeforePreFilter(ctx context.Context, state *framework.Cy
 	return nil, false, framework.NewStatus(framework.Success, "")
 }
 
+// PreFilter restricts the pod to the network topology domain selected for its gang group if the constraint is required.
 func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	nodes, spec, err := cs.pgMgr.GetTopologyDomainNodes(state, pod)
+	if spec == nil {
+		return nil, nil
+	}
+	required := spec.Mode == extension.GangNetworkTopologyModeRequired
+	if err != nil {
+		if required {
+			return nil, framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+		klog.V(4).InfoS("no preferred topology domain for gang", "pod", klog.KObj(pod), "err", err)
+		return nil, nil
+	}
+	if nodes == nil {
+		return nil, nil
+	}
+	if required {
+		return &framework.PreFilterResult{NodeNames: nodes}, nil
+	}
+	state.Write(topologyDomainStateKey, &topologyDomainState{nodes: nodes})
 	return nil, nil
 }
 
+type topologyDomainState struct {
+	nodes sets.Set[string]
+}
+
+func (s *topologyDomainState) Clone() framework.StateData {
+	return s
+}
+
+// Score prefers the nodes in the network topology domain selected for the gang group if the constraint is preferred.
+// The plugin should be weighted over the other score plugins but the Reservation, so the members are only placed
+// out of the domain when none of its nodes is feasible.
+func (cs *Coscheduling) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := state.Read(topologyDomainStateKey)
+	if err != nil {
+		return 0, nil
+	}
+	if domainState, ok := s.(*topologyDomainState); ok && domainState.nodes.Has(nodeName) {
+		return framework.MaxNodeScore, nil
+	}
+	return 0, nil
+}
+
+func (cs *Coscheduling) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
 func (cs *Coscheduling) AfterPreFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, preFilterResult *framework.PreFilterResult) *framework.Status {
 	return nil
 }
@@ -223,7 +274,29 @@ func (cs *Coscheduling) Permit(ctx context.Context, state *framework.CycleState,
 }
 
 // Reserve is the functions invoked by the framework at "reserve" extension point.
+// It records whether the pod is scheduled beyond min-available of its elastic gang.
 func (cs *Coscheduling) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if cs.pgMgr.IsElasticExtraMember(pod) {
+		state.Write(elasticMemberStateKey, &elasticMemberState{})
+	}
+	return nil
+}
+
+type elasticMemberState struct{}
+
+func (s *elasticMemberState) Clone() framework.StateData {
+	return s
+}
+
+// PreBind labels the elastic members beyond min-available, which can be preempted by the gangs not reaching min-available.
+func (cs *Coscheduling) PreBind(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if _, err := state.Read(elasticMemberStateKey); err != nil {
+		return nil
+	}
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[extension.LabelGangElasticMember] = "true"
 	return nil
 }
 
@@ -23,6 +23,16 @@ func (cs *Coscheduling) RegisterEndpoints(group *gin.RouterGroup) {
 		}
 		c.JSON(http.StatusOK, gangSummary)
 	})
+	group.GET("/gang/:namespace/:name/size", func(c *gin.Context) {
+		gangNamespace := c.Param("namespace")
+		gangName := c.Param("name")
+		gangSize, exist := cs.pgMgr.GetGangSize(util.GetId(gangNamespace, gangName))
+		if !exist {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "cannot find gang %s/%s", gangNamespace, gangName)
+			return
+		}
+		c.JSON(http.StatusOK, gangSize)
+	})
 	group.GET("/gangs", func(c *gin.Context) {
 		allGangSummaries := cs.pgMgr.GetGangSummaries()
 		c.JSON(http.StatusOK, allGangSummaries)
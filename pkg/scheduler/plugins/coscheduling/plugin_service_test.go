@@ -128,4 +128,26 @@ func TestEndpointsQueryGangInfo(t *testing.T) {
 		gangMarshalMap["ganga_ns/ganga"].GangGroupInfo = nil
 		assert.Equal(t, &gangExpected, gangMarshalMap["ganga_ns/ganga"])
 	}
+	{
+		engine := gin.Default()
+		gp.RegisterEndpoints(engine.Group("/"))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/gang/ganga_ns/ganga/size", nil)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		gangSize := &core.GangSize{}
+		err = json.NewDecoder(w.Result().Body).Decode(gangSize)
+		assert.NoError(t, err)
+		assert.Equal(t, &core.GangSize{
+			Name:          "ganga_ns/ganga",
+			MinNumber:     2,
+			TargetNumber:  2,
+			PendingNumber: 1,
+		}, gangSize)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/gang/ganga_ns/not-found/size", nil)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	}
 }
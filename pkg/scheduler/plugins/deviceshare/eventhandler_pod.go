@@ -45,6 +45,16 @@ func registerPodEventHandler(deviceCache *nodeDeviceCache, sharedInformerFactory
 	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), koordSharedInformerFactory, reservationInformer.Informer(), reservationEventHandler)
 }
 
+func registerJobMemberEventHandler(jobMemberCache *jobMemberCache, sharedInformerFactory informers.SharedInformerFactory) {
+	podInformer := sharedInformerFactory.Core().V1().Pods().Informer()
+	eventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    jobMemberCache.onPodAdd,
+		UpdateFunc: jobMemberCache.onPodUpdate,
+		DeleteFunc: jobMemberCache.onPodDelete,
+	}
+	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), sharedInformerFactory, podInformer, eventHandler)
+}
+
 func (n *nodeDeviceCache) onPodAdd(obj interface{}) {
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
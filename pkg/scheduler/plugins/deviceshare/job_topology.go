@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	coschedulingutil "github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// newJobJointAllocate returns the joint allocation required by the members of a multi-node job,
+// which places the GPUs and RDMA VFs of a member under the same PCIe switch.
+func newJobJointAllocate() *apiext.DeviceJointAllocate {
+	return &apiext.DeviceJointAllocate{
+		DeviceTypes:   []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU, schedulingv1alpha1.RDMA},
+		RequiredScope: apiext.SamePCIeDeviceJointAllocateScope,
+	}
+}
+
+// withJobTopologyHints makes the members of a multi-node job allocate RDMA VFs rather than PFs.
+func withJobTopologyHints(hints apiext.DeviceAllocateHints) apiext.DeviceAllocateHints {
+	if hints == nil {
+		hints = apiext.DeviceAllocateHints{}
+	}
+	hint := hints[schedulingv1alpha1.RDMA]
+	if hint == nil {
+		hint = &apiext.DeviceHint{}
+		hints[schedulingv1alpha1.RDMA] = hint
+	}
+	if hint.VFSelector == nil {
+		hint.VFSelector = &metav1.LabelSelector{}
+	}
+	return hints
+}
+
+func getDeviceJobName(pod *corev1.Pod, jobTopology *apiext.DeviceJobTopology) string {
+	if jobTopology.JobName != "" {
+		return jobTopology.JobName
+	}
+	return coschedulingutil.GetGangNameByPod(pod)
+}
+
+// getJobFabricDomains returns the fabric domains of the nodes which the other members of the job are placed on,
+// including the members assumed or waiting in Permit.
+func (p *Plugin) getJobFabricDomains(pod *corev1.Pod, jobTopology *apiext.DeviceJobTopology) sets.Set[string] {
+	jobName := getDeviceJobName(pod, jobTopology)
+	if jobName == "" || jobTopology.FabricDomainLabel == "" {
+		return nil
+	}
+	nodeInfos := p.handle.SnapshotSharedLister().NodeInfos()
+	domains := sets.New[string]()
+	for _, nodeName := range p.jobMemberCache.getMemberNodes(getJobKey(pod.Namespace, jobName), pod.UID) {
+		nodeInfo, err := nodeInfos.Get(nodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+		if domain := nodeInfo.Node().Labels[jobTopology.FabricDomainLabel]; domain != "" {
+			domains.Insert(domain)
+		}
+	}
+	if domains.Len() == 0 {
+		return nil
+	}
+	return domains
+}
+
+func getJobKey(namespace, jobName string) string {
+	return namespace + "/" + jobName
+}
+
+// jobMemberCache indexes the nodes of the placed members by the job, so that the fabric domains of a job
+// are resolved without listing the pods.
+type jobMemberCache struct {
+	lock sync.RWMutex
+	// members stores the node of each placed member, the key is the job key.
+	members map[string]map[types.UID]string
+	// podJobs stores the job key of each placed member.
+	podJobs map[types.UID]string
+}
+
+func newJobMemberCache() *jobMemberCache {
+	return &jobMemberCache{
+		members: map[string]map[types.UID]string{},
+		podJobs: map[types.UID]string{},
+	}
+}
+
+func (c *jobMemberCache) assignPod(jobKey string, podUID types.UID, nodeName string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deletePodLocked(podUID)
+	nodes := c.members[jobKey]
+	if nodes == nil {
+		nodes = map[types.UID]string{}
+		c.members[jobKey] = nodes
+	}
+	nodes[podUID] = nodeName
+	c.podJobs[podUID] = jobKey
+}
+
+func (c *jobMemberCache) deletePod(podUID types.UID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.deletePodLocked(podUID)
+}
+
+func (c *jobMemberCache) deletePodLocked(podUID types.UID) {
+	jobKey, ok := c.podJobs[podUID]
+	if !ok {
+		return
+	}
+	delete(c.podJobs, podUID)
+	delete(c.members[jobKey], podUID)
+	if len(c.members[jobKey]) == 0 {
+		delete(c.members, jobKey)
+	}
+}
+
+func (c *jobMemberCache) getMemberNodes(jobKey string, excludedPod types.UID) []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var nodeNames []string
+	for podUID, nodeName := range c.members[jobKey] {
+		if podUID != excludedPod {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	return nodeNames
+}
+
+func (c *jobMemberCache) updatePod(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" {
+		// the assumed members are added in Reserve
+		return
+	}
+	if util.IsPodTerminated(pod) {
+		c.deletePod(pod.UID)
+		return
+	}
+	jobTopology, err := apiext.GetDeviceJobTopology(pod.Annotations)
+	if err != nil || jobTopology == nil {
+		return
+	}
+	if jobName := getDeviceJobName(pod, jobTopology); jobName != "" {
+		c.assignPod(getJobKey(pod.Namespace, jobName), pod.UID, pod.Spec.NodeName)
+	}
+}
+
+func (c *jobMemberCache) onPodAdd(obj interface{}) {
+	if pod, ok := obj.(*corev1.Pod); ok {
+		c.updatePod(pod)
+	}
+}
+
+func (c *jobMemberCache) onPodUpdate(oldObj, newObj interface{}) {
+	if pod, ok := newObj.(*corev1.Pod); ok {
+		c.updatePod(pod)
+	}
+}
+
+func (c *jobMemberCache) onPodDelete(obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = t.Obj.(*corev1.Pod)
+	}
+	if pod != nil {
+		c.deletePod(pod.UID)
+	}
+}
+
+// scoreFabricDomain averages the device score with the fabric domain score,
+// which is the max score if the node is in the same fabric domain as the placed members of the job.
+func scoreFabricDomain(state *preFilterState, node *corev1.Node, score int64) int64 {
+	if state.jobTopology == nil || state.jobFabricDomains.Len() == 0 || node == nil {
+		return score
+	}
+	var domainScore int64
+	if state.jobFabricDomains.Has(node.Labels[state.jobTopology.FabricDomainLabel]) {
+		domainScore = framework.MaxNodeScore
+	}
+	return (score + domainScore) / 2
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestParsePodDeviceShareExtensionsWithJobTopology(t *testing.T) {
+	podRequests := map[schedulingv1alpha1.DeviceType]corev1.ResourceList{
+		schedulingv1alpha1.GPU: {
+			apiext.ResourceGPUCore:        resource.MustParse("100"),
+			apiext.ResourceGPUMemoryRatio: resource.MustParse("100"),
+		},
+		schedulingv1alpha1.RDMA: {
+			apiext.ResourceRDMA: resource.MustParse("1"),
+		},
+	}
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		wantJointAllocate *apiext.DeviceJointAllocate
+	}{
+		{
+			name: "default joint allocation",
+			annotations: map[string]string{
+				apiext.AnnotationDeviceJobTopology: `{"jobName":"job-1"}`,
+			},
+			wantJointAllocate: &apiext.DeviceJointAllocate{
+				DeviceTypes:   []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU, schedulingv1alpha1.RDMA},
+				RequiredScope: apiext.SamePCIeDeviceJointAllocateScope,
+			},
+		},
+		{
+			name: "explicit joint allocation",
+			annotations: map[string]string{
+				apiext.AnnotationDeviceJobTopology:   `{"jobName":"job-1"}`,
+				apiext.AnnotationDeviceJointAllocate: `{"deviceTypes":["gpu"]}`,
+			},
+			wantJointAllocate: &apiext.DeviceJointAllocate{
+				DeviceTypes: []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			state := &preFilterState{}
+			assert.NoError(t, parsePodDeviceShareExtensions(pod, podRequests, state))
+			assert.Equal(t, &apiext.DeviceJobTopology{JobName: "job-1"}, state.jobTopology)
+			assert.Equal(t, tt.wantJointAllocate, state.jointAllocate)
+			assert.True(t, mustAllocateVF(state.hints[schedulingv1alpha1.RDMA]))
+		})
+	}
+}
+
+func TestGetJobFabricDomains(t *testing.T) {
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"fabric": "domain-a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"fabric": "domain-b"}}},
+	}
+	jobTopology := `{"jobName":"job-1","fabricDomainLabel":"fabric"}`
+	newPod := func(name, nodeName, topology string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				UID:       types.UID(name),
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+		if topology != "" {
+			pod.Annotations = map[string]string{apiext.AnnotationDeviceJobTopology: topology}
+		}
+		return pod
+	}
+	suit := newPluginTestSuit(t, nodes)
+	for _, pod := range []*corev1.Pod{
+		newPod("member-1", "node-1", jobTopology),
+		newPod("member-2", "", jobTopology),
+		newPod("other-job", "node-2", `{"jobName":"job-2","fabricDomainLabel":"fabric"}`),
+		newPod("no-job", "node-2", ""),
+	} {
+		_, err := suit.ClientSet().CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	pl, err := suit.proxyNew(getDefaultArgs(), suit.Framework)
+	assert.NoError(t, err)
+	suit.Framework.SharedInformerFactory().Start(nil)
+	suit.Framework.SharedInformerFactory().WaitForCacheSync(nil)
+
+	pod := newPod("member-3", "", jobTopology)
+	topology, err := apiext.GetDeviceJobTopology(pod.Annotations)
+	assert.NoError(t, err)
+	domains := pl.(*Plugin).getJobFabricDomains(pod, topology)
+	assert.Equal(t, sets.New[string]("domain-a"), domains)
+
+	// the assumed members are recorded in Reserve before they are bound
+	pl.(*Plugin).jobMemberCache.assignPod(getJobKey("default", "job-1"), "member-2", "node-2")
+	assert.Equal(t, sets.New[string]("domain-a", "domain-b"), pl.(*Plugin).getJobFabricDomains(pod, topology))
+	pl.(*Plugin).jobMemberCache.deletePod("member-2")
+	assert.Equal(t, sets.New[string]("domain-a"), pl.(*Plugin).getJobFabricDomains(pod, topology))
+	// the pod itself is not counted as a member
+	pl.(*Plugin).jobMemberCache.assignPod(getJobKey("default", "job-1"), pod.UID, "node-2")
+	assert.Equal(t, sets.New[string]("domain-a"), pl.(*Plugin).getJobFabricDomains(pod, topology))
+
+	state := &preFilterState{jobTopology: topology, jobFabricDomains: domains}
+	assert.Equal(t, int64(80), scoreFabricDomain(state, nodes[0], 60))
+	assert.Equal(t, int64(30), scoreFabricDomain(state, nodes[1], 60))
+	assert.Equal(t, int64(60), scoreFabricDomain(&preFilterState{jobTopology: topology}, nodes[1], 60))
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
@@ -68,12 +69,14 @@ var (
 	_ frameworkext.ReservationScorePlugin     = &Plugin{}
 	_ frameworkext.ReservationScoreExtensions = &Plugin{}
 	_ frameworkext.ReservationPreBindPlugin   = &Plugin{}
+	_ frameworkext.SimulationReservePlugin    = &Plugin{}
 )
 
 type Plugin struct {
 	disableDeviceNUMATopologyAlignment bool
 	handle                             frameworkext.ExtendedHandle
 	nodeDeviceCache                    *nodeDeviceCache
+	jobMemberCache                     *jobMemberCache
 	scorer                             *resourceAllocationScorer
 }
 
@@ -84,6 +87,8 @@ type preFilterState struct {
 	hintSelectors                     map[schedulingv1alpha1.DeviceType][2]labels.Selector
 	hasSelectors                      bool
 	jointAllocate                     *apiext.DeviceJointAllocate
+	jobTopology                       *apiext.DeviceJobTopology
+	jobFabricDomains                  sets.Set[string]
 	primaryDeviceType                 schedulingv1alpha1.DeviceType
 	podFitsSecondaryDeviceWellPlanned bool
 	gpuRequirements                   *GPURequirements
@@ -114,6 +119,8 @@ func (s *preFilterState) Clone() framework.StateData {
 		gpuRequirements:                   s.gpuRequirements,
 		hintSelectors:                     s.hintSelectors,
 		jointAllocate:                     s.jointAllocate,
+		jobTopology:                       s.jobTopology,
+		jobFabricDomains:                  s.jobFabricDomains,
 		primaryDeviceType:                 s.primaryDeviceType,
 		podFitsSecondaryDeviceWellPlanned: s.podFitsSecondaryDeviceWellPlanned,
 		allocationResult:                  s.allocationResult,
@@ -177,6 +184,9 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	if !status.IsSuccess() {
 		return nil, status
 	}
+	if !state.skip && state.jobTopology != nil {
+		state.jobFabricDomains = p.getJobFabricDomains(pod, state.jobTopology)
+	}
 	cycleState.Write(stateKey, state)
 	if state.skip {
 		return nil, framework.NewStatus(framework.Skip)
@@ -482,9 +492,17 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 	}
 	nodeDeviceInfo.updateCacheUsed(result, pod, true)
 	state.allocationResult = result
+	if state.jobTopology != nil {
+		if jobName := getDeviceJobName(pod, state.jobTopology); jobName != "" {
+			p.jobMemberCache.assignPod(getJobKey(pod.Namespace, jobName), pod.UID, nodeName)
+		}
+	}
 	return nil
 }
 
+// SupportSimulationReserve implements SimulationReservePlugin since the Reserve only allocates in the plugin cache.
+func (p *Plugin) SupportSimulationReserve() {}
+
 func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
 	state, status := getPreFilterState(cycleState)
 	if !status.IsSuccess() {
@@ -504,6 +522,9 @@ func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState
 
 	nodeDeviceInfo.updateCacheUsed(state.allocationResult, pod, false)
 	state.allocationResult = nil
+	if state.jobTopology != nil {
+		p.jobMemberCache.deletePod(pod.UID)
+	}
 }
 
 func (p *Plugin) ResizePod(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
@@ -642,10 +663,13 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	registerDeviceEventHandler(deviceCache, extendedHandle.KoordinatorSharedInformerFactory())
 	registerPodEventHandler(deviceCache, handle.SharedInformerFactory(), extendedHandle.KoordinatorSharedInformerFactory())
 	go deviceCache.gcNodeDevice(context.TODO(), handle.SharedInformerFactory(), defaultGCPeriod)
+	jobMemberCache := newJobMemberCache()
+	registerJobMemberEventHandler(jobMemberCache, handle.SharedInformerFactory())
 
 	return &Plugin{
 		handle:                             extendedHandle,
 		nodeDeviceCache:                    deviceCache,
+		jobMemberCache:                     jobMemberCache,
 		scorer:                             scorePlugin(args),
 		disableDeviceNUMATopologyAlignment: args.DisableDeviceNUMATopologyAlignment,
 	}, nil
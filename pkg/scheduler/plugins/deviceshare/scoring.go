@@ -84,7 +84,7 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 	if reservationInfo != nil {
 		score, status := p.scoreWithNominatedReservation(allocator, state, restoreState, nodeName, pod, preemptible, reservationInfo)
 		if status.IsSuccess() {
-			return score, nil
+			return scoreFabricDomain(state, nodeInfo.Node(), score), nil
 		}
 		klog.ErrorS(status.AsError(), "Failed to scoreWithNominatedReservation of DeviceShare",
 			"pod", klog.KObj(pod), "reservation", klog.KObj(reservationInfo), "node", nodeName)
@@ -96,7 +96,7 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 		klog.ErrorS(status.AsError(), "Failed to score of DeviceShare", "pod", klog.KObj(pod), "node", nodeName)
 		return 0, status
 	}
-	return score, nil
+	return scoreFabricDomain(state, nodeInfo.Node(), score), nil
 }
 
 func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
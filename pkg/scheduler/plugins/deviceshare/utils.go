@@ -361,6 +361,14 @@ func parsePodDeviceShareExtensions(pod *corev1.Pod, podRequests map[schedulingv1
 		return fmt.Errorf("invalid DeviceAllocateHint annotation, err: %s", err.Error())
 	}
 
+	jobTopology, err := apiext.GetDeviceJobTopology(pod.Annotations)
+	if err != nil {
+		return fmt.Errorf("invalid DeviceJobTopology annotation, err: %s", err.Error())
+	}
+	if jobTopology != nil {
+		hints = withJobTopologyHints(hints)
+	}
+
 	hintSelectors, err := newHintSelectors(hints)
 	if err != nil {
 		return err
@@ -370,6 +378,9 @@ func parsePodDeviceShareExtensions(pod *corev1.Pod, podRequests map[schedulingv1
 	if err != nil {
 		return fmt.Errorf("invalid DeviceJointAllocate annotation, err: %s", err.Error())
 	}
+	if jointAllocate == nil && jobTopology != nil {
+		jointAllocate = newJobJointAllocate()
+	}
 
 	if jointAllocate != nil {
 		var deviceTypes []schedulingv1alpha1.DeviceType
@@ -395,6 +406,7 @@ func parsePodDeviceShareExtensions(pod *corev1.Pod, podRequests map[schedulingv1
 		}
 	}
 	state.jointAllocate = jointAllocate
+	state.jobTopology = jobTopology
 	return nil
 }
 
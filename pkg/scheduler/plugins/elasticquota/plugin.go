@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	v1 "k8s.io/client-go/listers/core/v1"
@@ -91,6 +92,15 @@ type Plugin struct {
 	// quotaToTreeMap store the relationship of quota and quota tree
 	// the key is the quota name, the value is the tree id
 	quotaToTreeMap map[string]string
+
+	fairShareLock sync.RWMutex
+	// fairShares store the dominant share of all quotas, which is used to sort the pending pods.
+	// the key is the quota name
+	fairShares map[string]float64
+	// queueSortKeys store the quota of the pending pods when they are enqueued.
+	// the key is the pod uid
+	queueSortKeyLock sync.Mutex
+	queueSortKeys    map[types.UID]*queueSortKey
 }
 
 var (
@@ -147,6 +157,7 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		nodeLister:                     handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
 		groupQuotaManagersForQuotaTree: make(map[string]*core.GroupQuotaManager),
 		quotaToTreeMap:                 make(map[string]string),
+		queueSortKeys:                  make(map[types.UID]*queueSortKey),
 	}
 	elasticQuota.groupQuotaManager = core.NewGroupQuotaManager("", pluginArgs.SystemQuotaGroupMax,
 		pluginArgs.DefaultQuotaGroupMax)
@@ -191,6 +202,8 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	}
 
 	elasticQuota.migrateDefaultQuotaGroupsPod()
+	elasticQuota.refreshFairShares()
+	go wait.Until(elasticQuota.refreshFairShares, RefreshFairShareCycle, ctx.Done())
 
 	return elasticQuota, nil
 }
@@ -239,6 +252,7 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 		return nil, framework.NewStatus(framework.Error, fmt.Sprintf("Could not find the specified ElasticQuota"))
 	}
 	state := g.snapshotPostFilterState(quotaInfo, cycleState)
+	g.addSimulatedPodsUsed(cycleState, state, quotaName, treeID)
 
 	podRequest := core.PodRequests(pod)
 	podRequest = quotav1.Mask(podRequest, quotav1.ResourceNames(quotaInfo.CalculateInfo.Max))
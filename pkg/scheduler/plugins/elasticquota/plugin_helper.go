@@ -33,6 +33,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulerv1alpha1 "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
 )
 
@@ -258,6 +259,23 @@ func (g *Plugin) snapshotPostFilterState(quotaInfo *core.QuotaInfo, state *frame
 	return postFilterState
 }
 
+// addSimulatedPodsUsed accounts the pods of the quota placed by the previous simulations of the same request into the
+// used of the PostFilterState, since they are never reserved in the quota manager.
+func (g *Plugin) addSimulatedPodsUsed(cycleState *framework.CycleState, postFilterState *PostFilterState, quotaName, treeID string) {
+	for _, pod := range frameworkext.GetSimulatedPods(cycleState) {
+		podQuotaName, podTreeID := g.getPodAssociateQuotaNameAndTreeID(pod)
+		if podQuotaName != quotaName || podTreeID != treeID {
+			continue
+		}
+		podRequest := core.PodRequests(pod)
+		podRequest = quotav1.Mask(podRequest, quotav1.ResourceNames(postFilterState.quotaInfo.CalculateInfo.Max))
+		postFilterState.used = quotav1.Add(postFilterState.used, podRequest)
+		if extension.IsPodNonPreemptible(pod) {
+			postFilterState.nonPreemptibleUsed = quotav1.Add(postFilterState.nonPreemptibleUsed, podRequest)
+		}
+	}
+}
+
 func (g *Plugin) skipPostFilterState(state *framework.CycleState) {
 	postFilterState := &PostFilterState{
 		skip: true,
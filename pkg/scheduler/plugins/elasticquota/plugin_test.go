@@ -714,6 +714,56 @@ func TestPlugin_PreFilter(t *testing.T) {
 	}
 }
 
+func TestPlugin_PreFilterWithSimulatedPods(t *testing.T) {
+	tests := []struct {
+		name           string
+		simulatedPods  []*corev1.Pod
+		expectedStatus *framework.Status
+	}{
+		{
+			name:           "no simulated pods",
+			expectedStatus: framework.NewStatus(framework.Success, ""),
+		},
+		{
+			name: "simulated pods within quota",
+			simulatedPods: []*corev1.Pod{
+				MakePod("t1-ns1", "pod1").Container(MakeResourceList().CPU(1).Mem(2).Obj()).Obj(),
+			},
+			expectedStatus: framework.NewStatus(framework.Success, ""),
+		},
+		{
+			name: "simulated pods exhaust quota",
+			simulatedPods: []*corev1.Pod{
+				MakePod("t1-ns1", "pod1").Container(MakeResourceList().CPU(1).Mem(2).Obj()).Obj(),
+				MakePod("t1-ns1", "pod2").Container(MakeResourceList().CPU(1).Mem(2).Obj()).Obj(),
+			},
+			expectedStatus: framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("Insufficient quotas, "+
+					"quotaName: %v, runtime: %v, used: %v, pod's request: %v, exceedDimensions: [cpu]",
+					extension.DefaultQuotaName, printResourceList(MakeResourceList().CPU(2).Mem(20).Obj()),
+					printResourceList(MakeResourceList().CPU(2).Mem(4).Obj()), printResourceList(MakeResourceList().CPU(1).Mem(2).Obj()))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suit := newPluginTestSuit(t, nil)
+			p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+			assert.Nil(t, err)
+			gp := p.(*Plugin)
+			gp.pluginArgs.EnableRuntimeQuota = true
+			qi := gp.groupQuotaManager.GetQuotaInfoByName(extension.DefaultQuotaName)
+			qi.Lock()
+			qi.CalculateInfo.Max = MakeResourceList().CPU(10).Mem(20).Obj()
+			qi.CalculateInfo.Runtime = MakeResourceList().CPU(2).Mem(20).Obj()
+			qi.UnLock()
+			pod := MakePod("t1-ns1", "pod3").Container(MakeResourceList().CPU(1).Mem(2).Obj()).Obj()
+			state := frameworkext.NewSimulationCycleState(tt.simulatedPods)
+			_, status := gp.PreFilter(context.TODO(), state, pod)
+			assert.Equal(t, tt.expectedStatus, status)
+		})
+	}
+}
+
 func TestPlugin_PreFilter_CheckParent(t *testing.T) {
 	test := []struct {
 		name           string
@@ -51,6 +51,9 @@ func (g *Plugin) OnPodUpdate(oldObj, newObj interface{}) {
 	if oldPod.ResourceVersion == newPod.ResourceVersion {
 		return
 	}
+	if newPod.Spec.NodeName != "" {
+		g.deleteQueueSortKey(newPod)
+	}
 
 	oldQuotaName, oldTree := g.getPodAssociateQuotaNameAndTreeID(oldPod)
 	newQuotaName, newTree := g.getPodAssociateQuotaNameAndTreeID(newPod)
@@ -115,6 +118,7 @@ func (g *Plugin) OnPodDelete(obj interface{}) {
 }
 
 func (g *Plugin) handlePodDelete(pod *corev1.Pod) {
+	g.deleteQueueSortKey(pod)
 	quotaName, treeID := g.getPodAssociateQuotaNameAndTreeID(pod)
 	if quotaName == "" {
 		return
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+const (
+	RefreshFairShareCycle = 1 * time.Second
+)
+
+var _ framework.QueueSortPlugin = &Plugin{}
+
+// Less orders the pending pods to share the cluster fairly across quotas.
+// 1. The pod with higher priority goes first.
+// 2. The pod whose quota has the lower dominant share, i.e. the max ratio of used to runtime over all the resources,
+// goes first. A quota with larger runtime is weighted to run more pods before it is penalized.
+// 3. The quotas with the same share are ordered by name, so the pods of a quota are not interleaved with others.
+// 4. Otherwise, pods are ordered by the time they are enqueued, so the pods of the same quota keep FIFO.
+//
+// The members of a gang are in the same quota, so they are ordered together, and once the first member is popped,
+// the rest are dequeued by the Coscheduling NextPod.
+// NOTE: the quota of a pod is fixed when the pod is enqueued, and the share is taken per quota from the last refresh,
+// so all the pods of a quota are compared with the same share. The plugin is not the default queueSort plugin,
+// enable it in place of PrioritySort in the scheduler config to opt in.
+func (g *Plugin) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
+	p1 := corev1helpers.PodPriority(podInfo1.Pod)
+	p2 := corev1helpers.PodPriority(podInfo2.Pod)
+	if p1 != p2 {
+		return p1 > p2
+	}
+
+	quotaName1, quotaName2 := g.getQueueSortKey(podInfo1).quotaName, g.getQueueSortKey(podInfo2).quotaName
+	if quotaName1 != quotaName2 {
+		share1, share2 := g.getFairShare(quotaName1), g.getFairShare(quotaName2)
+		if share1 != share2 {
+			return share1 < share2
+		}
+		return quotaName1 < quotaName2
+	}
+
+	if !podInfo1.Timestamp.Equal(podInfo2.Timestamp) {
+		return podInfo1.Timestamp.Before(podInfo2.Timestamp)
+	}
+	// keep the members of a gang adjacent if they are enqueued at the same time
+	return getGangID(podInfo1.Pod) < getGangID(podInfo2.Pod)
+}
+
+// queueSortKey is the quota of a queued pod when it is enqueued.
+type queueSortKey struct {
+	podInfo   *framework.QueuedPodInfo
+	timestamp time.Time
+	quotaName string
+}
+
+func (g *Plugin) getQueueSortKey(podInfo *framework.QueuedPodInfo) *queueSortKey {
+	g.queueSortKeyLock.Lock()
+	defer g.queueSortKeyLock.Unlock()
+	uid := podInfo.Pod.UID
+	if key := g.queueSortKeys[uid]; key != nil && key.podInfo == podInfo && key.timestamp.Equal(podInfo.Timestamp) {
+		return key
+	}
+	quotaName, _ := g.getPodAssociateQuotaNameAndTreeID(podInfo.Pod)
+	key := &queueSortKey{
+		podInfo:   podInfo,
+		timestamp: podInfo.Timestamp,
+		quotaName: quotaName,
+	}
+	g.queueSortKeys[uid] = key
+	return key
+}
+
+// deleteQueueSortKey forgets the pod which is deleted or leaves the scheduling queue after bound.
+func (g *Plugin) deleteQueueSortKey(pod *corev1.Pod) {
+	g.queueSortKeyLock.Lock()
+	defer g.queueSortKeyLock.Unlock()
+	delete(g.queueSortKeys, pod.UID)
+}
+
+func (g *Plugin) getFairShare(quotaName string) float64 {
+	g.fairShareLock.RLock()
+	defer g.fairShareLock.RUnlock()
+	return g.fairShares[quotaName]
+}
+
+// refreshFairShares calculates the dominant share of all the quotas of all the quota trees.
+// The runtime of a quota is refreshed when its pods are scheduled, so it is not refreshed here.
+func (g *Plugin) refreshFairShares() {
+	managers := append([]*core.GroupQuotaManager{g.groupQuotaManager}, g.ListGroupQuotaManagersForQuotaTree()...)
+	fairShares := map[string]float64{}
+	for _, mgr := range managers {
+		for quotaName := range mgr.GetAllQuotaNames() {
+			quotaInfo := mgr.GetQuotaInfoByName(quotaName)
+			if quotaInfo == nil {
+				continue
+			}
+			fairShares[quotaName] = dominantShare(quotaInfo.GetUsed(), g.getQuotaInfoUsedLimit(quotaInfo))
+		}
+	}
+
+	g.fairShareLock.Lock()
+	g.fairShares = fairShares
+	g.fairShareLock.Unlock()
+	klog.V(5).InfoS("refreshed fair shares of quotas", "count", len(fairShares))
+}
+
+// dominantShare returns the max ratio of used to limit over all the used resources.
+// The resources not limited are ignored, and a resource used beyond a zero limit makes the share infinite.
+func dominantShare(used, limit corev1.ResourceList) float64 {
+	var share float64
+	for resourceName, quantity := range used {
+		limitQuantity, ok := limit[resourceName]
+		if !ok || quantity.IsZero() {
+			continue
+		}
+		if limitQuantity.MilliValue() <= 0 {
+			return math.Inf(1)
+		}
+		if s := float64(quantity.MilliValue()) / float64(limitQuantity.MilliValue()); s > share {
+			share = s
+		}
+	}
+	return share
+}
+
+func getGangID(pod *corev1.Pod) string {
+	gangName := util.GetGangNameByPod(pod)
+	if gangName == "" {
+		return ""
+	}
+	return util.GetId(pod.Namespace, gangName)
+}
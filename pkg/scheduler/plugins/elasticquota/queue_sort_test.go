@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestPlugin_Less(t *testing.T) {
+	suit := newPluginTestSuitWithPod(t, nil, nil)
+	suit.elasticQuotaArgs.EnableRuntimeQuota = false
+	p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+	assert.Nil(t, err)
+	plugin := p.(*Plugin)
+	plugin.addQuota("test1", extension.RootQuotaName, 100, 100, 0, 0, 0, 0, false, "", "")
+	plugin.addQuota("test2", extension.RootQuotaName, 100, 100, 0, 0, 0, 0, false, "", "")
+	plugin.addQuota("test3", extension.RootQuotaName, 100, 100, 0, 0, 0, 0, false, "", "")
+	for _, pod := range []*corev1.Pod{
+		defaultCreatePodWithQuotaName("running-1", "test1", 10, 20, 10),
+		defaultCreatePodWithQuotaName("running-2", "test1", 10, 20, 10),
+		defaultCreatePodWithQuotaName("running-3", "test2", 10, 10, 30),
+	} {
+		plugin.OnPodAdd(pod)
+	}
+	plugin.refreshFairShares()
+	assert.Equal(t, 0.4, plugin.getFairShare("test1"))
+	assert.Equal(t, 0.3, plugin.getFairShare("test2"))
+
+	now := time.Now()
+	newPodInfo := func(name, quotaName string, priority int32, timestamp time.Time) *framework.QueuedPodInfo {
+		pod := defaultCreatePodWithQuotaName(name, quotaName, priority, 1, 1)
+		pod.Spec.NodeName = ""
+		return &framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}, Timestamp: timestamp}
+	}
+	tests := []struct {
+		name     string
+		podInfo1 *framework.QueuedPodInfo
+		podInfo2 *framework.QueuedPodInfo
+		want     bool
+	}{
+		{
+			name:     "higher priority first",
+			podInfo1: newPodInfo("pod-1", "test1", 100, now.Add(time.Second)),
+			podInfo2: newPodInfo("pod-2", "test2", 10, now),
+			want:     true,
+		},
+		{
+			name:     "lower share first",
+			podInfo1: newPodInfo("pod-1", "test1", 10, now),
+			podInfo2: newPodInfo("pod-2", "test2", 10, now.Add(time.Second)),
+			want:     false,
+		},
+		{
+			name:     "FIFO in the same quota",
+			podInfo1: newPodInfo("pod-1", "test1", 10, now),
+			podInfo2: newPodInfo("pod-2", "test1", 10, now.Add(time.Second)),
+			want:     true,
+		},
+		{
+			name:     "quota name for the same share",
+			podInfo1: newPodInfo("pod-1", "not-exist", 10, now.Add(time.Second)),
+			podInfo2: newPodInfo("pod-2", "test3", 10, now),
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plugin.Less(tt.podInfo1, tt.podInfo2))
+			assert.Equal(t, !tt.want, plugin.Less(tt.podInfo2, tt.podInfo1))
+		})
+	}
+}
+
+func TestPlugin_LessFollowsQuotaShare(t *testing.T) {
+	suit := newPluginTestSuitWithPod(t, nil, nil)
+	suit.elasticQuotaArgs.EnableRuntimeQuota = false
+	p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+	assert.Nil(t, err)
+	plugin := p.(*Plugin)
+	plugin.addQuota("test1", extension.RootQuotaName, 100, 100, 0, 0, 0, 0, false, "", "")
+	plugin.addQuota("test2", extension.RootQuotaName, 100, 100, 0, 0, 0, 0, false, "", "")
+	plugin.OnPodAdd(defaultCreatePodWithQuotaName("running-1", "test1", 10, 20, 10))
+	plugin.refreshFairShares()
+
+	now := time.Now()
+	newPodInfo := func(name, quotaName string, timestamp time.Time) *framework.QueuedPodInfo {
+		pod := defaultCreatePodWithQuotaName(name, quotaName, 10, 1, 1)
+		pod.Spec.NodeName = ""
+		return &framework.QueuedPodInfo{PodInfo: &framework.PodInfo{Pod: pod}, Timestamp: timestamp}
+	}
+	podInfo1 := newPodInfo("pod-1", "test1", now)
+	podInfo2 := newPodInfo("pod-2", "test2", now.Add(time.Second))
+	podInfo3 := newPodInfo("pod-3", "test1", now.Add(2*time.Second))
+	assert.False(t, plugin.Less(podInfo1, podInfo2))
+	assert.False(t, plugin.Less(podInfo3, podInfo2))
+	assert.True(t, plugin.Less(podInfo1, podInfo3))
+
+	// all the queued pods of a quota follow the refreshed share together
+	plugin.OnPodAdd(defaultCreatePodWithQuotaName("running-2", "test2", 10, 50, 50))
+	plugin.refreshFairShares()
+	assert.True(t, plugin.Less(podInfo1, podInfo2))
+	assert.True(t, plugin.Less(podInfo3, podInfo2))
+	assert.True(t, plugin.Less(podInfo1, podInfo3))
+
+	plugin.OnPodDelete(podInfo1.Pod)
+	plugin.OnPodDelete(podInfo2.Pod)
+	plugin.OnPodDelete(podInfo3.Pod)
+	assert.Empty(t, plugin.queueSortKeys)
+}
+
+func TestDominantShare(t *testing.T) {
+	assert.Equal(t, 0.0, dominantShare(nil, createResourceList(10, 10)))
+	assert.Equal(t, 0.5, dominantShare(createResourceList(5, 2), createResourceList(10, 10)))
+	assert.Equal(t, 0.5, dominantShare(corev1.ResourceList{
+		corev1.ResourceCPU:  *resource.NewQuantity(5, resource.DecimalSI),
+		corev1.ResourcePods: *resource.NewQuantity(100, resource.DecimalSI),
+	}, createResourceList(10, 10)))
+	assert.Equal(t, math.Inf(1), dominantShare(createResourceList(5, 2), createResourceList(10, 0)))
+}
@@ -37,6 +37,7 @@ import (
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
@@ -70,6 +71,7 @@ var (
 	_ frameworkext.ReservationRestorePlugin    = &Plugin{}
 	_ frameworkext.ReservationFilterPlugin     = &Plugin{}
 	_ frameworkext.ReservationPreBindPlugin    = &Plugin{}
+	_ frameworkext.SimulationReservePlugin     = &Plugin{}
 	_ topologymanager.NUMATopologyHintProvider = &Plugin{}
 )
 
@@ -139,7 +141,7 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 		options.resourceManager = NewResourceManager(handle, defaultNUMAAllocateStrategy, options.topologyOptionsManager)
 	}
 
-	nrtInformerFactory, err := initNRTInformerFactory(handle)
+	nrtInformerFactory, err := frameworkexthelper.GetNRTInformerFactory(handle)
 	if err != nil {
 		return nil, err
 	}
@@ -566,6 +568,9 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 	return nil
 }
 
+// SupportSimulationReserve implements SimulationReservePlugin since the Reserve only allocates in the plugin cache.
+func (p *Plugin) SupportSimulationReserve() {}
+
 func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
 	state, status := getPreFilterState(cycleState)
 	if !status.IsSuccess() {
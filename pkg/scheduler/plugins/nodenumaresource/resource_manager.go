@@ -30,6 +30,7 @@ import (
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -269,9 +270,9 @@ func tryBestToDistributeEvenly(requests corev1.ResourceList, totalAvailable map[
 	for resourceName := range resourceNamesByNUMA {
 		sortedNUMANodes := make([]int, len(numaNodes))
 		copy(sortedNUMANodes, numaNodes)
-		sort.Slice(sortedNUMANodes, func(i, j int) bool {
-			iAvailableOfResource := totalAvailable[i][corev1.ResourceName(resourceName)]
-			return (&iAvailableOfResource).Cmp(totalAvailable[j][corev1.ResourceName(resourceName)]) < 0
+		sort.SliceStable(sortedNUMANodes, func(i, j int) bool {
+			iAvailableOfResource := totalAvailable[sortedNUMANodes[i]][corev1.ResourceName(resourceName)]
+			return (&iAvailableOfResource).Cmp(totalAvailable[sortedNUMANodes[j]][corev1.ResourceName(resourceName)]) < 0
 		})
 		sortedNUMANodeByResource[corev1.ResourceName(resourceName)] = sortedNUMANodes
 	}
@@ -314,6 +315,15 @@ func tryBestToDistributeEvenly(requests corev1.ResourceList, totalAvailable map[
 }
 
 func splitQuantity(resourceName corev1.ResourceName, quantity resource.Quantity, numaNodeCount int, options *ResourceOptions) resource.Quantity {
+	if v1helper.IsHugePageResourceName(resourceName) {
+		// hugepages can only be allocated in whole pages on each NUMA node
+		pageSize, err := v1helper.HugePageSizeFromResourceName(resourceName)
+		if err == nil && pageSize.Value() > 0 {
+			numOfPages := quantity.Value() / pageSize.Value()
+			numOfPagesPerNUMA := numOfPages / int64(numaNodeCount)
+			return *resource.NewQuantity(numOfPagesPerNUMA*pageSize.Value(), quantity.Format)
+		}
+	}
 	if resourceName != corev1.ResourceCPU {
 		return *resource.NewQuantity(quantity.Value()/int64(numaNodeCount), quantity.Format)
 	}
@@ -1276,6 +1276,104 @@ func TestAllocateDistributeEvenly(t *testing.T) {
 	}
 }
 
+func Test_tryBestToDistributeEvenlyWithHugePages(t *testing.T) {
+	hugepages1Gi := corev1.ResourceName(corev1.ResourceHugePagesPrefix + "1Gi")
+	hugepages2Mi := corev1.ResourceName(corev1.ResourceHugePagesPrefix + "2Mi")
+	tests := []struct {
+		name           string
+		requests       corev1.ResourceList
+		totalAvailable map[int]corev1.ResourceList
+		want           []NUMANodeResource
+		wantReasons    []string
+	}{
+		{
+			name: "split hugepages in whole pages",
+			requests: corev1.ResourceList{
+				hugepages1Gi: resource.MustParse("3Gi"),
+				hugepages2Mi: resource.MustParse("10Mi"),
+			},
+			totalAvailable: map[int]corev1.ResourceList{
+				0: {hugepages1Gi: resource.MustParse("4Gi"), hugepages2Mi: resource.MustParse("1Gi")},
+				1: {hugepages1Gi: resource.MustParse("4Gi"), hugepages2Mi: resource.MustParse("1Gi")},
+			},
+			want: []NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("1Gi"), hugepages2Mi: resource.MustParse("4Mi")}},
+				{Node: 1, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("2Gi"), hugepages2Mi: resource.MustParse("6Mi")}},
+			},
+		},
+		{
+			name: "allocate from the NUMA node with less free hugepages first",
+			requests: corev1.ResourceList{
+				hugepages1Gi: resource.MustParse("4Gi"),
+			},
+			totalAvailable: map[int]corev1.ResourceList{
+				0: {hugepages1Gi: resource.MustParse("4Gi")},
+				1: {hugepages1Gi: resource.MustParse("1Gi")},
+			},
+			want: []NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("3Gi")}},
+				{Node: 1, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("1Gi")}},
+			},
+		},
+		{
+			name: "insufficient hugepages",
+			requests: corev1.ResourceList{
+				hugepages1Gi: resource.MustParse("6Gi"),
+			},
+			totalAvailable: map[int]corev1.ResourceList{
+				0: {hugepages1Gi: resource.MustParse("4Gi")},
+				1: {hugepages1Gi: resource.MustParse("1Gi")},
+			},
+			want: []NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("4Gi")}},
+				{Node: 1, Resources: corev1.ResourceList{hugepages1Gi: resource.MustParse("1Gi")}},
+			},
+			wantReasons: []string{"Insufficient NUMA hugepages-1Gi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, _ := bitmask.NewBitMask(0, 1)
+			options := &ResourceOptions{
+				hint: topologymanager.NUMATopologyHint{NUMANodeAffinity: mask},
+			}
+			got, reasons := tryBestToDistributeEvenly(tt.requests, tt.totalAvailable, options)
+			assert.Equal(t, tt.wantReasons, reasons)
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.Equal(t, tt.want[i].Node, got[i].Node)
+				assert.True(t, quotav1.Equals(tt.want[i].Resources, got[i].Resources), "want %v, got %v", tt.want[i].Resources, got[i].Resources)
+			}
+		})
+	}
+}
+
+func Test_tryBestToDistributeEvenlySortByAvailable(t *testing.T) {
+	mask, _ := bitmask.NewBitMask(1, 2)
+	options := &ResourceOptions{
+		hint: topologymanager.NUMATopologyHint{NUMANodeAffinity: mask},
+	}
+	totalAvailable := map[int]corev1.ResourceList{
+		0: {corev1.ResourceMemory: resource.MustParse("100Gi")},
+		1: {corev1.ResourceMemory: resource.MustParse("2Gi")},
+		2: {corev1.ResourceMemory: resource.MustParse("10Gi")},
+	}
+	requests := corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	got, reasons := tryBestToDistributeEvenly(requests, totalAvailable, options)
+	assert.Empty(t, reasons)
+	want := []NUMANodeResource{
+		{Node: 1, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+		{Node: 2, Resources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("6Gi")}},
+	}
+	assert.Equal(t, len(want), len(got))
+	for i := range want {
+		assert.Equal(t, want[i].Node, got[i].Node)
+		assert.True(t, quotav1.Equals(want[i].Resources, got[i].Resources), "want %v, got %v", want[i].Resources, got[i].Resources)
+	}
+}
+
 func TestResourceManagerGetTopologyHint(t *testing.T) {
 	tests := []struct {
 		name                string
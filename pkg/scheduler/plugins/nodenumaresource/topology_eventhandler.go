@@ -20,11 +20,8 @@ import (
 	"context"
 
 	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
-	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
 	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
 )
@@ -42,23 +39,6 @@ func registerNodeResourceTopologyEventHandler(informerFactory nrtinformers.Share
 	return nil
 }
 
-func initNRTInformerFactory(handle framework.Handle) (nrtinformers.SharedInformerFactory, error) {
-	nrtClient, ok := handle.(nrtclientset.Interface)
-	if !ok {
-		kubeConfig := *handle.KubeConfig()
-		kubeConfig.ContentType = runtime.ContentTypeJSON
-		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
-		var err error
-		nrtClient, err = nrtclientset.NewForConfig(&kubeConfig)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	nodeResTopologyInformerFactory := nrtinformers.NewSharedInformerFactoryWithOptions(nrtClient, 0)
-	return nodeResTopologyInformerFactory, nil
-}
-
 func (m *nodeResourceTopologyEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
 	nodeResTopology, ok := obj.(*nrtv1alpha1.NodeResourceTopology)
 	if !ok {
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/uuid"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
@@ -134,7 +135,7 @@ func TestTopologyOptionsManager(t *testing.T) {
 		FrameworkExtender: suit.Extender,
 		Clientset:         suit.NRTClientset,
 	}
-	nrtInformerFactory, err := initNRTInformerFactory(extendHandle)
+	nrtInformerFactory, err := frameworkexthelper.GetNRTInformerFactory(extendHandle)
 	assert.NoError(t, err)
 	err = registerNodeResourceTopologyEventHandler(nrtInformerFactory, topologyOptionsManager)
 	assert.NoError(t, err)
@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	nrtlisters "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/listers/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+)
+
+const (
+	// Name is the name of the plugin used in Registry and configurations.
+	Name = "Resctrl"
+
+	stateKey = Name
+
+	ErrInvalidResctrlConfig  = "invalid resctrl config"
+	ErrNRTNotSynced          = "NodeResourceTopology not synced"
+	ErrResctrlNotReported    = "node(s) resctrl info not reported"
+	ErrInsufficientClosIDs   = "Insufficient resctrl CLOS IDs"
+	ErrInsufficientCacheWays = "node(s) had too few cache ways for the resctrl LLC request"
+)
+
+var (
+	_ framework.PreFilterPlugin = &Plugin{}
+	_ framework.FilterPlugin    = &Plugin{}
+	_ framework.PreScorePlugin  = &Plugin{}
+	_ framework.ScorePlugin     = &Plugin{}
+	_ framework.ReservePlugin   = &Plugin{}
+	_ framework.PostBindPlugin  = &Plugin{}
+)
+
+// Plugin accounts the CLOS IDs taken by the pods with dedicated resctrl groups. Each pod with the resctrl
+// annotation takes a CLOS ID on the node, and the koordlet reports the CLOS IDs available for pods and the cache ways
+// in the NodeResourceTopology.
+type Plugin struct {
+	handle    framework.Handle
+	nrtLister nrtlisters.NodeResourceTopologyLister
+	nrtSynced cache.InformerSynced
+
+	lock sync.Mutex
+	// reserved is the pods of each node which hold the CLOS IDs since Reserve until they are bound
+	reserved map[string]map[types.UID]struct{}
+}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	nrtInformerFactory, err := frameworkexthelper.GetNRTInformerFactory(handle)
+	if err != nil {
+		return nil, err
+	}
+	nrtInformer := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies()
+	nrtSynced := nrtInformer.Informer().HasSynced
+	// the informer runs as long as the scheduler, and the pods are rejected in PreFilter until it has synced
+	nrtInformerFactory.Start(wait.NeverStop)
+
+	return &Plugin{
+		handle:    handle,
+		nrtLister: nrtInformer.Lister(),
+		nrtSynced: nrtSynced,
+		reserved:  map[string]map[types.UID]struct{}{},
+	}, nil
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+type preFilterState struct {
+	skip   bool
+	config *apiext.ResctrlConfig
+}
+
+func (s *preFilterState) Clone() framework.StateData {
+	return s
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, *framework.Status) {
+	value, err := cycleState.Read(stateKey)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	state, ok := value.(*preFilterState)
+	if !ok {
+		return nil, framework.AsStatus(fmt.Errorf("cannot convert %T to preFilterState", value))
+	}
+	return state, nil
+}
+
+func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	state := &preFilterState{skip: true}
+	if apiext.HasResctrlConfig(pod.Annotations) {
+		config, err := apiext.GetResctrlInfo(pod.Annotations)
+		if err != nil {
+			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidResctrlConfig)
+		}
+		state.skip, state.config = false, config
+	}
+	cycleState.Write(stateKey, state)
+	if state.skip {
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	if !p.nrtSynced() {
+		return nil, framework.NewStatus(framework.Unschedulable, ErrNRTNotSynced)
+	}
+	return nil, nil
+}
+
+func (p *Plugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+
+	resctrlInfo := p.getNodeResctrlInfo(node.Name)
+	if resctrlInfo == nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrResctrlNotReported)
+	}
+	if resctrlInfo.CacheWays > 0 && state.config.MinCacheWays(resctrlInfo.CacheWays) == 0 {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInsufficientCacheWays)
+	}
+	if p.countUsedClosIDs(node.Name, nodeInfo.Pods, pod.UID) >= resctrlInfo.ClosIDs {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientClosIDs)
+	}
+	return nil
+}
+
+func (p *Plugin) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodes []*corev1.Node) *framework.Status {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return framework.NewStatus(framework.Skip)
+	}
+	return nil
+}
+
+// Score prefers the nodes with more CLOS IDs left after placing the pod.
+func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	resctrlInfo := p.getNodeResctrlInfo(nodeName)
+	if resctrlInfo == nil || resctrlInfo.ClosIDs <= 0 {
+		return 0, nil
+	}
+	free := resctrlInfo.ClosIDs - p.countUsedClosIDs(nodeName, nodeInfo.Pods, pod.UID) - 1
+	if free <= 0 {
+		return 0, nil
+	}
+	return int64(free) * framework.MaxNodeScore / int64(resctrlInfo.ClosIDs), nil
+}
+
+func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// Reserve holds a CLOS ID for the pod until it is bound, and rejects the pod if the CLOS IDs have run out, e.g.
+// reserved by the pods being bound.
+func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return status
+	}
+	if state.skip {
+		return nil
+	}
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	resctrlInfo := p.getNodeResctrlInfo(nodeName)
+	if resctrlInfo == nil {
+		return framework.NewStatus(framework.Unschedulable, ErrResctrlNotReported)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.countUsedClosIDsLocked(nodeName, nodeInfo.Pods, pod.UID) >= resctrlInfo.ClosIDs {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientClosIDs)
+	}
+	reserved := p.reserved[nodeName]
+	if reserved == nil {
+		reserved = map[types.UID]struct{}{}
+		p.reserved[nodeName] = reserved
+	}
+	reserved[pod.UID] = struct{}{}
+	klog.V(5).Infof("pod %s/%s reserves a resctrl CLOS ID on node %s", pod.Namespace, pod.Name, nodeName)
+	return nil
+}
+
+func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	p.releaseReserved(pod, nodeName)
+}
+
+// PostBind releases the reservation since the bound pod is counted from the node.
+func (p *Plugin) PostBind(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	p.releaseReserved(pod, nodeName)
+}
+
+func (p *Plugin) releaseReserved(pod *corev1.Pod, nodeName string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	reserved := p.reserved[nodeName]
+	if reserved == nil {
+		return
+	}
+	delete(reserved, pod.UID)
+	if len(reserved) == 0 {
+		delete(p.reserved, nodeName)
+	}
+}
+
+func (p *Plugin) getNodeResctrlInfo(nodeName string) *apiext.NodeResctrlInfo {
+	nrt, err := p.nrtLister.Get(nodeName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.V(4).Infof("failed to get NodeResourceTopology %s, err: %v", nodeName, err)
+		}
+		return nil
+	}
+	resctrlInfo, err := apiext.GetNodeResctrlInfo(nrt.Annotations)
+	if err != nil {
+		klog.V(4).Infof("failed to parse resctrl info of NodeResourceTopology %s, err: %v", nodeName, err)
+		return nil
+	}
+	return resctrlInfo
+}
+
+func (p *Plugin) countUsedClosIDs(nodeName string, pods []*framework.PodInfo, excluded types.UID) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.countUsedClosIDsLocked(nodeName, pods, excluded)
+}
+
+// countUsedClosIDsLocked counts the CLOS IDs taken by the pods on the node and the pods reserved, except the
+// excluded pod.
+func (p *Plugin) countUsedClosIDsLocked(nodeName string, pods []*framework.PodInfo, excluded types.UID) int {
+	used := map[types.UID]struct{}{}
+	for _, podInfo := range pods {
+		if podInfo.Pod.UID != excluded && apiext.HasResctrlConfig(podInfo.Pod.Annotations) {
+			used[podInfo.Pod.UID] = struct{}{}
+		}
+	}
+	for uid := range p.reserved[nodeName] {
+		if uid != excluded {
+			used[uid] = struct{}{}
+		}
+	}
+	return len(used)
+}
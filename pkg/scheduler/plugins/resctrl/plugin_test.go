@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"context"
+	"testing"
+
+	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	nrtfake "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+var _ framework.SharedLister = &testSharedLister{}
+
+type testSharedLister struct {
+	nodes       []*corev1.Node
+	nodeInfos   []*framework.NodeInfo
+	nodeInfoMap map[string]*framework.NodeInfo
+}
+
+func newTestSharedLister(pods []*corev1.Pod, nodes []*corev1.Node) *testSharedLister {
+	nodeInfoMap := make(map[string]*framework.NodeInfo)
+	nodeInfos := make([]*framework.NodeInfo, 0)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if _, ok := nodeInfoMap[nodeName]; !ok {
+			nodeInfoMap[nodeName] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[nodeName].AddPod(pod)
+	}
+	for _, node := range nodes {
+		if _, ok := nodeInfoMap[node.Name]; !ok {
+			nodeInfoMap[node.Name] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[node.Name].SetNode(node)
+	}
+
+	for _, v := range nodeInfoMap {
+		nodeInfos = append(nodeInfos, v)
+	}
+
+	return &testSharedLister{
+		nodes:       nodes,
+		nodeInfos:   nodeInfos,
+		nodeInfoMap: nodeInfoMap,
+	}
+}
+
+func (f *testSharedLister) StorageInfos() framework.StorageInfoLister {
+	return f
+}
+
+func (f *testSharedLister) IsPVCUsedByPods(key string) bool {
+	return false
+}
+
+func (f *testSharedLister) NodeInfos() framework.NodeInfoLister {
+	return f
+}
+
+func (f *testSharedLister) List() ([]*framework.NodeInfo, error) {
+	return f.nodeInfos, nil
+}
+
+func (f *testSharedLister) HavePodsWithAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) HavePodsWithRequiredAntiAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) Get(nodeName string) (*framework.NodeInfo, error) {
+	return f.nodeInfoMap[nodeName], nil
+}
+
+type frameworkHandleExtender struct {
+	framework.Handle
+	*nrtfake.Clientset
+}
+
+func newTestPlugin(t *testing.T, pods []*corev1.Pod, nodes []*corev1.Node, nrts []*nrtv1alpha1.NodeResourceTopology) *Plugin {
+	nrtClientSet := nrtfake.NewSimpleClientset()
+	for _, nrt := range nrts {
+		_, err := nrtClientSet.TopologyV1alpha1().NodeResourceTopologies().Create(context.TODO(), nrt, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	registeredPlugins := []st.RegisterPluginFunc{
+		st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	fh, err := st.NewFramework(
+		context.TODO(),
+		registeredPlugins,
+		"koord-scheduler",
+		runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informerFactory),
+		runtime.WithSnapshotSharedLister(newTestSharedLister(pods, nodes)),
+	)
+	assert.NoError(t, err)
+
+	p, err := New(nil, &frameworkHandleExtender{Handle: fh, Clientset: nrtClientSet})
+	assert.NoError(t, err)
+	plugin := p.(*Plugin)
+	assert.True(t, cache.WaitForCacheSync(context.TODO().Done(), plugin.nrtSynced))
+	return plugin
+}
+
+func makeNRT(nodeName string, resctrlInfo string) *nrtv1alpha1.NodeResourceTopology {
+	nrt := &nrtv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeName,
+		},
+	}
+	if resctrlInfo != "" {
+		nrt.Annotations = map[string]string{
+			apiext.AnnotationNodeResctrlInfo: resctrlInfo,
+		}
+	}
+	return nrt
+}
+
+func makePod(name string, nodeName string, resctrl string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+	if resctrl != "" {
+		pod.Annotations = map[string]string{
+			apiext.AnnotationResctrl: resctrl,
+		}
+	}
+	return pod
+}
+
+func TestPlugin_PreFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		notSynced  bool
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "skip pod without resctrl config",
+			pod:        makePod("test-pod", "", ""),
+			wantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:       "invalid resctrl config",
+			pod:        makePod("test-pod", "", "{"),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidResctrlConfig),
+		},
+		{
+			name:       "pod with resctrl config",
+			pod:        makePod("test-pod", "", `{"llc":{"schemata":{"range":[0,30]}}}`),
+			wantStatus: nil,
+		},
+		{
+			name:       "NodeResourceTopology not synced",
+			pod:        makePod("test-pod", "", `{"llc":{"schemata":{"range":[0,30]}}}`),
+			notSynced:  true,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrNRTNotSynced),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlugin(t, nil, nil, nil)
+			if tt.notSynced {
+				p.nrtSynced = func() bool { return false }
+			}
+			cycleState := framework.NewCycleState()
+			_, status := p.PreFilter(context.TODO(), cycleState, tt.pod)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestPlugin_Filter(t *testing.T) {
+	node := st.MakeNode().Name("test-node").Obj()
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		pods       []*corev1.Pod
+		nrts       []*nrtv1alpha1.NodeResourceTopology
+		reserved   []types.UID
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "resctrl info not reported",
+			pod:        makePod("test-pod", "", `{"mb":{"schemata":{"percent":50}}}`),
+			nrts:       []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", "")},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrResctrlNotReported),
+		},
+		{
+			name: "CLOS IDs available",
+			pod:  makePod("test-pod", "", `{"mb":{"schemata":{"percent":50}}}`),
+			pods: []*corev1.Pod{
+				makePod("pod-1", "test-node", `{"mb":{"schemata":{"percent":50}}}`),
+				makePod("pod-2", "test-node", ""),
+			},
+			nrts:       []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", `{"closIDs":2,"cacheWays":12}`)},
+			wantStatus: nil,
+		},
+		{
+			name: "CLOS IDs used up by the pods on the node",
+			pod:  makePod("test-pod", "", `{"mb":{"schemata":{"percent":50}}}`),
+			pods: []*corev1.Pod{
+				makePod("pod-1", "test-node", `{"mb":{"schemata":{"percent":50}}}`),
+				makePod("pod-2", "test-node", `{"mb":{"schemata":{"percent":50}}}`),
+			},
+			nrts:       []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", `{"closIDs":2,"cacheWays":12}`)},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrInsufficientClosIDs),
+		},
+		{
+			name: "CLOS IDs used up by the reserved pods",
+			pod:  makePod("test-pod", "", `{"mb":{"schemata":{"percent":50}}}`),
+			pods: []*corev1.Pod{
+				makePod("pod-1", "test-node", `{"mb":{"schemata":{"percent":50}}}`),
+			},
+			nrts:       []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", `{"closIDs":2,"cacheWays":12}`)},
+			reserved:   []types.UID{"pod-2"},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrInsufficientClosIDs),
+		},
+		{
+			name:       "too few cache ways for the LLC request",
+			pod:        makePod("test-pod", "", `{"llc":{"schemata":{"range":[10,15]}}}`),
+			nrts:       []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", `{"closIDs":2,"cacheWays":11}`)},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInsufficientCacheWays),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPlugin(t, tt.pods, []*corev1.Node{node}, tt.nrts)
+			for _, uid := range tt.reserved {
+				p.reserved["test-node"] = map[types.UID]struct{}{uid: {}}
+			}
+			cycleState := framework.NewCycleState()
+			_, status := p.PreFilter(context.TODO(), cycleState, tt.pod)
+			assert.True(t, status.IsSuccess())
+			nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get("test-node")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, p.Filter(context.TODO(), cycleState, tt.pod, nodeInfo))
+		})
+	}
+}
+
+func TestPlugin_Score(t *testing.T) {
+	nodes := []*corev1.Node{
+		st.MakeNode().Name("node-1").Obj(),
+		st.MakeNode().Name("node-2").Obj(),
+	}
+	pods := []*corev1.Pod{
+		makePod("pod-1", "node-1", `{"mb":{"schemata":{"percent":50}}}`),
+		makePod("pod-2", "node-1", `{"mb":{"schemata":{"percent":50}}}`),
+	}
+	nrts := []*nrtv1alpha1.NodeResourceTopology{
+		makeNRT("node-1", `{"closIDs":4}`),
+		makeNRT("node-2", `{"closIDs":4}`),
+	}
+	p := newTestPlugin(t, pods, nodes, nrts)
+	pod := makePod("test-pod", "", `{"mb":{"schemata":{"percent":50}}}`)
+	cycleState := framework.NewCycleState()
+	_, status := p.PreFilter(context.TODO(), cycleState, pod)
+	assert.True(t, status.IsSuccess())
+	assert.True(t, p.PreScore(context.TODO(), cycleState, pod, nodes).IsSuccess())
+
+	score, status := p.Score(context.TODO(), cycleState, pod, "node-1")
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, int64(25), score)
+	score, status = p.Score(context.TODO(), cycleState, pod, "node-2")
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, int64(75), score)
+
+	cycleState = framework.NewCycleState()
+	pod = makePod("test-pod", "", "")
+	p.PreFilter(context.TODO(), cycleState, pod)
+	assert.Equal(t, framework.NewStatus(framework.Skip), p.PreScore(context.TODO(), cycleState, pod, nodes))
+}
+
+func TestPlugin_Reserve(t *testing.T) {
+	nodes := []*corev1.Node{st.MakeNode().Name("test-node").Obj()}
+	nrts := []*nrtv1alpha1.NodeResourceTopology{makeNRT("test-node", `{"closIDs":1}`)}
+	p := newTestPlugin(t, nil, nodes, nrts)
+
+	pod1 := makePod("pod-1", "", `{"mb":{"schemata":{"percent":50}}}`)
+	cycleState1 := framework.NewCycleState()
+	_, status := p.PreFilter(context.TODO(), cycleState1, pod1)
+	assert.True(t, status.IsSuccess())
+	assert.True(t, p.Reserve(context.TODO(), cycleState1, pod1, "test-node").IsSuccess())
+	// reserve again is idempotent
+	assert.True(t, p.Reserve(context.TODO(), cycleState1, pod1, "test-node").IsSuccess())
+
+	pod2 := makePod("pod-2", "", `{"mb":{"schemata":{"percent":50}}}`)
+	cycleState2 := framework.NewCycleState()
+	_, status = p.PreFilter(context.TODO(), cycleState2, pod2)
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, framework.NewStatus(framework.Unschedulable, ErrInsufficientClosIDs),
+		p.Reserve(context.TODO(), cycleState2, pod2, "test-node"))
+
+	p.Unreserve(context.TODO(), cycleState1, pod1, "test-node")
+	assert.Empty(t, p.reserved)
+	assert.True(t, p.Reserve(context.TODO(), cycleState2, pod2, "test-node").IsSuccess())
+	p.PostBind(context.TODO(), cycleState2, pod2, "test-node")
+	assert.Empty(t, p.reserved)
+
+	pod3 := makePod("pod-3", "", "")
+	cycleState3 := framework.NewCycleState()
+	p.PreFilter(context.TODO(), cycleState3, pod3)
+	assert.Nil(t, p.Reserve(context.TODO(), cycleState3, pod3, "test-node"))
+	assert.Empty(t, p.reserved)
+}
@@ -307,8 +307,16 @@ func (p *Plugin) calculateOnNode(strategy *configuration.ColocationStrategy, nod
 	// FIXME: resource reservation taking max is rather confusing.
 	nodeReserved := quotav1.Max(nodeKubeletReserved, nodeAnnoReserved)
 
+	// Pod(Prod/Mid).Predicted := max(sum(Pod(Prod/Mid).Request) - Prod.SeasonalReclaimable, 0) + sum(Pod(Dangling).Used)
+	var podsHPPredicted corev1.ResourceList
+	if seasonalReclaimable := nodeMetric.Status.ProdSeasonalReclaimableMetric; seasonalReclaimable != nil &&
+		seasonalReclaimable.Resource.ResourceList != nil {
+		podsHPPredicted = quotav1.Add(quotav1.Max(quotav1.Subtract(podsHPRequest,
+			resutil.GetResourceListForCPUAndMemory(seasonalReclaimable.Resource.ResourceList)), util.NewZeroResourceList()), podsDanglingUsed)
+	}
+
 	batchAllocatable, cpuMsg, memMsg := resutil.CalculateBatchResourceByPolicy(strategy, nodeCapacity, nodeSafetyMargin, nodeReserved,
-		systemUsed, podsHPRequest, podsHPUsed, podsHPMaxUsedReq)
+		systemUsed, podsHPRequest, podsHPUsed, podsHPMaxUsedReq, podsHPPredicted)
 	metrics.RecordNodeExtendedResourceAllocatableInternal(node, string(extension.BatchCPU), metrics.UnitInteger, float64(batchAllocatable.Cpu().MilliValue())/1000)
 	metrics.RecordNodeExtendedResourceAllocatableInternal(node, string(extension.BatchMemory), metrics.UnitByte, float64(batchAllocatable.Memory().Value()))
 	klog.V(6).InfoS("calculate batch resource for node", "node", node.Name, "batch resource",
@@ -444,9 +452,11 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 	var cpuMsg, memMsg string
 	for i := range batchZoneAllocatable {
 		zoneName := zoneIdxMap[i]
+		// the seasonal forecast is only reported at the node level, so the "prediction" policy falls back to "usage"
+		// for the NUMA zones, and the node-level allocatable still bounds the sum of the zones
 		batchZoneAllocatable[i], cpuMsg, memMsg = resutil.CalculateBatchResourceByPolicy(strategy, nodeZoneAllocatable[i],
 			nodeZoneReserve[i], systemZoneReserved[i], systemZoneUsed[i],
-			podsHPZoneRequested[i], podsHPZoneUsed[i], podsHPZoneMaxUsedReq[i])
+			podsHPZoneRequested[i], podsHPZoneUsed[i], podsHPZoneMaxUsedReq[i], nil)
 		klog.V(6).InfoS("calculate batch resource in NUMA level", "node", node.Name, "zone", zoneName,
 			"batch resource", batchZoneAllocatable[i], "cpu", cpuMsg, "memory", memMsg)
 
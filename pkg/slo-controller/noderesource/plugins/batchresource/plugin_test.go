@@ -1651,6 +1651,7 @@ func TestPluginCalculate(t *testing.T) {
 	assert.NoError(t, err)
 	memoryCalculateByReq := configuration.CalculateByPodRequest
 	cpuCalculateByMaxUsageReq := configuration.CalculateByPodMaxUsageRequest
+	calculateByPrediction := configuration.CalculateByPrediction
 	type fields struct {
 		client  ctrlclient.Client
 		checkFn func(t *testing.T, client ctrlclient.Client)
@@ -3670,6 +3671,126 @@ func TestPluginCalculate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "calculate with cpu and memory prediction",
+			args: args{
+				strategy: &configuration.ColocationStrategy{
+					Enable:                        pointer.Bool(true),
+					CPUReclaimThresholdPercent:    pointer.Int64(65),
+					CPUCalculatePolicy:            &calculateByPrediction,
+					MemoryReclaimThresholdPercent: pointer.Int64(65),
+					MemoryCalculatePolicy:         &calculateByPrediction,
+					DegradeTimeMinutes:            pointer.Int64(15),
+					UpdateTimeThresholdSeconds:    pointer.Int64(300),
+					ResourceDiffThreshold:         pointer.Float64(0.1),
+				},
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node1",
+					},
+					Status: makeNodeStat("100", "120G"),
+				},
+				resourceMetrics: func() *framework.ResourceMetrics {
+					resourceMetrics := getTestResourceMetrics()
+					resourceMetrics.NodeMetric.Status.ProdSeasonalReclaimableMetric = &slov1alpha1.ReclaimableMetric{
+						Resource: slov1alpha1.ResourceMap{
+							ResourceList: makeResourceList("2", "10G"),
+						},
+					}
+					return resourceMetrics
+				}(),
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(20000, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:20000 = min(nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPPredicted:38000, batchAllocatableByUsage:25000)",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(16, 9),
+					Message:  "batchAllocatable[Mem(GB)]:16 = min(nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPPredicted:50, batchAllocatableByUsage:33)",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "calculate with prediction bounded by usage",
+			args: args{
+				strategy: &configuration.ColocationStrategy{
+					Enable:                        pointer.Bool(true),
+					CPUReclaimThresholdPercent:    pointer.Int64(65),
+					CPUCalculatePolicy:            &calculateByPrediction,
+					MemoryReclaimThresholdPercent: pointer.Int64(65),
+					MemoryCalculatePolicy:         &calculateByPrediction,
+					DegradeTimeMinutes:            pointer.Int64(15),
+					UpdateTimeThresholdSeconds:    pointer.Int64(300),
+					ResourceDiffThreshold:         pointer.Float64(0.1),
+				},
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node1",
+					},
+					Status: makeNodeStat("100", "120G"),
+				},
+				resourceMetrics: func() *framework.ResourceMetrics {
+					resourceMetrics := getTestResourceMetrics()
+					resourceMetrics.NodeMetric.Status.ProdSeasonalReclaimableMetric = &slov1alpha1.ReclaimableMetric{
+						Resource: slov1alpha1.ResourceMap{
+							ResourceList: makeResourceList("20", "40G"),
+						},
+					}
+					return resourceMetrics
+				}(),
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(25000, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:25000 = min(nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPPredicted:20000, batchAllocatableByUsage:25000)",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(33, 9),
+					Message:  "batchAllocatable[Mem(GB)]:33 = min(nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPPredicted:20, batchAllocatableByUsage:33)",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "calculate with prediction but fallback to usage without forecast",
+			args: args{
+				strategy: &configuration.ColocationStrategy{
+					Enable:                        pointer.Bool(true),
+					CPUReclaimThresholdPercent:    pointer.Int64(65),
+					CPUCalculatePolicy:            &calculateByPrediction,
+					MemoryReclaimThresholdPercent: pointer.Int64(65),
+					MemoryCalculatePolicy:         &calculateByPrediction,
+					DegradeTimeMinutes:            pointer.Int64(15),
+					UpdateTimeThresholdSeconds:    pointer.Int64(300),
+					ResourceDiffThreshold:         pointer.Float64(0.1),
+				},
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node1",
+					},
+					Status: makeNodeStat("100", "120G"),
+				},
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(25000, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:25000 = nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPUsed:33000",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(33, 9),
+					Message:  "batchAllocatable[Mem(GB)]:33 = nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPUsed:33",
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "calculate with memory usage, including product host application usage",
 			args: args{
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskiobandwidthresource
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+	resutil "github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const PluginName = "DiskIOBandwidthResource"
+
+const (
+	ResetResourcesMsg = "reset node disk io bandwidth resources"
+
+	NeedSyncForResourceDiffMsg = "disk io bandwidth resource diff is big than threshold"
+)
+
+// ResourceNames defines the disk io bandwidth extended resource names to update.
+var ResourceNames = []corev1.ResourceName{extension.DiskIOBandwidth, extension.BatchDiskIOBandwidth}
+
+var client ctrlclient.Client
+
+// Plugin publishes the disk io bandwidth of the node as extended resources. The total bandwidth is taken from the
+// blk-iocost user models of the cgroup root blocks in the NodeSLO.
+type Plugin struct{}
+
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch
+
+func (p *Plugin) Setup(opt *framework.Option) error {
+	client = opt.Client
+	return nil
+}
+
+func (p *Plugin) NeedSync(strategy *configuration.ColocationStrategy, oldNode, newNode *corev1.Node) (bool, string) {
+	for _, resourceName := range ResourceNames {
+		if util.IsResourceDiff(oldNode.Status.Allocatable, newNode.Status.Allocatable, resourceName,
+			*strategy.ResourceDiffThreshold) {
+			klog.V(4).InfoS("need sync node since resource diff bigger than threshold", "node", newNode.Name,
+				"resource", resourceName, "threshold", *strategy.ResourceDiffThreshold)
+			return true, NeedSyncForResourceDiffMsg
+		}
+	}
+
+	return false, ""
+}
+
+func (p *Plugin) Prepare(_ *configuration.ColocationStrategy, node *corev1.Node, nr *framework.NodeResource) error {
+	for _, resourceName := range ResourceNames {
+		resutil.PrepareNodeForResource(node, nr, resourceName)
+	}
+	return nil
+}
+
+func (p *Plugin) Reset(node *corev1.Node, message string) []framework.ResourceItem {
+	items := make([]framework.ResourceItem, len(ResourceNames))
+	for i := range ResourceNames {
+		items[i].Name = ResourceNames[i]
+		items[i].Message = message
+		items[i].Reset = true
+	}
+
+	return items
+}
+
+// Calculate calculates the disk io bandwidth resources using the formula below:
+// TotalDiskIOBandwidth = sum(min(ModelReadBPS, ModelWriteBPS)) of the cgroup root blocks with user model,
+// DiskIOBandwidth = TotalDiskIOBandwidth,
+// BatchDiskIOBandwidth = max(TotalDiskIOBandwidth - Requested[Prod], 0).
+// The bandwidth is per direction, since a request limits both the read and write bps of a pod, so the sum of the
+// requests fits both the read and write bps of the disks.
+func (p *Plugin) Calculate(_ *configuration.ColocationStrategy, node *corev1.Node, podList *corev1.PodList,
+	_ *framework.ResourceMetrics) ([]framework.ResourceItem, error) {
+	if node == nil || podList == nil {
+		return nil, fmt.Errorf("missing essential arguments")
+	}
+
+	nodeSLO := &slov1alpha1.NodeSLO{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, nodeSLO); err != nil {
+		if !errors.IsNotFound(err) {
+			klog.V(4).InfoS("failed to get nodeSLO for node", "node", node.Name, "err", err)
+			return nil, fmt.Errorf("failed to get nodeSLO: %w", err)
+		}
+		klog.V(5).InfoS("nodeSLO not found, reset disk io bandwidth resources on node", "node", node.Name)
+		return p.Reset(node, ResetResourcesMsg), nil
+	}
+
+	var totalBandwidth int64
+	for _, block := range util.GetDiskIOBandwidthModelBlocks(nodeSLO.Spec.ResourceQOSStrategy) {
+		totalBandwidth += util.GetBlockDiskIOBandwidth(block)
+	}
+	if totalBandwidth <= 0 {
+		klog.V(5).InfoS("disk io bandwidth model not specified, reset disk io bandwidth resources on node",
+			"node", node.Name)
+		return p.Reset(node, ResetResourcesMsg), nil
+	}
+
+	return p.calculate(node, podList, totalBandwidth), nil
+}
+
+func (p *Plugin) calculate(node *corev1.Node, podList *corev1.PodList, totalBandwidth int64) []framework.ResourceItem {
+	var prodRequested int64
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		priorityClass := extension.GetPodPriorityClassWithDefault(pod)
+		// If the pod is not marked as low priority, it is considered high priority
+		isHighPriority := priorityClass != extension.PriorityMid && priorityClass != extension.PriorityBatch && priorityClass != extension.PriorityFree
+		if !isHighPriority {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		prodRequested += util.GetPodDiskIOBandwidthRequest(pod)
+	}
+
+	batchBandwidth := totalBandwidth - prodRequested
+	if batchBandwidth < 0 {
+		batchBandwidth = 0
+	}
+	bandwidthMsg := fmt.Sprintf("diskIOBandwidth[Bps]:%v = totalDiskIOBandwidth:%v", totalBandwidth, totalBandwidth)
+	batchBandwidthMsg := fmt.Sprintf("batchDiskIOBandwidth[Bps]:%v = max(totalDiskIOBandwidth:%v - prodRequested:%v, 0)",
+		batchBandwidth, totalBandwidth, prodRequested)
+
+	metrics.RecordNodeExtendedResourceAllocatableInternal(node, string(extension.DiskIOBandwidth), metrics.UnitByte, float64(totalBandwidth))
+	metrics.RecordNodeExtendedResourceAllocatableInternal(node, string(extension.BatchDiskIOBandwidth), metrics.UnitByte, float64(batchBandwidth))
+	klog.V(6).Infof("calculated disk io bandwidth for node %s, %s, %s", node.Name, bandwidthMsg, batchBandwidthMsg)
+
+	return []framework.ResourceItem{
+		{
+			Name:     extension.DiskIOBandwidth,
+			Quantity: resource.NewQuantity(totalBandwidth, resource.BinarySI),
+			Message:  bandwidthMsg,
+		},
+		{
+			Name:     extension.BatchDiskIOBandwidth,
+			Quantity: resource.NewQuantity(batchBandwidth, resource.BinarySI),
+			Message:  batchBandwidthMsg,
+		},
+	}
+}
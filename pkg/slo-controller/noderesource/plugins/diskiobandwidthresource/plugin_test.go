@@ -0,0 +1,290 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskiobandwidthresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+	"github.com/koordinator-sh/koordinator/pkg/util/testutil"
+)
+
+func TestPlugin(t *testing.T) {
+	p := &Plugin{}
+	assert.Equal(t, PluginName, p.Name())
+
+	testScheme := runtime.NewScheme()
+	testOpt := &framework.Option{
+		Scheme:  testScheme,
+		Client:  fake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Builder: builder.ControllerManagedBy(&testutil.FakeManager{}),
+	}
+	assert.NoError(t, p.Setup(testOpt))
+
+	got := p.Reset(nil, "reset")
+	assert.Equal(t, []framework.ResourceItem{
+		{Name: extension.DiskIOBandwidth, Message: "reset", Reset: true},
+		{Name: extension.BatchDiskIOBandwidth, Message: "reset", Reset: true},
+	}, got)
+}
+
+func TestPluginNeedSync(t *testing.T) {
+	testStrategy := &configuration.ColocationStrategy{
+		ResourceDiffThreshold: pointer.Float64(0.1),
+	}
+	genNode := func(bandwidth, batchBandwidth string) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("100"),
+				},
+			},
+		}
+		if bandwidth != "" {
+			node.Status.Allocatable[extension.DiskIOBandwidth] = resource.MustParse(bandwidth)
+			node.Status.Allocatable[extension.BatchDiskIOBandwidth] = resource.MustParse(batchBandwidth)
+		}
+		return node
+	}
+
+	p := &Plugin{}
+	got, _ := p.NeedSync(testStrategy, genNode("", ""), genNode("", ""))
+	assert.False(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("", ""), genNode("10Gi", "5Gi"))
+	assert.True(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("10Gi", "5Gi"), genNode("10Gi", "5.2Gi"))
+	assert.False(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("10Gi", "5Gi"), genNode("10Gi", "2Gi"))
+	assert.True(t, got)
+}
+
+func TestPluginPrepare(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				extension.BatchDiskIOBandwidth: resource.MustParse("5Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				extension.BatchDiskIOBandwidth: resource.MustParse("5Gi"),
+			},
+		},
+	}
+	nr := framework.NewNodeResource(framework.ResourceItem{
+		Name:     extension.DiskIOBandwidth,
+		Quantity: resource.NewQuantity(10737418240, resource.BinarySI),
+	}, framework.ResourceItem{
+		Name:  extension.BatchDiskIOBandwidth,
+		Reset: true,
+	})
+
+	p := &Plugin{}
+	assert.NoError(t, p.Prepare(nil, node, nr))
+	assert.Equal(t, corev1.ResourceList{
+		extension.DiskIOBandwidth: *resource.NewQuantity(10737418240, resource.BinarySI),
+	}, node.Status.Allocatable)
+	assert.Equal(t, corev1.ResourceList{
+		extension.DiskIOBandwidth: *resource.NewQuantity(10737418240, resource.BinarySI),
+	}, node.Status.Capacity)
+}
+
+func TestPluginCalculate(t *testing.T) {
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	testPodList := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-pod"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.DiskIOBandwidth: resource.MustParse("3Gi"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-pod-completed"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.DiskIOBandwidth: resource.MustParse("3Gi"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "batch-pod"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityBatchValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.BatchDiskIOBandwidth: resource.MustParse("2Gi"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	}
+	genNodeSLO := func(enable bool, modelReadBPS, modelWriteBPS int64) *slov1alpha1.NodeSLO {
+		return &slov1alpha1.NodeSLO{
+			ObjectMeta: metav1.ObjectMeta{Name: testNode.Name},
+			Spec: slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					CgroupRoot: &slov1alpha1.ResourceQOS{
+						BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{
+							Enable: pointer.Bool(enable),
+							BlkIOQOS: slov1alpha1.BlkIOQOS{
+								Blocks: []*slov1alpha1.BlockCfg{
+									{
+										Name:      "/dev/vdb",
+										BlockType: slov1alpha1.BlockTypeDevice,
+										IOCfg: slov1alpha1.IOCfg{
+											EnableUserModel: pointer.Bool(true),
+											ModelReadBPS:    pointer.Int64(modelReadBPS),
+											ModelWriteBPS:   pointer.Int64(modelWriteBPS),
+										},
+									},
+									{
+										Name:      "yoda-pool0",
+										BlockType: slov1alpha1.BlockTypeVolumeGroup,
+										IOCfg: slov1alpha1.IOCfg{
+											EnableUserModel: pointer.Bool(true),
+											ModelReadBPS:    pointer.Int64(modelReadBPS),
+											ModelWriteBPS:   pointer.Int64(modelWriteBPS),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		nodeSLO *slov1alpha1.NodeSLO
+		podList *corev1.PodList
+		want    []framework.ResourceItem
+		wantErr bool
+	}{
+		{
+			name:    "missing pod list",
+			nodeSLO: genNodeSLO(true, 4<<30, 2<<30),
+			wantErr: true,
+		},
+		{
+			name:    "reset without nodeSLO",
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{Name: extension.DiskIOBandwidth, Message: ResetResourcesMsg, Reset: true},
+				{Name: extension.BatchDiskIOBandwidth, Message: ResetResourcesMsg, Reset: true},
+			},
+		},
+		{
+			name:    "reset when cgroup root blkio disabled",
+			nodeSLO: genNodeSLO(false, 4<<30, 2<<30),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{Name: extension.DiskIOBandwidth, Message: ResetResourcesMsg, Reset: true},
+				{Name: extension.BatchDiskIOBandwidth, Message: ResetResourcesMsg, Reset: true},
+			},
+		},
+		{
+			name:    "calculate disk io bandwidth",
+			nodeSLO: genNodeSLO(true, 4<<30, 2<<30),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.DiskIOBandwidth,
+					Quantity: resource.NewQuantity(4294967296, resource.BinarySI),
+					Message:  "diskIOBandwidth[Bps]:4294967296 = totalDiskIOBandwidth:4294967296",
+				},
+				{
+					Name:     extension.BatchDiskIOBandwidth,
+					Quantity: resource.NewQuantity(1073741824, resource.BinarySI),
+					Message:  "batchDiskIOBandwidth[Bps]:1073741824 = max(totalDiskIOBandwidth:4294967296 - prodRequested:3221225472, 0)",
+				},
+			},
+		},
+		{
+			name:    "batch disk io bandwidth is not negative",
+			nodeSLO: genNodeSLO(true, 1<<30, 1<<30),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.DiskIOBandwidth,
+					Quantity: resource.NewQuantity(2147483648, resource.BinarySI),
+					Message:  "diskIOBandwidth[Bps]:2147483648 = totalDiskIOBandwidth:2147483648",
+				},
+				{
+					Name:     extension.BatchDiskIOBandwidth,
+					Quantity: resource.NewQuantity(0, resource.BinarySI),
+					Message:  "batchDiskIOBandwidth[Bps]:0 = max(totalDiskIOBandwidth:2147483648 - prodRequested:3221225472, 0)",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testScheme := runtime.NewScheme()
+			assert.NoError(t, slov1alpha1.AddToScheme(testScheme))
+			clientBuilder := fake.NewClientBuilder().WithScheme(testScheme)
+			if tt.nodeSLO != nil {
+				clientBuilder = clientBuilder.WithObjects(tt.nodeSLO)
+			}
+			client = clientBuilder.Build()
+
+			p := &Plugin{}
+			got, gotErr := p.Calculate(nil, testNode, tt.podList, nil)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
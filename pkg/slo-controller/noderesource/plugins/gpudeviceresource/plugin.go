@@ -119,6 +119,9 @@ func (p *Plugin) Prepare(_ *configuration.ColocationStrategy, node *corev1.Node,
 	// prepare node labels
 	// TBD: shall we reset labels if not exist in the NR
 	if nr.Labels != nil {
+		if _, ok := nr.Labels[extension.LabelGPUVendor]; ok {
+			node.Labels[extension.LabelGPUVendor] = nr.Labels[extension.LabelGPUVendor]
+		}
 		if _, ok := nr.Labels[extension.LabelGPUModel]; ok {
 			node.Labels[extension.LabelGPUModel] = nr.Labels[extension.LabelGPUModel]
 		}
@@ -203,8 +206,11 @@ func (p *Plugin) calculate(node *corev1.Node, device *schedulingv1alpha1.Device)
 	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
 	klog.V(5).InfoS("calculate gpu resources", "node", node.Name, "resources", gpuResources)
 
-	// calculate labels about gpu driver and model
+	// calculate labels about gpu vendor, driver and model
 	updatedLabels := map[string]string{}
+	if gpuVendor, ok := device.Labels[extension.LabelGPUVendor]; ok {
+		updatedLabels[extension.LabelGPUVendor] = gpuVendor
+	}
 	if gpuModel, ok := device.Labels[extension.LabelGPUModel]; ok {
 		updatedLabels[extension.LabelGPUModel] = gpuModel
 	}
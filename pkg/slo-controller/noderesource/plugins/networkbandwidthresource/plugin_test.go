@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkbandwidthresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+	"github.com/koordinator-sh/koordinator/pkg/util/testutil"
+)
+
+func TestPlugin(t *testing.T) {
+	p := &Plugin{}
+	assert.Equal(t, PluginName, p.Name())
+
+	testScheme := runtime.NewScheme()
+	testOpt := &framework.Option{
+		Scheme:  testScheme,
+		Client:  fake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Builder: builder.ControllerManagedBy(&testutil.FakeManager{}),
+	}
+	assert.NoError(t, p.Setup(testOpt))
+
+	got := p.Reset(nil, "reset")
+	assert.Equal(t, []framework.ResourceItem{
+		{Name: extension.NetworkBandwidth, Message: "reset", Reset: true},
+		{Name: extension.BatchNetworkBandwidth, Message: "reset", Reset: true},
+	}, got)
+}
+
+func TestPluginNeedSync(t *testing.T) {
+	testStrategy := &configuration.ColocationStrategy{
+		ResourceDiffThreshold: pointer.Float64(0.1),
+	}
+	genNode := func(bandwidth, batchBandwidth string) *corev1.Node {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("100"),
+				},
+			},
+		}
+		if bandwidth != "" {
+			node.Status.Allocatable[extension.NetworkBandwidth] = resource.MustParse(bandwidth)
+			node.Status.Allocatable[extension.BatchNetworkBandwidth] = resource.MustParse(batchBandwidth)
+		}
+		return node
+	}
+
+	p := &Plugin{}
+	got, _ := p.NeedSync(testStrategy, genNode("", ""), genNode("", ""))
+	assert.False(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("", ""), genNode("10G", "5G"))
+	assert.True(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("10G", "5G"), genNode("10G", "5.2G"))
+	assert.False(t, got)
+	got, _ = p.NeedSync(testStrategy, genNode("10G", "5G"), genNode("10G", "2G"))
+	assert.True(t, got)
+}
+
+func TestPluginPrepare(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				extension.BatchNetworkBandwidth: resource.MustParse("5G"),
+			},
+			Allocatable: corev1.ResourceList{
+				extension.BatchNetworkBandwidth: resource.MustParse("5G"),
+			},
+		},
+	}
+	nr := framework.NewNodeResource(framework.ResourceItem{
+		Name:     extension.NetworkBandwidth,
+		Quantity: resource.NewQuantity(10000000000, resource.DecimalSI),
+	}, framework.ResourceItem{
+		Name:  extension.BatchNetworkBandwidth,
+		Reset: true,
+	})
+
+	p := &Plugin{}
+	assert.NoError(t, p.Prepare(nil, node, nr))
+	assert.Equal(t, corev1.ResourceList{
+		extension.NetworkBandwidth: *resource.NewQuantity(10000000000, resource.DecimalSI),
+	}, node.Status.Allocatable)
+	assert.Equal(t, corev1.ResourceList{
+		extension.NetworkBandwidth: *resource.NewQuantity(10000000000, resource.DecimalSI),
+	}, node.Status.Capacity)
+}
+
+func TestPluginCalculate(t *testing.T) {
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}
+	testPodList := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-pod"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.NetworkBandwidth: resource.MustParse("3G"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "prod-pod-completed"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityProdValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.NetworkBandwidth: resource.MustParse("3G"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "batch-pod"},
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32(extension.PriorityBatchValueMax),
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									extension.BatchNetworkBandwidth: resource.MustParse("2G"),
+								},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	}
+	genNodeSLO := func(totalBandwidth string) *slov1alpha1.NodeSLO {
+		return &slov1alpha1.NodeSLO{
+			ObjectMeta: metav1.ObjectMeta{Name: testNode.Name},
+			Spec: slov1alpha1.NodeSLOSpec{
+				SystemStrategy: &slov1alpha1.SystemStrategy{
+					TotalNetworkBandwidth: resource.MustParse(totalBandwidth),
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		nodeSLO *slov1alpha1.NodeSLO
+		podList *corev1.PodList
+		want    []framework.ResourceItem
+		wantErr bool
+	}{
+		{
+			name:    "missing pod list",
+			nodeSLO: genNodeSLO("10G"),
+			wantErr: true,
+		},
+		{
+			name:    "reset without nodeSLO",
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{Name: extension.NetworkBandwidth, Message: ResetResourcesMsg, Reset: true},
+				{Name: extension.BatchNetworkBandwidth, Message: ResetResourcesMsg, Reset: true},
+			},
+		},
+		{
+			name:    "reset without total network bandwidth",
+			nodeSLO: genNodeSLO("0"),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{Name: extension.NetworkBandwidth, Message: ResetResourcesMsg, Reset: true},
+				{Name: extension.BatchNetworkBandwidth, Message: ResetResourcesMsg, Reset: true},
+			},
+		},
+		{
+			name:    "calculate network bandwidth",
+			nodeSLO: genNodeSLO("10G"),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.NetworkBandwidth,
+					Quantity: resource.NewQuantity(10000000000, resource.DecimalSI),
+					Message:  "networkBandwidth[bps]:10000000000 = totalNetworkBandwidth:10000000000",
+				},
+				{
+					Name:     extension.BatchNetworkBandwidth,
+					Quantity: resource.NewQuantity(7000000000, resource.DecimalSI),
+					Message:  "batchNetworkBandwidth[bps]:7000000000 = max(totalNetworkBandwidth:10000000000 - prodRequested:3000000000, 0)",
+				},
+			},
+		},
+		{
+			name:    "batch network bandwidth is not negative",
+			nodeSLO: genNodeSLO("2G"),
+			podList: testPodList,
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.NetworkBandwidth,
+					Quantity: resource.NewQuantity(2000000000, resource.DecimalSI),
+					Message:  "networkBandwidth[bps]:2000000000 = totalNetworkBandwidth:2000000000",
+				},
+				{
+					Name:     extension.BatchNetworkBandwidth,
+					Quantity: resource.NewQuantity(0, resource.DecimalSI),
+					Message:  "batchNetworkBandwidth[bps]:0 = max(totalNetworkBandwidth:2000000000 - prodRequested:3000000000, 0)",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testScheme := runtime.NewScheme()
+			assert.NoError(t, slov1alpha1.AddToScheme(testScheme))
+			clientBuilder := fake.NewClientBuilder().WithScheme(testScheme)
+			if tt.nodeSLO != nil {
+				clientBuilder = clientBuilder.WithObjects(tt.nodeSLO)
+			}
+			client = clientBuilder.Build()
+
+			p := &Plugin{}
+			got, gotErr := p.Calculate(nil, testNode, tt.podList, nil)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
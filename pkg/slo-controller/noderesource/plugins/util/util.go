@@ -47,8 +47,9 @@ const (
 	MidUnallocatedPercent = "midUnallocatedPercent"
 )
 
+// podHPPredicted is the forecasted usage of the HP pods, which is nil if the forecast is not reported.
 func CalculateBatchResourceByPolicy(strategy *configuration.ColocationStrategy, nodeCapacity, nodeSafetyMargin, nodeReserved,
-	systemUsed, podHPReq, podHPUsed, podHPMaxUsedReq corev1.ResourceList) (corev1.ResourceList, string, string) {
+	systemUsed, podHPReq, podHPUsed, podHPMaxUsedReq, podHPPredicted corev1.ResourceList) (corev1.ResourceList, string, string) {
 	// Node(Batch).Alloc[usage] := Node.Total - Node.SafetyMargin - System.Used - sum(Pod(Prod/Mid).Used)
 	// System.Used = max(Node.Used - Pod(All).Used, Node.Anno.Reserved, Node.Kubelet.Reserved)
 	systemUsed = quotav1.Max(systemUsed, nodeReserved)
@@ -64,11 +65,24 @@ func CalculateBatchResourceByPolicy(strategy *configuration.ColocationStrategy,
 	batchAllocatableByMaxUsageRequest := quotav1.Max(quotav1.Subtract(quotav1.Subtract(quotav1.Subtract(
 		nodeCapacity, nodeSafetyMargin), systemUsed), podHPMaxUsedReq), util.NewZeroResourceList())
 
-	batchAllocatable := batchAllocatableByUsage
+	// Node(Batch).Alloc[prediction] := min(Node.Total - Node.SafetyMargin - System.Used - sum(Pod(Prod/Mid).Predicted), Node(Batch).Alloc[usage])
+	var batchAllocatableByPrediction corev1.ResourceList
+	if podHPPredicted != nil {
+		batchAllocatableByPrediction = util.MinResourceList(quotav1.Max(quotav1.Subtract(quotav1.Subtract(quotav1.Subtract(
+			nodeCapacity, nodeSafetyMargin), systemUsed), podHPPredicted), util.NewZeroResourceList()), batchAllocatableByUsage)
+	}
+
+	batchAllocatable := batchAllocatableByUsage.DeepCopy()
 
 	var cpuMsg string
-	// batch cpu support policy "usage" and "maxUsageRequest"
-	if strategy != nil && strategy.CPUCalculatePolicy != nil && *strategy.CPUCalculatePolicy == configuration.CalculateByPodMaxUsageRequest {
+	// batch cpu support policy "usage", "maxUsageRequest" and "prediction"
+	if strategy != nil && strategy.CPUCalculatePolicy != nil && *strategy.CPUCalculatePolicy == configuration.CalculateByPrediction &&
+		batchAllocatableByPrediction != nil {
+		batchAllocatable[corev1.ResourceCPU] = *batchAllocatableByPrediction.Cpu()
+		cpuMsg = fmt.Sprintf("batchAllocatable[CPU(Milli-Core)]:%v = min(nodeCapacity:%v - nodeSafetyMargin:%v - systemUsageOrNodeReserved:%v - podHPPredicted:%v, batchAllocatableByUsage:%v)",
+			batchAllocatable.Cpu().MilliValue(), nodeCapacity.Cpu().MilliValue(), nodeSafetyMargin.Cpu().MilliValue(),
+			systemUsed.Cpu().MilliValue(), podHPPredicted.Cpu().MilliValue(), batchAllocatableByUsage.Cpu().MilliValue())
+	} else if strategy != nil && strategy.CPUCalculatePolicy != nil && *strategy.CPUCalculatePolicy == configuration.CalculateByPodMaxUsageRequest {
 		batchAllocatable[corev1.ResourceCPU] = *batchAllocatableByMaxUsageRequest.Cpu()
 		cpuMsg = fmt.Sprintf("batchAllocatable[CPU(Milli-Core)]:%v = nodeCapacity:%v - nodeSafetyMargin:%v - systemUsageOrNodeReserved:%v - podHPMaxUsedRequest:%v",
 			batchAllocatable.Cpu().MilliValue(), nodeCapacity.Cpu().MilliValue(), nodeSafetyMargin.Cpu().MilliValue(),
@@ -80,8 +94,15 @@ func CalculateBatchResourceByPolicy(strategy *configuration.ColocationStrategy,
 	}
 
 	var memMsg string
-	// batch memory support policy "usage", "request" and "maxUsageRequest"
-	if strategy != nil && strategy.MemoryCalculatePolicy != nil && *strategy.MemoryCalculatePolicy == configuration.CalculateByPodRequest {
+	// batch memory support policy "usage", "request", "maxUsageRequest" and "prediction"
+	if strategy != nil && strategy.MemoryCalculatePolicy != nil && *strategy.MemoryCalculatePolicy == configuration.CalculateByPrediction &&
+		batchAllocatableByPrediction != nil {
+		batchAllocatable[corev1.ResourceMemory] = *batchAllocatableByPrediction.Memory()
+		memMsg = fmt.Sprintf("batchAllocatable[Mem(GB)]:%v = min(nodeCapacity:%v - nodeSafetyMargin:%v - systemUsage:%v - podHPPredicted:%v, batchAllocatableByUsage:%v)",
+			batchAllocatable.Memory().ScaledValue(resource.Giga), nodeCapacity.Memory().ScaledValue(resource.Giga),
+			nodeSafetyMargin.Memory().ScaledValue(resource.Giga), systemUsed.Memory().ScaledValue(resource.Giga),
+			podHPPredicted.Memory().ScaledValue(resource.Giga), batchAllocatableByUsage.Memory().ScaledValue(resource.Giga))
+	} else if strategy != nil && strategy.MemoryCalculatePolicy != nil && *strategy.MemoryCalculatePolicy == configuration.CalculateByPodRequest {
 		batchAllocatable[corev1.ResourceMemory] = *batchAllocatableByRequest.Memory()
 		memMsg = fmt.Sprintf("batchAllocatable[Mem(GB)]:%v = nodeCapacity:%v - nodeSafetyMargin:%v - nodeReserved:%v - podHPRequest:%v",
 			batchAllocatable.Memory().ScaledValue(resource.Giga), nodeCapacity.Memory().ScaledValue(resource.Giga),
@@ -20,8 +20,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/batchresource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/cpunormalization"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/diskiobandwidthresource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/gpudeviceresource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/midresource"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/networkbandwidthresource"
 	rdmadeviceresource "github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/rdmadevicereource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/resourceamplification"
 )
@@ -36,6 +38,8 @@ func init() {
 	addPluginOption(&resourceamplification.Plugin{}, true)
 	addPluginOption(&gpudeviceresource.Plugin{}, true)
 	addPluginOption(&rdmadeviceresource.Plugin{}, true)
+	addPluginOption(&networkbandwidthresource.Plugin{}, true)
+	addPluginOption(&diskiobandwidthresource.Plugin{}, true)
 }
 
 func addPlugins(filter framework.FilterFn) {
@@ -56,6 +60,8 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&networkbandwidthresource.Plugin{},
+		&diskiobandwidthresource.Plugin{},
 	}
 	// NodePreUpdatePlugin implements node resource pre-updating.
 	nodePreUpdatePlugins = []framework.NodePreUpdatePlugin{
@@ -69,6 +75,8 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&networkbandwidthresource.Plugin{},
+		&diskiobandwidthresource.Plugin{},
 	}
 	// NodeSyncPlugin implements the check of resource updating.
 	nodeStatusCheckPlugins = []framework.NodeStatusCheckPlugin{
@@ -76,6 +84,8 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&networkbandwidthresource.Plugin{},
+		&diskiobandwidthresource.Plugin{},
 	}
 	// nodeMetaCheckPlugins implements the check of node meta updating.
 	nodeMetaCheckPlugins = []framework.NodeMetaCheckPlugin{
@@ -91,5 +101,7 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&networkbandwidthresource.Plugin{},
+		&diskiobandwidthresource.Plugin{},
 	}
 )
@@ -20,6 +20,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 // NOTE: functions in this file can be overwritten for extension
@@ -29,6 +30,18 @@ var ExtendedResourceNames = []corev1.ResourceName{
 	extension.BatchMemory,
 }
 
+// NetworkBandwidthResourceNames are the extended resources of the network bandwidth.
+var NetworkBandwidthResourceNames = []corev1.ResourceName{
+	extension.NetworkBandwidth,
+	extension.BatchNetworkBandwidth,
+}
+
+// DiskIOBandwidthResourceNames are the extended resources of the disk io bandwidth.
+var DiskIOBandwidthResourceNames = []corev1.ResourceName{
+	extension.DiskIOBandwidth,
+	extension.BatchDiskIOBandwidth,
+}
+
 func GetBatchMilliCPUFromResourceList(r corev1.ResourceList) int64 {
 	// assert r != nil
 	if milliCPU, ok := r[extension.BatchCPU]; ok {
@@ -60,3 +73,71 @@ func GetContainerBatchMemoryByteRequest(c *corev1.Container) int64 {
 func GetContainerBatchMemoryByteLimit(c *corev1.Container) int64 {
 	return GetBatchMemoryFromResourceList(c.Resources.Limits)
 }
+
+// GetPodNetworkBandwidthRequest returns the network bandwidth requested by the pod in bps.
+func GetPodNetworkBandwidthRequest(pod *corev1.Pod) int64 {
+	var bandwidth int64
+	for _, q := range GetPodRequest(pod, NetworkBandwidthResourceNames...) {
+		bandwidth += q.Value()
+	}
+	return bandwidth
+}
+
+// GetNetworkBandwidthRequestFromExtendedResourceSpec returns the network bandwidth requested by the containers in the
+// ExtendedResourceSpec in bps.
+func GetNetworkBandwidthRequestFromExtendedResourceSpec(spec *extension.ExtendedResourceSpec) int64 {
+	if spec == nil {
+		return 0
+	}
+	var bandwidth int64
+	for _, containerSpec := range spec.Containers {
+		for _, name := range NetworkBandwidthResourceNames {
+			if q, ok := containerSpec.Requests[name]; ok {
+				bandwidth += q.Value()
+			}
+		}
+	}
+	return bandwidth
+}
+
+// GetPodDiskIOBandwidthRequest returns the disk io bandwidth requested by the pod in bytes per second.
+func GetPodDiskIOBandwidthRequest(pod *corev1.Pod) int64 {
+	var bandwidth int64
+	for _, q := range GetPodRequest(pod, DiskIOBandwidthResourceNames...) {
+		bandwidth += q.Value()
+	}
+	return bandwidth
+}
+
+// GetDiskIOBandwidthModelBlocks returns the enabled blocks of the cgroup root whose blk-iocost user model specifies
+// both the read and write bps. These blocks make up the disk io bandwidth capacity of the node.
+func GetDiskIOBandwidthModelBlocks(strategy *slov1alpha1.ResourceQOSStrategy) []*slov1alpha1.BlockCfg {
+	if strategy == nil || strategy.CgroupRoot == nil || strategy.CgroupRoot.BlkIOQOS == nil ||
+		strategy.CgroupRoot.BlkIOQOS.Enable == nil || !*strategy.CgroupRoot.BlkIOQOS.Enable {
+		return nil
+	}
+	var blocks []*slov1alpha1.BlockCfg
+	for _, block := range strategy.CgroupRoot.BlkIOQOS.Blocks {
+		if block == nil || block.IOCfg.EnableUserModel == nil || !*block.IOCfg.EnableUserModel {
+			continue
+		}
+		if block.IOCfg.ModelReadBPS == nil || *block.IOCfg.ModelReadBPS <= 0 ||
+			block.IOCfg.ModelWriteBPS == nil || *block.IOCfg.ModelWriteBPS <= 0 {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// GetBlockDiskIOBandwidth returns the per-direction disk io bandwidth of the block model in bytes per second. Since a
+// request limits both the read and write bps, the bandwidth is the smaller one of the model read and write bps.
+func GetBlockDiskIOBandwidth(block *slov1alpha1.BlockCfg) int64 {
+	if block == nil || block.IOCfg.ModelReadBPS == nil || block.IOCfg.ModelWriteBPS == nil {
+		return 0
+	}
+	if *block.IOCfg.ModelReadBPS < *block.IOCfg.ModelWriteBPS {
+		return *block.IOCfg.ModelReadBPS
+	}
+	return *block.IOCfg.ModelWriteBPS
+}
@@ -22,8 +22,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 func Test_GetContainerXXXValue(t *testing.T) {
@@ -205,3 +208,123 @@ func TestGetBatchXXXFromResourceList(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNetworkBandwidthRequest(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pod",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:         resource.MustParse("1"),
+							extension.NetworkBandwidth: resource.MustParse("100M"),
+						},
+					},
+				},
+				{
+					Name: "sidecar",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							extension.BatchNetworkBandwidth: resource.MustParse("20M"),
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, int64(120000000), GetPodNetworkBandwidthRequest(pod))
+	assert.Equal(t, int64(0), GetPodNetworkBandwidthRequest(&corev1.Pod{}))
+
+	spec := &extension.ExtendedResourceSpec{
+		Containers: map[string]extension.ExtendedResourceContainerSpec{
+			"main": {
+				Requests: corev1.ResourceList{
+					extension.NetworkBandwidth: resource.MustParse("100M"),
+				},
+			},
+			"sidecar": {
+				Requests: corev1.ResourceList{
+					extension.BatchCPU:              resource.MustParse("1000"),
+					extension.BatchNetworkBandwidth: resource.MustParse("20M"),
+				},
+			},
+		},
+	}
+	assert.Equal(t, int64(120000000), GetNetworkBandwidthRequestFromExtendedResourceSpec(spec))
+	assert.Equal(t, int64(0), GetNetworkBandwidthRequestFromExtendedResourceSpec(nil))
+}
+
+func TestGetPodDiskIOBandwidthRequest(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pod",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							extension.BatchCPU:             resource.MustParse("1000"),
+							extension.BatchDiskIOBandwidth: resource.MustParse("100Mi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, int64(104857600), GetPodDiskIOBandwidthRequest(pod))
+	assert.Equal(t, int64(0), GetPodDiskIOBandwidthRequest(&corev1.Pod{}))
+}
+
+func TestGetDiskIOBandwidthModelBlocks(t *testing.T) {
+	modelBlock := &slov1alpha1.BlockCfg{
+		Name:      "/dev/vdb",
+		BlockType: slov1alpha1.BlockTypeDevice,
+		IOCfg: slov1alpha1.IOCfg{
+			EnableUserModel: pointer.Bool(true),
+			ModelReadBPS:    pointer.Int64(3000),
+			ModelWriteBPS:   pointer.Int64(2000),
+		},
+	}
+	autoModelBlock := &slov1alpha1.BlockCfg{
+		Name:      "yoda-pool0",
+		BlockType: slov1alpha1.BlockTypeVolumeGroup,
+		IOCfg: slov1alpha1.IOCfg{
+			ModelReadBPS:  pointer.Int64(3000),
+			ModelWriteBPS: pointer.Int64(2000),
+		},
+	}
+	incompleteModelBlock := &slov1alpha1.BlockCfg{
+		Name:      "/dev/vdc",
+		BlockType: slov1alpha1.BlockTypeDevice,
+		IOCfg: slov1alpha1.IOCfg{
+			EnableUserModel: pointer.Bool(true),
+			ModelReadBPS:    pointer.Int64(3000),
+		},
+	}
+	genStrategy := func(enable bool) *slov1alpha1.ResourceQOSStrategy {
+		return &slov1alpha1.ResourceQOSStrategy{
+			CgroupRoot: &slov1alpha1.ResourceQOS{
+				BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{
+					Enable: pointer.Bool(enable),
+					BlkIOQOS: slov1alpha1.BlkIOQOS{
+						Blocks: []*slov1alpha1.BlockCfg{modelBlock, autoModelBlock, incompleteModelBlock},
+					},
+				},
+			},
+		}
+	}
+
+	assert.Nil(t, GetDiskIOBandwidthModelBlocks(nil))
+	assert.Nil(t, GetDiskIOBandwidthModelBlocks(genStrategy(false)))
+	assert.Equal(t, []*slov1alpha1.BlockCfg{modelBlock}, GetDiskIOBandwidthModelBlocks(genStrategy(true)))
+
+	assert.Equal(t, int64(2000), GetBlockDiskIOBandwidth(modelBlock))
+	assert.Equal(t, int64(0), GetBlockDiskIOBandwidth(incompleteModelBlock))
+	assert.Equal(t, int64(0), GetBlockDiskIOBandwidth(nil))
+}
@@ -411,6 +411,7 @@ func DefaultCPUBurstStrategy() *slov1alpha1.CPUBurstStrategy {
 	return &slov1alpha1.CPUBurstStrategy{
 		CPUBurstConfig:            DefaultCPUBurstConfig(),
 		SharePoolThresholdPercent: pointer.Int64(50),
+		NodeBurstBudgetPercent:    pointer.Int64(100),
 	}
 }
 
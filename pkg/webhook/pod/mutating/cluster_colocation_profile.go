@@ -69,6 +69,9 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 	var matchedProfiles []*configv1alpha1.ClusterColocationProfile
 	for i := range profileList.Items {
 		profile := &profileList.Items[i]
+		if profile.Spec.DryRun { // the dry-run profile is only evaluated by the controller
+			continue
+		}
 		if profile.Spec.NamespaceSelector != nil {
 			matched, err := h.matchNamespaceSelector(ctx, pod.Namespace, profile.Spec.NamespaceSelector)
 			if !matched && err == nil {
@@ -259,6 +262,14 @@ func (h *PodMutatingHandler) mutatePodResourceSpec(pod *corev1.Pod) error {
 
 			restrictResourceRequestAndLimit(priorityClass, &container.Resources, corev1.ResourceCPU)
 			restrictResourceRequestAndLimit(priorityClass, &container.Resources, corev1.ResourceMemory)
+
+			// the disk io bandwidth of the low priority pods is allocated from the reclaimed bandwidth
+			replaceAndEraseResource(priorityClass, container.Resources.Requests, extension.DiskIOBandwidth)
+			replaceAndEraseResource(priorityClass, container.Resources.Limits, extension.DiskIOBandwidth)
+
+			// the network bandwidth of the low priority pods is allocated from the reclaimed bandwidth
+			replaceAndEraseResource(priorityClass, container.Resources.Requests, extension.NetworkBandwidth)
+			replaceAndEraseResource(priorityClass, container.Resources.Limits, extension.NetworkBandwidth)
 		}
 	}
 
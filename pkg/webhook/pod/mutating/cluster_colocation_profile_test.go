@@ -1707,3 +1707,64 @@ func TestClusterColocationProfileMutatingPod(t *testing.T) {
 
 	}
 }
+
+func TestMutatePodResourceSpecWithBandwidth(t *testing.T) {
+	newPod := func(priority int32, resourceList corev1.ResourceList) *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "test-container-a",
+						Resources: corev1.ResourceRequirements{
+							Limits:   resourceList.DeepCopy(),
+							Requests: resourceList.DeepCopy(),
+						},
+					},
+				},
+				Priority: pointer.Int32(priority),
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected *corev1.Pod
+	}{
+		{
+			name: "batch pod requests the reclaimed network bandwidth",
+			pod: newPod(extension.PriorityBatchValueMax, corev1.ResourceList{
+				extension.NetworkBandwidth: resource.MustParse("100M"),
+			}),
+			expected: newPod(extension.PriorityBatchValueMax, corev1.ResourceList{
+				extension.BatchNetworkBandwidth: resource.MustParse("100M"),
+			}),
+		},
+		{
+			name: "batch pod requests the reclaimed disk io bandwidth",
+			pod: newPod(extension.PriorityBatchValueMax, corev1.ResourceList{
+				corev1.ResourceCPU:        resource.MustParse("1"),
+				extension.DiskIOBandwidth: resource.MustParse("100Mi"),
+			}),
+			expected: newPod(extension.PriorityBatchValueMax, corev1.ResourceList{
+				extension.BatchCPU:             *resource.NewQuantity(1000, resource.DecimalSI),
+				extension.BatchDiskIOBandwidth: resource.MustParse("100Mi"),
+			}),
+		},
+		{
+			name: "prod pod requests the network bandwidth",
+			pod: newPod(extension.PriorityProdValueMax, corev1.ResourceList{
+				extension.NetworkBandwidth: resource.MustParse("100M"),
+			}),
+			expected: newPod(extension.PriorityProdValueMax, corev1.ResourceList{
+				extension.NetworkBandwidth: resource.MustParse("100M"),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &PodMutatingHandler{}
+			assert.NoError(t, h.mutatePodResourceSpec(tt.pod))
+			assert.Equal(t, tt.expected, tt.pod)
+		})
+	}
+}
@@ -38,7 +38,8 @@ func (h *PodMutatingHandler) extendedResourceSpecMutatingPod(ctx context.Context
 }
 
 func (h *PodMutatingHandler) mutateByExtendedResources(pod *corev1.Pod) error {
-	// dump batch-resource of pod.spec.containers[*].resources.requests/limits into ExtendedResourceSpec{}
+	// dump batch-resource and network bandwidth of pod.spec.containers[*].resources.requests/limits into
+	// ExtendedResourceSpec{}
 	extendedResourceSpec := &extension.ExtendedResourceSpec{}
 	containersSpec := map[string]extension.ExtendedResourceContainerSpec{}
 
@@ -48,6 +49,8 @@ func (h *PodMutatingHandler) mutateByExtendedResources(pod *corev1.Pod) error {
 		r := getContainerExtendedResourcesRequirement(container, []corev1.ResourceName{
 			extension.BatchCPU,
 			extension.BatchMemory,
+			extension.NetworkBandwidth,
+			extension.BatchNetworkBandwidth,
 		})
 		if r == nil {
 			continue